@@ -10,8 +10,12 @@ require (
 	github.com/gorilla/sessions v1.2.0 // indirect
 	github.com/monopole/mdrip v1.0.0
 	github.com/pkg/errors v0.8.1
+	github.com/spf13/afero v1.1.2
 	github.com/spf13/pflag v1.0.5
+	go.starlark.net v0.0.0-20191113183327-aaf7be003892
+	google.golang.org/grpc v1.25.1
 	gopkg.in/yaml.v2 v2.2.2
+	gopkg.in/yaml.v3 v3.0.1
 	k8s.io/api v0.0.0-20190313235455-40a48860b5ab
 	k8s.io/apimachinery v0.0.0-20190313205120-d7deff9243b1
 	k8s.io/client-go v11.0.0+incompatible
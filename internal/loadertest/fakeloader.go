@@ -54,6 +54,11 @@ func (f FakeLoader) AddDirectory(fullDirPath string) error {
 	return f.fs.Mkdir(fullDirPath)
 }
 
+// Delegate returns the delegate Loader.
+func (f FakeLoader) Delegate() ifc.Loader {
+	return f.delegate
+}
+
 // Root delegates.
 func (f FakeLoader) Root() string {
 	return f.delegate.Root()
@@ -27,6 +27,15 @@ func makeFreshConfigMap(
 // MakeConfigMap returns a new ConfigMap, or nil and an error.
 func (f *Factory) MakeConfigMap(
 	args *types.ConfigMapArgs) (*v1.ConfigMap, error) {
+	if args.Sops != nil {
+		// Decrypted data doesn't belong in a ConfigMap.
+		return nil, fmt.Errorf(
+			"configmap %s cannot use sops; use a secret", args.Name)
+	}
+	if len(args.Commands) > 0 {
+		return nil, fmt.Errorf(
+			"configmap %s cannot use commands; use a secret", args.Name)
+	}
 	all, err := f.ldr.LoadKvPairs(args.GeneratorArgs)
 	if err != nil {
 		return nil, errors.Wrap(err, "loading KV pairs")
@@ -51,6 +60,10 @@ func (f *Factory) addKvToConfigMap(configMap *v1.ConfigMap, p types.Pair) error
 	if err := f.ldr.Validator().ErrIfInvalidKey(p.Key); err != nil {
 		return err
 	}
+	// A key may be in either Data or BinaryData, not both.
+	if _, entryExists := configMap.BinaryData[p.Key]; entryExists {
+		return fmt.Errorf(keyExistsErrorMsg, p.Key, configMap.BinaryData)
+	}
 	// If the configmap data contains byte sequences that are all in the UTF-8
 	// range, we will write it to .Data
 	if utf8.Valid([]byte(p.Value)) {
@@ -61,6 +74,9 @@ func (f *Factory) addKvToConfigMap(configMap *v1.ConfigMap, p types.Pair) error
 		return nil
 	}
 	// otherwise, it's BinaryData
+	if _, entryExists := configMap.Data[p.Key]; entryExists {
+		return fmt.Errorf(keyExistsErrorMsg, p.Key, configMap.Data)
+	}
 	if configMap.BinaryData == nil {
 		configMap.BinaryData = map[string][]byte{}
 	}
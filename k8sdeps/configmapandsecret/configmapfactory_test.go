@@ -153,3 +153,17 @@ func TestConstructConfigMap(t *testing.T) {
 		}
 	}
 }
+
+func TestConstructConfigMapWithSops(t *testing.T) {
+	ldr := loader.NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	_, err := NewFactory(ldr, nil).MakeConfigMap(&types.ConfigMapArgs{
+		GeneratorArgs: types.GeneratorArgs{
+			Name: "decrypted",
+			Sops: &types.SopsArgs{},
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
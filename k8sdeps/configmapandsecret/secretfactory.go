@@ -28,11 +28,23 @@ func makeFreshSecret(
 // MakeSecret returns a new secret.
 func (f *Factory) MakeSecret(
 	args *types.SecretArgs) (*corev1.Secret, error) {
+	t, err := typedSecretType(args)
+	if err != nil {
+		return nil, err
+	}
 	all, err := f.ldr.LoadKvPairs(args.GeneratorArgs)
 	if err != nil {
 		return nil, err
 	}
+	typed, err := f.loadTypedPairs(args)
+	if err != nil {
+		return nil, err
+	}
+	all = append(all, typed...)
 	s := makeFreshSecret(args)
+	if t != "" {
+		s.Type = t
+	}
 	for _, p := range all {
 		err = f.addKvToSecret(s, p.Key, p.Value)
 		if err != nil {
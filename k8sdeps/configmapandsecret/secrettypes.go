@@ -0,0 +1,149 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package configmapandsecret
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// typedSecretType returns the type implied by the tls or
+// dockerConfig fields of the args, or the empty string if
+// neither is set.  A declared type must agree with it.
+func typedSecretType(args *types.SecretArgs) (corev1.SecretType, error) {
+	if args.TLS != nil && args.DockerConfig != nil {
+		return "", fmt.Errorf(
+			"secret %s cannot have both tls and dockerConfig", args.Name)
+	}
+	var t corev1.SecretType
+	switch {
+	case args.TLS != nil:
+		t = corev1.SecretTypeTLS
+	case args.DockerConfig != nil:
+		t = corev1.SecretTypeDockerConfigJson
+	default:
+		return "", nil
+	}
+	if args.Type != "" && corev1.SecretType(args.Type) != t {
+		return "", fmt.Errorf(
+			"secret %s has type %s, but its fields require type %s",
+			args.Name, args.Type, t)
+	}
+	return t, nil
+}
+
+// loadTypedPairs loads the keys of a tls or dockerconfigjson secret.
+func (f *Factory) loadTypedPairs(args *types.SecretArgs) ([]types.Pair, error) {
+	switch {
+	case args.TLS != nil:
+		return f.loadTLSPairs(args.TLS)
+	case args.DockerConfig != nil:
+		return f.loadDockerConfigPairs(args.DockerConfig)
+	}
+	return nil, nil
+}
+
+func (f *Factory) loadTLSPairs(args *types.TLSArgs) ([]types.Pair, error) {
+	if args.CertFile == "" || args.KeyFile == "" {
+		return nil, fmt.Errorf("tls requires both certFile and keyFile")
+	}
+	cert, err := f.ldr.Load(args.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := f.ldr.Load(args.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tls.X509KeyPair(cert, key); err != nil {
+		return nil, errors.Wrapf(
+			err, "tls certFile %s and keyFile %s", args.CertFile, args.KeyFile)
+	}
+	return []types.Pair{
+		{Key: corev1.TLSCertKey, Value: string(cert)},
+		{Key: corev1.TLSPrivateKeyKey, Value: string(key)},
+	}, nil
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+func (f *Factory) loadDockerConfigPairs(
+	args *types.DockerConfigArgs) ([]types.Pair, error) {
+	if args.ConfigFile != "" {
+		if args.Server != "" || args.Username != "" || args.PasswordFile != "" {
+			return nil, fmt.Errorf(
+				"dockerConfig configFile cannot be combined with " +
+					"server, username or passwordFile")
+		}
+		return f.loadDockerConfigFile(args.ConfigFile)
+	}
+	if args.Server == "" || args.Username == "" || args.PasswordFile == "" {
+		return nil, fmt.Errorf(
+			"dockerConfig requires either configFile, " +
+				"or server, username and passwordFile")
+	}
+	content, err := f.ldr.Load(args.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+	password := strings.TrimRight(string(content), "\r\n")
+	if password == "" {
+		return nil, fmt.Errorf(
+			"dockerConfig passwordFile %s is empty", args.PasswordFile)
+	}
+	c := dockerConfigJSON{
+		Auths: map[string]dockerConfigEntry{
+			args.Server: {
+				Username: args.Username,
+				Password: password,
+				Email:    args.Email,
+				Auth: base64.StdEncoding.EncodeToString(
+					[]byte(args.Username + ":" + password)),
+			},
+		},
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return []types.Pair{
+		{Key: corev1.DockerConfigJsonKey, Value: string(b)},
+	}, nil
+}
+
+// loadDockerConfigFile loads an existing docker config,
+// requiring it to name at least one registry.
+func (f *Factory) loadDockerConfigFile(path string) ([]types.Pair, error) {
+	content, err := f.ldr.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var c dockerConfigJSON
+	if err = json.Unmarshal(content, &c); err != nil {
+		return nil, errors.Wrapf(err, "dockerConfig configFile %s", path)
+	}
+	if len(c.Auths) == 0 {
+		return nil, fmt.Errorf(
+			"dockerConfig configFile %s has no auths", path)
+	}
+	return []types.Pair{
+		{Key: corev1.DockerConfigJsonKey, Value: string(content)},
+	}, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package configmapandsecret
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func makeKeyPair(t *testing.T) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func makeTypedSecretFactory(t *testing.T) *Factory {
+	cert, key := makeKeyPair(t)
+	_, otherKey := makeKeyPair(t)
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/tls/tls.crt", cert)
+	fSys.WriteFile("/tls/tls.key", key)
+	fSys.WriteFile("/tls/other.key", otherKey)
+	fSys.WriteFile("/docker/password", []byte("s3cret\n"))
+	fSys.WriteFile("/docker/config.json",
+		[]byte(`{"auths":{"quay.io":{"auth":"Zm9vOmJhcg=="}}}`))
+	fSys.WriteFile("/docker/empty.json", []byte(`{"auths":{}}`))
+	return NewFactory(
+		loader.NewFileLoaderAtRoot(validators.MakeFakeValidator(), fSys), nil)
+}
+
+func TestMakeTLSSecret(t *testing.T) {
+	f := makeTypedSecretFactory(t)
+	s, err := f.MakeSecret(&types.SecretArgs{
+		GeneratorArgs: types.GeneratorArgs{
+			Name: "tlsSecret",
+			DataSources: types.DataSources{
+				LiteralSources: []string{"ca.crt=none"},
+			},
+		},
+		TLS: &types.TLSArgs{
+			CertFile: "tls/tls.crt",
+			KeyFile:  "tls/tls.key",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if s.Type != corev1.SecretTypeTLS {
+		t.Fatalf("unexpected type %s", s.Type)
+	}
+	for _, k := range []string{"tls.crt", "tls.key", "ca.crt"} {
+		if _, ok := s.Data[k]; !ok {
+			t.Fatalf("missing key %s in %v", k, s.Data)
+		}
+	}
+	if !strings.HasPrefix(string(s.Data["tls.crt"]), "-----BEGIN CERTIFICATE") {
+		t.Fatalf("unexpected tls.crt %s", s.Data["tls.crt"])
+	}
+}
+
+func TestMakeDockerConfigSecret(t *testing.T) {
+	f := makeTypedSecretFactory(t)
+	s, err := f.MakeSecret(&types.SecretArgs{
+		GeneratorArgs: types.GeneratorArgs{Name: "pull"},
+		DockerConfig: &types.DockerConfigArgs{
+			Server:       "registry.example.com",
+			Username:     "foo",
+			PasswordFile: "docker/password",
+			Email:        "foo@example.com",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if s.Type != corev1.SecretTypeDockerConfigJson {
+		t.Fatalf("unexpected type %s", s.Type)
+	}
+	expected := `{"auths":{"registry.example.com":{` +
+		`"username":"foo","password":"s3cret","email":"foo@example.com",` +
+		`"auth":"Zm9vOnMzY3JldA=="}}}`
+	if actual := string(s.Data[".dockerconfigjson"]); actual != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+	}
+
+	s, err = f.MakeSecret(&types.SecretArgs{
+		GeneratorArgs: types.GeneratorArgs{Name: "pull"},
+		DockerConfig:  &types.DockerConfigArgs{ConfigFile: "docker/config.json"},
+	})
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	expected = `{"auths":{"quay.io":{"auth":"Zm9vOmJhcg=="}}}`
+	if actual := string(s.Data[".dockerconfigjson"]); actual != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, actual)
+	}
+}
+
+func TestMakeTypedSecretErrors(t *testing.T) {
+	testCases := map[string]struct {
+		args     types.SecretArgs
+		expected string
+	}{
+		"mismatchedKey": {
+			args: types.SecretArgs{
+				TLS: &types.TLSArgs{
+					CertFile: "tls/tls.crt",
+					KeyFile:  "tls/other.key",
+				},
+			},
+			expected: "tls certFile tls/tls.crt and keyFile tls/other.key",
+		},
+		"missingKeyFile": {
+			args: types.SecretArgs{
+				TLS: &types.TLSArgs{CertFile: "tls/tls.crt"},
+			},
+			expected: "tls requires both certFile and keyFile",
+		},
+		"conflictingType": {
+			args: types.SecretArgs{
+				Type: "Opaque",
+				TLS: &types.TLSArgs{
+					CertFile: "tls/tls.crt",
+					KeyFile:  "tls/tls.key",
+				},
+			},
+			expected: "has type Opaque, but its fields require type kubernetes.io/tls",
+		},
+		"both": {
+			args: types.SecretArgs{
+				TLS:          &types.TLSArgs{},
+				DockerConfig: &types.DockerConfigArgs{},
+			},
+			expected: "cannot have both tls and dockerConfig",
+		},
+		"missingUsername": {
+			args: types.SecretArgs{
+				DockerConfig: &types.DockerConfigArgs{
+					Server:       "registry.example.com",
+					PasswordFile: "docker/password",
+				},
+			},
+			expected: "requires either configFile, or server, username and passwordFile",
+		},
+		"noAuths": {
+			args: types.SecretArgs{
+				DockerConfig: &types.DockerConfigArgs{
+					ConfigFile: "docker/empty.json",
+				},
+			},
+			expected: "configFile docker/empty.json has no auths",
+		},
+		"duplicateKey": {
+			args: types.SecretArgs{
+				GeneratorArgs: types.GeneratorArgs{
+					DataSources: types.DataSources{
+						LiteralSources: []string{"tls.key=x"},
+					},
+				},
+				TLS: &types.TLSArgs{
+					CertFile: "tls/tls.crt",
+					KeyFile:  "tls/tls.key",
+				},
+			},
+			expected: "cannot add key tls.key",
+		},
+	}
+	f := makeTypedSecretFactory(t)
+	for n, tc := range testCases {
+		tc.args.Name = "s"
+		_, err := f.MakeSecret(&tc.args)
+		if err == nil {
+			t.Fatalf("%s: expected error", n)
+		}
+		if !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf("%s: unexpected error %v", n, err)
+		}
+	}
+}
@@ -23,6 +23,16 @@ func NewKustHash() *kustHash {
 
 // Hash returns a hash of either a ConfigMap or a Secret
 func (h *kustHash) Hash(m ifc.Kunstructured) (string, error) {
+	return h.HashWith(m, "", 0, false)
+}
+
+// HashWith returns a hash of either a ConfigMap or a Secret,
+// computed with the named algorithm and encoded to the given
+// length.  The empty name and zero length mean the defaults.
+// If withNamespace is true, the namespace is hashed too.
+func (h *kustHash) HashWith(
+	m ifc.Kunstructured, algorithm string, length int,
+	withNamespace bool) (string, error) {
 	u := unstructured.Unstructured{
 		Object: m.Map(),
 	}
@@ -33,14 +43,20 @@ func (h *kustHash) Hash(m ifc.Kunstructured) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		return configMapHash(cm)
+		if !withNamespace {
+			cm.Namespace = ""
+		}
+		return configMapHash(cm, algorithm, length)
 	case "Secret":
 		sec, err := unstructuredToSecret(u)
 
 		if err != nil {
 			return "", err
 		}
-		return secretHash(sec)
+		if !withNamespace {
+			sec.Namespace = ""
+		}
+		return secretHash(sec, algorithm, length)
 	default:
 		return "", fmt.Errorf(
 			"type %s is not supported for hashing in %v",
@@ -49,13 +65,14 @@ func (h *kustHash) Hash(m ifc.Kunstructured) (string, error) {
 }
 
 // configMapHash returns a hash of the ConfigMap.
-// The Data, Kind, and Name are taken into account.
-func configMapHash(cm *v1.ConfigMap) (string, error) {
+// The Data, Kind, Name, and Namespace are taken into account.
+func configMapHash(
+	cm *v1.ConfigMap, algorithm string, length int) (string, error) {
 	encoded, err := encodeConfigMap(cm)
 	if err != nil {
 		return "", err
 	}
-	h, err := hasher.Encode(hasher.Hash(encoded))
+	h, err := hasher.HashAndEncode(algorithm, length, encoded)
 	if err != nil {
 		return "", err
 	}
@@ -63,13 +80,14 @@ func configMapHash(cm *v1.ConfigMap) (string, error) {
 }
 
 // SecretHash returns a hash of the Secret.
-// The Data, Kind, Name, and Type are taken into account.
-func secretHash(sec *v1.Secret) (string, error) {
+// The Data, Kind, Name, Namespace, and Type are taken into account.
+func secretHash(
+	sec *v1.Secret, algorithm string, length int) (string, error) {
 	encoded, err := encodeSecret(sec)
 	if err != nil {
 		return "", err
 	}
-	h, err := hasher.Encode(hasher.Hash(encoded))
+	h, err := hasher.HashAndEncode(algorithm, length, encoded)
 	if err != nil {
 		return "", err
 	}
@@ -77,13 +95,16 @@ func secretHash(sec *v1.Secret) (string, error) {
 }
 
 // encodeConfigMap encodes a ConfigMap.
-// Data, Kind, and Name are taken into account.
+// Data, Kind, Name, and Namespace, if set, are taken into account.
 func encodeConfigMap(cm *v1.ConfigMap) (string, error) {
 	// json.Marshal sorts the keys in a stable order in the encoding
 	m := map[string]interface{}{"kind": "ConfigMap", "name": cm.Name, "data": cm.Data}
 	if len(cm.BinaryData) > 0 {
 		m["binaryData"] = cm.BinaryData
 	}
+	if cm.Namespace != "" {
+		m["namespace"] = cm.Namespace
+	}
 	data, err := json.Marshal(m)
 	if err != nil {
 		return "", err
@@ -92,10 +113,14 @@ func encodeConfigMap(cm *v1.ConfigMap) (string, error) {
 }
 
 // encodeSecret encodes a Secret.
-// Data, Kind, Name, and Type are taken into account.
+// Data, Kind, Name, Namespace, if set, and Type are taken into account.
 func encodeSecret(sec *v1.Secret) (string, error) {
 	// json.Marshal sorts the keys in a stable order in the encoding
-	data, err := json.Marshal(map[string]interface{}{"kind": "Secret", "type": sec.Type, "name": sec.Name, "data": sec.Data})
+	m := map[string]interface{}{"kind": "Secret", "type": sec.Type, "name": sec.Name, "data": sec.Data}
+	if sec.Namespace != "" {
+		m["namespace"] = sec.Namespace
+	}
+	data, err := json.Marshal(m)
 	if err != nil {
 		return "", err
 	}
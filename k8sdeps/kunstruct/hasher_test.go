@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestConfigMapHash(t *testing.T) {
@@ -35,7 +36,7 @@ func TestConfigMapHash(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		h, err := configMapHash(c.cm)
+		h, err := configMapHash(c.cm, "", 0)
 		if SkipRest(t, c.desc, err, c.err) {
 			continue
 		}
@@ -61,7 +62,7 @@ func TestSecretHash(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		h, err := secretHash(c.secret)
+		h, err := secretHash(c.secret, "", 0)
 		if SkipRest(t, c.desc, err, c.err) {
 			continue
 		}
@@ -96,6 +97,9 @@ func TestEncodeConfigMap(t *testing.T) {
 		// two keys, one string and one binary values
 		{"two keys with one each", &v1.ConfigMap{Data: map[string]string{"one": ""}, BinaryData: map[string][]byte{"two": []byte("")}},
 			`{"binaryData":{"two":""},"data":{"one":""},"kind":"ConfigMap","name":""}`, ""},
+		// namespace
+		{"namespace", &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "prod"}, Data: map[string]string{"one": ""}},
+			`{"data":{"one":""},"kind":"ConfigMap","name":"","namespace":"prod"}`, ""},
 	}
 	for _, c := range cases {
 		s, err := encodeConfigMap(c.cm)
@@ -125,6 +129,9 @@ func TestEncodeSecret(t *testing.T) {
 			Data: map[string][]byte{"two": []byte("2"), "one": []byte(""), "three": []byte("3")},
 		},
 			`{"data":{"one":"","three":"Mw==","two":"Mg=="},"kind":"Secret","name":"","type":"my-type"}`, ""},
+		// namespace
+		{"namespace", &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "prod"}, Type: "my-type", Data: map[string][]byte{}},
+			`{"data":{},"kind":"Secret","name":"","namespace":"prod","type":"my-type"}`, ""},
 	}
 	for _, c := range cases {
 		s, err := encodeSecret(c.secret)
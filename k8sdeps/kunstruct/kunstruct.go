@@ -296,12 +296,34 @@ func (fs *UnstructAdapter) MatchesAnnotationSelector(selector string) (bool, err
 }
 
 func (fs *UnstructAdapter) Patch(patch ifc.Kunstructured) error {
+	return fs.PatchWithMergeKeys(patch, nil)
+}
+
+// PatchWithMergeKeys is like Patch, except that if the type
+// has no schema, the lists at the given field paths, e.g.
+// "spec/workers", are merged by the given keys rather
+// than replaced.
+func (fs *UnstructAdapter) PatchWithMergeKeys(
+	patch ifc.Kunstructured, mergeKeys map[string]string) error {
 	versionedObj, err := scheme.Scheme.New(
 		toSchemaGvk(patch.GetGvk()))
 	merged := map[string]interface{}{}
 	saveName := fs.GetName()
 	switch {
+	case runtime.IsNotRegisteredError(err) && len(mergeKeys) > 0:
+		merged, err = strategicpatch.StrategicMergeMapPatchUsingLookupPatchMeta(
+			fs.Map(),
+			patch.Map(),
+			newPatchMetaFromMergeKeys(mergeKeys, fs.Map(), patch.Map()))
+		if err != nil {
+			return err
+		}
 	case runtime.IsNotRegisteredError(err):
+		// JSON merge patches have no delete directive,
+		// so honor the strategic merge one here.
+		if patch.Map()["$patch"] == "delete" {
+			break
+		}
 		baseBytes, err := json.Marshal(fs.Map())
 		if err != nil {
 			return err
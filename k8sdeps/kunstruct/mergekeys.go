@@ -0,0 +1,79 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kunstruct
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+const (
+	patchStrategyExtension = "x-kubernetes-patch-strategy"
+	patchMergeKeyExtension = "x-kubernetes-patch-merge-key"
+)
+
+// newPatchMetaFromMergeKeys returns the patch metadata a strategic
+// merge of the given documents needs when the documents' type has
+// no schema.  The metadata is read from a schema made up to fit the
+// documents, in which the lists at the paths in mergeKeys carry the
+// merge strategy and the corresponding merge keys, and all other
+// lists and maps are replaced and merged as in a JSON merge patch.
+func newPatchMetaFromMergeKeys(
+	mergeKeys map[string]string,
+	docs ...interface{}) strategicpatch.LookupPatchMeta {
+	return strategicpatch.NewPatchMetaFromOpenAPI(
+		schemaFor(mergeKeys, []string{}, proto.NewPath("root"), docs))
+}
+
+// schemaFor returns a schema fitting all the given values,
+// which are found at the same field path in the documents.
+func schemaFor(
+	mergeKeys map[string]string, path []string,
+	protoPath proto.Path, values []interface{}) proto.Schema {
+	base := proto.BaseSchema{Path: protoPath}
+	var maps []map[string]interface{}
+	var elements []interface{}
+	isList := false
+	for _, v := range values {
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			maps = append(maps, typed)
+		case []interface{}:
+			isList = true
+			elements = append(elements, typed...)
+		}
+	}
+	switch {
+	case len(maps) > 0:
+		kind := &proto.Kind{BaseSchema: base, Fields: map[string]proto.Schema{}}
+		fieldValues := map[string][]interface{}{}
+		for _, m := range maps {
+			for k, v := range m {
+				fieldValues[k] = append(fieldValues[k], v)
+			}
+		}
+		for k, vs := range fieldValues {
+			kind.Fields[k] = schemaFor(
+				mergeKeys, append(path[:len(path):len(path)], k),
+				protoPath.FieldPath(k), vs)
+		}
+		return kind
+	case isList:
+		if key, ok := mergeKeys[strings.Join(path, "/")]; ok {
+			base.Extensions = map[string]interface{}{
+				patchStrategyExtension: "merge",
+				patchMergeKeyExtension: key,
+			}
+		}
+		return &proto.Array{
+			BaseSchema: base,
+			SubType: schemaFor(
+				mergeKeys, path, protoPath.ArrayPath(0), elements),
+		}
+	default:
+		return &proto.Primitive{BaseSchema: base, Type: proto.String}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package kunstruct
+
+import (
+	"reflect"
+	"testing"
+)
+
+func makeWorkers(workers ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "MyKind",
+		"metadata": map[string]interface{}{
+			"name": "mine",
+		},
+		"spec": map[string]interface{}{
+			"workers": workers,
+		},
+	}
+}
+
+func worker(name, size string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "size": size}
+}
+
+func TestPatchWithMergeKeys(t *testing.T) {
+	factory := NewKunstructuredFactoryImpl()
+	testCases := map[string]struct {
+		mergeKeys map[string]string
+		expected  map[string]interface{}
+	}{
+		"mergeKey": {
+			mergeKeys: map[string]string{"spec/workers": "name"},
+			expected: makeWorkers(
+				worker("a", "small"), worker("b", "large")),
+		},
+		"otherPath": {
+			mergeKeys: map[string]string{"spec/others": "name"},
+			expected:  makeWorkers(worker("b", "large")),
+		},
+		"noMergeKeys": {
+			expected: makeWorkers(worker("b", "large")),
+		},
+	}
+	for n, tc := range testCases {
+		obj := factory.FromMap(makeWorkers(
+			worker("a", "small"), worker("b", "small")))
+		patch := factory.FromMap(makeWorkers(worker("b", "large")))
+		err := obj.PatchWithMergeKeys(patch, tc.mergeKeys)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if !reflect.DeepEqual(obj.Map(), tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", n, tc.expected, obj.Map())
+		}
+	}
+}
@@ -23,3 +23,16 @@ func (p *FactoryImpl) MergePatches(patches []*resource.Resource,
 	resmap.ResMap, error) {
 	return patch.MergePatches(patches, rf)
 }
+
+func (p *FactoryImpl) MergeVariants(dst, src *resource.Resource) error {
+	return patch.MergeVariants(dst, src)
+}
+
+func (p *FactoryImpl) HasConflict(a, b *resource.Resource) (bool, error) {
+	return patch.VariantsConflict(a, b)
+}
+
+func (p *FactoryImpl) Rebase(
+	oldBase, newBase, overlay *resource.Resource) (*resource.Resource, error) {
+	return patch.RebaseVariant(oldBase, newBase, overlay)
+}
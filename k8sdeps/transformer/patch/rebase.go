@@ -0,0 +1,99 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// RebaseVariant returns a patch that, applied to newBase,
+// makes the customizations that overlay made to oldBase.
+// This is a three-way merge: it fails if the changes from
+// oldBase to newBase conflict with the customizations.
+// The patch is nil if newBase needs no customization,
+// e.g. because it adopted all of them.
+func RebaseVariant(
+	oldBase, newBase, overlay *resource.Resource) (*resource.Resource, error) {
+	original := oldBase.Copy().Map()
+	current := newBase.Copy().Map()
+	modified := overlay.Copy().Map()
+	versionedObj, err := scheme.Scheme.New(toSchemaGvk(newBase.GetGvk()))
+	var p map[string]interface{}
+	switch {
+	case runtime.IsNotRegisteredError(err):
+		p, err = rebaseWithJMP(original, current, modified)
+	case err != nil:
+		return nil, err
+	default:
+		p, err = rebaseWithSMP(versionedObj, original, current, modified)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to rebase %s: %v", newBase.CurId(), err)
+	}
+	if len(p) == 0 {
+		return nil, nil
+	}
+	p["apiVersion"] = current["apiVersion"]
+	p["kind"] = current["kind"]
+	meta, _ := p["metadata"].(map[string]interface{})
+	if meta == nil {
+		meta = map[string]interface{}{}
+		p["metadata"] = meta
+	}
+	meta["name"] = newBase.GetName()
+	if ns := newBase.GetNamespace(); ns != "" {
+		meta["namespace"] = ns
+	}
+	result := newBase.DeepCopy()
+	result.SetMap(p)
+	return result, nil
+}
+
+func rebaseWithSMP(
+	versionedObj runtime.Object,
+	original, current, modified map[string]interface{}) (map[string]interface{}, error) {
+	rebased, err := mergeVariantsWithSMP(
+		versionedObj, original, runtime.DeepCopyJSON(current), modified)
+	if err != nil {
+		return nil, err
+	}
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(versionedObj)
+	if err != nil {
+		return nil, err
+	}
+	return strategicpatch.CreateTwoWayMergeMapPatchUsingLookupPatchMeta(
+		current, rebased, lookupPatchMeta)
+}
+
+func rebaseWithJMP(
+	original, current, modified map[string]interface{}) (map[string]interface{}, error) {
+	rebased, err := mergeVariantsWithJMP(original, runtime.DeepCopyJSON(current), modified)
+	if err != nil {
+		return nil, err
+	}
+	currentBytes, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	rebasedBytes, err := json.Marshal(rebased)
+	if err != nil {
+		return nil, err
+	}
+	patchBytes, err := jsonpatch.CreateMergePatch(currentBytes, rebasedBytes)
+	if err != nil {
+		return nil, err
+	}
+	var p map[string]interface{}
+	if err = json.Unmarshal(patchBytes, &p); err != nil {
+		return nil, fmt.Errorf("unable to read patch: %v", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/mergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// MergeVariants merges into dst the customizations that src
+// made to the pristine resource that dst and src share.
+// It errors out if the customizations made to dst and
+// the customizations made to src conflict.
+func MergeVariants(dst, src *resource.Resource) error {
+	merged, err := mergedVariants(dst, src)
+	if err != nil {
+		return err
+	}
+	dst.SetMap(merged)
+	return nil
+}
+
+// VariantsConflict returns true if the customizations
+// a and b made to the pristine resource they share
+// conflict, i.e. if MergeVariants(a, b) would fail
+// for that reason.
+func VariantsConflict(a, b *resource.Resource) (bool, error) {
+	_, err := mergedVariants(a, b)
+	if err == nil {
+		return false, nil
+	}
+	if e, ok := err.(*mergeError); ok {
+		if _, ok := e.err.(*variantConflict); ok {
+			return true, nil
+		}
+	}
+	return false, err
+}
+
+// variantConflict reports the conflicting customizations.
+type variantConflict struct {
+	dstDelta, srcDelta map[string]interface{}
+}
+
+func (c *variantConflict) Error() string {
+	return fmt.Sprintf("conflict between %#v and %#v", c.dstDelta, c.srcDelta)
+}
+
+type mergeError struct {
+	id  fmt.Stringer
+	err error
+}
+
+func (e *mergeError) Error() string {
+	return fmt.Sprintf("unable to merge variants of %s: %v", e.id, e.err)
+}
+
+// mergedVariants returns the content MergeVariants
+// would give dst, leaving dst untouched.
+func mergedVariants(dst, src *resource.Resource) (map[string]interface{}, error) {
+	if !dst.SharesPristine(src) {
+		return nil, fmt.Errorf(
+			"%s and %s are not variants of the same resource",
+			dst.CurId(), src.CurId())
+	}
+	// Merge copies, as patching may modify its input.
+	original := src.Pristine().Copy().Map()
+	dstMap := dst.Copy().Map()
+	srcMap := src.Copy().Map()
+	versionedObj, err := scheme.Scheme.New(toSchemaGvk(dst.GetGvk()))
+	var merged map[string]interface{}
+	switch {
+	case runtime.IsNotRegisteredError(err):
+		merged, err = mergeVariantsWithJMP(original, dstMap, srcMap)
+	case err != nil:
+		return nil, err
+	default:
+		merged, err = mergeVariantsWithSMP(
+			versionedObj, original, dstMap, srcMap)
+	}
+	if err != nil {
+		return nil, &mergeError{id: dst.CurId(), err: err}
+	}
+	return merged, nil
+}
+
+func mergeVariantsWithSMP(
+	versionedObj runtime.Object,
+	original, dst, src map[string]interface{}) (map[string]interface{}, error) {
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(versionedObj)
+	if err != nil {
+		return nil, err
+	}
+	dstDelta, err := strategicpatch.CreateTwoWayMergeMapPatchUsingLookupPatchMeta(
+		original, dst, lookupPatchMeta)
+	if err != nil {
+		return nil, err
+	}
+	srcDelta, err := strategicpatch.CreateTwoWayMergeMapPatchUsingLookupPatchMeta(
+		original, src, lookupPatchMeta)
+	if err != nil {
+		return nil, err
+	}
+	conflict, err := strategicpatch.MergingMapsHaveConflicts(
+		dstDelta, srcDelta, lookupPatchMeta)
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		return nil, &variantConflict{dstDelta: dstDelta, srcDelta: srcDelta}
+	}
+	return strategicpatch.StrategicMergeMapPatchUsingLookupPatchMeta(
+		dst, srcDelta, lookupPatchMeta)
+}
+
+func mergeVariantsWithJMP(
+	original, dst, src map[string]interface{}) (map[string]interface{}, error) {
+	originalBytes, err := json.Marshal(original)
+	if err != nil {
+		return nil, err
+	}
+	dstBytes, err := json.Marshal(dst)
+	if err != nil {
+		return nil, err
+	}
+	srcBytes, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	dstDelta, err := jsonpatch.CreateMergePatch(originalBytes, dstBytes)
+	if err != nil {
+		return nil, err
+	}
+	srcDelta, err := jsonpatch.CreateMergePatch(originalBytes, srcBytes)
+	if err != nil {
+		return nil, err
+	}
+	var dstDeltaMap, srcDeltaMap map[string]interface{}
+	if err = json.Unmarshal(dstDelta, &dstDeltaMap); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(srcDelta, &srcDeltaMap); err != nil {
+		return nil, err
+	}
+	conflict, err := mergepatch.HasConflicts(dstDeltaMap, srcDeltaMap)
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		return nil, &variantConflict{dstDelta: dstDeltaMap, srcDelta: srcDeltaMap}
+	}
+	mergedBytes, err := jsonpatch.MergePatch(dstBytes, srcDelta)
+	if err != nil {
+		return nil, err
+	}
+	merged := map[string]interface{}{}
+	err = json.Unmarshal(mergedBytes, &merged)
+	return merged, err
+}
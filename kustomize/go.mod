@@ -6,6 +6,7 @@ require (
 	github.com/pkg/errors v0.8.1
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v3 v3.0.1
 	sigs.k8s.io/kustomize/v3 v3.3.0
 	sigs.k8s.io/yaml v1.1.0
 )
@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"errors"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kubectl"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+type applyOptions struct {
+	kustomizationPath string
+	fieldManager      string
+	forceConflicts    bool
+	prune             bool
+	selector          string
+	dryRun            bool
+	kubectl           kubectl.Options
+	build             kubectl.Builder
+}
+
+var examples = `
+To apply the resources that 'someDir/kustomization.yaml'
+specifies to the cluster of the current context, run
+
+  kustomize apply someDir
+
+The resources are applied server-side, their fields
+owned by the field manager 'kustomize', or that of
+--field-manager.  Fields another manager owns are
+left alone, the apply failing, unless --force-conflicts
+is set.
+
+With --prune, the resources that the selector of
+--selector selects, but that the build no longer holds,
+are deleted, e.g.
+
+  kustomize apply someDir --prune --selector app=web
+
+The resources are built as kustomize build builds
+them, taking the same flags shaping the build, e.g.
+--load-restrictor, --enable_helm or --set.
+
+The resources are applied by kubectl, which must be
+on the PATH or given with --kubectl.
+`
+
+// NewCmdApply returns an instance of 'apply' subcommand.
+func NewCmdApply(
+	out io.Writer, fSys fs.FileSystem, v ifc.Validator,
+	rf *resmap.Factory, ptf resmap.PatchFactory) *cobra.Command {
+	var o applyOptions
+
+	cmd := &cobra.Command{
+		Use:          "apply [path]",
+		Short:        "Apply the built resources to the cluster server-side",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunApply(out, cmd.ErrOrStderr(), fSys, v, rf, ptf)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.fieldManager, "field-manager", kubectl.DefaultFieldManager,
+		"The manager of the fields the apply sets.")
+	cmd.Flags().BoolVar(
+		&o.forceConflicts, "force-conflicts", false,
+		"If set, take over the fields other managers own.")
+	cmd.Flags().BoolVar(
+		&o.prune, "prune", false,
+		"If set, delete the resources --selector selects "+
+			"that the build no longer holds.")
+	cmd.Flags().StringVarP(
+		&o.selector, "selector", "l", "",
+		"The label selector of the resources to prune.")
+	cmd.Flags().BoolVar(
+		&o.dryRun, "dry-run", false,
+		"If set, only show what the apply would do, "+
+			"in a server-side dry run.")
+	o.kubectl.AddFlags(cmd.Flags())
+	o.build.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Validate validates apply command.
+func (o *applyOptions) Validate(args []string) error {
+	if len(args) > 1 {
+		return errors.New("specify one path to a kustomization")
+	}
+	o.kustomizationPath = loader.CWD
+	if len(args) == 1 {
+		o.kustomizationPath = args[0]
+	}
+	if o.fieldManager == "" {
+		return errors.New("--field-manager must not be empty")
+	}
+	if o.prune && o.selector == "" {
+		return errors.New(
+			"--prune requires --selector, lest it delete " +
+				"every resource the build doesn't hold")
+	}
+	if !o.prune && o.selector != "" {
+		return errors.New("--selector applies only with --prune")
+	}
+	return o.build.Validate(o.kustomizationPath)
+}
+
+// RunApply builds the kustomization and
+// applies its resources to the cluster.
+func (o *applyOptions) RunApply(
+	out, errOut io.Writer, fSys fs.FileSystem, v ifc.Validator,
+	rf *resmap.Factory, ptf resmap.PatchFactory) error {
+	resources, err := o.build.Build(errOut, v, fSys, rf, ptf)
+	if err != nil {
+		return err
+	}
+	_, err = o.kubectl.Run(out, resources, o.kubectlArgs()...)
+	return err
+}
+
+func (o *applyOptions) kubectlArgs() []string {
+	args := []string{
+		"apply", "-f", "-",
+		"--server-side", "--field-manager", o.fieldManager,
+	}
+	if o.forceConflicts {
+		args = append(args, "--force-conflicts")
+	}
+	if o.prune {
+		args = append(args, "--prune", "--selector", o.selector)
+	}
+	if o.dryRun {
+		args = append(args, "--dry-run=server")
+	}
+	return args
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package apply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestRunApply(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+commonLabels:
+  app: web
+resources:
+- cm.yaml
+`))
+	fSys.WriteFile("/app/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	run := func(o applyOptions, exitCode string) (string, error) {
+		bin, cleanup := testutils.WriteFakeKubectl(t, exitCode)
+		defer cleanup()
+		o.kubectl.Kubectl = bin
+		if err := o.Validate([]string{"/app"}); err != nil {
+			return "", err
+		}
+		var out, errOut bytes.Buffer
+		err := o.RunApply(&out, &errOut, fSys,
+			validators.MakeFakeValidator(), rf, transformer.NewFactoryImpl())
+		return out.String(), err
+	}
+
+	actual, err := run(applyOptions{
+		fieldManager: "ci", forceConflicts: true,
+		prune: true, selector: "app=web", dryRun: true}, "0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `args: apply -f - --server-side --field-manager ci --force-conflicts --prune --selector app=web --dry-run=server
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    app: web
+  name: cm
+`
+	if actual != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	_, err = run(applyOptions{fieldManager: "kustomize"}, "1")
+	if err == nil || !strings.Contains(err.Error(), "running ") {
+		t.Fatalf("expected kubectl error, got %v", err)
+	}
+	_, err = run(applyOptions{fieldManager: "kustomize", prune: true}, "0")
+	if err == nil || !strings.HasPrefix(err.Error(), "--prune requires --selector") {
+		t.Fatalf("expected prune error, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+const (
+	flagAllowIdConflictsName = "allow-id-conflicts"
+)
+
+var (
+	flagAllowIdConflictsValue = ""
+	flagAllowIdConflictsHelp  = "Use '" + string(types.IdConflictsLastWins) +
+		"' to resolve resource id conflicts in favor of the later resource, " +
+		"with a warning, instead of failing."
+)
+
+func addFlagAllowIdConflicts(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagAllowIdConflictsValue, flagAllowIdConflictsName,
+		"", flagAllowIdConflictsHelp)
+}
+
+func validateFlagAllowIdConflicts() (target.ConflictResolver, error) {
+	switch types.IdConflictPolicy(flagAllowIdConflictsValue) {
+	case types.IdConflictsDisallowed:
+		return nil, nil
+	case types.IdConflictsLastWins:
+		return target.LastWinsResolver{}, nil
+	default:
+		return nil, fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagAllowIdConflictsName, flagAllowIdConflictsValue,
+			[]string{string(types.IdConflictsLastWins)})
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagAllowVarEnvName = "allow-var-env"
+	flagAllowVarEnvHelp = "Comma separated names of the environment " +
+		"variables that vars with an env source may read."
+)
+
+var flagAllowVarEnvValue []string
+
+func addFlagAllowVarEnv(set *pflag.FlagSet) {
+	set.StringSliceVar(
+		&flagAllowVarEnvValue, flagAllowVarEnvName,
+		nil, flagAllowVarEnvHelp)
+}
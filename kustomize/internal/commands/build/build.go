@@ -4,13 +4,19 @@
 package build
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/loader"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
@@ -18,16 +24,46 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/target"
-	"sigs.k8s.io/kustomize/v3/plugin/builtin"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/yamlcomments"
 	"sigs.k8s.io/yaml"
 )
 
 // Options contain the options for running a build
 type Options struct {
 	kustomizationPath string
+	baseDir           string
+	stdin             io.Reader
+	fromStdin         bool
 	outputPath        string
+	outputFormat      string
+	listKind          listKind
+	style             yamlStyle
+	selectors         []types.Selector
+	splitNamespaces   string
+	pruneEmpty        bool
+	warnUnusedFiles   bool
+	watch             bool
+	watchInterval     time.Duration
+	fileTemplate      string
 	loadRestrictor    loader.LoadRestrictorFunc
+	symlinkPolicy     loader.SymlinkPolicy
 	outOrder          reorderOutput
+	resolver          target.ConflictResolver
+	execSecrets       bool
+	sopsCommand       string
+	helm              bool
+	helmCommand       string
+	allowedEnv        []string
+	settings          map[string]string
+	resultsFormat     string
+	fetchOptions      types.FetchOptions
+	fetchLimits       loader.FetchLimits
+	inputLimits       loader.InputLimits
+	maxResources      int
+	offline           bool
+	noCache           bool
+	updateLock        bool
 }
 
 // NewOptions creates a Options object
@@ -35,6 +71,8 @@ func NewOptions(p, o string) *Options {
 	return &Options{
 		kustomizationPath: p,
 		outputPath:        o,
+		outputFormat:      outputYaml,
+		listKind:          listKind{apiVersion: "v1", kind: "List"},
 		loadRestrictor:    loader.RestrictionRootOnly,
 	}
 }
@@ -53,7 +91,58 @@ with a kustomization.yaml file, e.g.
     github.com/kubernetes-sigs/kustomize//examples/multibases/dev/?ref=v1.0.6
 
 The URL should be formulated as described at
-https://github.com/hashicorp/go-getter#url-format
+https://github.com/hashicorp/go-getter#url-format,
+with a double slash separating the repo from the
+directory, and the ref and depth of the clone in
+the query, e.g.
+
+  kustomize build \
+    https://github.com/org/repo//deploy/overlays/prod?ref=v1.2.3
+
+The argument '-' builds the kustomization read from
+stdin, whose paths are relative to --base-dir, by
+default the current working directory, e.g.
+
+  generate-kustomization | kustomize build - --base-dir someDir
+
+The resources are printed as YAML documents, or, with
+--output-format, as a JSON array, a JSON object per line,
+or the items of a list object, e.g.
+
+  kustomize build someDir --output-format ndjson | jq .metadata.name
+  kustomize build someDir --output-format list --list-kind v1/List
+
+With --file-template, each resource is written to a file of its
+own, below the --output directory, that the template names, e.g.
+
+  kustomize build someDir -o out --file-template '{namespace}/{kind}_{name}.yaml'
+
+With --select, only the resources a selector selects are
+printed, e.g.
+
+  kustomize build someDir --select kind=Service,labelSelector=app=web
+
+With --split-namespaces, the resources of each namespace are
+written to a file, or a directory, of its own, below the
+--output directory, e.g.
+
+  kustomize build someDir -o out --split-namespaces dirs
+
+With --prune-empty, null fields, and empty maps and lists,
+like those patches leave, are dropped from the output, e.g.
+
+  kustomize build someDir --prune-empty
+
+With --watch, the build runs again whenever a file it
+read changes, e.g. in a base, printing or writing its
+output anew, until interrupted, e.g.
+
+  kustomize build someDir --watch -o out/
+
+YAML output may be formatted to match a repo's conventions with
+--indent, --flow-lists and --quote-style, e.g.
+
+  kustomize build someDir --indent 4 --flow-lists 3 --quote-style single
 `
 
 // NewCmdBuild creates a new build command.
@@ -77,22 +166,88 @@ func NewCmdBuild(
 			if err != nil {
 				return err
 			}
-			return o.RunBuild(out, v, fSys, rf, ptf, pl)
+			o.stdin = cmd.InOrStdin()
+			if o.watch {
+				return o.RunWatch(
+					out, cmd.ErrOrStderr(), v, fSys, rf, ptf, pl,
+					interrupted())
+			}
+			return o.RunBuild(out, cmd.ErrOrStderr(), v, fSys, rf, ptf, pl)
 		},
 	}
 
 	cmd.Flags().StringVarP(
 		&o.outputPath,
 		"output", "o", "",
-		"If specified, write the build output to this path.")
-	loader.AddFlagLoadRestrictor(cmd.Flags())
-	plugins.AddFlagEnablePlugins(
-		cmd.Flags(), &pluginConfig.Enabled)
-	addFlagReorderOutput(cmd.Flags())
+		"If specified, write the build output to this path. "+
+			"If the path is a directory, or ends with a slash, "+
+			"each resource is written to a file of its own in it.")
+	cmd.Flags().StringVar(
+		&o.baseDir,
+		"base-dir", "",
+		"The directory the paths of a kustomization read from "+
+			"stdin, with the path '-', are relative to.")
+	o.addFlagsBuilding(cmd.Flags(), pluginConfig)
+	addFlagResultsFormat(cmd.Flags())
+	addFlagOutputFormat(cmd.Flags())
+	addFlagFileTemplate(cmd.Flags())
+	addFlagsYamlStyle(cmd.Flags())
+	addFlagSelect(cmd.Flags())
+	addFlagSplitNamespaces(cmd.Flags())
+	addFlagPruneEmpty(cmd.Flags(), &o.pruneEmpty)
+	cmd.Flags().BoolVar(
+		&o.warnUnusedFiles, "warn-unused-files", false,
+		"If set, log a warning for each file in the directory of "+
+			"a kustomization, or below it, that the kustomization "+
+			"doesn't use.")
+	addFlagsWatch(cmd.Flags(), &o.watch, &o.watchInterval)
 	cmd.AddCommand(NewCmdBuildPrune(out, v, fSys, rf, ptf, pl))
 	return cmd
 }
 
+// AddFlags adds the flags shaping how a kustomization is
+// built, but not how the build's output is written, to the
+// set, returning the options and the plugin config they set.
+// Commands acting on the resources of a build, like diff
+// and apply, thus build them as kustomize build would.
+func AddFlags(set *pflag.FlagSet) (*Options, *types.PluginConfig) {
+	o := &Options{}
+	pc := plugins.DefaultPluginConfig()
+	o.addFlagsBuilding(set, pc)
+	return o, pc
+}
+
+func (o *Options) addFlagsBuilding(
+	set *pflag.FlagSet, pc *types.PluginConfig) {
+	loader.AddFlagLoadRestrictor(set)
+	loader.AddFlagSymlinkPolicy(set)
+	loader.AddFlagEnableExecSecrets(set, &o.execSecrets)
+	loader.AddFlagSopsCommand(set, &o.sopsCommand)
+	loader.AddFlagsHelm(set, &o.helm, &o.helmCommand)
+	loader.AddFlagsFetchOptions(set, &o.fetchOptions)
+	loader.AddFlagsFetchLimits(set, &o.fetchLimits)
+	loader.AddFlagsInputLimits(set, &o.inputLimits)
+	set.IntVar(
+		&o.maxResources, "max-resources", 0,
+		"how many resources the build may accumulate, "+
+			"counting those of each base; 0 means any number.")
+	loader.AddFlagOffline(set, &o.offline)
+	set.BoolVar(
+		&o.noCache, "no-cache", false,
+		"If set, clone remote bases afresh, neither using "+
+			"nor filling the cache 'kustomize cache' manages.")
+	loader.AddFlagUpdateLock(set, &o.updateLock)
+	plugins.AddFlagEnablePlugins(set, &pc.Enabled)
+	plugins.AddFlagEnableExternalSecrets(set, &pc.ExternalSecretsEnabled)
+	plugins.AddFlagsContainerPlugins(set, pc)
+	plugins.AddFlagsPluginTrust(set, pc)
+	plugins.AddFlagsSandboxPlugins(set, pc)
+	addFlagReorderOutput(set)
+	addFlagAllowIdConflicts(set)
+	addFlagAllowVarEnv(set)
+	addFlagSet(set)
+}
+
 // Validate validates build command.
 func (o *Options) Validate(args []string) (err error) {
 	if len(args) > 1 {
@@ -105,73 +260,300 @@ func (o *Options) Validate(args []string) (err error) {
 	} else {
 		o.kustomizationPath = args[0]
 	}
+	if o.kustomizationPath == stdinPath {
+		o.fromStdin = true
+		o.kustomizationPath = loader.CWD
+		if o.baseDir != "" {
+			o.kustomizationPath = o.baseDir
+		}
+	} else if o.baseDir != "" {
+		return fmt.Errorf(
+			"--base-dir applies only to a kustomization read from stdin, "+
+				"with the path '%s'", stdinPath)
+	}
 	o.loadRestrictor, err = loader.ValidateFlagLoadRestrictor()
 	if err != nil {
 		return err
 	}
+	o.symlinkPolicy, err = loader.ValidateFlagSymlinkPolicy()
+	if err != nil {
+		return err
+	}
 	o.outOrder, err = validateFlagReorderOutput()
+	if err != nil {
+		return err
+	}
+	o.allowedEnv = flagAllowVarEnvValue
+	o.settings, err = validateFlagSet()
+	if err != nil {
+		return err
+	}
+	o.resolver, err = validateFlagAllowIdConflicts()
+	if err != nil {
+		return err
+	}
+	o.resultsFormat, err = validateFlagResultsFormat()
+	if err != nil {
+		return err
+	}
+	o.outputFormat, err = validateFlagOutputFormat()
+	if err != nil {
+		return err
+	}
+	o.listKind, err = validateFlagListKind()
+	if err != nil {
+		return err
+	}
+	o.selectors, err = validateFlagSelect()
+	if err != nil {
+		return err
+	}
+	o.style, err = validateFlagsYamlStyle()
+	if err != nil {
+		return err
+	}
+	o.fileTemplate, err = validateFlagFileTemplate()
+	if err != nil {
+		return err
+	}
+	o.splitNamespaces, err = validateFlagSplitNamespaces()
+	if err != nil {
+		return err
+	}
+	if o.fileTemplate != "" && o.outputPath == "" {
+		return fmt.Errorf(
+			"--%s requires --output naming a directory", flagFileTemplateName)
+	}
+	if o.splitNamespaces != "" && o.outputPath == "" {
+		return fmt.Errorf(
+			"--%s requires --output naming a directory", flagSplitNamespacesName)
+	}
+	if o.watch && o.fromStdin {
+		return fmt.Errorf(
+			"--%s can't watch a kustomization read from stdin", flagWatchName)
+	}
+	if o.watch && loader.IsRemote(o.kustomizationPath) {
+		return fmt.Errorf(
+			"--%s can't watch remote %s", flagWatchName, o.kustomizationPath)
+	}
+	if o.splitNamespaces != "" && o.fileTemplate != "" {
+		return fmt.Errorf(
+			"--%s and --%s are mutually exclusive",
+			flagSplitNamespacesName, flagFileTemplateName)
+	}
+	if o.fetchOptions.CABundle != "" {
+		o.fetchOptions.CABundle, err = filepath.Abs(o.fetchOptions.CABundle)
+	}
 	return
 }
 
-// RunBuild runs build command.
+// newLoader returns the loader of the kustomization
+// to build, configured as the flags say.
+func (o *Options) newLoader(
+	v ifc.Validator, fSys fs.FileSystem) (ifc.Loader, error) {
+	if o.offline && loader.IsRemote(o.kustomizationPath) {
+		return nil, fmt.Errorf(
+			"refusing to fetch %s; --offline is set", o.kustomizationPath)
+	}
+	if o.updateLock && loader.IsRemote(o.kustomizationPath) {
+		return nil, fmt.Errorf(
+			"can't write %s of remote %s", loader.LockFileName, o.kustomizationPath)
+	}
+	cloner := loader.CachingCloner()
+	if o.noCache {
+		cloner = git.ClonerUsingGitExec
+	}
+	ldr, err := loader.NewLoaderUsingCloner(
+		o.loadRestrictor, v, o.kustomizationPath, fSys, cloner)
+	if err != nil {
+		return nil, err
+	}
+	loader.SetSymlinkPolicy(ldr, o.symlinkPolicy)
+	if o.execSecrets {
+		loader.EnableExecSecrets(ldr)
+	}
+	loader.SetSopsCommand(ldr, o.sopsCommand)
+	if o.helm {
+		loader.EnableHelm(ldr, o.helmCommand)
+	}
+	if err := loader.SetFetchOptions(ldr, o.fetchOptions); err != nil {
+		ldr.Cleanup()
+		return nil, err
+	}
+	loader.SetFetchLimits(ldr, o.fetchLimits)
+	loader.SetInputLimits(ldr, o.inputLimits)
+	if o.offline {
+		loader.SetOffline(ldr)
+	}
+	if o.updateLock {
+		loader.SetLock(ldr, nil)
+		return ldr, nil
+	}
+	lock, err := loader.ReadLock(fSys, ldr.Root())
+	if err != nil {
+		ldr.Cleanup()
+		return nil, err
+	}
+	if lock != nil {
+		loader.SetLock(ldr, lock)
+	}
+	return ldr, nil
+}
+
+// writeLock writes the lock file of the
+// kustomization built, if asked to.
+func (o *Options) writeLock(ldr ifc.Loader, fSys fs.FileSystem) error {
+	if !o.updateLock {
+		return nil
+	}
+	return loader.WriteLock(fSys, ldr.Root(), loader.Locked(ldr))
+}
+
+// offlineErr returns the error of a build that failed,
+// listing the remote references it refused, if any, as
+// the error of a file missing, say, may hide them.
+func offlineErr(ldr ifc.Loader, err error) error {
+	refs := loader.OfflineRefusals(ldr)
+	if len(refs) == 0 {
+		return err
+	}
+	return fmt.Errorf(
+		"%v\nremote references refused in offline mode:\n  %s",
+		err, strings.Join(refs, "\n  "))
+}
+
+// stdinPath is the path of the kustomization read from stdin.
+const stdinPath = "-"
+
+// newKustTarget returns the target of the kustomization
+// to build, read from stdin if its path is '-'.
+func (o *Options) newKustTarget(
+	ldr ifc.Loader, rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader) (*target.KustTarget, error) {
+	if !o.fromStdin {
+		return target.NewKustTarget(ldr, rf, ptf, pl)
+	}
+	if o.stdin == nil {
+		return nil, errors.New("no stdin to read the kustomization from")
+	}
+	content, err := ioutil.ReadAll(o.stdin)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading the kustomization from stdin")
+	}
+	return target.NewKustTargetFromContent(ldr, content, rf, ptf, pl)
+}
+
+// RunBuild runs build command, printing the results
+// plugins report, even if the build fails, to errOut.
 func (o *Options) RunBuild(
-	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
+	out, errOut io.Writer, v ifc.Validator, fSys fs.FileSystem,
 	rf *resmap.Factory, ptf resmap.PatchFactory,
 	pl *plugins.Loader) error {
-	ldr, err := loader.NewLoader(
-		o.loadRestrictor, v, o.kustomizationPath, fSys)
+	ldr, err := o.newLoader(v, fSys)
 	if err != nil {
 		return err
 	}
 	defer ldr.Cleanup()
-	kt, err := target.NewKustTarget(ldr, rf, ptf, pl)
+	kt, err := o.newKustTarget(ldr, rf, ptf, pl)
 	if err != nil {
 		return err
 	}
+	if o.resolver != nil {
+		kt.SetConflictResolver(o.resolver)
+	}
+	kt.AllowEnvVars(o.allowedEnv)
+	kt.SetValues(o.settings)
+	kt.SetMaxResources(o.maxResources)
+	kt.WarnUnusedFiles(o.warnUnusedFiles)
 	m, err := kt.MakeCustomizedResMap()
+	if rErr := emitResults(errOut, o.resultsFormat, kt.Results()); rErr != nil {
+		return rErr
+	}
 	if err != nil {
+		return offlineErr(ldr, err)
+	}
+	if err = o.writeLock(ldr, fSys); err != nil {
 		return err
 	}
-	return o.emitResources(out, fSys, m)
+	if err = selectResources(m, o.selectors); err != nil {
+		return err
+	}
+	if o.pruneEmpty {
+		pruneEmpty(m)
+	}
+	return o.emitResources(out, fSys, m, kt.SortOptions())
 }
 
 func (o *Options) RunBuildPrune(
 	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
 	rf *resmap.Factory, ptf resmap.PatchFactory,
 	pl *plugins.Loader) error {
-	ldr, err := loader.NewLoader(
-		o.loadRestrictor, v, o.kustomizationPath, fSys)
+	ldr, err := o.newLoader(v, fSys)
 	if err != nil {
 		return err
 	}
 	defer ldr.Cleanup()
-	kt, err := target.NewKustTarget(ldr, rf, ptf, pl)
+	kt, err := o.newKustTarget(ldr, rf, ptf, pl)
 	if err != nil {
 		return err
 	}
+	if o.resolver != nil {
+		kt.SetConflictResolver(o.resolver)
+	}
+	kt.AllowEnvVars(o.allowedEnv)
+	kt.SetValues(o.settings)
+	kt.SetMaxResources(o.maxResources)
 	m, err := kt.MakePruneConfigMap()
 	if err != nil {
+		return offlineErr(ldr, err)
+	}
+	if err = o.writeLock(ldr, fSys); err != nil {
+		return err
+	}
+	if err = selectResources(m, o.selectors); err != nil {
 		return err
 	}
-	return o.emitResources(out, fSys, m)
+	if o.pruneEmpty {
+		pruneEmpty(m)
+	}
+	return o.emitResources(out, fSys, m, kt.SortOptions())
 }
 
 func (o *Options) emitResources(
-	out io.Writer, fSys fs.FileSystem, m resmap.ResMap) error {
+	out io.Writer, fSys fs.FileSystem, m resmap.ResMap,
+	sortOptions *types.SortOptions) error {
+	if o.fileTemplate != "" {
+		return writeTemplatedFiles(
+			fSys, o.outputPath, m, o.fileTemplate, o.outputFormat, o.style)
+	}
+	if o.splitNamespaces != "" {
+		if err := reorder(m, o.outOrder, sortOptions); err != nil {
+			return err
+		}
+		return o.writeByNamespace(fSys, o.outputPath, m)
+	}
+	if o.outputPath != "" && isDirPath(o.outputPath) {
+		if err := fSys.MkdirAll(o.outputPath); err != nil {
+			return err
+		}
+	}
 	if o.outputPath != "" && fSys.IsDir(o.outputPath) {
-		return writeIndividualFiles(fSys, o.outputPath, m)
+		return writeIndividualFiles(
+			fSys, o.outputPath, m, o.outputFormat, o.style)
 	}
-	if o.outOrder == legacy {
-		// Done this way just to show how overall sorting
-		// can be performed by a plugin.  This particular
-		// plugin doesn't require configuration; just make
-		// it and call transform.
-		builtin.NewLegacyOrderTransformerPlugin().Transform(m)
+	if err := reorder(m, o.outOrder, sortOptions); err != nil {
+		return err
 	}
-	res, err := m.AsYaml()
+	res, err := encodeResources(m, o.outputFormat, o.listKind)
 	if err != nil {
 		return err
 	}
+	if !isJson(o.outputFormat) {
+		res, err = o.style.apply(res)
+		if err != nil {
+			return err
+		}
+	}
 	if o.outputPath != "" {
 		return fSys.WriteFile(o.outputPath, res)
 	}
@@ -179,6 +561,13 @@ func (o *Options) emitResources(
 	return err
 }
 
+// isDirPath returns true if the path ends with a
+// separator, naming a directory, existing or not.
+func isDirPath(path string) bool {
+	return strings.HasSuffix(path, "/") ||
+		strings.HasSuffix(path, string(filepath.Separator))
+}
+
 func NewCmdBuildPrune(
 	out io.Writer, v ifc.Validator, fSys fs.FileSystem,
 	rf *resmap.Factory, ptf resmap.PatchFactory,
@@ -195,29 +584,34 @@ func NewCmdBuildPrune(
 			if err != nil {
 				return err
 			}
+			o.stdin = cmd.InOrStdin()
 			return o.RunBuildPrune(out, v, fSys, rf, ptf, pl)
 		},
 	}
 	return cmd
 }
 
+// writeIndividualFiles writes each resource to a file of its own,
+// in JSON if the format is JSON, or NDJSON, else in styled YAML.
 func writeIndividualFiles(
-	fSys fs.FileSystem, folderPath string, m resmap.ResMap) error {
+	fSys fs.FileSystem, folderPath string, m resmap.ResMap,
+	format string, style yamlStyle) error {
 	byNamespace := m.GroupedByCurrentNamespace()
 	for namespace, resList := range byNamespace {
 		for _, res := range resList {
-			fName := fileName(res)
+			fName := fileName(res, format)
 			if len(byNamespace) > 1 {
 				fName = strings.ToLower(namespace) + "_" + fName
 			}
-			err := writeFile(fSys, folderPath, fName, res)
+			err := writeFile(fSys, folderPath, fName, res, format, style)
 			if err != nil {
 				return err
 			}
 		}
 	}
 	for _, res := range m.NonNamespaceable() {
-		err := writeFile(fSys, folderPath, fileName(res), res)
+		err := writeFile(
+			fSys, folderPath, fileName(res, format), res, format, style)
 		if err != nil {
 			return err
 		}
@@ -225,14 +619,32 @@ func writeIndividualFiles(
 	return nil
 }
 
-func fileName(res *resource.Resource) string {
+func fileName(res *resource.Resource, format string) string {
+	ext := ".yaml"
+	if isJson(format) {
+		ext = ".json"
+	}
 	return strings.ToLower(res.GetGvk().String()) +
-		"_" + strings.ToLower(res.GetName()) + ".yaml"
+		"_" + strings.ToLower(res.GetName()) + ext
 }
 
 func writeFile(
-	fSys fs.FileSystem, path, fName string, res *resource.Resource) error {
-	out, err := yaml.Marshal(res.Map())
+	fSys fs.FileSystem, path, fName string, res *resource.Resource,
+	format string, style yamlStyle) error {
+	var out []byte
+	var err error
+	if isJson(format) {
+		out, err = json.MarshalIndent(res.Map(), "", "  ")
+		out = append(out, '\n')
+	} else {
+		out, err = yaml.Marshal(res.Map())
+		if err == nil {
+			out, err = yamlcomments.Apply(out, res.Comments())
+		}
+		if err == nil {
+			out, err = style.apply(out)
+		}
+	}
 	if err != nil {
 		return err
 	}
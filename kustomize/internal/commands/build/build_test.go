@@ -4,9 +4,26 @@
 package build
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
 )
 
 func TestNewOptionsToSilenceCodeInspectionError(t *testing.T) {
@@ -51,3 +68,966 @@ func TestBuildValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildValidateStdin(t *testing.T) {
+	var cases = []struct {
+		name    string
+		args    []string
+		baseDir string
+		path    string
+		erMsg   string
+	}{
+		{"stdin", []string{"-"}, "", ".", ""},
+		{"baseDir", []string{"-"}, "a/b", "a/b", ""},
+		{"baseDirWithoutStdin", []string{"a/b"}, "a/b", "",
+			"--base-dir applies only to a kustomization read from stdin, " +
+				"with the path '-'"},
+	}
+	for _, c := range cases {
+		opts := Options{baseDir: c.baseDir}
+		err := opts.Validate(c.args)
+		if c.erMsg != "" {
+			if err == nil || err.Error() != c.erMsg {
+				t.Fatalf("%s: expected error %s, got %v", c.name, c.erMsg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if !opts.fromStdin || opts.kustomizationPath != c.path {
+			t.Fatalf("%s: expected path '%s' read from stdin, got '%s'",
+				c.name, c.path, opts.kustomizationPath)
+		}
+	}
+}
+
+func TestRunBuildFromStdin(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+	opts := Options{baseDir: "/app"}
+	if err := opts.Validate([]string{"-"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	opts.stdin = strings.NewReader(`
+namePrefix: p-
+resources:
+- cm.yaml
+`)
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	var out, errOut bytes.Buffer
+	err := opts.RunBuild(
+		&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+		transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: p-cm
+`
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestBuildValidateAllowIdConflicts(t *testing.T) {
+	defer func() { flagAllowIdConflictsValue = "" }()
+
+	flagAllowIdConflictsValue = "last-wins"
+	opts := Options{}
+	if err := opts.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.resolver == nil {
+		t.Fatalf("expected a conflict resolver")
+	}
+
+	flagAllowIdConflictsValue = "first-wins"
+	opts = Options{}
+	err := opts.Validate(nil)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	expected := "illegal flag value --allow-id-conflicts first-wins; legal values: [last-wins]"
+	if err.Error() != expected {
+		t.Fatalf("expected error %s, got %v", expected, err)
+	}
+}
+
+func TestBuildValidateSet(t *testing.T) {
+	defer func() { flagSetValue = nil }()
+
+	flagSetValue = []string{"image-tag=v1.2.3", "replicas=", "url=a=b"}
+	opts := Options{}
+	if err := opts.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"image-tag": "v1.2.3", "replicas": "", "url": "a=b"}
+	if !reflect.DeepEqual(opts.settings, expected) {
+		t.Fatalf("expected %v, got %v", expected, opts.settings)
+	}
+
+	flagSetValue = []string{"image-tag"}
+	opts = Options{}
+	err := opts.Validate(nil)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	expectedErr := "illegal flag value --set image-tag; must be like name=value"
+	if err.Error() != expectedErr {
+		t.Fatalf("expected error %s, got %v", expectedErr, err)
+	}
+}
+
+func TestRunBuildOutputFormat(t *testing.T) {
+	defer func() {
+		flagOutputFormatValue = outputYaml
+		flagListKindValue = defaultListKind
+	}()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- cm.yaml
+`))
+	fSys.WriteFile("/app/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	for format, expected := range map[string]string{
+		outputJson: `[
+  {
+    "apiVersion": "v1",
+    "kind": "ConfigMap",
+    "metadata": {
+      "name": "cm"
+    }
+  }
+]
+`,
+		outputNdJson: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}
+`,
+		outputList: `apiVersion: v1
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: cm
+kind: List
+`,
+	} {
+		flagOutputFormatValue = format
+		opts := Options{}
+		if err := opts.Validate([]string{"/app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out, errOut bytes.Buffer
+		err := opts.RunBuild(
+			&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+			transformer.NewFactoryImpl(),
+			plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.String() != expected {
+			t.Fatalf("%s: expected %q, got %q", format, expected, out.String())
+		}
+	}
+
+	flagOutputFormatValue = "toml"
+	opts := Options{}
+	err := opts.Validate(nil)
+	expected := "illegal flag value --output-format toml; " +
+		"legal values: [yaml json ndjson list]"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %s, got %v", expected, err)
+	}
+}
+
+func TestBuildValidateListKind(t *testing.T) {
+	defer func() { flagListKindValue = defaultListKind }()
+
+	flagListKindValue = "config.kubernetes.io/v1/ResourceList"
+	opts := Options{}
+	if err := opts.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := listKind{
+		apiVersion: "config.kubernetes.io/v1", kind: "ResourceList"}
+	if opts.listKind != expected {
+		t.Fatalf("expected %v, got %v", expected, opts.listKind)
+	}
+
+	flagListKindValue = "List"
+	opts = Options{}
+	err := opts.Validate(nil)
+	expectedErr := "illegal flag value --list-kind List; must be like v1/List"
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("expected error %s, got %v", expectedErr, err)
+	}
+}
+
+func TestEmitResults(t *testing.T) {
+	results := []resmap.Result{
+		{Severity: resmap.SeverityWarning, Message: "replicas unset",
+			FieldPath: "spec.replicas"},
+		{Message: "nothing to do"},
+	}
+	var cases = []struct {
+		format   string
+		results  []resmap.Result
+		expected string
+	}{
+		{resultsText, results,
+			"warning: spec.replicas: replicas unset\ninfo: nothing to do\n"},
+		{resultsText, nil, ""},
+		{resultsJson, results[:1], `[
+  {
+    "severity": "warning",
+    "message": "replicas unset",
+    "fieldPath": "spec.replicas"
+  }
+]
+`},
+		{resultsJson, nil, "[]\n"},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		if err := emitResults(&buf, c.format, c.results); err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		if buf.String() != c.expected {
+			t.Fatalf("%s: expected '%s', got '%s'",
+				c.format, c.expected, buf.String())
+		}
+	}
+}
+
+func TestRunBuildFileTemplate(t *testing.T) {
+	defer func() { flagFileTemplateValue = "" }()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- resources.yaml
+`))
+	fSys.WriteFile("/app/resources.yaml", []byte(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: web
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	build := func(template string) error {
+		flagFileTemplateValue = template
+		opts := Options{outputPath: "/out"}
+		if err := opts.Validate([]string{"/app"}); err != nil {
+			return err
+		}
+		var out, errOut bytes.Buffer
+		return opts.RunBuild(
+			&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+			transformer.NewFactoryImpl(),
+			plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	}
+
+	if err := build("{namespace}/{kind}_{name}.yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for path, expected := range map[string]string{
+		"/out/Namespace_web.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: web
+`,
+		"/out/web/Deployment_app.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: web
+`,
+	} {
+		content, err := fSys.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(content) != expected {
+			t.Fatalf("%s: expected %q, got %q", path, expected, content)
+		}
+	}
+
+	err := build("{version}.yaml")
+	if err == nil || !strings.Contains(err.Error(), "names v1.yaml for both") {
+		t.Fatalf("expected shared file error, got %v", err)
+	}
+	err = build("../{name}.yaml")
+	if err == nil || !strings.Contains(err.Error(), "outside /out") {
+		t.Fatalf("expected outside error, got %v", err)
+	}
+	err = build("{uid}.yaml")
+	expected := "illegal flag value --file-template {uid}.yaml; " +
+		"unknown placeholder {uid}"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %s, got %v", expected, err)
+	}
+}
+
+func TestRunBuildSortOptions(t *testing.T) {
+	defer func() { flagReorderOutputValue = "" }()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/resources.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	build := func(kustomization, flag string) string {
+		fSys.WriteFile("/app/kustomization.yaml", []byte(kustomization))
+		flagReorderOutputValue = flag
+		opts := Options{}
+		if err := opts.Validate([]string{"/app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out, errOut bytes.Buffer
+		err := opts.RunBuild(
+			&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+			transformer.NewFactoryImpl(),
+			plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var names []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if strings.HasPrefix(line, "  name: ") {
+				names = append(names, strings.TrimPrefix(line, "  name: "))
+			}
+		}
+		return strings.Join(names, ",")
+	}
+
+	for _, c := range []struct {
+		kustomization string
+		flag          string
+		expected      string
+	}{
+		{"resources:\n- resources.yaml\n", "", "ns,cm,svc"},
+		{"resources:\n- resources.yaml\nsortOptions:\n  order: fifo\n",
+			"", "svc,ns,cm"},
+		{"resources:\n- resources.yaml\nsortOptions:\n  order: fifo\n",
+			"legacy", "ns,cm,svc"},
+		{"resources:\n- resources.yaml\n", "fifo", "svc,ns,cm"},
+		{`resources:
+- resources.yaml
+sortOptions:
+  order: legacy
+  legacySortOptions:
+    orderFirst:
+    - Service
+    orderLast:
+    - Namespace
+`, "", "svc,cm,ns"},
+	} {
+		if actual := build(c.kustomization, c.flag); actual != c.expected {
+			t.Fatalf("expected %s, got %s for --reorder '%s' and\n%s",
+				c.expected, actual, c.flag, c.kustomization)
+		}
+	}
+}
+
+func TestRunBuildYamlStyle(t *testing.T) {
+	defer func() {
+		flagIndentValue = defaultIndent
+		flagFlowListsValue = 0
+		flagQuoteStyleValue = ""
+	}()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- pod.yaml
+`))
+	fSys.WriteFile("/app/pod.yaml", []byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+  annotations:
+    enabled: "on"
+spec:
+  containers:
+  - name: app
+    args: [serve, "8080"] # The port.
+    command:
+    - /app
+    - --verbose
+    - --trace
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	build := func(indent, flowLists int, quote string) string {
+		flagIndentValue, flagFlowListsValue, flagQuoteStyleValue =
+			indent, flowLists, quote
+		opts := Options{}
+		if err := opts.Validate([]string{"/app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out, errOut bytes.Buffer
+		err := opts.RunBuild(
+			&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+			transformer.NewFactoryImpl(),
+			plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out.String()
+	}
+
+	for _, c := range []struct {
+		indent    int
+		flowLists int
+		quote     string
+		expected  string
+	}{
+		{defaultIndent, 0, "", `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    enabled: "on"
+  name: app
+spec:
+  containers:
+  - args: # The port.
+    - serve
+    - "8080"
+    command:
+    - /app
+    - --verbose
+    - --trace
+    name: app
+`},
+		{4, 2, quoteSingle, `apiVersion: v1
+kind: Pod
+metadata:
+    annotations:
+        enabled: 'on'
+    name: app
+spec:
+    containers:
+        - args: [serve, '8080'] # The port.
+          command:
+            - /app
+            - --verbose
+            - --trace
+          name: app
+`},
+		{defaultIndent, 0, quoteAll, `apiVersion: "v1"
+kind: "Pod"
+metadata:
+  annotations:
+    enabled: "on"
+  name: "app"
+spec:
+  containers:
+    - args: # The port.
+        - "serve"
+        - "8080"
+      command:
+        - "/app"
+        - "--verbose"
+        - "--trace"
+      name: "app"
+`},
+	} {
+		actual := build(c.indent, c.flowLists, c.quote)
+		if actual != c.expected {
+			t.Fatalf("%d %d %s: expected\n%s\ngot\n%s",
+				c.indent, c.flowLists, c.quote, c.expected, actual)
+		}
+	}
+
+	flagQuoteStyleValue = "backtick"
+	opts := Options{}
+	err := opts.Validate(nil)
+	expected := "illegal flag value --quote-style backtick; " +
+		"legal values: [single double all]"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %s, got %v", expected, err)
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	var cases = []struct {
+		in       string
+		expected types.Selector
+		err      string
+	}{
+		{in: "kind=Service,labelSelector=app=web",
+			expected: types.Selector{
+				Gvk: gvk.Gvk{Kind: "Service"}, LabelSelector: "app=web"}},
+		{in: "labelSelector=app=web,tier in (front,back),name=web-.*",
+			expected: types.Selector{
+				Name: "web-.*", LabelSelector: "app=web,tier in (front,back)"}},
+		{in: "group=apps,version=v1,namespace=prod,annotationSelector=team",
+			expected: types.Selector{
+				Gvk:       gvk.Gvk{Group: "apps", Version: "v1"},
+				Namespace: "prod", AnnotationSelector: "team"}},
+		{in: "app=web", err: "unknown field app; must be like kind=Service"},
+		{in: "kind=Service,kind=Pod", err: "kind is repeated"},
+		{in: "name=", err: "name is empty"},
+	}
+	for _, c := range cases {
+		actual, err := parseSelector(c.in)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Fatalf("%s: expected error %s, got %v", c.in, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.in, err)
+		}
+		if actual != c.expected {
+			t.Fatalf("%s: expected %v, got %v", c.in, c.expected, actual)
+		}
+	}
+}
+
+func TestRunBuildSelect(t *testing.T) {
+	defer func() { flagSelectValue = nil }()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namePrefix: p-
+resources:
+- resources.yaml
+`))
+	fSys.WriteFile("/app/resources.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    app: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  labels:
+    app: db
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	build := func(selectors ...string) string {
+		flagSelectValue = selectors
+		opts := Options{}
+		if err := opts.Validate([]string{"/app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out, errOut bytes.Buffer
+		err := opts.RunBuild(
+			&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+			transformer.NewFactoryImpl(),
+			plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out.String()
+	}
+
+	expected := `apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: web
+  name: p-web
+`
+	if actual := build("kind=Service,labelSelector=app=web"); actual != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, actual)
+	}
+	expected = `apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: db
+  name: p-db
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: p-web
+`
+	if actual := build("name=db", "kind=Deployment"); actual != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	flagSelectValue = []string{"app=web"}
+	opts := Options{}
+	err := opts.Validate(nil)
+	expectedErr := "illegal flag value --select app=web; " +
+		"unknown field app; must be like kind=Service"
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("expected error %s, got %v", expectedErr, err)
+	}
+}
+
+func TestRunBuildSplitNamespaces(t *testing.T) {
+	defer func() { flagSplitNamespacesValue = "" }()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- resources.yaml
+`))
+	fSys.WriteFile("/app/resources.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  namespace: team-a
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+  namespace: team-b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: team-b
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	build := func(split, output string) error {
+		flagSplitNamespacesValue = split
+		opts := Options{outputPath: output}
+		if err := opts.Validate([]string{"/app"}); err != nil {
+			return err
+		}
+		var out, errOut bytes.Buffer
+		return opts.RunBuild(
+			&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+			transformer.NewFactoryImpl(),
+			plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	}
+
+	if err := build(splitFiles, "/files"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := build(splitDirs, "/dirs"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for path, expected := range map[string]string{
+		"/files/_cluster.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+`,
+		"/files/team-a.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  namespace: team-a
+`,
+		"/files/team-b.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: team-b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+  namespace: team-b
+`,
+		"/dirs/_cluster/~g_v1_namespace_team-a.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+`,
+		"/dirs/team-b/~g_v1_service_b.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: b
+  namespace: team-b
+`,
+	} {
+		content, err := fSys.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(content) != expected {
+			t.Fatalf("%s: expected %q, got %q", path, expected, content)
+		}
+	}
+
+	err := build(splitDirs, "")
+	expected := "--split-namespaces requires --output naming a directory"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %s, got %v", expected, err)
+	}
+}
+
+func TestRunBuildPruneEmpty(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- deployment.yaml
+- pdb.yaml
+patchesStrategicMerge:
+- patch.yaml
+`))
+	fSys.WriteFile("/app/pdb.yaml", []byte(`
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: all
+spec:
+  maxUnavailable: 1
+  selector: {}
+`))
+	fSys.WriteFile("/app/deployment.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations: {}
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        args: null
+        env: []
+        resources:
+          limits:
+            cpu: 1
+      topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: zone
+        whenUnsatisfiable: DoNotSchedule
+        labelSelector: {}
+      volumes:
+      - name: scratch
+        emptyDir: {}
+`))
+	fSys.WriteFile("/app/patch.yaml", []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          limits:
+            cpu: null
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	opts := Options{pruneEmpty: true}
+	if err := opts.Validate([]string{"/app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out, errOut bytes.Buffer
+	err := opts.RunBuild(
+		&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+		transformer.NewFactoryImpl(),
+		plugins.NewLoader(plugins.DefaultPluginConfig(), rf))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+      topologySpreadConstraints:
+      - labelSelector: {}
+        maxSkew: 1
+        topologyKey: zone
+        whenUnsatisfiable: DoNotSchedule
+      volumes:
+      - emptyDir: {}
+        name: scratch
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: all
+spec:
+  maxUnavailable: 1
+  selector: {}
+`
+	if out.String() != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+// syncBuffer is a buffer safe to write and read at once.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunWatch(t *testing.T) {
+	// The build reads files as the test writes them;
+	// the file system on disk allows that.
+	dir, err := ioutil.TempDir("", "watch-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fSys := fs.MakeFsOnDisk()
+	fSys.MkdirAll(filepath.Join(dir, "base"))
+	fSys.MkdirAll(filepath.Join(dir, "overlay"))
+	fSys.WriteFile(dir+"/base/kustomization.yaml", []byte(`
+resources:
+- cm.yaml
+`))
+	fSys.WriteFile(dir+"/base/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+	fSys.WriteFile(dir+"/overlay/kustomization.yaml", []byte(`
+namePrefix: p-
+resources:
+- ../base
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	opts := Options{watchInterval: time.Millisecond}
+	if err := opts.Validate([]string{dir + "/overlay"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out, errOut syncBuffer
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- opts.RunWatch(
+			&out, &errOut, validators.MakeFakeValidator(), fSys, rf,
+			transformer.NewFactoryImpl(),
+			plugins.NewLoader(plugins.DefaultPluginConfig(), rf), stop)
+	}()
+	waitFor := func(expected string) {
+		for i := 0; out.String() != expected; i++ {
+			if i == 1000 {
+				t.Fatalf("expected\n%s\ngot\n%s\nerrors: %s",
+					expected, out.String(), errOut.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	first := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: p-cm
+`
+	waitFor(first)
+	// A change in the base is built, a broken one reported.
+	fSys.WriteFile(dir+"/base/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+`))
+	second := first + `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: p-cm2
+`
+	waitFor(second)
+	fSys.WriteFile(dir+"/base/cm.yaml", []byte("not: [yaml"))
+	for i := 0; !strings.HasPrefix(errOut.String(), "Error: "); i++ {
+		if i == 1000 {
+			t.Fatalf("expected an error, got %q", errOut.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != second {
+		t.Fatalf("expected\n%s\ngot\n%s", second, out.String())
+	}
+
+	opts = Options{watch: true}
+	err = opts.Validate([]string{"-"})
+	expected := "--watch can't watch a kustomization read from stdin"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %s, got %v", expected, err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const (
+	flagFileTemplateName = "file-template"
+)
+
+var (
+	flagFileTemplateValue = ""
+	flagFileTemplateHelp  = "If set, --output names a directory, created " +
+		"if missing, and each resource is written to the file, relative " +
+		"to it, that the template names, e.g. " +
+		"'{namespace}/{kind}_{name}.yaml'. The template may refer to " +
+		"{group}, {version}, {kind}, {namespace} and {name}."
+
+	placeholder = regexp.MustCompile(`{[^{}]*}`)
+)
+
+func addFlagFileTemplate(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagFileTemplateValue, flagFileTemplateName,
+		"", flagFileTemplateHelp)
+}
+
+func validateFlagFileTemplate() (string, error) {
+	t := flagFileTemplateValue
+	if t == "" {
+		return "", nil
+	}
+	if filepath.IsAbs(t) {
+		return "", fmt.Errorf(
+			"illegal flag value --%s %s; must be a relative path",
+			flagFileTemplateName, t)
+	}
+	for _, p := range placeholder.FindAllString(t, -1) {
+		switch p {
+		case "{group}", "{version}", "{kind}", "{namespace}", "{name}":
+		default:
+			return "", fmt.Errorf(
+				"illegal flag value --%s %s; unknown placeholder %s",
+				flagFileTemplateName, t, p)
+		}
+	}
+	return t, nil
+}
+
+// expandFileTemplate returns the path the
+// template names for the resource.
+func expandFileTemplate(t string, res *resource.Resource) string {
+	g := res.GetGvk()
+	values := map[string]string{
+		"{group}":     g.Group,
+		"{version}":   g.Version,
+		"{kind}":      g.Kind,
+		"{namespace}": res.GetNamespace(),
+		"{name}":      res.GetName(),
+	}
+	return placeholder.ReplaceAllStringFunc(t, func(p string) string {
+		return values[p]
+	})
+}
+
+// writeTemplatedFiles writes each resource to the
+// file, in the directory, that the template names,
+// failing if two resources would share a file.
+func writeTemplatedFiles(
+	fSys fs.FileSystem, dir string, m resmap.ResMap,
+	t, format string, style yamlStyle) error {
+	written := make(map[string]string)
+	for _, res := range m.Resources() {
+		rel := filepath.Clean(expandFileTemplate(t, res))
+		if rel == "." || rel == ".." ||
+			strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf(
+				"--%s names %s, outside %s, for %s",
+				flagFileTemplateName, rel, dir, res.CurId())
+		}
+		if other, ok := written[rel]; ok {
+			return fmt.Errorf(
+				"--%s names %s for both %s and %s",
+				flagFileTemplateName, rel, other, res.CurId())
+		}
+		written[rel] = res.CurId().String()
+		if err := fSys.MkdirAll(filepath.Dir(filepath.Join(dir, rel))); err != nil {
+			return err
+		}
+		if err := writeFile(fSys, dir, rel, res, format, style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+const (
+	flagOutputFormatName = "output-format"
+	flagListKindName     = "list-kind"
+
+	outputYaml   = "yaml"
+	outputJson   = "json"
+	outputNdJson = "ndjson"
+	outputList   = "list"
+
+	defaultListKind = "v1/List"
+)
+
+var (
+	flagOutputFormatValue = outputYaml
+	flagOutputFormatHelp  = "How to print the resources. Use '" +
+		outputYaml + "' for YAML documents separated by '---', '" +
+		outputJson + "' for a JSON array of them, '" + outputNdJson +
+		"' for a JSON object per line, or '" + outputList +
+		"' for a YAML list object holding them."
+
+	flagListKindValue = defaultListKind
+	flagListKindHelp  = "The apiVersion and kind, as apiVersion/kind, " +
+		"of the list object holding the resources with --" +
+		flagOutputFormatName + " " + outputList + "."
+)
+
+func addFlagOutputFormat(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagOutputFormatValue, flagOutputFormatName,
+		outputYaml, flagOutputFormatHelp)
+	set.StringVar(
+		&flagListKindValue, flagListKindName,
+		defaultListKind, flagListKindHelp)
+}
+
+func validateFlagOutputFormat() (string, error) {
+	switch flagOutputFormatValue {
+	case outputYaml, outputJson, outputNdJson, outputList:
+		return flagOutputFormatValue, nil
+	default:
+		return "", fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagOutputFormatName, flagOutputFormatValue,
+			[]string{outputYaml, outputJson, outputNdJson, outputList})
+	}
+}
+
+// listKind holds the apiVersion and
+// kind of the list object to print.
+type listKind struct {
+	apiVersion string
+	kind       string
+}
+
+func validateFlagListKind() (listKind, error) {
+	i := strings.LastIndex(flagListKindValue, "/")
+	if i < 1 || i == len(flagListKindValue)-1 {
+		return listKind{}, fmt.Errorf(
+			"illegal flag value --%s %s; must be like %s",
+			flagListKindName, flagListKindValue, defaultListKind)
+	}
+	return listKind{
+		apiVersion: flagListKindValue[:i],
+		kind:       flagListKindValue[i+1:],
+	}, nil
+}
+
+// isJson returns true if the format is JSON or NDJSON.
+func isJson(format string) bool {
+	return format == outputJson || format == outputNdJson
+}
+
+// encodeResources returns the resources in the format,
+// in a list object of the kind if the format is list.
+func encodeResources(
+	m resmap.ResMap, format string, kind listKind) ([]byte, error) {
+	switch format {
+	case outputJson:
+		return m.AsJson()
+	case outputNdJson:
+		return m.AsNdJson()
+	case outputList:
+		return m.AsList(kind.apiVersion, kind.kind)
+	default:
+		return m.AsYaml()
+	}
+}
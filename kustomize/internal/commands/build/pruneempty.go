@@ -0,0 +1,80 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+const (
+	flagPruneEmptyName = "prune-empty"
+	flagPruneEmptyHelp = "Drop fields that are null, or empty maps or " +
+		"lists, e.g. as patches leave them, except those whose " +
+		"presence has a meaning, like emptyDir: {}. " +
+		"Items of lists are kept, empty or not."
+)
+
+// meaningfulWhenEmpty holds the fields whose empty
+// map means something other than their absence.
+var meaningfulWhenEmpty = map[string]bool{
+	// An empty dir volume.
+	"emptyDir": true,
+	// Selectors of every object, e.g. the pods of a network
+	// policy or disruption budget, or of pod affinities and
+	// topology spread constraints.
+	"podSelector":       true,
+	"namespaceSelector": true,
+	"selector":          true,
+	"labelSelector":     true,
+}
+
+func addFlagPruneEmpty(set *pflag.FlagSet, value *bool) {
+	set.BoolVar(value, flagPruneEmptyName, false, flagPruneEmptyHelp)
+}
+
+// pruneEmpty drops the null and empty fields of the resources.
+func pruneEmpty(m resmap.ResMap) {
+	for _, res := range m.Resources() {
+		res.SetMap(pruneMap(res.Map()))
+	}
+}
+
+// pruneMap returns the map without its null and
+// empty fields, pruning the fields below them first.
+func pruneMap(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		v = pruneValue(v)
+		if isEmpty(v) && !(meaningfulWhenEmpty[k] && v != nil) {
+			delete(m, k)
+			continue
+		}
+		m[k] = v
+	}
+	return m
+}
+
+func pruneValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return pruneMap(x)
+	case []interface{}:
+		for i := range x {
+			x[i] = pruneValue(x[i])
+		}
+	}
+	return v
+}
+
+func isEmpty(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(x) == 0
+	case []interface{}:
+		return len(x) == 0
+	}
+	return false
+}
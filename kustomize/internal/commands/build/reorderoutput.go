@@ -7,6 +7,9 @@ import (
 	"fmt"
 
 	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/plugin/builtin"
 )
 
 //go:generate stringer -type=reorderOutput
@@ -20,24 +23,31 @@ const (
 
 const (
 	flagReorderOutputName = "reorder"
+
+	// fifo is another name of none, as in sortOptions.
+	fifo = "fifo"
 )
 
 var (
-	flagReorderOutputValue = legacy.String()
+	flagReorderOutputValue = ""
 	flagReorderOutputHelp  = "Reorder the resources just before output. " +
 		"Use '" + legacy.String() + "' to apply a legacy reordering (Namespaces first, Webhooks last, etc). " +
-		"Use '" + none.String() + "' to suppress a final reordering."
+		"Use '" + none.String() + "', or '" + fifo + "', to suppress a final reordering. " +
+		"If unset, the sortOptions of the kustomization built decide, " +
+		"by default applying the legacy reordering."
 )
 
 func addFlagReorderOutput(set *pflag.FlagSet) {
 	set.StringVar(
 		&flagReorderOutputValue, flagReorderOutputName,
-		legacy.String(), flagReorderOutputHelp)
+		"", flagReorderOutputHelp)
 }
 
 func validateFlagReorderOutput() (reorderOutput, error) {
 	switch flagReorderOutputValue {
-	case none.String():
+	case "":
+		return unspecified, nil
+	case none.String(), fifo:
 		return none, nil
 	case legacy.String():
 		return legacy, nil
@@ -45,6 +55,30 @@ func validateFlagReorderOutput() (reorderOutput, error) {
 		return unspecified, fmt.Errorf(
 			"illegal flag value --%s %s; legal values: %v",
 			flagReorderOutputName, flagReorderOutputValue,
-			[]string{legacy.String(), none.String()})
+			[]string{legacy.String(), none.String(), fifo})
+	}
+}
+
+// reorder sorts the resources as the flag says or, if
+// it's unset, as the sort options, if any, of the
+// kustomization built say, by default in legacy order.
+func reorder(
+	m resmap.ResMap, order reorderOutput, opts *types.SortOptions) error {
+	if order == unspecified {
+		order = legacy
+		if opts != nil && opts.Order == types.SortOrderFIFO {
+			order = none
+		}
+	}
+	if order != legacy {
+		return nil
+	}
+	// Done this way just to show how overall sorting
+	// can be performed by a plugin.
+	p := &builtin.LegacyOrderTransformerPlugin{}
+	if opts != nil && opts.LegacySortOptions != nil {
+		p.OrderFirst = opts.LegacySortOptions.OrderFirst
+		p.OrderLast = opts.LegacySortOptions.OrderLast
 	}
+	return p.Transform(m)
 }
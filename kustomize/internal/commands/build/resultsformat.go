@@ -0,0 +1,72 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+const (
+	flagResultsFormatName = "results-format"
+
+	resultsText = "text"
+	resultsJson = "json"
+)
+
+var (
+	flagResultsFormatValue = resultsText
+	flagResultsFormatHelp  = "How to print the results, e.g. warnings, " +
+		"plugins report to stderr. Use '" + resultsText + "' for a line " +
+		"per result, or '" + resultsJson + "' for a JSON list of them."
+)
+
+func addFlagResultsFormat(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagResultsFormatValue, flagResultsFormatName,
+		resultsText, flagResultsFormatHelp)
+}
+
+func validateFlagResultsFormat() (string, error) {
+	switch flagResultsFormatValue {
+	case resultsText, resultsJson:
+		return flagResultsFormatValue, nil
+	default:
+		return "", fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagResultsFormatName, flagResultsFormatValue,
+			[]string{resultsText, resultsJson})
+	}
+}
+
+// emitResults prints the results in the format.
+// The JSON list is printed even if it's empty,
+// for tools reading it.
+func emitResults(w io.Writer, format string, results []resmap.Result) error {
+	if format == resultsJson {
+		if results == nil {
+			results = []resmap.Result{}
+		}
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+	for _, r := range results {
+		severity := r.Severity
+		if severity == "" {
+			severity = resmap.SeverityInfo
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", severity, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
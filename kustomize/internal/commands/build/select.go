@@ -0,0 +1,126 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+const (
+	flagSelectName = "select"
+	flagSelectHelp = "Print only the resources the selector selects, " +
+		"as comma separated field=value pairs of the fields group, " +
+		"version, kind, name, namespace, labelSelector and " +
+		"annotationSelector, e.g. kind=Service,labelSelector=app=web. " +
+		"Names and namespaces are regular expressions. " +
+		"May be repeated, to print the resources any selects."
+)
+
+var flagSelectValue []string
+
+func addFlagSelect(set *pflag.FlagSet) {
+	set.StringArrayVar(
+		&flagSelectValue, flagSelectName, nil, flagSelectHelp)
+}
+
+func validateFlagSelect() ([]types.Selector, error) {
+	var result []types.Selector
+	for _, s := range flagSelectValue {
+		sel, err := parseSelector(s)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"illegal flag value --%s %s; %v", flagSelectName, s, err)
+		}
+		result = append(result, sel)
+	}
+	return result, nil
+}
+
+// parseSelector parses field=value pairs into a
+// selector.  Since label and annotation selectors
+// hold commas themselves, a pair not starting with
+// a field continues the value of the one before it,
+// e.g. labelSelector=app=web,tier=front.
+func parseSelector(s string) (types.Selector, error) {
+	var sel types.Selector
+	var value *string
+	for _, pair := range strings.Split(s, ",") {
+		field := pair
+		if i := strings.Index(pair, "="); i >= 0 {
+			field = pair[:i]
+		}
+		v := selectorField(&sel, field)
+		if v == nil {
+			if value == nil {
+				return sel, fmt.Errorf(
+					"unknown field %s; must be like kind=Service", field)
+			}
+			*value += "," + pair
+			continue
+		}
+		if *v != "" {
+			return sel, fmt.Errorf("%s is repeated", field)
+		}
+		value = v
+		*value = strings.TrimPrefix(pair, field+"=")
+		if *value == "" {
+			return sel, fmt.Errorf("%s is empty", field)
+		}
+	}
+	return sel, nil
+}
+
+// selectorField returns the field of the selector
+// of the name, or nil if it has no such field.
+func selectorField(sel *types.Selector, name string) *string {
+	switch name {
+	case "group":
+		return &sel.Group
+	case "version":
+		return &sel.Version
+	case "kind":
+		return &sel.Kind
+	case "name":
+		return &sel.Name
+	case "namespace":
+		return &sel.Namespace
+	case "labelSelector":
+		return &sel.LabelSelector
+	case "annotationSelector":
+		return &sel.AnnotationSelector
+	}
+	return nil
+}
+
+// selectResources removes the resources none of
+// the selectors select, if there are any selectors.
+func selectResources(m resmap.ResMap, selectors []types.Selector) error {
+	if len(selectors) == 0 {
+		return nil
+	}
+	selected := make(map[*resource.Resource]bool)
+	for _, s := range selectors {
+		resources, err := m.Select(s)
+		if err != nil {
+			return err
+		}
+		for _, r := range resources {
+			selected[r] = true
+		}
+	}
+	for _, r := range m.Resources() {
+		if !selected[r] {
+			if err := m.Remove(r.CurId()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
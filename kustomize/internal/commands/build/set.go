@@ -0,0 +1,38 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagSetName = "set"
+	flagSetHelp = "Value of a setting of the kustomizations, " +
+		"as name=value.  May be repeated."
+)
+
+var flagSetValue []string
+
+func addFlagSet(set *pflag.FlagSet) {
+	set.StringArrayVar(
+		&flagSetValue, flagSetName, nil, flagSetHelp)
+}
+
+func validateFlagSet() (map[string]string, error) {
+	values := make(map[string]string, len(flagSetValue))
+	for _, s := range flagSetValue {
+		i := strings.Index(s, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf(
+				"illegal flag value --%s %s; must be like name=value",
+				flagSetName, s)
+		}
+		values[s[:i]] = s[i+1:]
+	}
+	return values, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+const (
+	flagSplitNamespacesName = "split-namespaces"
+
+	splitFiles = "files"
+	splitDirs  = "dirs"
+
+	// clusterScoped names the file or directory of the
+	// resources in no namespace; no namespace can have
+	// the name, as namespace names hold no underscores.
+	clusterScoped = "_cluster"
+)
+
+var (
+	flagSplitNamespacesValue = ""
+	flagSplitNamespacesHelp  = "Write the resources of each namespace " +
+		"below the --output directory, in a file named for the namespace " +
+		"with '" + splitFiles + "', or in a file per resource in a " +
+		"directory named for the namespace with '" + splitDirs + "'. " +
+		"Cluster scoped resources go in the file or directory " +
+		clusterScoped + "."
+)
+
+func addFlagSplitNamespaces(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagSplitNamespacesValue, flagSplitNamespacesName,
+		"", flagSplitNamespacesHelp)
+}
+
+func validateFlagSplitNamespaces() (string, error) {
+	switch flagSplitNamespacesValue {
+	case "", splitFiles, splitDirs:
+		return flagSplitNamespacesValue, nil
+	default:
+		return "", fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagSplitNamespacesName, flagSplitNamespacesValue,
+			[]string{splitFiles, splitDirs})
+	}
+}
+
+// splitByNamespace returns the resources of each namespace,
+// keeping their order, those in none under clusterScoped.
+func splitByNamespace(m resmap.ResMap) (map[string]resmap.ResMap, error) {
+	result := make(map[string]resmap.ResMap)
+	for _, res := range m.Resources() {
+		namespace := res.CurId().EffectiveNamespace()
+		if namespace == resid.TotallyNotANamespace {
+			namespace = clusterScoped
+		}
+		if _, ok := result[namespace]; !ok {
+			result[namespace] = resmap.New()
+		}
+		if err := result[namespace].Append(res); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// writeByNamespace writes the resources of each namespace
+// to a file, or a directory, named for it below dir.
+func (o *Options) writeByNamespace(
+	fSys fs.FileSystem, dir string, m resmap.ResMap) error {
+	byNamespace, err := splitByNamespace(m)
+	if err != nil {
+		return err
+	}
+	if err = fSys.MkdirAll(dir); err != nil {
+		return err
+	}
+	for namespace, nm := range byNamespace {
+		if o.splitNamespaces == splitDirs {
+			nsDir := filepath.Join(dir, namespace)
+			if err = fSys.MkdirAll(nsDir); err != nil {
+				return err
+			}
+			for _, res := range nm.Resources() {
+				err = writeFile(
+					fSys, nsDir, fileName(res, o.outputFormat),
+					res, o.outputFormat, o.style)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		out, err := encodeResources(nm, o.outputFormat, o.listKind)
+		if err != nil {
+			return err
+		}
+		ext := ".yaml"
+		switch o.outputFormat {
+		case outputJson:
+			ext = ".json"
+		case outputNdJson:
+			ext = ".ndjson"
+		default:
+			if out, err = o.style.apply(out); err != nil {
+				return err
+			}
+		}
+		err = fSys.WriteFile(filepath.Join(dir, namespace+ext), out)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+const (
+	flagWatchName         = "watch"
+	flagWatchIntervalName = "watch-interval"
+
+	defaultWatchInterval = time.Second
+)
+
+func addFlagsWatch(set *pflag.FlagSet, watch *bool, interval *time.Duration) {
+	set.BoolVar(
+		watch, flagWatchName, false,
+		"If set, build again, printing or writing the output "+
+			"anew, whenever a file the build read changes, "+
+			"until interrupted.")
+	set.DurationVar(
+		interval, flagWatchIntervalName, defaultWatchInterval,
+		"How often to look for changes with --"+flagWatchName+".")
+}
+
+// RunWatch runs the build, and runs it again whenever
+// a file it read changes, until stop is closed.  Errors
+// of a build are printed to errOut, the watch going on.
+func (o *Options) RunWatch(
+	out, errOut io.Writer, v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory,
+	pl *plugins.Loader, stop <-chan struct{}) error {
+	interval := o.watchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	for {
+		rfs := newRecordingFs(fSys)
+		if err := o.RunBuild(out, errOut, v, rfs, rf, ptf, pl); err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+		}
+		read := rfs.snapshot()
+		for unchanged := true; unchanged; {
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(interval):
+			}
+			unchanged = rfs.snapshot() == read
+		}
+	}
+}
+
+// interrupted returns a channel closed on an interrupt.
+func interrupted() <-chan struct{} {
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+	return stop
+}
+
+// recordingFs records the files, directories and globs
+// that a build asks about, to tell when they change.
+type recordingFs struct {
+	fs.FileSystem
+	mu    sync.Mutex
+	files map[string]bool
+	paths map[string]bool
+	globs map[string]bool
+}
+
+func newRecordingFs(fSys fs.FileSystem) *recordingFs {
+	return &recordingFs{
+		FileSystem: fSys,
+		files:      make(map[string]bool),
+		paths:      make(map[string]bool),
+		globs:      make(map[string]bool),
+	}
+}
+
+func (r *recordingFs) record(m map[string]bool, path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m[path] = true
+}
+
+func (r *recordingFs) ReadFile(path string) ([]byte, error) {
+	r.record(r.files, path)
+	return r.FileSystem.ReadFile(path)
+}
+
+func (r *recordingFs) Open(path string) (fs.File, error) {
+	r.record(r.files, path)
+	return r.FileSystem.Open(path)
+}
+
+func (r *recordingFs) Exists(path string) bool {
+	r.record(r.paths, path)
+	return r.FileSystem.Exists(path)
+}
+
+func (r *recordingFs) IsDir(path string) bool {
+	r.record(r.paths, path)
+	return r.FileSystem.IsDir(path)
+}
+
+func (r *recordingFs) Glob(pattern string) ([]string, error) {
+	r.record(r.globs, pattern)
+	return r.FileSystem.Glob(pattern)
+}
+
+// snapshot returns the state of what was recorded,
+// as a string that's the same while nothing changes.
+// Paths that no longer exist, like those of clones of
+// remote bases, which builds remove, are just absent.
+func (r *recordingFs) snapshot() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b strings.Builder
+	for _, p := range sortedKeys(r.files) {
+		content, err := r.FileSystem.ReadFile(p)
+		if err != nil {
+			fmt.Fprintf(&b, "file %s absent\n", p)
+			continue
+		}
+		fmt.Fprintf(&b, "file %s %x\n", p, sha256.Sum256(content))
+	}
+	for _, p := range sortedKeys(r.paths) {
+		fmt.Fprintf(&b, "path %s %t %t\n",
+			p, r.FileSystem.Exists(p), r.FileSystem.IsDir(p))
+	}
+	for _, p := range sortedKeys(r.globs) {
+		matches, _ := r.FileSystem.Glob(p)
+		fmt.Fprintf(&b, "glob %s %v\n", p, matches)
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	flagIndentName     = "indent"
+	flagFlowListsName  = "flow-lists"
+	flagQuoteStyleName = "quote-style"
+
+	defaultIndent = 2
+
+	// Quote the strings that would otherwise be read as
+	// something else, e.g. "on" or "8080", in single or
+	// double quotes, or quote every string value.
+	quoteSingle = "single"
+	quoteDouble = "double"
+	quoteAll    = "all"
+)
+
+var (
+	flagIndentValue = defaultIndent
+	flagIndentHelp  = "How many spaces to indent nested YAML by, from 2 to 9."
+
+	flagFlowListsValue = 0
+	flagFlowListsHelp  = "Write lists of at most this many scalars " +
+		"in flow style, e.g. [a, b]; 0 writes every list in block style."
+
+	flagQuoteStyleValue = ""
+	flagQuoteStyleHelp  = "How to quote strings in YAML. Use '" +
+		quoteSingle + "' or '" + quoteDouble + "' to quote strings " +
+		"that would otherwise be read as something else, like 'on' " +
+		"or '8080', in single or double quotes, or '" + quoteAll +
+		"' to double quote every string value. If unset, strings " +
+		"are quoted only as needed, in the serializer's quotes."
+)
+
+func addFlagsYamlStyle(set *pflag.FlagSet) {
+	set.IntVar(
+		&flagIndentValue, flagIndentName,
+		defaultIndent, flagIndentHelp)
+	set.IntVar(
+		&flagFlowListsValue, flagFlowListsName,
+		0, flagFlowListsHelp)
+	set.StringVar(
+		&flagQuoteStyleValue, flagQuoteStyleName,
+		"", flagQuoteStyleHelp)
+}
+
+// yamlStyle holds how to format YAML output;
+// its zero value keeps the serializer's format.
+type yamlStyle struct {
+	indent    int
+	flowLists int
+	quote     string
+}
+
+func validateFlagsYamlStyle() (yamlStyle, error) {
+	if flagIndentValue < 2 || flagIndentValue > 9 {
+		return yamlStyle{}, fmt.Errorf(
+			"illegal flag value --%s %d; must be from 2 to 9",
+			flagIndentName, flagIndentValue)
+	}
+	if flagFlowListsValue < 0 {
+		return yamlStyle{}, fmt.Errorf(
+			"illegal flag value --%s %d; must not be negative",
+			flagFlowListsName, flagFlowListsValue)
+	}
+	switch flagQuoteStyleValue {
+	case "", quoteSingle, quoteDouble, quoteAll:
+	default:
+		return yamlStyle{}, fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagQuoteStyleName, flagQuoteStyleValue,
+			[]string{quoteSingle, quoteDouble, quoteAll})
+	}
+	s := yamlStyle{
+		flowLists: flagFlowListsValue,
+		quote:     flagQuoteStyleValue,
+	}
+	if flagIndentValue != defaultIndent {
+		s.indent = flagIndentValue
+	}
+	return s, nil
+}
+
+// apply returns the YAML documents, with
+// their comments, reformatted in the style.
+func (s yamlStyle) apply(in []byte) ([]byte, error) {
+	if s == (yamlStyle{}) {
+		return in, nil
+	}
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	indent := defaultIndent
+	if s.indent != 0 {
+		indent = s.indent
+	}
+	enc.SetIndent(indent)
+	dec := yaml.NewDecoder(bytes.NewReader(in))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.restyle(&doc, false)
+		if err = enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// restyle sets the style of the node and those below
+// it; isKey is true if the node is a mapping key.
+func (s yamlStyle) restyle(n *yaml.Node, isKey bool) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		s.quoteScalar(n, isKey)
+	case yaml.SequenceNode:
+		if s.isFlowList(n) {
+			n.Style = yaml.FlowStyle
+		}
+	}
+	for i, c := range n.Content {
+		s.restyle(c, n.Kind == yaml.MappingNode && i%2 == 0)
+	}
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if v.Style&yaml.FlowStyle != 0 && v.LineComment == "" {
+			// The comment at the end of the key's line
+			// now follows the list on that line.
+			v.LineComment, k.LineComment = k.LineComment, ""
+		}
+	}
+}
+
+func (s yamlStyle) quoteScalar(n *yaml.Node, isKey bool) {
+	if n.Tag != "!!str" ||
+		n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return
+	}
+	quoted := n.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0
+	switch {
+	case s.quote == quoteSingle && quoted:
+		n.Style = yaml.SingleQuotedStyle
+	case s.quote == quoteDouble && quoted:
+		n.Style = yaml.DoubleQuotedStyle
+	case s.quote == quoteAll && (quoted || !isKey):
+		n.Style = yaml.DoubleQuotedStyle
+	}
+}
+
+// isFlowList returns true if the list is short enough to
+// go in flow style, holding only uncommented scalars.
+func (s yamlStyle) isFlowList(n *yaml.Node) bool {
+	if len(n.Content) == 0 || len(n.Content) > s.flowLists {
+		return false
+	}
+	for _, c := range n.Content {
+		if c.Kind != yaml.ScalarNode || c.HeadComment != "" ||
+			c.LineComment != "" || c.FootComment != "" ||
+			c.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cache holds the commands managing the cache of remote bases.
+package cache
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+)
+
+// NewCmdCache returns an instance of 'cache' subcommand.
+func NewCmdCache(w io.Writer) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cache",
+		Short: "Manages the cache of remote bases",
+		Long: `Manages the cache of the git repos remote bases are cloned from.
+
+Clones of commit SHAs are reused by later builds without fetching
+them again; clones of branches and tags are refreshed by each build.
+The cache is kept in ` + "$XDG_CACHE_HOME/kustomize/repos, or ~/.cache/kustomize/repos.",
+		Example: `
+	# List the cached clones
+	kustomize cache list
+
+	# Remove them
+	kustomize cache clean
+`,
+		Args: cobra.MinimumNArgs(1),
+	}
+	c.AddCommand(
+		newCmdList(w, git.DefaultCacheDir),
+		newCmdClean(w, git.DefaultCacheDir),
+	)
+	return c
+}
+
+func newCmdList(w io.Writer, dir func() string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Lists the cached clones of remote bases",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := git.ListCache(dir())
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "REPO\tREF\tFETCHED\tDIR")
+			for _, e := range entries {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+					e.Repo, e.Ref, e.Fetched.Format(time.RFC3339), e.Dir)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newCmdClean(w io.Writer, dir func() string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean",
+		Short: "Removes the cached clones of remote bases",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := git.CleanCache(dir()); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(w, "removed %s\n", dir())
+			return err
+		},
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCacheListAndClean(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "kustomize-cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	dir := func() string { return tmp }
+	entry := `{"repo":"https://github.com/someOrg/someRepo.git",` +
+		`"ref":"v1","dir":"` + filepath.Join(tmp, "abc") + `",` +
+		`"fetched":"2019-10-01T12:00:00Z"}`
+	if err = ioutil.WriteFile(
+		filepath.Join(tmp, "abc.json"), []byte(entry), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	if err = newCmdList(out, dir).RunE(nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 ||
+		!strings.HasPrefix(lines[0], "REPO") ||
+		!strings.Contains(lines[1], "someRepo.git  v1   2019-10-01T12:00:00Z") {
+		t.Fatalf("unexpected listing:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err = newCmdClean(out, dir).RunE(nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = os.Stat(tmp); !os.IsNotExist(err) {
+		t.Fatalf("expected the cache to be removed, got %v", err)
+	}
+}
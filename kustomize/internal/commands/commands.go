@@ -9,10 +9,15 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/apply"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/build"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/cache"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/config"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/create"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/diff"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/edit"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/localize"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/rebase"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/version"
 	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
@@ -45,9 +50,14 @@ See https://sigs.k8s.io/kustomize
 		build.NewCmdBuild(
 			stdOut, fSys, v,
 			rf, pf),
+		diff.NewCmdDiff(stdOut, fSys, v, rf, pf),
+		apply.NewCmdApply(stdOut, fSys, v, rf, pf),
 		edit.NewCmdEdit(fSys, v, uf),
 		create.NewCmdCreate(fSys, uf),
 		config.NewCmdConfig(fSys),
+		rebase.NewCmdRebase(stdOut, fSys, rf, pf),
+		cache.NewCmdCache(stdOut),
+		localize.NewCmdLocalize(stdOut, fSys, v),
 		version.NewCmdVersion(stdOut),
 	)
 	c.PersistentFlags().AddGoFlagSet(flag.CommandLine)
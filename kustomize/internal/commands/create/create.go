@@ -15,6 +15,7 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 type createFlags struct {
@@ -130,7 +131,9 @@ func runCreate(opts createFlags, fSys fs.FileSystem, uf ifc.KunstructuredFactory
 	if err != nil {
 		return err
 	}
-	m.Resources = resources
+	for _, r := range resources {
+		m.Resources = append(m.Resources, types.ResourceEntry{Path: r})
+	}
 	m.Namespace = opts.namespace
 	m.NamePrefix = opts.prefix
 	m.NameSuffix = opts.suffix
@@ -138,7 +141,9 @@ func runCreate(opts createFlags, fSys fs.FileSystem, uf ifc.KunstructuredFactory
 	if err != nil {
 		return err
 	}
-	m.CommonAnnotations = annotations
+	if len(annotations) > 0 {
+		m.CommonAnnotations = &types.CommonAnnotations{Pairs: annotations}
+	}
 	labels, err := util.ConvertToMap(opts.labels, "label")
 	if err != nil {
 		return err
@@ -47,8 +47,8 @@ func TestCreateWithResources(t *testing.T) {
 	}
 	m := readKustomizationFS(t, fSys)
 	expected := []string{"foo.yaml", "bar.yaml"}
-	if !reflect.DeepEqual(m.Resources, expected) {
-		t.Fatalf("expected %+v but got %+v", expected, m.Resources)
+	if !reflect.DeepEqual(m.ResourcePaths(), expected) {
+		t.Fatalf("expected %+v but got %+v", expected, m.ResourcePaths())
 	}
 }
 
@@ -90,8 +90,8 @@ func TestCreateWithAnnotations(t *testing.T) {
 	}
 	m := readKustomizationFS(t, fSys)
 	expected := map[string]string{"foo": "bar"}
-	if !reflect.DeepEqual(m.CommonAnnotations, expected) {
-		t.Fatalf("expected %+v but got %+v", expected, m.CommonAnnotations)
+	if !reflect.DeepEqual(m.CommonAnnotations.Pairs, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, m.CommonAnnotations.Pairs)
 	}
 }
 
@@ -174,8 +174,8 @@ func TestCreateWithDetect(t *testing.T) {
 	}
 	m := readKustomizationFS(t, fSys)
 	expected := []string{"/test.yaml"}
-	if !reflect.DeepEqual(m.Resources, expected) {
-		t.Fatalf("expected %+v but got %+v", expected, m.Resources)
+	if !reflect.DeepEqual(m.ResourcePaths(), expected) {
+		t.Fatalf("expected %+v but got %+v", expected, m.ResourcePaths())
 	}
 }
 
@@ -189,7 +189,7 @@ func TestCreateWithDetectRecursive(t *testing.T) {
 	}
 	m := readKustomizationFS(t, fSys)
 	expected := []string{"/overlay", "/sub/test.yaml", "/test.yaml"}
-	if !reflect.DeepEqual(m.Resources, expected) {
-		t.Fatalf("expected %+v but got %+v", expected, m.Resources)
+	if !reflect.DeepEqual(m.ResourcePaths(), expected) {
+		t.Fatalf("expected %+v but got %+v", expected, m.ResourcePaths())
 	}
 }
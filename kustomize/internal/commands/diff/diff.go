@@ -0,0 +1,112 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"errors"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kubectl"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+type diffOptions struct {
+	kustomizationPath string
+	serverSide        bool
+	fieldManager      string
+	kubectl           kubectl.Options
+	build             kubectl.Builder
+}
+
+var examples = `
+To review what applying the resources that
+'someDir/kustomization.yaml' specifies would
+change in the cluster of the current context, run
+
+  kustomize diff someDir
+
+Each resource is sent to the cluster in a server-side
+dry run, and its unified diff against the live
+resource printed; new resources diff against nothing.
+With --server-side, the dry run is a server-side apply,
+as 'kustomize apply --server-side' would do, e.g.
+
+  kustomize diff someDir --context prod --server-side
+
+The resources are built as kustomize build builds
+them, taking the same flags shaping the build, e.g.
+--load-restrictor, --enable_helm or --set.
+
+The diff is made by kubectl, which must be on the PATH
+or given with --kubectl; KUBECTL_EXTERNAL_DIFF picks
+the diff program it runs.
+`
+
+// NewCmdDiff returns an instance of 'diff' subcommand.
+func NewCmdDiff(
+	out io.Writer, fSys fs.FileSystem, v ifc.Validator,
+	rf *resmap.Factory, ptf resmap.PatchFactory) *cobra.Command {
+	var o diffOptions
+
+	cmd := &cobra.Command{
+		Use:          "diff [path]",
+		Short:        "Diff the built resources against the live cluster",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunDiff(out, cmd.ErrOrStderr(), fSys, v, rf, ptf)
+		},
+	}
+	cmd.Flags().BoolVar(
+		&o.serverSide, "server-side", false,
+		"If set, diff against a server-side apply.")
+	cmd.Flags().StringVar(
+		&o.fieldManager, "field-manager", kubectl.DefaultFieldManager,
+		"The manager of the fields a server-side apply sets.")
+	o.kubectl.AddFlags(cmd.Flags())
+	o.build.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// Validate validates diff command.
+func (o *diffOptions) Validate(args []string) error {
+	if len(args) > 1 {
+		return errors.New("specify one path to a kustomization")
+	}
+	o.kustomizationPath = loader.CWD
+	if len(args) == 1 {
+		o.kustomizationPath = args[0]
+	}
+	return o.build.Validate(o.kustomizationPath)
+}
+
+// RunDiff builds the kustomization and prints the
+// diff of its resources against the live cluster.
+func (o *diffOptions) RunDiff(
+	out, errOut io.Writer, fSys fs.FileSystem, v ifc.Validator,
+	rf *resmap.Factory, ptf resmap.PatchFactory) error {
+	resources, err := o.build.Build(errOut, v, fSys, rf, ptf)
+	if err != nil {
+		return err
+	}
+	args := []string{"diff", "-f", "-"}
+	if o.serverSide {
+		args = append(args,
+			"--server-side", "--field-manager", o.fieldManager)
+	}
+	code, err := o.kubectl.Run(out, resources, args...)
+	// kubectl diff exits with 1 if there are differences.
+	if code == 1 {
+		return nil
+	}
+	return err
+}
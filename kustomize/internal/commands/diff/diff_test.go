@@ -0,0 +1,119 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestRunDiff(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+namePrefix: p-
+resources:
+- cm.yaml
+`))
+	fSys.WriteFile("/app/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+
+	for _, c := range []struct {
+		exitCode   string
+		serverSide bool
+		expected   string
+		err        string
+	}{
+		{exitCode: "1", serverSide: true, expected: `args: --context prod diff -f - --server-side --field-manager kustomize
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: p-cm
+`},
+		{exitCode: "0", expected: `args: --context prod diff -f -
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: p-cm
+`},
+		{exitCode: "2", err: "running "},
+	} {
+		bin, cleanup := testutils.WriteFakeKubectl(t, c.exitCode)
+		defer cleanup()
+		o := diffOptions{serverSide: c.serverSide, fieldManager: "kustomize"}
+		o.kubectl.Kubectl = bin
+		o.kubectl.Context = "prod"
+		if err := o.Validate([]string{"/app"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out, errOut bytes.Buffer
+		err := o.RunDiff(&out, &errOut, fSys,
+			validators.MakeFakeValidator(), rf, transformer.NewFactoryImpl())
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("expected error %s, got %v", c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.String() != c.expected {
+			t.Fatalf("expected\n%s\ngot\n%s", c.expected, out.String())
+		}
+	}
+}
+
+func TestDiffTakesBuildFlags(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+resources:
+- ../shared/cm.yaml
+`))
+	fSys.WriteFile("/shared/cm.yaml", []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`))
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), transformer.NewFactoryImpl())
+	bin, cleanup := testutils.WriteFakeKubectl(t, "0")
+	defer cleanup()
+
+	var out bytes.Buffer
+	cmd := NewCmdDiff(&out, fSys, validators.MakeFakeValidator(),
+		rf, transformer.NewFactoryImpl())
+	cmd.SetArgs([]string{"/app", "--kubectl", bin})
+	if err := cmd.Execute(); err == nil ||
+		!strings.Contains(err.Error(), "security") {
+		t.Fatalf("expected a load restriction error, got %v", err)
+	}
+
+	out.Reset()
+	cmd = NewCmdDiff(&out, fSys, validators.MakeFakeValidator(),
+		rf, transformer.NewFactoryImpl())
+	cmd.SetArgs([]string{
+		"/app", "--kubectl", bin, "--load-restrictor", "LoadRestrictionsNone"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "name: cm") {
+		t.Fatalf("expected the built ConfigMap, got\n%s", out.String())
+	}
+}
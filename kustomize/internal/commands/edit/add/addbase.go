@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 type addBaseOptions struct {
@@ -73,10 +74,10 @@ func (o *addBaseOptions) RunAddBase(fSys fs.FileSystem) error {
 		if !fSys.Exists(path) {
 			return errors.New(path + " does not exist")
 		}
-		if kustfile.StringInSlice(path, m.Resources) {
+		if kustfile.StringInSlice(path, m.ResourcePaths()) {
 			return fmt.Errorf("base %s already in kustomization file", path)
 		}
-		m.Resources = append(m.Resources, path)
+		m.Resources = append(m.Resources, types.ResourceEntry{Path: path})
 
 	}
 
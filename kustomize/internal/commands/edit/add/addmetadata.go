@@ -124,9 +124,12 @@ func (o *addMetadataOptions) validateAndParse(args []string) error {
 
 func (o *addMetadataOptions) addAnnotations(m *types.Kustomization) error {
 	if m.CommonAnnotations == nil {
-		m.CommonAnnotations = make(map[string]string)
+		m.CommonAnnotations = &types.CommonAnnotations{}
 	}
-	return o.writeToMap(m.CommonAnnotations, annotation)
+	if m.CommonAnnotations.Pairs == nil {
+		m.CommonAnnotations.Pairs = make(map[string]string)
+	}
+	return o.writeToMap(m.CommonAnnotations.Pairs, annotation)
 }
 
 func (o *addMetadataOptions) addLabels(m *types.Kustomization) error {
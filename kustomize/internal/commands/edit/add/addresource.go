@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/util"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 type addResourceOptions struct {
@@ -76,11 +77,12 @@ func (o *addResourceOptions) RunAddResource(fSys fs.FileSystem) error {
 	}
 
 	for _, resource := range resources {
-		if kustfile.StringInSlice(resource, m.Resources) {
+		if kustfile.StringInSlice(resource, m.ResourcePaths()) {
 			log.Printf("resource %s already in kustomization file", resource)
 			continue
 		}
-		m.Resources = append(m.Resources, resource)
+		m.Resources = append(
+			m.Resources, types.ResourceEntry{Path: resource})
 	}
 
 	return mf.Write(m)
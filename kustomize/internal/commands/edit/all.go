@@ -37,7 +37,7 @@ func NewCmdEdit(
 	c.AddCommand(
 		add.NewCmdAdd(fSys, loader.NewFileLoaderAtCwd(v, fSys), kf),
 		set.NewCmdSet(fSys, v),
-		fix.NewCmdFix(fSys),
+		fix.NewCmdFix(fSys, kf),
 		remove.NewCmdRemove(fSys, loader.NewFileLoaderAtCwd(v, fSys)),
 	)
 	return c
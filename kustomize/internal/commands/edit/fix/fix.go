@@ -4,13 +4,19 @@
 package fix
 
 import (
+	"log"
+
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 )
 
+const flagTopLevel = "top-level"
+
 // NewCmdFix returns an instance of 'fix' subcommand.
-func NewCmdFix(fSys fs.FileSystem) *cobra.Command {
+func NewCmdFix(fSys fs.FileSystem, kf ifc.KunstructuredFactory) *cobra.Command {
+	var topLevel bool
 	cmd := &cobra.Command{
 		Use:   "fix",
 		Short: "Fix the missing fields in kustomization file",
@@ -19,16 +25,26 @@ func NewCmdFix(fSys fs.FileSystem) *cobra.Command {
 	# Fix the missing and deprecated fields in kustomization file
 	kustomize edit fix
 
+	# Also convert vars to replacements where possible, in a
+	# kustomization that no other kustomization includes
+	kustomize edit fix --top-level
+
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunFix(fSys)
+			return RunFix(fSys, kf, topLevel)
 		},
 	}
+	cmd.Flags().BoolVar(
+		&topLevel, flagTopLevel, false,
+		"If set, no other kustomization includes this one, "+
+			"so vars that only its own files refer to "+
+			"may be converted to replacements.")
 	return cmd
 }
 
 // RunFix runs `fix` command
-func RunFix(fSys fs.FileSystem) error {
+func RunFix(
+	fSys fs.FileSystem, kf ifc.KunstructuredFactory, topLevel bool) error {
 	mf, err := kustfile.NewKustomizationFile(fSys)
 	if err != nil {
 		return err
@@ -39,5 +55,13 @@ func RunFix(fSys fs.FileSystem) error {
 		return err
 	}
 
+	warnings, err := migrateVars(fSys, kf, m, topLevel)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		log.Printf("warning: %s", w)
+	}
+
 	return mf.Write(m)
 }
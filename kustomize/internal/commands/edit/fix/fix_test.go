@@ -4,18 +4,24 @@
 package fix
 
 import (
+	"bytes"
+	"log"
+	"os"
 	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/testutils"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 )
 
+var factory = kunstruct.NewKunstructuredFactoryImpl()
+
 func TestFix(t *testing.T) {
 	fSys := fs.MakeFsInMemory()
 	testutils.WriteTestKustomizationWith(fSys, []byte(`nameprefix: some-prefix-`))
 
-	cmd := NewCmdFix(fSys)
+	cmd := NewCmdFix(fSys, factory)
 	err := cmd.RunE(cmd, nil)
 	if err != nil {
 		t.Errorf("unexpected cmd error: %v", err)
@@ -31,3 +37,239 @@ func TestFix(t *testing.T) {
 		t.Errorf("expected kind in kustomization")
 	}
 }
+
+func TestFixVars(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	testutils.WriteTestKustomizationWith(fSys, []byte(`
+resources:
+- statefulset.yaml
+patchesStrategicMerge:
+- replicas.yaml
+vars:
+- name: SERVICE_NAME
+  objref:
+    kind: Service
+    name: cassandra
+    apiVersion: v1
+- name: IMAGE
+  objref:
+    kind: Deployment
+    name: web
+    apiVersion: apps/v1
+  fieldref:
+    fieldpath: spec.template.spec.containers[0].image
+- name: ARG
+  objref:
+    kind: Service
+    name: cassandra
+    apiVersion: v1
+- name: LOG_LEVEL
+  literal: debug
+- name: UNUSED
+  objref:
+    kind: Service
+    name: cassandra
+    apiVersion: v1
+- name: REPLICAS
+  objref:
+    kind: ConfigMap
+    name: scale
+    apiVersion: v1
+  fieldref:
+    fieldPath: data.replicas
+`))
+	fSys.WriteFile("replicas.yaml", []byte(`
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: cassandra
+  annotations:
+    replicas: $(REPLICAS)
+`))
+	fSys.WriteFile("statefulset.yaml", []byte(`
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: cassandra
+  annotations:
+    image: $(IMAGE)
+    scale: $(REPLICAS)
+    url: http://$(SERVICE_NAME):9042/
+spec:
+  serviceName: $(SERVICE_NAME)
+  template:
+    spec:
+      containers:
+      - name: cassandra
+        args:
+        - --log-level=$(LOG_LEVEL)
+        - --peer=$(ARG)
+`))
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	cmd := NewCmdFix(fSys, factory)
+	cmd.Flags().Set(flagTopLevel, "true")
+	err := cmd.RunE(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected cmd error: %v", err)
+	}
+	content, err := testutils.ReadTestKustomization(fSys)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	expected := `
+resources:
+- statefulset.yaml
+patchesStrategicMerge:
+- replicas.yaml
+vars:
+- fieldref: {}
+  name: ARG
+  objref:
+    apiVersion: v1
+    kind: Service
+    name: cassandra
+- fieldref: {}
+  literal: debug
+  name: LOG_LEVEL
+  objref:
+    name: ""
+- fieldref: {}
+  name: UNUSED
+  objref:
+    apiVersion: v1
+    kind: Service
+    name: cassandra
+- fieldref:
+    fieldPath: data.replicas
+  name: REPLICAS
+  objref:
+    apiVersion: v1
+    kind: ConfigMap
+    name: scale
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+replacements:
+- source:
+    kind: Service
+    name: cassandra
+    version: v1
+  targets:
+  - fieldPaths:
+    - metadata.annotations.url
+    options:
+      regex: \$\(SERVICE_NAME\)
+    select:
+      kind: StatefulSet
+      name: cassandra
+  - fieldPaths:
+    - spec.serviceName
+    select:
+      kind: StatefulSet
+      name: cassandra
+- source:
+    fieldPath: spec.template.spec.containers[0].image
+    group: apps
+    kind: Deployment
+    name: web
+    version: v1
+  targets:
+  - fieldPaths:
+    - metadata.annotations.image
+    select:
+      kind: StatefulSet
+      name: cassandra
+`
+	if string(content) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+	for _, warning := range []string{
+		"warning: not converting var ARG to a replacement, " +
+			"StatefulSet cassandra refers to it in " +
+			"spec.template.spec.containers.args, in a list",
+		"warning: not converting var LOG_LEVEL to a replacement, " +
+			"it doesn't refer to an object",
+		"warning: not converting var UNUSED to a replacement, " +
+			"it isn't referred to in the resource files of the kustomization",
+		"warning: not converting var REPLICAS to a replacement, " +
+			"the patch replicas.yaml refers to it",
+	} {
+		if !strings.Contains(buf.String(), warning) {
+			t.Errorf("expected log containing '%s', got '%s'", warning, buf.String())
+		}
+	}
+}
+
+func TestFixVarsUnscanned(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		kustomization string
+		topLevel      bool
+		warning       string
+	}{
+		{
+			name: "maybe included",
+			kustomization: `
+resources:
+- service.yaml
+`,
+			warning: "kustomizations including this one may refer to them",
+		},
+		{
+			name: "base",
+			kustomization: `
+resources:
+- service.yaml
+- ../base
+`,
+			topLevel: true,
+			warning:  "the base ../base may refer to them",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fSys := fs.MakeFsInMemory()
+			kustomization := tc.kustomization + `vars:
+- name: SERVICE_NAME
+  objref:
+    apiVersion: v1
+    kind: Service
+    name: cassandra
+`
+			testutils.WriteTestKustomizationWith(fSys, []byte(kustomization))
+			fSys.WriteFile("service.yaml", []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: cassandra
+  annotations:
+    url: $(SERVICE_NAME)
+`))
+			var buf bytes.Buffer
+			log.SetOutput(&buf)
+			defer func() {
+				log.SetOutput(os.Stderr)
+			}()
+			cmd := NewCmdFix(fSys, factory)
+			if tc.topLevel {
+				cmd.Flags().Set(flagTopLevel, "true")
+			}
+			if err := cmd.RunE(cmd, nil); err != nil {
+				t.Fatalf("unexpected cmd error: %v", err)
+			}
+			content, err := testutils.ReadTestKustomization(fSys)
+			if err != nil {
+				t.Fatalf("unexpected read error: %v", err)
+			}
+			if !strings.Contains(string(content), "name: SERVICE_NAME") ||
+				strings.Contains(string(content), "replacements:") {
+				t.Fatalf("expected the var kept, got:\n%s", content)
+			}
+			if !strings.Contains(buf.String(), tc.warning) {
+				t.Fatalf("expected log containing '%s', got '%s'", tc.warning, buf.String())
+			}
+		})
+	}
+}
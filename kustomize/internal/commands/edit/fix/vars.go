@@ -0,0 +1,343 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fix
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// varRef is a field of a resource referring to a var.
+type varRef struct {
+	kind      string
+	name      string
+	namespace string
+	path      string
+	// whole is true if the field is just the reference,
+	// e.g. "$(FOO)", rather than containing it.
+	whole bool
+	// problem, if not empty, says why a replacement
+	// can't address the reference.
+	problem string
+}
+
+// migrateVars replaces the vars of the kustomization
+// by replacements copying the same fields into the
+// fields of its resource files referring to them.
+// Vars are converted only if every place that could
+// refer to them was scanned: the kustomization mustn't
+// be included by others, unless topLevel says it isn't,
+// nor hold bases or plugins, whose resources aren't
+// scanned.  It leaves alone vars it can't convert,
+// returning a warning saying why.
+func migrateVars(
+	fSys fs.FileSystem, kf ifc.KunstructuredFactory,
+	m *types.Kustomization, topLevel bool) ([]string, error) {
+	if len(m.Vars) == 0 {
+		return nil, nil
+	}
+	if !topLevel {
+		return []string{fmt.Sprintf(
+			"not converting vars to replacements, kustomizations "+
+				"including this one may refer to them; "+
+				"with none including it, pass --%s", flagTopLevel)}, nil
+	}
+	if unscanned := unscannedSources(fSys, m); len(unscanned) > 0 {
+		return []string{fmt.Sprintf(
+			"not converting vars to replacements, %s may refer to them",
+			strings.Join(unscanned, ", "))}, nil
+	}
+	refs, err := findVarRefs(fSys, kf, m)
+	if err != nil {
+		return nil, err
+	}
+	patchRefs, err := findPatchVarRefs(fSys, m)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	var vars []types.Var
+	for _, v := range m.Vars {
+		r, err := replacementOf(v, refs[v.Name])
+		if err == nil && patchRefs[v.Name] != "" {
+			err = fmt.Errorf("the patch %s refers to it", patchRefs[v.Name])
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf(
+				"not converting var %s to a replacement, %v", v.Name, err))
+			vars = append(vars, v)
+			continue
+		}
+		m.Replacements = append(m.Replacements, r)
+	}
+	m.Vars = vars
+	return warnings, nil
+}
+
+// unscannedSources describes the sources of resources
+// of the kustomization that findVarRefs doesn't scan.
+func unscannedSources(fSys fs.FileSystem, m *types.Kustomization) []string {
+	var result []string
+	for _, e := range m.Resources {
+		if !loader.IsGlob(e.Path) &&
+			(!fSys.Exists(e.Path) || fSys.IsDir(e.Path)) {
+			result = append(result, "the base "+e.Path)
+		}
+	}
+	for _, b := range m.Bases {
+		result = append(result, "the base "+b)
+	}
+	for _, c := range m.Components {
+		result = append(result, "the component "+c)
+	}
+	for _, c := range m.HelmCharts {
+		result = append(result, "the helm chart "+c.Name)
+	}
+	if len(m.Generators) > 0 {
+		result = append(result, "the generators")
+	}
+	if len(m.Transformers) > 0 {
+		result = append(result, "the transformers")
+	}
+	return result
+}
+
+// resourceFiles returns the paths of the resource
+// files of the kustomization, expanding globs.
+func resourceFiles(
+	fSys fs.FileSystem, m *types.Kustomization) ([]string, error) {
+	var result []string
+	for _, e := range m.Resources {
+		if !loader.IsGlob(e.Path) {
+			if fSys.Exists(e.Path) && !fSys.IsDir(e.Path) {
+				result = append(result, e.Path)
+			}
+			continue
+		}
+		matches, err := fSys.Glob(e.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range matches {
+			if !fSys.IsDir(p) && !isKustomizationFile(p) {
+				result = append(result, p)
+			}
+		}
+	}
+	return result, nil
+}
+
+func isKustomizationFile(path string) bool {
+	for _, n := range pgmconfig.RecognizedKustomizationFileNames() {
+		if filepath.Base(path) == n {
+			return true
+		}
+	}
+	return false
+}
+
+// findPatchVarRefs returns, by var name, a patch
+// of the kustomization referring to the var.
+func findPatchVarRefs(
+	fSys fs.FileSystem, m *types.Kustomization) (map[string]string, error) {
+	patches := make(map[string]string)
+	add := func(path, inline string) error {
+		if path == "" {
+			patches["inlined in the kustomization"] += inline
+			return nil
+		}
+		content, err := fSys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		patches[path] = string(content)
+		return nil
+	}
+	for _, p := range m.PatchesStrategicMerge {
+		// A strategic merge patch is a file, or inline.
+		path, inline := string(p), ""
+		if !fSys.Exists(path) {
+			path, inline = "", string(p)
+		}
+		if err := add(path, inline); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range m.PatchesJson6902 {
+		if err := add(p.Path, p.Patch); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range m.Patches {
+		if err := add(p.Path, p.Patch); err != nil {
+			return nil, err
+		}
+	}
+	refs := make(map[string]string)
+	for _, v := range m.Vars {
+		for name, content := range patches {
+			if strings.Contains(content, "$("+v.Name+")") &&
+				(refs[v.Name] == "" || name < refs[v.Name]) {
+				refs[v.Name] = name
+			}
+		}
+	}
+	return refs, nil
+}
+
+// replacementOf returns the replacement of the var,
+// or an error if the var can't be converted.
+func replacementOf(v types.Var, refs []varRef) (types.Replacement, error) {
+	if !v.RefersToObject() {
+		return types.Replacement{}, fmt.Errorf(
+			"it doesn't refer to an object")
+	}
+	if len(refs) == 0 {
+		return types.Replacement{}, fmt.Errorf(
+			"it isn't referred to in the resource files of the kustomization")
+	}
+	for _, ref := range refs {
+		if ref.problem != "" {
+			return types.Replacement{}, fmt.Errorf(
+				"%s %s refers to it in %s, %s",
+				ref.kind, ref.name, ref.path, ref.problem)
+		}
+	}
+	source := &types.ReplacementSource{
+		Selector: types.Selector{
+			Gvk:       v.ObjRef.GVK(),
+			Name:      v.ObjRef.Name,
+			Namespace: v.ObjRef.Namespace,
+		},
+	}
+	if v.FieldRef.FieldPath != types.DefaultReplacementFieldPath {
+		source.FieldPath = v.FieldRef.FieldPath
+	}
+	r := types.Replacement{Source: source}
+	for _, ref := range refs {
+		t := types.ReplacementTarget{
+			Select: &types.Selector{
+				Gvk:       gvk.Gvk{Kind: ref.kind},
+				Name:      ref.name,
+				Namespace: ref.namespace,
+			},
+		}
+		if !ref.whole {
+			t.Options = &types.ReplacementOptions{
+				Regex: regexp.QuoteMeta("$(" + v.Name + ")"),
+			}
+		}
+		r.Targets = addTargetPath(r.Targets, t, ref.path)
+	}
+	return r, nil
+}
+
+// addTargetPath adds the path to the target like
+// t, appending t if there's no such target yet.
+func addTargetPath(
+	targets []types.ReplacementTarget,
+	t types.ReplacementTarget, path string) []types.ReplacementTarget {
+	for i := range targets {
+		if *targets[i].Select == *t.Select &&
+			(targets[i].Options == nil) == (t.Options == nil) {
+			targets[i].FieldPaths = append(targets[i].FieldPaths, path)
+			return targets
+		}
+	}
+	t.FieldPaths = []string{path}
+	return append(targets, t)
+}
+
+// findVarRefs returns, by var name, the fields of the
+// resource files of the kustomization referring to its
+// vars.  Resources that aren't files, e.g. bases, are
+// left out.
+func findVarRefs(
+	fSys fs.FileSystem, kf ifc.KunstructuredFactory,
+	m *types.Kustomization) (map[string][]varRef, error) {
+	files, err := resourceFiles(fSys, m)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string][]varRef)
+	for _, path := range files {
+		content, err := fSys.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		objects, err := kf.SliceFromBytes(content)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %v", path, err)
+		}
+		for _, obj := range objects {
+			for _, v := range m.Vars {
+				for _, ref := range walkVarRefs(
+					obj.Map(), nil, "", v.Name) {
+					ref.kind = obj.GetKind()
+					ref.name = obj.GetName()
+					ref.namespace, _ = obj.GetString("metadata.namespace")
+					refs[v.Name] = append(refs[v.Name], ref)
+				}
+			}
+		}
+	}
+	return refs, nil
+}
+
+// walkVarRefs returns the string fields, at or
+// under the path, referring to the named var.
+// The problem, if not empty, says why replacements
+// can't address the fields under the path.
+func walkVarRefs(
+	in interface{}, path []string, problem string, name string) []varRef {
+	var refs []varRef
+	switch v := in.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := problem
+			if p == "" && strings.Contains(k, ".") {
+				p = "under a key containing a dot"
+			}
+			refs = append(refs, walkVarRefs(
+				v[k], append(path[:len(path):len(path)], k),
+				p, name)...)
+		}
+	case []interface{}:
+		for _, e := range v {
+			p := problem
+			if p == "" {
+				p = "in a list"
+			}
+			refs = append(refs, walkVarRefs(e, path, p, name)...)
+		}
+	case string:
+		placeholder := "$(" + name + ")"
+		if n := strings.Count(v, placeholder); n > 0 {
+			if problem == "" && n > 1 {
+				problem = "more than once"
+			}
+			refs = append(refs, varRef{
+				path:    strings.Join(path, "."),
+				whole:   v == placeholder,
+				problem: problem,
+			})
+		}
+	}
+	return refs
+}
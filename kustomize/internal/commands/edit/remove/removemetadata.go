@@ -136,10 +136,21 @@ func (o *removeMetadataOptions) convertToArray(arg string) ([]string, error) {
 }
 
 func (o *removeMetadataOptions) removeAnnotations(m *types.Kustomization) error {
-	if m.CommonAnnotations == nil && !o.ignore {
-		return fmt.Errorf("commonAnnotations is not defined in kustomization file")
+	if m.CommonAnnotations == nil {
+		if !o.ignore {
+			return fmt.Errorf("commonAnnotations is not defined in kustomization file")
+		}
+		return nil
+	}
+	err := o.removeFromMap(m.CommonAnnotations.Pairs, annotation)
+	if err != nil {
+		return err
+	}
+	a := m.CommonAnnotations
+	if len(a.Pairs) == 0 && len(a.Include) == 0 && len(a.Exclude) == 0 {
+		m.CommonAnnotations = nil
 	}
-	return o.removeFromMap(m.CommonAnnotations, annotation)
+	return nil
 }
 
 func (o *removeMetadataOptions) removeLabels(m *types.Kustomization) error {
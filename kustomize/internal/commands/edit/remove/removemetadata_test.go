@@ -59,12 +59,12 @@ func TestRemoveAnnotation(t *testing.T) {
 		t.Errorf("expected not exist in kustomization file error")
 	}
 
-	_, exists := m.CommonAnnotations["annotation1"]
+	_, exists := m.CommonAnnotations.Pairs["annotation1"]
 	if exists {
 		t.Errorf("annotation1 must be deleted")
 	}
 
-	_, exists = m.CommonAnnotations["annotation2"]
+	_, exists = m.CommonAnnotations.Pairs["annotation2"]
 	if !exists {
 		t.Errorf("annotation2 must exist")
 	}
@@ -166,11 +166,8 @@ func TestRemoveAnnotationMultipleArgs(t *testing.T) {
 	}
 
 	m := readKustomizationFS(t, fSys)
-	splitArgs := strings.Split(args[0], ",")
-	for _, k := range splitArgs {
-		if _, exist := m.CommonAnnotations[k]; exist {
-			t.Errorf("%s must be deleted", k)
-		}
+	if m.CommonAnnotations != nil {
+		t.Errorf("commonAnnotations must be deleted, got %v", m.CommonAnnotations)
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 type removeResourceOptions struct {
@@ -72,7 +73,7 @@ func (o *removeResourceOptions) RunRemoveResource(fSys fs.FileSystem) error {
 		return err
 	}
 
-	resources, err := globPatterns(m.Resources, o.resourceFilePaths)
+	resources, err := globPatterns(m.ResourcePaths(), o.resourceFilePaths)
 	if err != nil {
 		return err
 	}
@@ -81,9 +82,9 @@ func (o *removeResourceOptions) RunRemoveResource(fSys fs.FileSystem) error {
 		return nil
 	}
 
-	newResources := make([]string, 0, len(m.Resources))
+	newResources := make([]types.ResourceEntry, 0, len(m.Resources))
 	for _, resource := range m.Resources {
-		if kustfile.StringInSlice(resource, resources) {
+		if kustfile.StringInSlice(resource.Path, resources) {
 			continue
 		}
 		newResources = append(newResources, resource)
@@ -0,0 +1,125 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kubectl runs kubectl on the resources a
+// kustomization builds, for the commands that act
+// on a live cluster.
+package kubectl
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/build"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+const (
+	defaultKubectl = "kubectl"
+
+	// DefaultFieldManager is the manager of the
+	// fields of kustomize's server-side applies.
+	DefaultFieldManager = "kustomize"
+)
+
+// Options holds how to reach the cluster: the kubectl
+// to run, and the kubeconfig and context it uses.
+type Options struct {
+	Kubectl    string
+	Kubeconfig string
+	Context    string
+}
+
+// AddFlags adds the flags of the options to the set.
+func (o *Options) AddFlags(set *pflag.FlagSet) {
+	set.StringVar(
+		&o.Kubectl, "kubectl", defaultKubectl,
+		"The kubectl program to reach the cluster with.")
+	set.StringVar(
+		&o.Kubeconfig, "kubeconfig", "",
+		"The kubeconfig file of the cluster; "+
+			"if unset, kubectl's default.")
+	set.StringVar(
+		&o.Context, "context", "",
+		"The kubeconfig context of the cluster; "+
+			"if unset, the current context.")
+}
+
+// Run runs kubectl with the args, and the resources
+// on its stdin, writing its output to out.  It returns
+// kubectl's exit code if it exits with one; any other
+// failure to run it is an error.
+func (o *Options) Run(
+	out io.Writer, resources []byte, args ...string) (int, error) {
+	kubectl := o.Kubectl
+	if kubectl == "" {
+		kubectl = defaultKubectl
+	}
+	var global []string
+	if o.Kubeconfig != "" {
+		global = append(global, "--kubeconfig", o.Kubeconfig)
+	}
+	if o.Context != "" {
+		global = append(global, "--context", o.Context)
+	}
+	args = append(global, args...)
+	cmd := exec.Command(kubectl, args...)
+	cmd.Stdin = bytes.NewReader(resources)
+	cmd.Stdout = out
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), errors.Errorf(
+			"running %s %v: %s", kubectl, args, stderr.String())
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "running %s %v", kubectl, args)
+	}
+	return 0, nil
+}
+
+// Builder builds the kustomization whose resources
+// kubectl acts on, as kustomize build, given the same
+// flags shaping the build, would.
+type Builder struct {
+	options      *build.Options
+	pluginConfig *types.PluginConfig
+}
+
+// AddFlags adds the flags of kustomize build
+// shaping the build to the set.
+func (b *Builder) AddFlags(set *pflag.FlagSet) {
+	b.options, b.pluginConfig = build.AddFlags(set)
+}
+
+// Validate validates the flags, for building
+// the kustomization at the path.
+func (b *Builder) Validate(path string) error {
+	if b.options == nil {
+		b.options = &build.Options{}
+		b.pluginConfig = plugins.DefaultPluginConfig()
+	}
+	return b.options.Validate([]string{path})
+}
+
+// Build builds the kustomization, returning the output.
+func (b *Builder) Build(
+	errOut io.Writer, v ifc.Validator, fSys fs.FileSystem,
+	rf *resmap.Factory, ptf resmap.PatchFactory) ([]byte, error) {
+	var out bytes.Buffer
+	err := b.options.RunBuild(
+		&out, errOut, v, fSys, rf, ptf,
+		plugins.NewLoader(b.pluginConfig, rf))
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
@@ -39,25 +40,53 @@ func determineFieldOrder() []string {
 
 	ordered := []string{
 		"Resources",
+		"ExcludeResources",
 		"Bases",
+		"MergeStrategy",
+		"AllowIdConflicts",
+		"FetchOptions",
+		"Components",
 		"NamePrefix",
 		"NameSuffix",
+		"NameOptions",
+		"BuildMetadata",
 		"Namespace",
+		"NamespaceOptions",
+		"CreateNamespace",
 		"Crds",
+		"OpenAPI",
 		"CommonLabels",
+		"Labels",
 		"CommonAnnotations",
 		"PatchesStrategicMerge",
 		"PatchesJson6902",
 		"Patches",
+		"Sidecars",
+		"DefaultResources",
+		"DefaultSecurityContext",
+		"Scheduling",
+		"KubeVersion",
 		"ConfigMapGenerator",
 		"SecretGenerator",
+		"VaultSecretGenerator",
 		"GeneratorOptions",
+		"HelmGlobals",
+		"HelmCharts",
 		"Vars",
+		"VarOptions",
+		"Replacements",
+		"Exports",
+		"Parameters",
+		"Settings",
+		"EnvSubst",
 		"Images",
+		"ImageRegistryRewrite",
 		"Replicas",
 		"Configurations",
 		"Generators",
 		"Transformers",
+		"Validators",
+		"SortOptions",
 		"Inventory",
 	}
 
@@ -101,6 +130,7 @@ func squash(x [][]byte) []byte {
 }
 
 type kustomizationFile struct {
+	dir            string
 	path           string
 	fSys           fs.FileSystem
 	originalFields []*commentedField
@@ -116,10 +146,23 @@ func NewKustomizationFile(fSys fs.FileSystem) (*kustomizationFile, error) { // n
 	return mf, nil
 }
 
+// NewKustomizationFileInDir returns a new instance
+// for the kustomization file in the given directory.
+func NewKustomizationFileInDir(
+	fSys fs.FileSystem, dir string) (*kustomizationFile, error) { // nolint
+	mf := &kustomizationFile{fSys: fSys, dir: dir}
+	err := mf.validate()
+	if err != nil {
+		return nil, err
+	}
+	return mf, nil
+}
+
 func (mf *kustomizationFile) validate() error {
 	match := 0
 	var path []string
 	for _, kfilename := range pgmconfig.RecognizedKustomizationFileNames() {
+		kfilename = filepath.Join(mf.dir, kfilename)
 		if mf.fSys.Exists(kfilename) {
 			match += 1
 			path = append(path, kfilename)
@@ -287,9 +330,11 @@ func marshalField(field string, kustomization *types.Kustomization) ([]byte, err
 }
 
 func isEmpty(v reflect.Value) bool {
-	// If v is a pointer type
-	if v.Type().Kind() == reflect.Ptr {
+	switch v.Type().Kind() {
+	case reflect.Ptr:
 		return v.IsNil()
+	case reflect.Bool:
+		return !v.Bool()
 	}
 	return v.Len() == 0
 }
@@ -20,25 +20,53 @@ func TestFieldOrder(t *testing.T) {
 		"APIVersion",
 		"Kind",
 		"Resources",
+		"ExcludeResources",
 		"Bases",
+		"MergeStrategy",
+		"AllowIdConflicts",
+		"FetchOptions",
+		"Components",
 		"NamePrefix",
 		"NameSuffix",
+		"NameOptions",
+		"BuildMetadata",
 		"Namespace",
+		"NamespaceOptions",
+		"CreateNamespace",
 		"Crds",
+		"OpenAPI",
 		"CommonLabels",
+		"Labels",
 		"CommonAnnotations",
 		"PatchesStrategicMerge",
 		"PatchesJson6902",
 		"Patches",
+		"Sidecars",
+		"DefaultResources",
+		"DefaultSecurityContext",
+		"Scheduling",
+		"KubeVersion",
 		"ConfigMapGenerator",
 		"SecretGenerator",
+		"VaultSecretGenerator",
 		"GeneratorOptions",
+		"HelmGlobals",
+		"HelmCharts",
 		"Vars",
+		"VarOptions",
+		"Replacements",
+		"Exports",
+		"Parameters",
+		"Settings",
+		"EnvSubst",
 		"Images",
+		"ImageRegistryRewrite",
 		"Replicas",
 		"Configurations",
 		"Generators",
 		"Transformers",
+		"Validators",
+		"SortOptions",
 		"Inventory",
 	}
 	actual := determineFieldOrder()
@@ -0,0 +1,344 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package localize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kustomize/v3/internal/commands/kustfile"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// localizedDir is the directory, at the top of the
+// destination, holding what was fetched.
+const localizedDir = "localized"
+
+type localizeOptions struct {
+	target string
+	scope  string
+	dst    string
+}
+
+var examples = `
+To make a copy of the kustomization in 'overlays/prod',
+and of the local files and bases it refers to, below the
+current directory, holding all its remote bases and files
+too, for use where they can't be fetched, run
+
+  kustomize localize overlays/prod /tmp/prod --scope .
+
+The copy of 'overlays/prod' is then '/tmp/prod/overlays/prod'.
+Remote bases and files go below '/tmp/prod/localized', and
+references to them are rewritten to refer to the copies.
+The scope defaults to the target, and the destination to
+'localized-' followed by the target's name.
+`
+
+// NewCmdLocalize returns an instance of 'localize' subcommand.
+func NewCmdLocalize(
+	out io.Writer, fSys fs.FileSystem, v ifc.Validator) *cobra.Command {
+	var o localizeOptions
+
+	cmd := &cobra.Command{
+		Use:          "localize [target [destination]]",
+		Short:        "Copy a kustomization, vendoring its remote bases and files",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunLocalize(out, fSys, v, loader.CachingCloner())
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.scope, "scope", "",
+		"Directory holding the target and the local files "+
+			"and bases it refers to, which is copied whole.  "+
+			"Defaults to the target.")
+	return cmd
+}
+
+// Validate validates localize command.
+func (o *localizeOptions) Validate(args []string) error {
+	switch len(args) {
+	case 0:
+		o.target = loader.CWD
+	case 1:
+		o.target = args[0]
+	case 2:
+		o.target, o.dst = args[0], args[1]
+	default:
+		return errors.New("specify a target and, optionally, a destination")
+	}
+	if loader.IsRemote(o.target) {
+		return fmt.Errorf("target %s must be a local directory", o.target)
+	}
+	if o.scope == "" {
+		o.scope = o.target
+	}
+	return nil
+}
+
+// RunLocalize runs localize command.
+func (o *localizeOptions) RunLocalize(
+	out io.Writer, fSys fs.FileSystem,
+	v ifc.Validator, cloner git.Cloner) error {
+	target, _, err := fSys.CleanedAbs(o.target)
+	if err != nil {
+		return err
+	}
+	scope, _, err := fSys.CleanedAbs(o.scope)
+	if err != nil {
+		return err
+	}
+	if !target.HasPrefix(scope) {
+		return fmt.Errorf("target %s is outside scope %s", target, scope)
+	}
+	dst := o.dst
+	if dst == "" {
+		dst = "localized-" + filepath.Base(target.String())
+	}
+	if dst, err = filepath.Abs(dst); err != nil {
+		return err
+	}
+	if fSys.Exists(dst) {
+		return fmt.Errorf("destination %s already exists", dst)
+	}
+	if fs.ConfirmedDir(dst).HasPrefix(scope) {
+		return fmt.Errorf("destination %s is inside scope %s", dst, scope)
+	}
+	ldr, err := loader.NewLoaderUsingCloner(
+		loader.RestrictionNone, v, target.String(), fSys, cloner)
+	if err != nil {
+		return err
+	}
+	defer ldr.Cleanup()
+
+	l := &localizer{fSys: fSys, dst: dst, done: make(map[string]bool)}
+	if err = l.copyTree(scope.String(), dst); err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(scope.String(), target.String())
+	if err != nil {
+		return err
+	}
+	dstTarget := filepath.Join(dst, rel)
+	if err = l.localize(ldr, dstTarget); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "localized %s to %s\n", target, dstTarget)
+	return err
+}
+
+// localizer copies kustomizations into dst,
+// fetching their remote bases and files.
+type localizer struct {
+	fSys fs.FileSystem
+	dst  string
+	// done holds the kustomization directories localized.
+	done map[string]bool
+}
+
+// localize rewrites the references of the kustomization copied
+// to dir, whose original ldr loads, fetching remote ones.
+func (l *localizer) localize(ldr ifc.Loader, dir string) error {
+	if l.done[dir] {
+		return nil
+	}
+	l.done[dir] = true
+	kf, err := kustfile.NewKustomizationFileInDir(l.fSys, dir)
+	if err != nil {
+		return err
+	}
+	k, err := kf.Read()
+	if err != nil {
+		return err
+	}
+	for i, r := range k.Resources {
+		if k.Resources[i].Path, err = l.localizeRef(ldr, dir, r.Path, true); err != nil {
+			return err
+		}
+	}
+	for i, c := range k.Components {
+		if k.Components[i], err = l.localizeRef(ldr, dir, c, true); err != nil {
+			return err
+		}
+	}
+	for i, c := range k.Crds {
+		if k.Crds[i], err = l.localizeRef(ldr, dir, c, false); err != nil {
+			return err
+		}
+	}
+	for i, p := range k.PatchesStrategicMerge {
+		path, err := l.localizeRef(ldr, dir, string(p), false)
+		if err != nil {
+			return err
+		}
+		k.PatchesStrategicMerge[i] = types.PatchStrategicMerge(path)
+	}
+	for i, p := range k.Patches {
+		if p.Path == "" {
+			continue
+		}
+		if k.Patches[i].Path, err = l.localizeRef(ldr, dir, p.Path, false); err != nil {
+			return err
+		}
+	}
+	for i, p := range k.PatchesJson6902 {
+		if p.Path == "" {
+			continue
+		}
+		if k.PatchesJson6902[i].Path, err = l.localizeRef(ldr, dir, p.Path, false); err != nil {
+			return err
+		}
+	}
+	return kf.Write(k)
+}
+
+// localizeRef returns the reference, in the kustomization copied
+// to dir, to what ref refers to, fetching it if it's remote.
+// Like a build, it takes ref to be a base if it can be one.
+func (l *localizer) localizeRef(
+	ldr ifc.Loader, dir, ref string, mayBeBase bool) (string, error) {
+	if !loader.IsRemote(ref) {
+		return ref, l.localizeLocal(ldr, dir, ref, mayBeBase)
+	}
+	if mayBeBase {
+		child, err := ldr.New(ref)
+		if err == nil {
+			defer child.Cleanup()
+			return l.localizeBase(child, dir)
+		}
+	}
+	content, err := ldr.Load(ref)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(l.dst, localizedDir, localName(ref))
+	if err = l.fSys.MkdirAll(filepath.Dir(path)); err != nil {
+		return "", err
+	}
+	if err = l.fSys.WriteFile(path, content); err != nil {
+		return "", err
+	}
+	return refTo(dir, path)
+}
+
+// refTo returns the reference, in the kustomization in dir,
+// to path, separated by slashes, as kustomizations are, so
+// that a copy localized on Windows builds anywhere.
+func refTo(dir, path string) (string, error) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// localizeLocal checks that the local file or base ref
+// refers to was copied, localizing the base.
+func (l *localizer) localizeLocal(
+	ldr ifc.Loader, dir, ref string, mayBeBase bool) error {
+	ref = fs.LocalPath(ref)
+	path := filepath.Join(dir, ref)
+	if filepath.IsAbs(ref) || !strings.HasPrefix(path, l.dst+string(filepath.Separator)) {
+		return fmt.Errorf(
+			"%s, referred to in %s, is outside the scope localized", ref, dir)
+	}
+	if !mayBeBase {
+		return nil
+	}
+	child, err := ldr.New(ref)
+	if err != nil {
+		// A file.
+		return nil
+	}
+	defer child.Cleanup()
+	return l.localize(child, path)
+}
+
+// localizeBase copies the tree the remote base of
+// child was fetched into, once, and localizes the
+// base's copy, returning the reference to it.
+func (l *localizer) localizeBase(child ifc.Loader, dir string) (string, error) {
+	root, url, ok := loader.FetchedTree(child)
+	if !ok {
+		return "", fmt.Errorf("%s isn't a remote base", child.Root())
+	}
+	cleanedRoot, _, err := l.fSys.CleanedAbs(root)
+	if err != nil {
+		return "", err
+	}
+	sub, err := filepath.Rel(cleanedRoot.String(), child.Root())
+	if err != nil {
+		return "", err
+	}
+	tree := filepath.Join(l.dst, localizedDir, localName(url))
+	if !l.fSys.Exists(tree) {
+		if err = l.copyTree(cleanedRoot.String(), tree); err != nil {
+			return "", err
+		}
+	}
+	base := filepath.Join(tree, sub)
+	if err = l.localize(child, base); err != nil {
+		return "", err
+	}
+	return refTo(dir, base)
+}
+
+// copyTree copies the tree at from to to,
+// leaving out git's metadata.
+func (l *localizer) copyTree(from, to string) error {
+	return l.fSys.Walk(from, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if fi.IsDir() {
+			if filepath.Base(path) == ".git" {
+				return filepath.SkipDir
+			}
+			return l.fSys.MkdirAll(target)
+		}
+		content, err := l.fSys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return l.fSys.WriteFile(target, content)
+	})
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// localName returns the path, below the localized
+// directory, of what was fetched from the URL.
+func localName(url string) string {
+	url = strings.SplitN(url, "#", 2)[0]
+	url = strings.Replace(url, "://", "/", 1)
+	var parts []string
+	for _, p := range strings.Split(url, "/") {
+		if p == "" || p == "." || p == ".." {
+			continue
+		}
+		parts = append(parts, unsafeChars.ReplaceAllString(p, "_"))
+	}
+	return filepath.Join(parts...)
+}
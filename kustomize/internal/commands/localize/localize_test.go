@@ -0,0 +1,140 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package localize
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestLocalize(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	for path, content := range map[string]string{
+		"/app/base/kustomization.yaml": `
+resources:
+- deployment.yaml
+`,
+		"/app/base/deployment.yaml": "kind: Deployment\n",
+		"/app/overlay/kustomization.yaml": `
+namePrefix: prod-
+resources:
+- ../base
+- github.com/someOrg/someRepo/web?ref=v1
+`,
+		"/clone/web/kustomization.yaml": `
+resources:
+- ../common
+`,
+		"/clone/common/kustomization.yaml": `
+resources:
+- service.yaml
+`,
+		"/clone/common/service.yaml": "kind: Service\n",
+		"/clone/.git/HEAD":           "ref: refs/heads/master\n",
+	} {
+		fSys.WriteFile(path, []byte(content))
+	}
+	clones := 0
+	cloner := func(repoSpec *git.RepoSpec) error {
+		clones++
+		repoSpec.Dir = "/clone"
+		return nil
+	}
+
+	o := localizeOptions{}
+	if err := o.Validate([]string{"/app/overlay", "/out"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	o.scope = "/app"
+	out := &bytes.Buffer{}
+	err := o.RunLocalize(
+		out, fSys, validators.MakeFakeValidator(), cloner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "localized /app/overlay to /out/overlay\n" || clones != 1 {
+		t.Fatalf("unexpected output %q after %d clones", out.String(), clones)
+	}
+
+	content, err := fSys.ReadFile("/out/overlay/kustomization.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `
+namePrefix: prod-
+resources:
+- ../base
+- ../localized/https/github.com/someOrg/someRepo/v1/web
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+`
+	if string(content) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+	for _, path := range []string{
+		"/out/base/deployment.yaml",
+		"/out/localized/https/github.com/someOrg/someRepo/v1/web/kustomization.yaml",
+		"/out/localized/https/github.com/someOrg/someRepo/v1/common/service.yaml",
+	} {
+		if !fSys.Exists(path) {
+			t.Fatalf("expected %s to exist", path)
+		}
+	}
+	if fSys.Exists("/out/localized/https/github.com/someOrg/someRepo/v1/.git") {
+		t.Fatalf("expected git's metadata to be left out")
+	}
+}
+
+func TestLocalizeErrors(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/base/kustomization.yaml", []byte("resources: []\n"))
+	fSys.WriteFile("/app/overlay/kustomization.yaml", []byte(`
+resources:
+- ../base
+`))
+	fSys.WriteFile("/taken", []byte{})
+	for _, c := range []struct {
+		args     []string
+		scope    string
+		expected string
+	}{
+		{[]string{"/app/overlay", "/out"}, "", "outside the scope localized"},
+		{[]string{"/app/overlay", "/out"}, "/app/base", "outside scope"},
+		{[]string{"/app/overlay", "/app/out"}, "/app", "inside scope"},
+		{[]string{"/app/overlay", "/taken"}, "/app", "already exists"},
+	} {
+		o := localizeOptions{scope: c.scope}
+		if err := o.Validate(c.args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := o.RunLocalize(
+			&bytes.Buffer{}, fSys, validators.MakeFakeValidator(), nil)
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Fatalf("%v: expected error '%s', got %v", c.args, c.expected, err)
+		}
+	}
+	o := localizeOptions{}
+	if err := o.Validate([]string{"github.com/someOrg/someRepo"}); err == nil {
+		t.Fatalf("expected an error for a remote target")
+	}
+}
+
+func TestLocalName(t *testing.T) {
+	for url, expected := range map[string]string{
+		"https://example.com/cm.yaml":                  "https/example.com/cm.yaml",
+		"https://example.com/web.tar.gz#sha256=9f86d0": "https/example.com/web.tar.gz",
+		"s3://acme-bases/rendered/web":                 "s3/acme-bases/rendered/web",
+		"git@github.com:someOrg/someRepo/v1":           "git_github.com_someOrg/someRepo/v1",
+		"https://example.com/../etc/cm.yaml?version=2": "https/example.com/etc/cm.yaml_version_2",
+	} {
+		if actual := localName(url); actual != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, url, actual)
+		}
+	}
+}
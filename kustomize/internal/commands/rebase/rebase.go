@@ -0,0 +1,116 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rebase
+
+import (
+	"errors"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/merge"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+type rebaseOptions struct {
+	oldBasePath string
+	newBasePath string
+	overlayPath string
+	outputPath  string
+}
+
+var examples = `
+To carry the customizations an overlay made to version 1
+of a base over to version 2 of that base, run
+
+  kustomize build base-v1 > old.yaml
+  kustomize build base-v2 > new.yaml
+  kustomize build overlay > overlay.yaml
+  kustomize rebase --old-base old.yaml --new-base new.yaml \
+    --overlay overlay.yaml
+
+The output is a set of strategic merge patches that,
+applied to version 2 of the base, make the overlay's
+customizations.  The command fails if the changes
+between the two versions of the base conflict with
+those customizations.
+`
+
+// NewCmdRebase returns an instance of 'rebase' subcommand.
+func NewCmdRebase(
+	out io.Writer, fSys fs.FileSystem,
+	rf *resmap.Factory, r merge.Rebaser) *cobra.Command {
+	var o rebaseOptions
+
+	cmd := &cobra.Command{
+		Use:          "rebase",
+		Short:        "Rebase overlay customizations onto an updated base",
+		Example:      examples,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunRebase(out, fSys, rf, r)
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.oldBasePath, "old-base", "",
+		"File holding the output of building the old base.")
+	cmd.Flags().StringVar(
+		&o.newBasePath, "new-base", "",
+		"File holding the output of building the new base.")
+	cmd.Flags().StringVar(
+		&o.overlayPath, "overlay", "",
+		"File holding the output of building the overlay of the old base.")
+	cmd.Flags().StringVarP(
+		&o.outputPath,
+		"output", "o", "",
+		"If specified, write the patches to this path.")
+	return cmd
+}
+
+// Validate validates rebase command.
+func (o *rebaseOptions) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("rebase takes no arguments")
+	}
+	if o.oldBasePath == "" || o.newBasePath == "" || o.overlayPath == "" {
+		return errors.New("must specify --old-base, --new-base and --overlay")
+	}
+	return nil
+}
+
+// RunRebase runs rebase command.
+func (o *rebaseOptions) RunRebase(
+	out io.Writer, fSys fs.FileSystem,
+	rf *resmap.Factory, r merge.Rebaser) error {
+	var maps []resmap.ResMap
+	for _, path := range []string{
+		o.oldBasePath, o.newBasePath, o.overlayPath} {
+		b, err := fSys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		m, err := rf.NewResMapFromBytes(b)
+		if err != nil {
+			return err
+		}
+		maps = append(maps, m)
+	}
+	patches, err := merge.RebasePatches(r, maps[0], maps[1], maps[2])
+	if err != nil {
+		return err
+	}
+	res, err := patches.AsYaml()
+	if err != nil {
+		return err
+	}
+	if o.outputPath != "" {
+		return fSys.WriteFile(o.outputPath, res)
+	}
+	_, err = out.Write(res)
+	return err
+}
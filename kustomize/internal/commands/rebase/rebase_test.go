@@ -0,0 +1,115 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package rebase
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+const deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+spec:
+  replicas: %REPLICAS%
+  template:
+    spec:
+      containers:
+      - name: app
+        image: %IMAGE%
+`
+
+func writeDeployment(
+	fSys fs.FileSystem, path, replicas, image, extra string) {
+	d := strings.Replace(deployment, "%REPLICAS%", replicas, 1)
+	d = strings.Replace(d, "%IMAGE%", image, 1)
+	fSys.WriteFile(path, []byte(d+extra))
+}
+
+func runRebase(t *testing.T, fSys fs.FileSystem) (string, error) {
+	pf := transformer.NewFactoryImpl()
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), pf)
+	o := rebaseOptions{
+		oldBasePath: "old.yaml",
+		newBasePath: "new.yaml",
+		overlayPath: "overlay.yaml",
+	}
+	if err := o.Validate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	err := o.RunRebase(&out, fSys, rf, pf)
+	return out.String(), err
+}
+
+func TestRebase(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	writeDeployment(fSys, "old.yaml", "1", "app:v1", "")
+	writeDeployment(fSys, "new.yaml", "1", "app:v2", "")
+	writeDeployment(fSys, "overlay.yaml", "3", "app:v1", `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: overlay-only
+`)
+
+	out, err := runRebase(t, fSys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+spec:
+  replicas: 3
+`
+	if out != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, out)
+	}
+}
+
+func TestRebaseConflict(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	writeDeployment(fSys, "old.yaml", "1", "app:v1", "")
+	writeDeployment(fSys, "new.yaml", "2", "app:v2", "")
+	writeDeployment(fSys, "overlay.yaml", "3", "app:v1", "")
+
+	_, err := runRebase(t, fSys)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"unable to rebase apps_v1_Deployment|~X|my-deployment") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRebaseValidate(t *testing.T) {
+	o := rebaseOptions{oldBasePath: "old.yaml"}
+	err := o.Validate(nil)
+	if err == nil || !strings.Contains(err.Error(), "must specify") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	o = rebaseOptions{
+		oldBasePath: "old.yaml",
+		newBasePath: "new.yaml",
+		overlayPath: "overlay.yaml",
+	}
+	err = o.Validate([]string{"extra"})
+	if err == nil || !strings.Contains(err.Error(), "no arguments") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
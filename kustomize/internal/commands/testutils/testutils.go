@@ -4,6 +4,11 @@
 package testutils
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 )
@@ -45,3 +50,31 @@ func WriteTestKustomizationWith(fSys fs.FileSystem, bytes []byte) {
 func ReadTestKustomization(fSys fs.FileSystem) ([]byte, error) {
 	return fSys.ReadFile(pgmconfig.DefaultKustomizationFileName())
 }
+
+// fakeKubectl prints its args and stdin, exiting
+// with the code in the file exitcode beside it.
+const fakeKubectl = `#!/bin/sh
+echo "args: $*"
+cat
+exit $(cat "$(dirname "$0")/exitcode")
+`
+
+// WriteFakeKubectl writes a kubectl that prints its args
+// and stdin, and exits with the code, returning its path
+// and a func removing it.
+func WriteFakeKubectl(t *testing.T, exitCode string) (string, func()) {
+	dir, err := ioutil.TempDir("", "fake-kubectl-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	bin := filepath.Join(dir, "kubectl")
+	if err = ioutil.WriteFile(bin, []byte(fakeKubectl), 0700); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	err = ioutil.WriteFile(
+		filepath.Join(dir, "exitcode"), []byte(exitCode), 0600)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	return bin, func() { os.RemoveAll(dir) }
+}
@@ -4,12 +4,17 @@
 package accumulator
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"sort"
 	"strings"
 
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
@@ -22,6 +27,15 @@ type ResAccumulator struct {
 	resMap  resmap.ResMap
 	tConfig *config.TransformerConfig
 	varSet  types.VarSet
+	// varsInAllFields is true if vars are expanded in all
+	// string fields, not just those of tConfig.VarReference.
+	varsInAllFields bool
+	// allowedEnv holds the environment variables
+	// that vars may take their values from.
+	allowedEnv map[string]bool
+	// maxResources bounds how many resources may
+	// be accumulated, unless it's 0.
+	maxResources int
 }
 
 func MakeEmptyAccumulator() *ResAccumulator {
@@ -44,12 +58,34 @@ func (ra *ResAccumulator) Vars() []types.Var {
 
 func (ra *ResAccumulator) AppendAll(
 	resources resmap.ResMap) error {
-	return ra.resMap.AppendAll(resources)
+	if err := ra.resMap.AppendAll(resources); err != nil {
+		return err
+	}
+	return ra.errIfTooMany()
 }
 
 func (ra *ResAccumulator) AbsorbAll(
 	resources resmap.ResMap) error {
-	return ra.resMap.AbsorbAll(resources)
+	if err := ra.resMap.AbsorbAll(resources); err != nil {
+		return err
+	}
+	return ra.errIfTooMany()
+}
+
+// SetMaxResources bounds how many resources may be
+// accumulated; appending more is an error.  0 means
+// any number.
+func (ra *ResAccumulator) SetMaxResources(n int) {
+	ra.maxResources = n
+}
+
+func (ra *ResAccumulator) errIfTooMany() error {
+	if ra.maxResources > 0 && ra.resMap.Size() > ra.maxResources {
+		return fmt.Errorf(
+			"%d resources exceed the limit of %d",
+			ra.resMap.Size(), ra.maxResources)
+	}
+	return nil
 }
 
 func (ra *ResAccumulator) MergeConfig(
@@ -64,6 +100,9 @@ func (ra *ResAccumulator) GetTransformerConfig() *config.TransformerConfig {
 
 func (ra *ResAccumulator) MergeVars(incoming []types.Var) error {
 	for _, v := range incoming {
+		if !v.RefersToObject() {
+			continue
+		}
 		targetId := resid.NewResIdWithNamespace(v.ObjRef.GVK(), v.ObjRef.Name, v.ObjRef.Namespace)
 		idMatcher := targetId.GvknEquals
 		if targetId.Namespace != "" || !targetId.IsNamespaceableKind() {
@@ -85,6 +124,133 @@ func (ra *ResAccumulator) MergeVars(incoming []types.Var) error {
 	return ra.varSet.MergeSlice(incoming)
 }
 
+// Exclude removes the resources that the selector
+// selects, returning how many it removed.
+func (ra *ResAccumulator) Exclude(s types.Selector) (int, error) {
+	resources, err := ra.resMap.Select(s)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range resources {
+		if err := ra.resMap.Remove(r.CurId()); err != nil {
+			return 0, err
+		}
+	}
+	return len(resources), nil
+}
+
+// Export marks the fields the exports name, of the one
+// resource each selects, so that replacements of including
+// kustomizations can refer to them by the export names.
+func (ra *ResAccumulator) Export(exports []types.Export) error {
+	for _, e := range exports {
+		resources, err := ra.resMap.Select(*e.Select)
+		if err != nil {
+			return err
+		}
+		if len(resources) != 1 {
+			return fmt.Errorf(
+				"export %s must select one resource, selected %d",
+				e.Name, len(resources))
+		}
+		path := e.FieldPath
+		if path == "" {
+			path = types.DefaultReplacementFieldPath
+		}
+		resources[0].Export(e.Name, path)
+	}
+	return nil
+}
+
+// DeclareParameters records the values of the fields the
+// parameters name, of the one resource each selects, for
+// CheckParameters to tell whether including kustomizations
+// set them.
+func (ra *ResAccumulator) DeclareParameters(params []types.Parameter) error {
+	for _, p := range params {
+		resources, err := ra.resMap.Select(*p.Select)
+		if err != nil {
+			return err
+		}
+		if len(resources) != 1 {
+			return fmt.Errorf(
+				"parameter %s must select one resource, selected %d",
+				p.Name, len(resources))
+		}
+		declared, err := parameterValue(resources[0], p.FieldPath)
+		if err != nil {
+			return err
+		}
+		resources[0].AddParameter(resource.Parameter{
+			Name:        p.Name,
+			FieldPath:   p.FieldPath,
+			Description: p.Description,
+			Declared:    declared,
+		})
+	}
+	return nil
+}
+
+// CheckParameters returns an error listing the parameters
+// whose fields still have the values they had when the
+// parameters were declared.
+func (ra *ResAccumulator) CheckParameters() error {
+	var unset []string
+	for _, res := range ra.resMap.Resources() {
+		for _, p := range res.GetParameters() {
+			value, err := parameterValue(res, p.FieldPath)
+			if err != nil {
+				return err
+			}
+			if value != p.Declared {
+				continue
+			}
+			msg := fmt.Sprintf(
+				"parameter %s, %s of %s, isn't set",
+				p.Name, p.FieldPath, res.CurId())
+			if p.Description != "" {
+				msg += ": " + p.Description
+			}
+			unset = append(unset, msg)
+		}
+	}
+	if len(unset) > 0 {
+		return fmt.Errorf("%s", strings.Join(unset, "\n"))
+	}
+	return nil
+}
+
+// parameterValue returns the field at the path as
+// JSON, or an empty string if it's missing.
+func parameterValue(res *resource.Resource, path string) (string, error) {
+	v, err := res.GetFieldValue(path)
+	if err != nil {
+		return "", nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// AllowEnvVars lets vars take their values from
+// the environment variables with the given names.
+func (ra *ResAccumulator) AllowEnvVars(names []string) {
+	ra.allowedEnv = make(map[string]bool, len(names))
+	for _, n := range names {
+		ra.allowedEnv[n] = true
+	}
+}
+
+// ExpandVarsInAllFields makes ResolveVars expand vars in
+// all string fields of the resources, rather than only in
+// the fields the transformer config lists.  Accumulators
+// merging this one do so too.
+func (ra *ResAccumulator) ExpandVarsInAllFields() {
+	ra.varsInAllFields = true
+}
+
 func (ra *ResAccumulator) MergeAccumulator(other *ResAccumulator) (err error) {
 	err = ra.AppendAll(other.resMap)
 	if err != nil {
@@ -94,9 +260,126 @@ func (ra *ResAccumulator) MergeAccumulator(other *ResAccumulator) (err error) {
 	if err != nil {
 		return err
 	}
+	ra.varsInAllFields = ra.varsInAllFields || other.varsInAllFields
 	return ra.varSet.MergeSet(other.varSet)
 }
 
+// ConflictResolver is called when a resource being accumulated
+// has the same CurId as an already accumulated resource.
+// It returns the resource to keep in place of the accumulated
+// one, which may be either of the two, or one of them modified
+// to absorb the other.  The resource returned must have the
+// same CurId.  Returning nil and no error declines to resolve
+// the conflict, which is then reported as an error.
+type ConflictResolver func(
+	accumulated, incoming *resource.Resource) (*resource.Resource, error)
+
+// AppendAllResolvingConflicts behaves like AppendAll, except
+// that a resource identical to the accumulated resource with
+// the same CurId is dropped, and resolve gets a chance to
+// settle any other CurId collision.  A nil resolve settles
+// nothing.
+func (ra *ResAccumulator) AppendAllResolvingConflicts(
+	resources resmap.ResMap, resolve ConflictResolver) error {
+	for _, res := range resources.Resources() {
+		err := ra.appendResolvingConflict(res, resolve)
+		if err != nil {
+			return err
+		}
+	}
+	return ra.errIfTooMany()
+}
+
+// MergeAccumulatorResolvingConflicts behaves like MergeAccumulator,
+// except that resolve gets a chance to settle CurId collisions.
+func (ra *ResAccumulator) MergeAccumulatorResolvingConflicts(
+	other *ResAccumulator, resolve ConflictResolver) (err error) {
+	err = ra.AppendAllResolvingConflicts(other.resMap, resolve)
+	if err != nil {
+		return err
+	}
+	err = ra.MergeConfig(other.tConfig)
+	if err != nil {
+		return err
+	}
+	ra.varsInAllFields = ra.varsInAllFields || other.varsInAllFields
+	return ra.varSet.MergeSet(other.varSet)
+}
+
+func (ra *ResAccumulator) appendResolvingConflict(
+	res *resource.Resource, resolve ConflictResolver) error {
+	matches := ra.resMap.GetMatchingResourcesByCurrentId(res.CurId().Equals)
+	if len(matches) != 1 {
+		return ra.resMap.Append(res)
+	}
+	accumulated := matches[0]
+	if accumulated.KunstructEqual(res) {
+		// The same resource reached by two paths,
+		// e.g. a base included by two overlays
+		// that leave it untouched.
+		absorb(accumulated, res)
+		return nil
+	}
+	if resolve == nil {
+		return conflictError(accumulated, res, ra.resMap.Append(res))
+	}
+	kept, err := resolve(accumulated, res)
+	if err != nil {
+		return conflictError(accumulated, res, err)
+	}
+	switch kept {
+	case nil:
+		return conflictError(accumulated, res, ra.resMap.Append(res))
+	case accumulated:
+		absorb(accumulated, res)
+		return nil
+	case res:
+		absorb(res, accumulated)
+	}
+	_, err = ra.resMap.Replace(kept)
+	return err
+}
+
+// conflictError adds to err the paths of the files
+// that contributed to the conflicting resources.
+func conflictError(accumulated, incoming *resource.Resource, err error) error {
+	return fmt.Errorf(
+		"%v\n  accumulated resource from: %s\n  conflicting resource from: %s",
+		err,
+		strings.Join(accumulated.Provenance(), ", "),
+		strings.Join(incoming.Provenance(), ", "))
+}
+
+// absorb adds to dst the names of vars referring to src,
+// and the exports of src, so vars and exports referring
+// to a resource dropped in favor of another still resolve,
+// and the parameters and provenance of src.
+func absorb(dst, src *resource.Resource) {
+	for _, name := range src.GetRefVarNames() {
+		if !hasString(dst.GetRefVarNames(), name) {
+			dst.AppendRefVarName(types.Var{Name: name})
+		}
+	}
+	for name, path := range src.GetExports() {
+		if _, ok := dst.GetExports()[name]; !ok {
+			dst.Export(name, path)
+		}
+	}
+	for _, p := range src.GetParameters() {
+		dst.AddParameter(p)
+	}
+	dst.AppendProvenance(src.Provenance()...)
+}
+
+func hasString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
 func (ra *ResAccumulator) findVarValueFromResources(v types.Var) (interface{}, error) {
 	for _, res := range ra.resMap.Resources() {
 		for _, varName := range res.GetRefVarNames() {
@@ -107,7 +390,9 @@ func (ra *ResAccumulator) findVarValueFromResources(v types.Var) (interface{}, e
 						"field specified in var '%v' "+
 							"not found in corresponding resource", v)
 				}
-
+				if isSecretData(res, v.FieldRef.FieldPath) {
+					return decodeSecretData(v, s)
+				}
 				return s, nil
 			}
 		}
@@ -118,18 +403,70 @@ func (ra *ResAccumulator) findVarValueFromResources(v types.Var) (interface{}, e
 			"in the set of known resources", v)
 }
 
+// isSecretData is true if the path selects
+// a data key of a Secret, e.g. data.password
+// or data[tls.key].
+func isSecretData(res *resource.Resource, path string) bool {
+	return res.GetKind() == "Secret" &&
+		(strings.HasPrefix(path, "data.") ||
+			strings.HasPrefix(path, "data["))
+}
+
+// decodeSecretData returns the base64 decoded
+// value of the Secret data key of the var, so
+// that it can be reused in plain text.
+func decodeSecretData(v types.Var, s interface{}) (interface{}, error) {
+	encoded, ok := s.(string)
+	if !ok {
+		return s, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf(
+			"var '%s' refers to Secret data that isn't base64 encoded: %v",
+			v.Name, err)
+	}
+	return string(decoded), nil
+}
+
+// findVarValueFromEnv returns the value of the environment
+// variable of the var, if it's allowed and set.
+func (ra *ResAccumulator) findVarValueFromEnv(v types.Var) (string, error) {
+	if !ra.allowedEnv[v.Env] {
+		return "", fmt.Errorf(
+			"var '%s' reads environment variable %s, "+
+				"which isn't allowed", v.Name, v.Env)
+	}
+	s, ok := os.LookupEnv(v.Env)
+	if !ok {
+		return "", fmt.Errorf(
+			"environment variable %s of var '%s' isn't set", v.Env, v.Name)
+	}
+	return s, nil
+}
+
 // makeVarReplacementMap returns a map of Var names to
 // their final values. The values are strings intended
 // for substitution wherever the $(var.Name) occurs.
 func (ra *ResAccumulator) makeVarReplacementMap() (map[string]interface{}, error) {
 	result := map[string]interface{}{}
 	for _, v := range ra.Vars() {
-		s, err := ra.findVarValueFromResources(v)
-		if err != nil {
-			return nil, err
+		switch {
+		case v.Literal != "":
+			result[v.Name] = v.Literal
+		case v.Env != "":
+			s, err := ra.findVarValueFromEnv(v)
+			if err != nil {
+				return nil, err
+			}
+			result[v.Name] = s
+		default:
+			s, err := ra.findVarValueFromResources(v)
+			if err != nil {
+				return nil, err
+			}
+			result[v.Name] = s
 		}
-
-		result[v.Name] = s
 	}
 
 	return result, nil
@@ -149,11 +486,15 @@ func (ra *ResAccumulator) ResolveVars() error {
 	}
 	t := transformers.NewRefVarTransformer(
 		replacementMap, ra.tConfig.VarReference)
+	if ra.varsInAllFields {
+		t = transformers.NewAllFieldsRefVarTransformer(replacementMap)
+	}
 	err = ra.Transform(t)
-	if len(t.UnusedVars()) > 0 {
+	if unused := t.UnusedVars(); len(unused) > 0 {
+		sort.Strings(unused)
 		log.Printf(
-			"well-defined vars that were never replaced: %s\n",
-			strings.Join(t.UnusedVars(), ","))
+			"warning: well-defined vars that were never replaced: %s\n",
+			strings.Join(unused, ","))
 	}
 	return err
 }
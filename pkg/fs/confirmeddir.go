@@ -6,6 +6,7 @@ package fs
 import (
 	"io/ioutil"
 	"path/filepath"
+	"runtime"
 	"strings"
 )
 
@@ -50,24 +51,40 @@ func NewTmpConfirmedDir() (ConfirmedDir, error) {
 //   d.HasPrefix("/foo")
 //   d.HasPrefix("/")
 //
+// and on Windows, where paths differing only in case
+// are the same path,
+//
+//   d := fSys.ConfirmDir(`C:\Foo\Bar`)
+//   d.HasPrefix(`c:\foo`)
+//   d.HasPrefix(`C:\`)
+//
+//   d := fSys.ConfirmDir(`\\server\share\bar`)
+//   d.HasPrefix(`\\server\share\`)
+//
 // Not contacting a file system here to check for
 // actual path existence.
-//
-// This is tested on linux, but will have trouble
-// on other operating systems.
-// TODO(monopole) Refactor when #golang/go/18358 closes.
-// See also:
-//   https://github.com/golang/go/issues/18358
-//   https://github.com/golang/dep/issues/296
-//   https://github.com/golang/dep/blob/master/internal/fs/fs.go#L33
-//   https://codereview.appspot.com/5712045
 func (d ConfirmedDir) HasPrefix(path ConfirmedDir) bool {
-	if path.String() == string(filepath.Separator) || path == d {
+	return hasPathPrefix(
+		string(d), string(path),
+		string(filepath.Separator), runtime.GOOS == "windows")
+}
+
+// hasPathPrefix returns true if prefix equals or contains
+// d, given the separator of their elements, comparing
+// them ignoring case if fold is true.  A prefix ending in
+// the separator, like "/" or the volume root `C:\`,
+// contains the paths it's a string prefix of.
+func hasPathPrefix(d, prefix, sep string, fold bool) bool {
+	if fold {
+		d, prefix = strings.ToLower(d), strings.ToLower(prefix)
+	}
+	if d == prefix {
 		return true
 	}
-	return strings.HasPrefix(
-		string(d),
-		string(path)+string(filepath.Separator))
+	if !strings.HasSuffix(prefix, sep) {
+		prefix += sep
+	}
+	return strings.HasPrefix(d, prefix)
 }
 
 func (d ConfirmedDir) Join(path string) string {
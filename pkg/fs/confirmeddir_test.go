@@ -104,3 +104,24 @@ func TestNewTempConfirmDir(t *testing.T) {
 		t.Fatalf("unexpected path containing symlinks")
 	}
 }
+
+func TestHasPathPrefix_Windows(t *testing.T) {
+	for _, c := range []struct {
+		d, prefix string
+		expected  bool
+	}{
+		{`C:\foo\bar`, `C:\foo`, true},
+		{`C:\foo\bar`, `c:\FOO\bar`, true},
+		{`C:\foo\bar`, `C:\`, true},
+		{`C:\foo\bar`, `D:\`, false},
+		{`C:\foobar`, `C:\foo`, false},
+		{`\\server\share\bar`, `\\server\share\`, true},
+		{`\\server\share\bar`, `\\server\share`, true},
+		{`\\server\shared\bar`, `\\server\share`, false},
+	} {
+		if hasPathPrefix(c.d, c.prefix, `\`, true) != c.expected {
+			t.Fatalf("expected %s having prefix %s to be %v",
+				c.d, c.prefix, c.expected)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+var _ FileSystem = fsAfero{}
+
+// fsAfero implements FileSystem using an afero.Fs,
+// e.g. one an operator or a test suite supplies.
+type fsAfero struct {
+	fs afero.Fs
+}
+
+// MakeFsFromAfero makes a FileSystem of the afero.Fs.
+func MakeFsFromAfero(a afero.Fs) FileSystem {
+	return fsAfero{fs: a}
+}
+
+// Create delegates to afero.Fs.Create.
+func (x fsAfero) Create(name string) (File, error) { return x.fs.Create(name) }
+
+// Mkdir delegates to afero.Fs.Mkdir.
+func (x fsAfero) Mkdir(name string) error {
+	return x.fs.Mkdir(name, 0777|os.ModeDir)
+}
+
+// MkdirAll delegates to afero.Fs.MkdirAll.
+func (x fsAfero) MkdirAll(name string) error {
+	return x.fs.MkdirAll(name, 0777|os.ModeDir)
+}
+
+// RemoveAll delegates to afero.Fs.RemoveAll.
+func (x fsAfero) RemoveAll(name string) error {
+	return x.fs.RemoveAll(name)
+}
+
+// Open delegates to afero.Fs.Open.
+func (x fsAfero) Open(name string) (File, error) { return x.fs.Open(name) }
+
+// CleanedAbs converts the given path into a
+// directory and a file name, as fsOnDisk does,
+// except that symlinks aren't followed, as an
+// afero.Fs needn't support them.
+func (x fsAfero) CleanedAbs(
+	path string) (ConfirmedDir, string, error) {
+	absRoot, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", fmt.Errorf(
+			"abs path error on '%s' : %v", path, err)
+	}
+	if x.IsDir(absRoot) {
+		return ConfirmedDir(absRoot), "", nil
+	}
+	d := filepath.Dir(absRoot)
+	if !x.IsDir(d) {
+		return "", "", fmt.Errorf(
+			"first part of '%s' not a directory", absRoot)
+	}
+	return ConfirmedDir(d), filepath.Base(absRoot), nil
+}
+
+// Exists delegates to afero.Exists.
+func (x fsAfero) Exists(name string) bool {
+	ok, err := afero.Exists(x.fs, name)
+	return err == nil && ok
+}
+
+// Glob delegates to afero.Glob.
+func (x fsAfero) Glob(pattern string) ([]string, error) {
+	return afero.Glob(x.fs, pattern)
+}
+
+// IsDir delegates to afero.IsDir.
+func (x fsAfero) IsDir(name string) bool {
+	ok, err := afero.IsDir(x.fs, name)
+	return err == nil && ok
+}
+
+// ReadFile delegates to afero.ReadFile.
+func (x fsAfero) ReadFile(name string) ([]byte, error) {
+	return afero.ReadFile(x.fs, name)
+}
+
+// WriteFile delegates to afero.WriteFile with read/write permissions.
+func (x fsAfero) WriteFile(name string, c []byte) error {
+	return afero.WriteFile(x.fs, name, c, 0666)
+}
+
+// Walk delegates to afero.Walk.
+func (x fsAfero) Walk(path string, walkFn filepath.WalkFunc) error {
+	return afero.Walk(x.fs, path, walkFn)
+}
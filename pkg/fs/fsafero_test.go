@@ -0,0 +1,59 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestAfero(t *testing.T) {
+	x := MakeFsFromAfero(afero.NewMemMapFs())
+	if err := x.MkdirAll("/app/base"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := x.WriteFile("/app/base/cm.yaml", []byte("kind: ConfigMap")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shouldExist(t, x, "/app/base/cm.yaml")
+	shouldNotExist(t, x, "/app/base/missing.yaml")
+	if !x.IsDir("/app/base") || x.IsDir("/app/base/cm.yaml") {
+		t.Fatalf("unexpected IsDir")
+	}
+	content, err := x.ReadFile("/app/base/cm.yaml")
+	if err != nil || string(content) != "kind: ConfigMap" {
+		t.Fatalf("unexpected content %q, %v", content, err)
+	}
+
+	d, f, err := x.CleanedAbs("/app/base/../base/cm.yaml")
+	if err != nil || d != "/app/base" || f != "cm.yaml" {
+		t.Fatalf("unexpected %s, %s, %v", d, f, err)
+	}
+	if _, _, err = x.CleanedAbs("/app/missing/cm.yaml"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	matches, err := x.Glob("/app/base/*.yaml")
+	if err != nil || !reflect.DeepEqual(matches, []string{"/app/base/cm.yaml"}) {
+		t.Fatalf("unexpected matches %v, %v", matches, err)
+	}
+	var walked []string
+	err = x.Walk("/app", func(path string, info os.FileInfo, err error) error {
+		walked = append(walked, filepath.ToSlash(path))
+		return err
+	})
+	expected := []string{"/app", "/app/base", "/app/base/cm.yaml"}
+	if err != nil || !reflect.DeepEqual(walked, expected) {
+		t.Fatalf("expected %v, got %v, %v", expected, walked, err)
+	}
+
+	if err = x.RemoveAll("/app/base"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shouldNotExist(t, x, "/app/base/cm.yaml")
+}
@@ -158,7 +158,11 @@ func (fs *fsInMemory) pathMatch(path, pattern string) bool {
 func (fs *fsInMemory) lstat(path string) (*fileInfo, error) {
 	f, found := fs.m[path]
 	if !found {
-		return nil, os.ErrNotExist
+		if !fs.IsDir(path) {
+			return nil, os.ErrNotExist
+		}
+		// A directory implied by the files under it.
+		f = makeDir(path)
 	}
 	return &fileInfo{f}, nil
 }
@@ -178,16 +182,17 @@ func (fs *fsInMemory) readDirNames(path string) []string {
 	if !strings.HasSuffix(path, separator) {
 		path += separator
 	}
-	pathSegments := strings.Count(path, separator)
+	seen := make(map[string]bool)
 	for name := range fs.m {
-		if name == path {
-			continue
-		}
-		if strings.Count(name, separator) > pathSegments {
+		if name == path || !strings.HasPrefix(name, path) {
 			continue
 		}
-		if strings.HasPrefix(name, path) {
-			names = append(names, filepath.Base(name))
+		// Directories implied by files deeper
+		// in the tree are named once.
+		base := strings.SplitN(name[len(path):], separator, 2)[0]
+		if base != "" && !seen[base] {
+			seen[base] = true
+			names = append(names, base)
 		}
 	}
 	sort.Strings(names)
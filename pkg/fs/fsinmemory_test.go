@@ -5,6 +5,7 @@ package fs
 
 import (
 	"bytes"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -145,3 +146,34 @@ func TestGlob(t *testing.T) {
 		t.Fatalf("incorrect files found by glob: %v", files)
 	}
 }
+
+func TestWalkImpliedDirs(t *testing.T) {
+	x := MakeFsInMemory()
+	x.WriteFile("/foo/project/file.yaml", []byte("Unused"))
+	x.WriteFile("/foo/project/subdir/file.yaml", []byte("Unused"))
+	var paths []string
+	err := x.Walk("/foo", func(
+		path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			path += "/"
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"/foo/",
+		"/foo/project/",
+		"/foo/project/file.yaml",
+		"/foo/project/subdir/",
+		"/foo/project/subdir/file.yaml",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+}
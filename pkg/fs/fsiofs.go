@@ -0,0 +1,151 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build go1.16
+// +build go1.16
+
+package fs
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var _ FileSystem = fsIOFS{}
+
+// fsIOFS implements a read-only FileSystem using an
+// fs.FS, e.g. an embed.FS or a fstest.MapFS.  Paths
+// are taken to be relative to the root of the fs.FS,
+// whether they start with a slash or not, and the
+// paths it returns start with one.
+type fsIOFS struct {
+	fs fs.FS
+}
+
+// MakeFsFromIOFS makes a read-only FileSystem of the fs.FS.
+// Writes fail with an error wrapping fs.ErrPermission.
+func MakeFsFromIOFS(f fs.FS) FileSystem {
+	return fsIOFS{fs: f}
+}
+
+// name returns the fs.FS name of the path.
+func (fsIOFS) name(p string) string {
+	p = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(p)), "/")
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+// path returns the path of the fs.FS name.
+func (fsIOFS) path(name string) string {
+	if name == "." {
+		return "/"
+	}
+	return "/" + name
+}
+
+func (fsIOFS) readOnly(op, name string) error {
+	return &os.PathError{Op: op, Path: name, Err: fs.ErrPermission}
+}
+
+// Create fails; the file system is read-only.
+func (x fsIOFS) Create(name string) (File, error) {
+	return nil, x.readOnly("create", name)
+}
+
+// Mkdir fails; the file system is read-only.
+func (x fsIOFS) Mkdir(name string) error { return x.readOnly("mkdir", name) }
+
+// MkdirAll fails; the file system is read-only.
+func (x fsIOFS) MkdirAll(name string) error { return x.readOnly("mkdir", name) }
+
+// RemoveAll fails; the file system is read-only.
+func (x fsIOFS) RemoveAll(name string) error { return x.readOnly("remove", name) }
+
+// Open delegates to fs.FS.Open.
+func (x fsIOFS) Open(name string) (File, error) {
+	f, err := x.fs.Open(x.name(name))
+	if err != nil {
+		return nil, err
+	}
+	return fileIOFS{File: f, name: name}, nil
+}
+
+// CleanedAbs converts the given path into a
+// directory and a file name, rooted at the
+// root of the fs.FS.
+func (x fsIOFS) CleanedAbs(p string) (ConfirmedDir, string, error) {
+	name := x.name(p)
+	if x.IsDir(name) {
+		return ConfirmedDir(x.path(name)), "", nil
+	}
+	d := path.Dir(name)
+	if !x.IsDir(d) {
+		return "", "", &os.PathError{
+			Op: "stat", Path: p, Err: fs.ErrNotExist}
+	}
+	return ConfirmedDir(x.path(d)), path.Base(name), nil
+}
+
+// Exists delegates to fs.Stat.
+func (x fsIOFS) Exists(name string) bool {
+	_, err := fs.Stat(x.fs, x.name(name))
+	return err == nil
+}
+
+// Glob delegates to fs.Glob.
+func (x fsIOFS) Glob(pattern string) ([]string, error) {
+	names, err := fs.Glob(x.fs, x.name(pattern))
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, n := range names {
+		result = append(result, x.path(n))
+	}
+	return result, nil
+}
+
+// IsDir delegates to fs.Stat and FileInfo.IsDir.
+func (x fsIOFS) IsDir(name string) bool {
+	info, err := fs.Stat(x.fs, x.name(name))
+	return err == nil && info.IsDir()
+}
+
+// ReadFile delegates to fs.ReadFile.
+func (x fsIOFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(x.fs, x.name(name))
+}
+
+// WriteFile fails; the file system is read-only.
+func (x fsIOFS) WriteFile(name string, _ []byte) error {
+	return x.readOnly("write", name)
+}
+
+// Walk delegates to fs.WalkDir.
+func (x fsIOFS) Walk(p string, walkFn filepath.WalkFunc) error {
+	return fs.WalkDir(x.fs, x.name(p), func(name string, d fs.DirEntry, err error) error {
+		var info os.FileInfo
+		if d != nil {
+			if info, err = d.Info(); err != nil {
+				return walkFn(x.path(name), nil, err)
+			}
+		}
+		return walkFn(x.path(name), info, err)
+	})
+}
+
+// fileIOFS is a read-only File of an fs.FS.
+type fileIOFS struct {
+	fs.File
+	name string
+}
+
+// Write fails; the file system is read-only.
+func (f fileIOFS) Write([]byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build go1.16
+// +build go1.16
+
+package fs
+
+import (
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestIOFS(t *testing.T) {
+	x := MakeFsFromIOFS(fstest.MapFS{
+		"app/base/cm.yaml":            {Data: []byte("kind: ConfigMap")},
+		"app/base/kustomization.yaml": {Data: []byte("resources: []")},
+	})
+	shouldExist(t, x, "/app/base/cm.yaml")
+	shouldExist(t, x, "app/base/cm.yaml")
+	shouldNotExist(t, x, "/app/base/missing.yaml")
+	if !x.IsDir("/app/base") || x.IsDir("/app/base/cm.yaml") {
+		t.Fatalf("unexpected IsDir")
+	}
+	content, err := x.ReadFile("/app/base/cm.yaml")
+	if err != nil || string(content) != "kind: ConfigMap" {
+		t.Fatalf("unexpected content %q, %v", content, err)
+	}
+	f, err := x.Open("/app/base/cm.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content, err = ioutil.ReadAll(f); err != nil || string(content) != "kind: ConfigMap" {
+		t.Fatalf("unexpected content %q, %v", content, err)
+	}
+	f.Close()
+
+	d, name, err := x.CleanedAbs("/app/base/../base/cm.yaml")
+	if err != nil || d != "/app/base" || name != "cm.yaml" {
+		t.Fatalf("unexpected %s, %s, %v", d, name, err)
+	}
+	if d, name, err = x.CleanedAbs("/"); err != nil || d != "/" || name != "" {
+		t.Fatalf("unexpected %s, %s, %v", d, name, err)
+	}
+
+	matches, err := x.Glob("/app/base/*.yaml")
+	expected := []string{"/app/base/cm.yaml", "/app/base/kustomization.yaml"}
+	if err != nil || !reflect.DeepEqual(matches, expected) {
+		t.Fatalf("unexpected matches %v, %v", matches, err)
+	}
+	var walked []string
+	err = x.Walk("/app", func(path string, info os.FileInfo, err error) error {
+		walked = append(walked, path)
+		return err
+	})
+	expected = []string{
+		"/app", "/app/base", "/app/base/cm.yaml", "/app/base/kustomization.yaml"}
+	if err != nil || !reflect.DeepEqual(walked, expected) {
+		t.Fatalf("expected %v, got %v, %v", expected, walked, err)
+	}
+
+	for _, err := range []error{
+		x.WriteFile("/app/base/cm.yaml", nil),
+		x.MkdirAll("/app/overlay"),
+		x.RemoveAll("/app"),
+	} {
+		if !errors.Is(err, fs.ErrPermission) {
+			t.Fatalf("expected a permission error, got %v", err)
+		}
+	}
+}
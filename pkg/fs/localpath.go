@@ -0,0 +1,19 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// LocalPath returns the path, as written in a kustomization
+// with / or \ separating its elements, using the separator
+// of the OS, so that e.g. the base "..\base" of a
+// kustomization written on Windows builds on Linux, and
+// "../base" builds on Windows.  A UNC path like
+// "\\server\share\base" stays one on Windows.
+func LocalPath(path string) string {
+	return filepath.FromSlash(strings.Replace(path, `\`, "/", -1))
+}
@@ -0,0 +1,22 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package fs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"../base":         "../base",
+		`..\base`:         "../base",
+		`overlays\prod/`:  "overlays/prod/",
+		"deployment.yaml": "deployment.yaml",
+	} {
+		if actual := LocalPath(path); actual != filepath.FromSlash(expected) {
+			t.Fatalf("expected %s, got %s", filepath.FromSlash(expected), actual)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+// CacheEntry describes a repo clone in the cache.
+type CacheEntry struct {
+	// Repo is the spec the repo was cloned with.
+	Repo string `json:"repo"`
+
+	// Ref is the branch, tag or commit cloned.
+	Ref string `json:"ref,omitempty"`
+
+	// Dir is the directory holding the clone.
+	Dir string `json:"dir"`
+
+	// Fetched is when the clone was made.
+	Fetched time.Time `json:"fetched"`
+}
+
+// commitSha matches full commit SHAs, which unlike
+// branches and tags always name the same tree.
+var commitSha = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// DefaultCacheDir returns the directory clones are cached
+// in, below $XDG_CACHE_HOME, or else below ~/.cache.
+func DefaultCacheDir() string {
+	dir := os.Getenv(pgmconfig.XdgCacheHome)
+	if dir == "" {
+		home := os.Getenv("HOME")
+		if runtime.GOOS == "windows" {
+			home = os.Getenv("USERPROFILE")
+		}
+		dir = filepath.Join(home, pgmconfig.DefaultCacheSubdir)
+	}
+	return filepath.Join(dir, pgmconfig.ProgramName, "repos")
+}
+
+// cacheKey names the clone of the repo and ref in the cache.
+func cacheKey(repoSpec *RepoSpec) string {
+	sum := sha256.Sum256([]byte(repoSpec.CloneSpec() + "@" + repoSpec.Ref))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+// supersededAfter is how long a clone replaced in the cache
+// by a newer one is kept, for the builds still reading it.
+const supersededAfter = 24 * time.Hour
+
+// ClonerUsingCache returns a cloner keeping the clones the
+// given cloner makes in the cache dir, by repo and ref.  A
+// clone of a commit SHA is reused, without fetching it again;
+// clones of branches and tags, which may move, are refreshed.
+// Clones taken from the cache aren't removed once loaded.
+func ClonerUsingCache(dir string, cloner Cloner) Cloner {
+	return func(repoSpec *RepoSpec) error {
+		key := cacheKey(repoSpec)
+		if commitSha.MatchString(repoSpec.Ref) {
+			if cached := lookUpCache(dir, key); cached != "" {
+				repoSpec.Dir = fs.ConfirmedDir(cached)
+				repoSpec.Cached = true
+				return nil
+			}
+		}
+		ref := repoSpec.Ref
+		if err := cloner(repoSpec); err != nil {
+			return err
+		}
+		if err := storeInCache(dir, key, repoSpec, ref); err != nil {
+			// The clone is fine, if not cached.
+			log.Printf("warning: not caching %s: %v", repoSpec.Raw(), err)
+			return nil
+		}
+		return nil
+	}
+}
+
+// lookUpCache returns the directory of the clone
+// cached under the key, or "" if there's none.
+func lookUpCache(dir, key string) string {
+	content, err := ioutil.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return ""
+	}
+	var e CacheEntry
+	if err = json.Unmarshal(content, &e); err != nil {
+		return ""
+	}
+	if fi, err := os.Stat(e.Dir); err != nil || !fi.IsDir() {
+		return ""
+	}
+	return e.Dir
+}
+
+// storeInCache moves the clone into a directory of its own
+// in the cache, then points the key's entry at it, replacing
+// the entry atomically.  A clone the entry pointed at before
+// is left for the builds that may be reading it, and removed
+// by a later store once it's been superseded for a while.
+func storeInCache(dir, key string, repoSpec *RepoSpec, ref string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	cached, err := ioutil.TempDir(dir, key+"-")
+	if err != nil {
+		return err
+	}
+	clone := repoSpec.Dir.String()
+	if err := os.Rename(clone, cached); err != nil {
+		// The temp dir may be on another device.
+		if err = copyDir(clone, cached); err != nil {
+			os.RemoveAll(cached)
+			return err
+		}
+		os.RemoveAll(clone)
+	}
+	repoSpec.Dir = fs.ConfirmedDir(cached)
+	repoSpec.Cached = true
+	entry, err := json.Marshal(CacheEntry{
+		Repo: repoSpec.CloneSpec(), Ref: ref, Dir: cached,
+		Fetched: time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	previous := lookUpCache(dir, key)
+	if err = writeFileAtomically(
+		filepath.Join(dir, key+".json"), entry); err != nil {
+		return err
+	}
+	removeSuperseded(dir, key, cached, previous)
+	return nil
+}
+
+// writeFileAtomically writes the file by renaming a
+// temp file holding the content over it, so that
+// readers see either the old content or the new.
+func writeFileAtomically(path string, content []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// removeSuperseded removes the clones cached under the key,
+// other than the live one and the one it just replaced, that
+// were made long enough ago that no build still reads them.
+func removeSuperseded(dir, key, live, previous string) {
+	versions, err := filepath.Glob(filepath.Join(dir, key+"-*"))
+	if err != nil {
+		return
+	}
+	for _, v := range versions {
+		if v == live || v == previous {
+			continue
+		}
+		fi, err := os.Stat(v)
+		if err != nil || !fi.IsDir() ||
+			time.Since(fi.ModTime()) < supersededAfter {
+			continue
+		}
+		os.RemoveAll(v)
+	}
+}
+
+// copyDir copies the tree at from to to.
+func copyDir(from, to string) error {
+	return filepath.Walk(from, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, strings.TrimPrefix(path, from))
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, fi.Mode().Perm()|0700)
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, fi.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(from, to string, perm os.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(to, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ListCache returns the clones in the cache dir,
+// ordered by repo and ref.
+func ListCache(dir string) ([]CacheEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var result []CacheEntry
+	for _, p := range paths {
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		var e CacheEntry
+		if err := json.Unmarshal(content, &e); err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Repo != result[j].Repo {
+			return result[i].Repo < result[j].Repo
+		}
+		return result[i].Ref < result[j].Ref
+	})
+	return result, nil
+}
+
+// CleanCache removes the clones in the cache dir.
+func CleanCache(dir string) error {
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+)
+
+// makeFakeCloner returns a cloner writing a file
+// into a new temp dir, counting its calls.
+func makeFakeCloner(t *testing.T, calls *int) Cloner {
+	return func(repoSpec *RepoSpec) error {
+		*calls++
+		dir, err := ioutil.TempDir("", "kustomize-clone")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = ioutil.WriteFile(
+			filepath.Join(dir, "kustomization.yaml"), []byte("resources: []\n"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		repoSpec.Dir = fs.ConfirmedDir(dir)
+		return nil
+	}
+}
+
+func makeCacheDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "kustomize-cache")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return filepath.Join(dir, "repos")
+}
+
+func TestClonerUsingCache(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	dir := makeCacheDir(t)
+	defer os.RemoveAll(filepath.Dir(dir))
+	calls := 0
+	cloner := ClonerUsingCache(dir, makeFakeCloner(t, &calls))
+
+	for _, c := range []struct {
+		url   string
+		calls int
+	}{
+		// The first clone of a SHA is fetched, later ones aren't.
+		{"github.com/someOrg/someRepo/base?ref=" + sha, 1},
+		{"github.com/someOrg/someRepo/other?ref=" + sha, 1},
+		// Branches are fetched each time.
+		{"github.com/someOrg/someRepo/base?ref=master", 2},
+		{"github.com/someOrg/someRepo/base?ref=master", 3},
+	} {
+		repoSpec, err := NewRepoSpecFromUrl(c.url)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = cloner(repoSpec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != c.calls {
+			t.Fatalf("%s: expected %d clones, got %d", c.url, c.calls, calls)
+		}
+		if !repoSpec.Cached || filepath.Dir(repoSpec.Dir.String()) != dir {
+			t.Fatalf("%s: expected a clone in the cache, got %s", c.url, repoSpec.Dir)
+		}
+		if _, err = os.Stat(
+			filepath.Join(repoSpec.Dir.String(), "kustomization.yaml")); err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.url, err)
+		}
+		if err = repoSpec.Cleaner(fs.MakeFsOnDisk())(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err = os.Stat(repoSpec.Dir.String()); err != nil {
+			t.Fatalf("%s: expected the clone to be kept: %v", c.url, err)
+		}
+	}
+
+	entries, err := ListCache(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %v", entries)
+	}
+	if entries[0].Repo != "https://github.com/someOrg/someRepo.git" ||
+		entries[0].Ref != sha || entries[1].Ref != "master" {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+
+	if err = CleanCache(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries, err = ListCache(dir); err != nil || len(entries) != 0 {
+		t.Fatalf("expected an empty cache, got %v, %v", entries, err)
+	}
+}
+
+func TestClonerUsingCacheKeepsReplacedClones(t *testing.T) {
+	dir := makeCacheDir(t)
+	defer os.RemoveAll(filepath.Dir(dir))
+	calls := 0
+	cloner := ClonerUsingCache(dir, makeFakeCloner(t, &calls))
+
+	var clones []string
+	for i := 0; i < 2; i++ {
+		repoSpec, err := NewRepoSpecFromUrl(
+			"github.com/someOrg/someRepo/base?ref=master")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = cloner(repoSpec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clones = append(clones, repoSpec.Dir.String())
+	}
+	if clones[0] == clones[1] {
+		t.Fatalf("expected the refreshed clone in a new dir, got %s", clones[1])
+	}
+	// A build may still be reading the replaced clone.
+	for _, c := range clones {
+		if _, err := os.Stat(filepath.Join(c, "kustomization.yaml")); err != nil {
+			t.Fatalf("expected %s to be kept: %v", c, err)
+		}
+	}
+	entries, err := ListCache(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Dir != clones[1] {
+		t.Fatalf("expected one entry for %s, got %v", clones[1], entries)
+	}
+}
@@ -18,8 +18,11 @@ package git
 
 import (
 	"bytes"
+	"context"
 	"log"
+	"os"
 	"os/exec"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
@@ -40,10 +43,13 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 	if err != nil {
 		return err
 	}
-	cmd := exec.Command(
-		gitProgram,
+	ctx, cancel := repoSpec.context()
+	defer cancel()
+	cmd := exec.CommandContext(
+		ctx, gitProgram,
 		"init",
 		repoSpec.Dir.String())
+	cmd.Env = repoSpec.environ()
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
@@ -56,12 +62,13 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 			repoSpec.Dir.String())
 	}
 
-	cmd = exec.Command(
-		gitProgram,
+	cmd = exec.CommandContext(
+		ctx, gitProgram,
 		"remote",
 		"add",
 		"origin",
 		repoSpec.CloneSpec())
+	cmd.Env = repoSpec.environ()
 	cmd.Stdout = &out
 	cmd.Stderr = &out
 	cmd.Dir = repoSpec.Dir.String()
@@ -76,12 +83,17 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 	if repoSpec.Ref == "" {
 		repoSpec.Ref = "master"
 	}
-	cmd = exec.Command(
-		gitProgram,
+	depth := repoSpec.Depth
+	if depth < 1 {
+		depth = 1
+	}
+	cmd = exec.CommandContext(
+		ctx, gitProgram,
 		"fetch",
-		"--depth=1",
+		"--depth="+strconv.Itoa(depth),
 		"origin",
 		repoSpec.Ref)
+	cmd.Env = repoSpec.environ()
 	cmd.Stdout = &out
 	cmd.Stderr = &out
 	cmd.Dir = repoSpec.Dir.String()
@@ -91,11 +103,12 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 		return errors.Wrapf(err, "trouble fetching %s", repoSpec.Ref)
 	}
 
-	cmd = exec.Command(
-		gitProgram,
+	cmd = exec.CommandContext(
+		ctx, gitProgram,
 		"reset",
 		"--hard",
 		"FETCH_HEAD")
+	cmd.Env = repoSpec.environ()
 	cmd.Stdout = &out
 	cmd.Stderr = &out
 	cmd.Dir = repoSpec.Dir.String()
@@ -106,12 +119,13 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 			err, "trouble hard resetting empty repository to %s", repoSpec.Ref)
 	}
 
-	cmd = exec.Command(
-		gitProgram,
+	cmd = exec.CommandContext(
+		ctx, gitProgram,
 		"submodule",
 		"update",
 		"--init",
 		"--recursive")
+	cmd.Env = repoSpec.environ()
 	cmd.Stdout = &out
 	cmd.Dir = repoSpec.Dir.String()
 	err = cmd.Run()
@@ -122,6 +136,24 @@ func ClonerUsingGitExec(repoSpec *RepoSpec) error {
 	return nil
 }
 
+// context returns the context of the git commands,
+// cancelled once the timeout, if any, passes.
+func (x *RepoSpec) context() (context.Context, context.CancelFunc) {
+	if x.Timeout > 0 {
+		return context.WithTimeout(context.Background(), x.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// environ returns the environment of the git commands,
+// or nil, for them to inherit kustomize's own.
+func (x *RepoSpec) environ() []string {
+	if len(x.Env) == 0 {
+		return nil
+	}
+	return append(os.Environ(), x.Env...)
+}
+
 // DoNothingCloner returns a cloner that only sets
 // cloneDir field in the repoSpec.  It's assumed that
 // the cloneDir is associated with some fake filesystem
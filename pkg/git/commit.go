@@ -0,0 +1,35 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HeadCommit returns the commit checked out in the git
+// work tree holding dir, abbreviated if short is true.
+func HeadCommit(dir string, short bool) (string, error) {
+	gitProgram, err := exec.LookPath("git")
+	if err != nil {
+		return "", errors.Wrap(err, "no 'git' program on path")
+	}
+	args := []string{"rev-parse"}
+	if short {
+		args = append(args, "--short")
+	}
+	cmd := exec.Command(gitProgram, append(args, "HEAD")...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(
+			err, "reading the git commit of %s: %s", dir, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
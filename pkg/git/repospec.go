@@ -18,10 +18,13 @@ package git
 
 import (
 	"fmt"
+	"net/url"
 	"path/filepath"
-	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 )
 
@@ -55,8 +58,24 @@ type RepoSpec struct {
 	// Branch or tag reference.
 	Ref string
 
+	// Depth of the clone's history, 1 unless set.
+	Depth int
+
 	// e.g. .git or empty in case of _git is present
 	GitSuffix string
+
+	// Env holds variables, e.g. https_proxy, to add
+	// to the environment of the git commands cloning
+	// the repo.
+	Env []string
+
+	// Timeout bounds the cloning of the
+	// repo, unless it's 0.
+	Timeout time.Duration
+
+	// Cached is true if Dir is in a cache,
+	// and must outlive the loading of the repo.
+	Cached bool
 }
 
 // CloneSpec returns a string suitable for "git clone {spec}".
@@ -80,17 +99,27 @@ func (x *RepoSpec) AbsPath() string {
 }
 
 func (x *RepoSpec) Cleaner(fSys fs.FileSystem) func() error {
+	if x.Cached {
+		return func() error { return nil }
+	}
 	return func() error { return fSys.RemoveAll(x.Dir.String()) }
 }
 
 // From strings like git@github.com:someOrg/someRepo.git or
-// https://github.com/someOrg/someRepo?ref=someHash, extract
-// the parts.
+// https://github.com/someOrg/someRepo//someDir?ref=someHash,
+// extract the parts.  As in hashicorp's go-getter, a double
+// slash separates the repo from the subdirectory, and the
+// query holds the ref, or version, and the depth of the clone.
 func NewRepoSpecFromUrl(n string) (*RepoSpec, error) {
 	if filepath.IsAbs(n) {
 		return nil, fmt.Errorf("uri looks like abs path: %s", n)
 	}
-	host, orgRepo, path, gitRef, gitSuffix := parseGitUrl(n)
+	u, query := splitQuery(n)
+	gitRef, depth, err := parseQuery(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad query in url %s", n)
+	}
+	host, orgRepo, path, gitSuffix := parseGitUrl(u)
 	if orgRepo == "" {
 		return nil, fmt.Errorf("url lacks orgRepo: %s", n)
 	}
@@ -99,28 +128,87 @@ func NewRepoSpecFromUrl(n string) (*RepoSpec, error) {
 	}
 	return &RepoSpec{
 		raw: n, Host: host, OrgRepo: orgRepo,
-		Dir: notCloned, Path: path, Ref: gitRef, GitSuffix: gitSuffix}, nil
+		Dir: notCloned, Path: path, Ref: gitRef, Depth: depth,
+		GitSuffix: gitSuffix}, nil
 }
 
 const (
-	refQuery      = "?ref="
-	refQueryRegex = "\\?(version|ref)="
-	gitSuffix     = ".git"
-	gitDelimiter  = "_git/"
+	refQuery     = "?ref="
+	gitSuffix    = ".git"
+	gitDelimiter = "_git/"
+	subdirSep    = "//"
+	schemeSep    = "://"
 )
 
+// splitQuery splits the url at the first '?'.
+func splitQuery(n string) (string, string) {
+	if i := strings.Index(n, "?"); i >= 0 {
+		return n[:i], n[i+1:]
+	}
+	return n, ""
+}
+
+// parseQuery returns the ref, given as either ref or
+// version, and the depth that the query specifies.
+func parseQuery(query string) (gitRef string, depth int, err error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", 0, err
+	}
+	for k, v := range values {
+		if len(v) > 1 {
+			return "", 0, fmt.Errorf("%s is repeated", k)
+		}
+		switch k {
+		case "ref", "version":
+			if gitRef != "" {
+				return "", 0, fmt.Errorf("only one of ref and version may be set")
+			}
+			gitRef = v[0]
+		case "depth":
+			depth, err = strconv.Atoi(v[0])
+			if err != nil || depth < 1 {
+				return "", 0, fmt.Errorf("depth must be a positive integer")
+			}
+		default:
+			return "", 0, fmt.Errorf("unknown parameter %s", k)
+		}
+	}
+	return gitRef, depth, nil
+}
+
+// splitSubdir splits the url at the double slash,
+// following the scheme, if any, that separates the
+// repo from the subdirectory.
+func splitSubdir(n string) (string, string, bool) {
+	start := 0
+	if i := strings.Index(n, schemeSep); i >= 0 {
+		start = i + len(schemeSep)
+	}
+	i := strings.Index(n[start:], subdirSep)
+	if i < 0 {
+		return n, "", false
+	}
+	i += start
+	return n[:i], n[i+len(subdirSep):], true
+}
+
 // From strings like git@github.com:someOrg/someRepo.git or
-// https://github.com/someOrg/someRepo?ref=someHash, extract
-// the parts.
+// https://github.com/someOrg/someRepo//someDir, with no query,
+// extract the parts.  Lacking a double slash, the first two
+// segments following the host are taken to be the orgRepo.
 func parseGitUrl(n string) (
-	host string, orgRepo string, path string, gitRef string, gitSuff string) {
-
+	host string, orgRepo string, path string, gitSuff string) {
+	n, subdir, hasSubdir := splitSubdir(n)
+	if hasSubdir {
+		defer func() { path = filepath.Join(path, subdir) }()
+	}
 	if strings.Contains(n, gitDelimiter) {
 		index := strings.Index(n, gitDelimiter)
 		// Adding _git/ to host
 		host = normalizeGitHostSpec(n[:index+len(gitDelimiter)])
-		orgRepo = strings.Split(strings.Split(n[index+len(gitDelimiter):], "/")[0], "?")[0]
-		path, gitRef = peelQuery(n[index+len(gitDelimiter)+len(orgRepo):])
+		orgRepo = strings.Split(n[index+len(gitDelimiter):], "/")[0]
+		path = n[index+len(gitDelimiter)+len(orgRepo):]
 		return
 	}
 	host, n = parseHostSpec(n)
@@ -128,36 +216,27 @@ func parseGitUrl(n string) (
 	if strings.Contains(n, gitSuffix) {
 		index := strings.Index(n, gitSuffix)
 		orgRepo = n[0:index]
-		n = n[index+len(gitSuffix):]
-		path, gitRef = peelQuery(n)
+		path = n[index+len(gitSuffix):]
+		return
+	}
+	if hasSubdir {
+		orgRepo = n
 		return
 	}
 
 	i := strings.Index(n, "/")
 	if i < 1 {
-		return "", "", "", "", ""
+		return "", "", "", ""
 	}
 	j := strings.Index(n[i+1:], "/")
 	if j >= 0 {
 		j += i + 1
 		orgRepo = n[:j]
-		path, gitRef = peelQuery(n[j+1:])
+		path = n[j+1:]
 		return
 	}
-	path = ""
-	orgRepo, gitRef = peelQuery(n)
-	return host, orgRepo, path, gitRef, gitSuff
-}
-
-func peelQuery(arg string) (string, string) {
-
-	r, _ := regexp.Compile(refQueryRegex)
-	j := r.FindStringIndex(arg)
-
-	if len(j) > 0 {
-		return arg[:j[0]], arg[j[0]+len(r.FindString(arg)):]
-	}
-	return arg, ""
+	orgRepo = n
+	return
 }
 
 func parseHostSpec(n string) (string, string) {
@@ -106,6 +106,8 @@ var badData = [][]string{
 	{"htxxxtp://github.com/", "url lacks host"},
 	{"ssh://git.example.com", "url lacks orgRepo"},
 	{"git::___", "url lacks orgRepo"},
+	{"github.com/someOrg/someRepo?ref=a&version=b", "bad query in url"},
+	{"github.com/someOrg/someRepo?depth=0", "bad query in url"},
 }
 
 func TestNewRepoSpecFromUrlErrors(t *testing.T) {
@@ -193,6 +195,30 @@ func TestNewRepoSpecFromUrl_CloneSpecs(t *testing.T) {
 			absPath:   notCloned.String(),
 			ref:       "",
 		},
+		{
+			input:     "https://github.com/someorg/somerepo//deploy/overlays/prod?ref=v1.2.3",
+			cloneSpec: "https://github.com/someorg/somerepo.git",
+			absPath:   notCloned.Join("deploy/overlays/prod"),
+			ref:       "v1.2.3",
+		},
+		{
+			input:     "https://gitlab.com/somegroup/subgroup/somerepo//deploy?ref=v1.2.3&depth=5",
+			cloneSpec: "https://gitlab.com/somegroup/subgroup/somerepo.git",
+			absPath:   notCloned.Join("deploy"),
+			ref:       "v1.2.3",
+		},
+		{
+			input:     "git::https://gitlab.com/somegroup/subgroup/somerepo.git//deploy",
+			cloneSpec: "https://gitlab.com/somegroup/subgroup/somerepo.git",
+			absPath:   notCloned.Join("deploy"),
+			ref:       "",
+		},
+		{
+			input:     "https://dev.azure.com/someorg/someproject/_git/somerepo//deploy?version=v1.0.0",
+			cloneSpec: "https://dev.azure.com/someorg/someproject/_git/somerepo",
+			absPath:   notCloned.Join("deploy"),
+			ref:       "v1.0.0",
+		},
 	}
 	for _, testcase := range testcases {
 		rs, err := NewRepoSpecFromUrl(testcase.input)
@@ -244,28 +270,54 @@ func TestIsAzureHost(t *testing.T) {
 	}
 }
 
-func TestPeelQuery(t *testing.T) {
+func TestParseQuery(t *testing.T) {
 	testcases := []struct {
-		input  string
-		expect [2]string
+		input string
+		ref   string
+		depth int
 	}{
 		{
-			input:  "somerepos?ref=v1.0.0",
-			expect: [2]string{"somerepos", "v1.0.0"},
+			input: "ref=v1.0.0",
+			ref:   "v1.0.0",
+		},
+		{
+			input: "version=master",
+			ref:   "master",
 		},
 		{
-			input:  "somerepos?version=master",
-			expect: [2]string{"somerepos", "master"},
+			input: "ref=v1.0.0&depth=10",
+			ref:   "v1.0.0",
+			depth: 10,
 		},
 		{
-			input:  "somerepos",
-			expect: [2]string{"somerepos", ""},
+			input: "",
 		},
 	}
 	for _, testcase := range testcases {
-		path, ref := peelQuery(testcase.input)
-		if path != testcase.expect[0] || ref != testcase.expect[1] {
-			t.Errorf("peelQuery: expected (%s, %s) got (%s, %s) on %s", testcase.expect[0], testcase.expect[1], path, ref, testcase.input)
+		ref, depth, err := parseQuery(testcase.input)
+		if err != nil {
+			t.Errorf("parseQuery: unexpected error %v on %s", err, testcase.input)
+		}
+		if ref != testcase.ref || depth != testcase.depth {
+			t.Errorf("parseQuery: expected (%s, %d) got (%s, %d) on %s",
+				testcase.ref, testcase.depth, ref, depth, testcase.input)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	testcases := map[string]string{
+		"ref=a&version=b": "only one of ref and version may be set",
+		"ref=a&ref=b":     "ref is repeated",
+		"depth=0":         "depth must be a positive integer",
+		"depth=deep":      "depth must be a positive integer",
+		"timeout=10s":     "unknown parameter timeout",
+	}
+	for input, expected := range testcases {
+		_, _, err := parseQuery(input)
+		if err == nil || err.Error() != expected {
+			t.Errorf("parseQuery: expected error %q, got %v on %s",
+				expected, err, input)
 		}
 	}
 }
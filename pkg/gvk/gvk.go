@@ -104,21 +104,35 @@ var orderFirst = []string{
 var orderLast = []string{
 	"ValidatingWebhookConfiguration",
 }
-var typeOrders = func() map[string]int {
-	m := map[string]int{}
-	for i, n := range orderFirst {
-		m[n] = -len(orderFirst) + i
-	}
-	for i, n := range orderLast {
+var typeOrders = NewKindOrder(orderFirst, orderLast)
+
+// KindOrder ranks kinds, for sorting.
+type KindOrder map[string]int
+
+// NewKindOrder returns the order ranking the kinds
+// first listed first, in order, and the kinds last
+// listed last, in order, and other kinds in between.
+func NewKindOrder(first, last []string) KindOrder {
+	m := KindOrder{}
+	for i, n := range first {
+		m[n] = -len(first) + i
+	}
+	for i, n := range last {
 		m[n] = 1 + i
 	}
 	return m
-}()
+}
 
 // IsLessThan returns true if self is less than the argument.
 func (x Gvk) IsLessThan(o Gvk) bool {
-	indexI := typeOrders[x.Kind]
-	indexJ := typeOrders[o.Kind]
+	return x.IsLessThanIn(o, typeOrders)
+}
+
+// IsLessThanIn returns true if self is less than
+// the argument, ranking kinds by the given order.
+func (x Gvk) IsLessThanIn(o Gvk, order KindOrder) bool {
+	indexI := order[x.Kind]
+	indexJ := order[o.Kind]
 	if indexI != indexJ {
 		return indexI < indexJ
 	}
@@ -4,12 +4,47 @@
 package hasher
 
 import (
+	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 )
 
+const (
+	// DefaultAlgorithm names the function hashing
+	// generated resources by default.
+	DefaultAlgorithm = "sha256"
+	// DefaultLength is the length of encoded hashes by default.
+	DefaultLength = 10
+	// MinLength is the shortest encoded hash allowed.
+	MinLength = 5
+)
+
+// algorithms maps the names of hash functions
+// to functions returning the hex form of a hash.
+var algorithms = map[string]func(data string) string{
+	"sha1": func(data string) string {
+		return fmt.Sprintf("%x", sha1.Sum([]byte(data)))
+	},
+	"sha256": Hash,
+	"sha512": func(data string) string {
+		return fmt.Sprintf("%x", sha512.Sum512([]byte(data)))
+	},
+}
+
+// Algorithms returns the names of the known hash functions.
+func Algorithms() []string {
+	var result []string
+	for k := range algorithms {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
 // SortArrayAndComputeHash sorts a string array and
 // returns a hash for it
 func SortArrayAndComputeHash(s []string) (string, error) {
@@ -24,11 +59,16 @@ func SortArrayAndComputeHash(s []string) (string, error) {
 // Copied from https://github.com/kubernetes/kubernetes
 // /blob/master/pkg/kubectl/util/hash/hash.go
 func Encode(hex string) (string, error) {
-	if len(hex) < 10 {
+	return EncodeN(hex, DefaultLength)
+}
+
+// EncodeN is like Encode, keeping the first n characters.
+func EncodeN(hex string, n int) (string, error) {
+	if len(hex) < n {
 		return "", fmt.Errorf(
-			"input length must be at least 10")
+			"input length must be at least %d", n)
 	}
-	enc := []rune(hex[:10])
+	enc := []rune(hex[:n])
 	for i := range enc {
 		switch enc[i] {
 		case '0':
@@ -50,3 +90,28 @@ func Encode(hex string) (string, error) {
 func Hash(data string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(data)))
 }
+
+// HashAndEncode hashes the data with the named algorithm,
+// and encodes the first n characters of the hash.  The
+// empty name and zero length mean the defaults.
+func HashAndEncode(algorithm string, n int, data string) (string, error) {
+	if algorithm == "" {
+		algorithm = DefaultAlgorithm
+	}
+	if n == 0 {
+		n = DefaultLength
+	}
+	f, ok := algorithms[algorithm]
+	if !ok {
+		return "", fmt.Errorf(
+			"unknown hash algorithm %q, must be one of %s",
+			algorithm, strings.Join(Algorithms(), ", "))
+	}
+	hex := f(data)
+	if n < MinLength || n > len(hex) {
+		return "", fmt.Errorf(
+			"hash length %d with %s must be between %d and %d",
+			n, algorithm, MinLength, len(hex))
+	}
+	return EncodeN(hex, n)
+}
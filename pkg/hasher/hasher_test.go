@@ -4,6 +4,7 @@
 package hasher_test
 
 import (
+	"strings"
 	"testing"
 
 	. "sigs.k8s.io/kustomize/v3/pkg/hasher"
@@ -39,3 +40,35 @@ func TestHash(t *testing.T) {
 		t.Errorf("expected hash %q but got %q", expect, sum)
 	}
 }
+
+func TestHashAndEncode(t *testing.T) {
+	cases := []struct {
+		algorithm string
+		length    int
+		expect    string
+		err       string
+	}{
+		{"", 0, "tkbgc44298", ""},
+		{"sha256", 5, "tkbgc", ""},
+		{"sha1", 10, "dmk9mktt5t", ""},
+		{"sha512", 16, "cf8kthk57ttfb8bd", ""},
+		{"md5", 10, "", `unknown hash algorithm "md5"`},
+		{"sha256", 4, "", "hash length 4 with sha256 must be between 5 and 64"},
+		{"sha1", 41, "", "hash length 41 with sha1 must be between 5 and 40"},
+	}
+	for _, c := range cases {
+		h, err := HashAndEncode(c.algorithm, c.length, "")
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("expected error %q but got %v", c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if h != c.expect {
+			t.Errorf("expected hash %q but got %q", c.expect, h)
+		}
+	}
+}
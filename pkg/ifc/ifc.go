@@ -66,6 +66,7 @@ type Kunstructured interface {
 	MatchesLabelSelector(selector string) (bool, error)
 	MatchesAnnotationSelector(selector string) (bool, error)
 	Patch(Kunstructured) error
+	PatchWithMergeKeys(Kunstructured, map[string]string) error
 }
 
 // KunstructuredFactory makes instances of Kunstructured.
@@ -87,6 +88,12 @@ type KunstructuredFactory interface {
 // or an error.
 type KunstructuredHasher interface {
 	Hash(Kunstructured) (string, error)
+	// HashWith is like Hash, using the named hash algorithm and
+	// encoding the hash to the given length.  The empty name
+	// and zero length mean the defaults.  If withNamespace is
+	// true, the namespace of the argument is hashed too.
+	HashWith(k Kunstructured, algorithm string, length int,
+		withNamespace bool) (string, error)
 }
 
 // See core.v1.SecretTypeOpaque
@@ -0,0 +1,65 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import "strings"
+
+// DefaultRegistry is the registry of images
+// whose names don't start with one.
+const DefaultRegistry = "docker.io"
+
+// RegistryRewrite replaces the registry, or a longer
+// prefix of the name, of images, e.g. to pull them
+// from a mirror.
+type RegistryRewrite struct {
+	// From is a registry, e.g. "docker.io", optionally
+	// followed by path components, e.g. "gcr.io/my-project".
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+
+	// To replaces From, e.g. "internal-mirror.example.com".
+	To string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Rewrite returns the image with From replaced by To, and
+// true, if From is a prefix of the image's fully qualified
+// name.  Otherwise it returns the image unchanged, and false.
+func (r RegistryRewrite) Rewrite(image string) (string, bool) {
+	name, tag := splitTag(image)
+	name = Qualify(name)
+	from := strings.TrimSuffix(r.From, "/")
+	if name != from && !strings.HasPrefix(name, from+"/") {
+		return image, false
+	}
+	return strings.TrimSuffix(r.To, "/") + name[len(from):] + tag, true
+}
+
+// Qualify returns the image name, without tag or digest,
+// prefixed with the default registry if it has none, as
+// docker does.  Official images of the default registry
+// are prefixed with "library/" too, e.g. "nginx" becomes
+// "docker.io/library/nginx".
+func Qualify(name string) string {
+	i := strings.Index(name, "/")
+	if i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			return name
+		}
+		return DefaultRegistry + "/" + name
+	}
+	return DefaultRegistry + "/library/" + name
+}
+
+// splitTag separates the tag or digest, with its
+// separator, from the name of the image.
+func splitTag(image string) (name, tag string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i], image[i:]
+	}
+	i := strings.LastIndex(image, ":")
+	if i > strings.LastIndex(image, "/") {
+		return image[:i], image[i:]
+	}
+	return image, ""
+}
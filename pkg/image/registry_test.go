@@ -0,0 +1,36 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import "testing"
+
+func TestRegistryRewrite(t *testing.T) {
+	mirror := RegistryRewrite{
+		From: "docker.io", To: "internal-mirror.example.com"}
+	project := RegistryRewrite{
+		From: "gcr.io/my-project/", To: "registry.local:5000/gcr"}
+	for _, tc := range []struct {
+		r        RegistryRewrite
+		image    string
+		expected string
+		ok       bool
+	}{
+		{mirror, "nginx", "internal-mirror.example.com/library/nginx", true},
+		{mirror, "nginx:1.17", "internal-mirror.example.com/library/nginx:1.17", true},
+		{mirror, "bitnami/redis@sha256:abc", "internal-mirror.example.com/bitnami/redis@sha256:abc", true},
+		{mirror, "docker.io/bitnami/redis:6", "internal-mirror.example.com/bitnami/redis:6", true},
+		{mirror, "docker.iox/redis", "docker.iox/redis", false},
+		{mirror, "quay.io/coreos/etcd:v3", "quay.io/coreos/etcd:v3", false},
+		{mirror, "localhost:5000/app", "localhost:5000/app", false},
+		{project, "gcr.io/my-project/app:v1", "registry.local:5000/gcr/app:v1", true},
+		{project, "gcr.io/my-project-2/app:v1", "gcr.io/my-project-2/app:v1", false},
+		{project, "gcr.io/other/app", "gcr.io/other/app", false},
+	} {
+		actual, ok := tc.r.Rewrite(tc.image)
+		if actual != tc.expected || ok != tc.ok {
+			t.Errorf("%v rewriting %s: expected %s %v, got %s %v",
+				tc.r, tc.image, tc.expected, tc.ok, actual, ok)
+		}
+	}
+}
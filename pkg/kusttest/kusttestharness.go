@@ -66,6 +66,12 @@ func (th *KustTestHarness) MakeKustTarget() *target.KustTarget {
 	return kt
 }
 
+// EnableHelm lets the kustomizations inflate
+// helm charts, running the given helm command.
+func (th *KustTestHarness) EnableHelm(command string) {
+	loader.EnableHelm(th.ldr, command)
+}
+
 func (th *KustTestHarness) WriteF(dir string, content string) {
 	err := th.ldr.AddFile(dir, []byte(content))
 	if err != nil {
@@ -83,6 +89,16 @@ kind: Kustomization
 `+content)
 }
 
+func (th *KustTestHarness) WriteC(dir string, content string) {
+	th.WriteF(
+		filepath.Join(
+			dir,
+			pgmconfig.DefaultKustomizationFileName()), `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+`+content)
+}
+
 func (th *KustTestHarness) RF() *resource.Factory {
 	return th.rf.RF()
 }
@@ -124,6 +140,20 @@ func (th *KustTestHarness) LoadAndRunGenerator(
 	return rm
 }
 
+func (th *KustTestHarness) ErrorFromLoadAndRunGenerator(
+	config string) error {
+	res, err := th.rf.RF().FromBytes([]byte(config))
+	if err != nil {
+		th.t.Fatalf("Err: %v", err)
+	}
+	g, err := th.pl.LoadGenerator(th.ldr, res)
+	if err != nil {
+		return err
+	}
+	_, err = g.Generate()
+	return err
+}
+
 func (th *KustTestHarness) LoadAndRunTransformer(
 	config, input string) resmap.ResMap {
 	resMap, err := th.RunTransformer(config, input)
@@ -0,0 +1,319 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+// An archive base is a kustomization in a tarball or zip
+// archive, named by its https URL, optionally followed
+// by the expected sha256 of the archive, e.g.
+//
+//   https://example.com/bases/web-v1.tar.gz#sha256=9f86d0...
+//
+// The archive is extracted to a temporary directory, which
+// the loader of the base is rooted at.  If the archive holds
+// nothing but a directory, as e.g. GitHub's release archives
+// do, the loader is rooted at that directory instead.
+var archiveSuffixes = []string{".tar.gz", ".tgz", ".zip"}
+
+func isArchive(location string) bool {
+	if !isRemoteFile(location) {
+		return false
+	}
+	for _, s := range archiveSuffixes {
+		if strings.HasSuffix(remoteFileBase(location), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveSpec specifies an extracted archive.
+type archiveSpec struct {
+	// raw is the URL of the archive.
+	raw string
+
+	// Dir is where the archive is extracted to.
+	Dir fs.ConfirmedDir
+}
+
+func (a *archiveSpec) Cleaner(fSys fs.FileSystem) func() error {
+	return func() error {
+		if err := fSys.RemoveAll(a.Dir.String()); err != nil {
+			return err
+		}
+		// The directory was made on disk, even
+		// if the archive went to another fSys.
+		return os.RemoveAll(a.Dir.String())
+	}
+}
+
+// newLoaderAtArchive returns a new Loader pinned to a
+// temporary directory holding an extracted archive.
+func (fl *fileLoader) newLoaderAtArchive(location string) (ifc.Loader, error) {
+	if err := fl.errIfArchiveCycle(location); err != nil {
+		return nil, err
+	}
+	content, err := fl.loadRemoteFile(location)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := fs.NewTmpConfirmedDir()
+	if err != nil {
+		return nil, err
+	}
+	a := &archiveSpec{raw: location, Dir: dir}
+	budget := &archiveBudget{lim: fl.inheritedInputLimits()}
+	if err = fl.fSys.MkdirAll(dir.String()); err == nil {
+		if strings.HasSuffix(remoteFileBase(location), ".zip") {
+			err = extractZip(fl.fSys, dir, content, budget)
+		} else {
+			err = extractTarGz(fl.fSys, dir, content, budget)
+		}
+	}
+	if err != nil {
+		a.Cleaner(fl.fSys)()
+		return nil, errors.Wrapf(err, "extracting %s", location)
+	}
+	root, err := archiveRoot(fl.fSys, dir)
+	if err != nil {
+		a.Cleaner(fl.fSys)()
+		return nil, err
+	}
+	return &fileLoader{
+		// Archives are never allowed to escape root.
+		loadRestrictor: RestrictionRootOnly,
+		validator:      fl.validator,
+		root:           root,
+		referrer:       fl,
+		archive:        a,
+		fSys:           fl.fSys,
+		cloner:         fl.cloner,
+		fetcher:        fl.fetcher,
+		cleaner:        a.Cleaner(fl.fSys),
+	}, nil
+}
+
+// archiveRoot returns the directory an archive
+// extracted to dir holds, if it holds nothing
+// else, and dir otherwise.
+func archiveRoot(
+	fSys fs.FileSystem, dir fs.ConfirmedDir) (fs.ConfirmedDir, error) {
+	entries, err := fSys.Glob(dir.Join("*"))
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && fSys.IsDir(entries[0]) {
+		return fs.ConfirmedDir(entries[0]), nil
+	}
+	return dir, nil
+}
+
+// archivePath returns the path below dir an entry of
+// an archive is extracted to, refusing entries that
+// would land outside of dir.  Backslashes, which some
+// Windows tools separate the elements of names with,
+// separate them too.
+func archivePath(dir fs.ConfirmedDir, name string) (string, error) {
+	clean := path.Clean(strings.Replace(name, `\`, "/", -1))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf(
+			"archive entry '%s' is outside the archive", name)
+	}
+	return dir.Join(filepath.FromSlash(clean)), nil
+}
+
+// archiveBudget bounds what extracting an archive
+// takes in, before a decompression bomb fills memory:
+// the bytes of each file, of all files together, and
+// the number of entries.  Limits of 0 bound nothing.
+type archiveBudget struct {
+	lim     InputLimits
+	entries int
+	size    int64
+}
+
+// addEntry counts an entry, failing if
+// there are more than the limit allows.
+func (b *archiveBudget) addEntry() error {
+	b.entries++
+	if max := b.lim.MaxArchiveEntries; max > 0 && b.entries > max {
+		return fmt.Errorf(
+			"the archive holds more than %d entries, the limit", max)
+	}
+	return nil
+}
+
+// read reads the file of an entry, failing if it, or all
+// the files read so far, hold more than the limits allow.
+func (b *archiveBudget) read(r io.Reader, name string) ([]byte, error) {
+	max := b.lim.MaxFileSize
+	left := b.lim.MaxArchiveSize - b.size
+	if b.lim.MaxArchiveSize <= 0 || (max > 0 && max <= left) {
+		data, err := readLimited(r, max, name)
+		b.size += int64(len(data))
+		return data, err
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, left+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > left {
+		return nil, fmt.Errorf(
+			"the files of the archive hold more than %d bytes, the limit",
+			b.lim.MaxArchiveSize)
+	}
+	b.size += int64(len(data))
+	return data, nil
+}
+
+// extractTarGz extracts the archive into dir, failing
+// if it takes in more than the budget allows.
+func extractTarGz(
+	fSys fs.FileSystem, dir fs.ConfirmedDir,
+	content []byte, b *archiveBudget) error {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	r := tar.NewReader(gz)
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = b.addEntry(); err != nil {
+			return err
+		}
+		switch h.Typeflag {
+		case tar.TypeDir:
+			err = extractDir(fSys, dir, h.Name)
+		case tar.TypeReg, tar.TypeRegA:
+			err = extractFile(fSys, dir, h.Name, r, b)
+		default:
+			// Links and devices have no place in a base.
+			err = fmt.Errorf(
+				"archive entry '%s' isn't a file or directory", h.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// extractZip extracts the archive as extractTarGz does.
+func extractZip(
+	fSys fs.FileSystem, dir fs.ConfirmedDir,
+	content []byte, b *archiveBudget) error {
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return err
+	}
+	for _, f := range r.File {
+		if err = b.addEntry(); err != nil {
+			return err
+		}
+		mode := f.Mode()
+		if mode.IsDir() {
+			err = extractDir(fSys, dir, f.Name)
+		} else if mode.IsRegular() {
+			var rc io.ReadCloser
+			if rc, err = f.Open(); err == nil {
+				err = extractFile(fSys, dir, f.Name, rc, b)
+				rc.Close()
+			}
+		} else {
+			err = fmt.Errorf(
+				"archive entry '%s' isn't a file or directory", f.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractDir(fSys fs.FileSystem, dir fs.ConfirmedDir, name string) error {
+	p, err := archivePath(dir, name)
+	if err != nil {
+		return err
+	}
+	return fSys.MkdirAll(p)
+}
+
+func extractFile(
+	fSys fs.FileSystem, dir fs.ConfirmedDir,
+	name string, r io.Reader, b *archiveBudget) error {
+	p, err := archivePath(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := fSys.MkdirAll(filepath.Dir(p)); err != nil {
+		return err
+	}
+	data, err := b.read(r, name)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(p, data)
+}
+
+// containingArchive looks back through referrers for
+// an extracted archive, returning nil if none found.
+func (fl *fileLoader) containingArchive() *archiveSpec {
+	if fl.archive != nil {
+		return fl.archive
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.containingArchive()
+}
+
+func (fl *fileLoader) errIfArchiveContainmentViolation(
+	base fs.ConfirmedDir) error {
+	a := fl.containingArchive()
+	if a == nil {
+		return nil
+	}
+	if !base.HasPrefix(a.Dir) {
+		return fmt.Errorf(
+			"security; bases in kustomizations found in "+
+				"archives must be within the archive, "+
+				"but base '%s' is outside '%s'",
+			base, a.Dir)
+	}
+	return nil
+}
+
+func (fl *fileLoader) errIfArchiveCycle(location string) error {
+	if fl.archive != nil && fl.archive.raw == location {
+		return fmt.Errorf(
+			"cycle detected: URI '%s' referenced by previous URI '%s'",
+			location, fl.archive.raw)
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.errIfArchiveCycle(location)
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+const archivedKustomization = "resources:\n- deployment.yaml\n"
+
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range files {
+		err := w.WriteHeader(&tar.Header{
+			Name: name, Mode: 0644, Size: int64(len(content)),
+			Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		w.Write([]byte(content))
+	}
+	w.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func makeZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f.Write([]byte(content))
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+func makeLoaderWithArchives(
+	t *testing.T, archives map[string][]byte) (*fileLoader, *httptest.Server) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			content, ok := archives[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(content)
+		}))
+	l := NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	l.http = server.Client()
+	return l, server
+}
+
+func TestIsArchive(t *testing.T) {
+	for location, expected := range map[string]bool{
+		"https://example.com/web.tar.gz":             true,
+		"https://example.com/web.tgz#sha256=9f86d0":  true,
+		"https://example.com/web.zip?token=secret":   true,
+		"https://example.com/web.yaml":               false,
+		"http://example.com/web.zip":                 false,
+		"github.com/someOrg/someRepo/web.tar.gz":     false,
+		"https://github.com/someOrg/someRepo//bases": false,
+	} {
+		if isArchive(location) != expected {
+			t.Fatalf("expected isArchive(%s) to be %v", location, expected)
+		}
+	}
+}
+
+func TestLoaderAtArchive(t *testing.T) {
+	tarGz := makeTarGz(t, map[string]string{
+		"web-v1/kustomization.yaml": archivedKustomization,
+		"web-v1/deployment.yaml":    "kind: Deployment\n",
+	})
+	l, server := makeLoaderWithArchives(t, map[string][]byte{
+		"/web-v1.tar.gz": tarGz,
+		"/web-v1.zip": makeZip(t, map[string]string{
+			"kustomization.yaml": archivedKustomization,
+			"deployment.yaml":    "kind: Deployment\n",
+		}),
+	})
+	defer server.Close()
+
+	for _, location := range []string{
+		server.URL + "/web-v1.tar.gz",
+		server.URL + "/web-v1.tar.gz#sha256=" + sha256Of(string(tarGz)),
+		server.URL + "/web-v1.zip",
+	} {
+		l1, err := l.New(location)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		content, err := l1.Load("kustomization.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(content) != archivedKustomization {
+			t.Fatalf("unexpected content %q", content)
+		}
+		root := l1.Root()
+		if err = l1.Cleanup(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if l.fSys.Exists(root) {
+			t.Fatalf("expected %s to be removed", root)
+		}
+	}
+
+	_, err := l.New(server.URL + "/web-v1.tar.gz#sha256=" + sha256Of("other"))
+	if err == nil || !strings.Contains(err.Error(), "sha256 of") {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+}
+
+func TestLoaderAtArchiveRefusesEscapes(t *testing.T) {
+	l, server := makeLoaderWithArchives(t, map[string][]byte{
+		"/evil.tar.gz": makeTarGz(t, map[string]string{
+			"../../etc/kustomization.yaml": archivedKustomization,
+		}),
+		// Some Windows tools separate names with backslashes.
+		"/evil.zip": makeZip(t, map[string]string{
+			`..\..\etc\kustomization.yaml`: archivedKustomization,
+		}),
+		"/base.zip": makeZip(t, map[string]string{
+			"kustomization.yaml": archivedKustomization,
+		}),
+	})
+	defer server.Close()
+
+	for _, evil := range []string{"/evil.tar.gz", "/evil.zip"} {
+		_, err := l.New(server.URL + evil)
+		if err == nil || !strings.Contains(err.Error(), "outside the archive") {
+			t.Fatalf("expected escape error for %s, got %v", evil, err)
+		}
+	}
+
+	l1, err := l.New(server.URL + "/base.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer l1.Cleanup()
+	_, err = l1.New("../other")
+	if err == nil {
+		t.Fatalf("expected an error for a base outside the archive")
+	}
+	_, err = l1.New(server.URL + "/base.zip")
+	if err == nil || !strings.Contains(err.Error(), "cycle detected") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+// A bucket base is a kustomization published to an S3
+// or GCS bucket, named by the URL of its prefix, e.g.
+//
+//   s3://acme-bases/rendered/web
+//   gs://acme-bases/rendered/web
+//
+// The objects below the prefix are copied to a temporary
+// directory, which the loader of the base is rooted at.
+const (
+	s3Scheme  = "s3://"
+	gcsScheme = "gs://"
+)
+
+// bucketSpec specifies the prefix of a bucket holding a base.
+type bucketSpec struct {
+	// raw is the URL the spec was made from.
+	raw string
+
+	// Scheme is either s3Scheme or gcsScheme.
+	Scheme string
+
+	// Bucket is the name of the bucket.
+	Bucket string
+
+	// Prefix is the prefix of the objects in the
+	// bucket, without leading or trailing slashes.
+	Prefix string
+
+	// Dir is where the objects are copied to.
+	Dir fs.ConfirmedDir
+
+	// Timeout bounds the copying, unless it's 0.
+	Timeout time.Duration
+}
+
+// newBucketSpec parses a bucket URL.
+func newBucketSpec(location string) (*bucketSpec, error) {
+	var scheme string
+	switch {
+	case strings.HasPrefix(location, s3Scheme):
+		scheme = s3Scheme
+	case strings.HasPrefix(location, gcsScheme):
+		scheme = gcsScheme
+	default:
+		return nil, fmt.Errorf(
+			"%s isn't a %s or %s url", location, s3Scheme, gcsScheme)
+	}
+	rest := strings.Trim(strings.TrimPrefix(location, scheme), "/")
+	parts := strings.SplitN(rest, "/", 2)
+	if parts[0] == "" {
+		return nil, fmt.Errorf("url lacks bucket: %s", location)
+	}
+	b := &bucketSpec{raw: location, Scheme: scheme, Bucket: parts[0]}
+	if len(parts) > 1 {
+		b.Prefix = strings.Trim(parts[1], "/")
+	}
+	return b, nil
+}
+
+// URL returns the URL of the prefix, in the
+// form the bucket's command line tool takes.
+func (b *bucketSpec) URL() string {
+	if b.Prefix == "" {
+		return b.Scheme + b.Bucket
+	}
+	return b.Scheme + b.Bucket + "/" + b.Prefix
+}
+
+func (b *bucketSpec) Cleaner(fSys fs.FileSystem) func() error {
+	return func() error {
+		if err := fSys.RemoveAll(b.Dir.String()); err != nil {
+			return err
+		}
+		// The directory was made on disk, even
+		// if the objects went to another fSys.
+		return os.RemoveAll(b.Dir.String())
+	}
+}
+
+// bucketFetcher is a function that can copy the
+// objects below the prefix of a bucket to its Dir.
+type bucketFetcher func(b *bucketSpec) error
+
+// fetchBucketUsingCli uses a local install of the aws or gsutil
+// command, which find credentials the way other tools of their
+// cloud do, e.g. in the environment, a profile or the metadata
+// server of the instance kustomize is running on.
+func fetchBucketUsingCli(b *bucketSpec) error {
+	program := "aws"
+	args := []string{"s3", "sync", "--only-show-errors", b.URL()}
+	if b.Scheme == gcsScheme {
+		program = "gsutil"
+		args = []string{"-m", "-q", "rsync", "-r", b.URL()}
+	}
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return errors.Wrapf(err, "no '%s' program on path", program)
+	}
+	b.Dir, err = fs.NewTmpConfirmedDir()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.Background(), func() {}
+	if b.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+	}
+	defer cancel()
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, append(args, b.Dir.String())...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(
+			err, "trouble copying %s: %s", b.URL(), out.String())
+	}
+	return nil
+}
+
+// doNothingFetcher returns a fetcher that only sets
+// the Dir of the bucketSpec.  It's assumed that the
+// dir is in some fake filesystem used in a test.
+func doNothingFetcher(dir fs.ConfirmedDir) bucketFetcher {
+	return func(b *bucketSpec) error {
+		b.Dir = dir
+		return nil
+	}
+}
+
+// newLoaderAtBucketCopy returns a new Loader pinned to a
+// temporary directory holding a copy of a bucket's prefix.
+func newLoaderAtBucketCopy(
+	b *bucketSpec,
+	v ifc.Validator, fSys fs.FileSystem,
+	referrer *fileLoader, cloner git.Cloner,
+	fetcher bucketFetcher) (ifc.Loader, error) {
+	err := referrer.fetchWithRetries(func(timeout time.Duration) error {
+		b.Timeout = timeout
+		dir := b.Dir
+		err := fetcher(b)
+		if err != nil && b.Dir != dir {
+			// Don't leave a failed attempt behind.
+			b.Cleaner(fSys)()
+			b.Dir = dir
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	l := &fileLoader{
+		// Bucket copies are never allowed to escape root.
+		loadRestrictor: RestrictionRootOnly,
+		validator:      v,
+		root:           b.Dir,
+		referrer:       referrer,
+		bucket:         b,
+		fSys:           fSys,
+		cloner:         cloner,
+		fetcher:        fetcher,
+		cleaner:        b.Cleaner(fSys),
+	}
+	if err = l.lockBucket(); err != nil {
+		l.Cleanup()
+		return nil, err
+	}
+	return l, nil
+}
+
+// containingBucket looks back through referrers for a
+// bucket copy, returning nil if none found.
+func (fl *fileLoader) containingBucket() *bucketSpec {
+	if fl.bucket != nil {
+		return fl.bucket
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.containingBucket()
+}
+
+func (fl *fileLoader) errIfBucketContainmentViolation(
+	base fs.ConfirmedDir) error {
+	b := fl.containingBucket()
+	if b == nil {
+		return nil
+	}
+	if !base.HasPrefix(b.Dir) {
+		return fmt.Errorf(
+			"security; bases in kustomizations found in "+
+				"buckets must be within the bucket's copy, "+
+				"but base '%s' is outside '%s'",
+			base, b.Dir)
+	}
+	return nil
+}
+
+// errIfBucketCycle returns an error if the bucket's prefix
+// holds, or is, that of a bucket in the referrer chain.
+func (fl *fileLoader) errIfBucketCycle(b *bucketSpec) error {
+	if fl.bucket != nil && (fl.bucket.URL() == b.URL() ||
+		strings.HasPrefix(fl.bucket.URL(), b.URL()+"/")) {
+		return fmt.Errorf(
+			"cycle detected: URI '%s' referenced by previous URI '%s'",
+			b.raw, fl.bucket.raw)
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.errIfBucketCycle(b)
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestNewBucketSpec(t *testing.T) {
+	for _, c := range []struct {
+		location string
+		expected *bucketSpec
+	}{
+		{"s3://acme-bases/rendered/web/", &bucketSpec{
+			raw: "s3://acme-bases/rendered/web/", Scheme: s3Scheme,
+			Bucket: "acme-bases", Prefix: "rendered/web"}},
+		{"gs://acme-bases", &bucketSpec{
+			raw: "gs://acme-bases", Scheme: gcsScheme,
+			Bucket: "acme-bases"}},
+	} {
+		b, err := newBucketSpec(c.location)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(b, c.expected) {
+			t.Fatalf("expected %v, got %v", c.expected, b)
+		}
+	}
+	for _, location := range []string{
+		"s3://", "https://acme.com/bases", "../base",
+		"github.com/someOrg/someRepo/base",
+	} {
+		if _, err := newBucketSpec(location); err == nil {
+			t.Fatalf("expected an error for %s", location)
+		}
+	}
+}
+
+func makeLoaderWithBucket(t *testing.T) *fileLoader {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app")
+	fSys.MkdirAll("/bucketCopy/web")
+	fSys.MkdirAll("/bucketCopy/common")
+	fSys.WriteFile("/bucketCopy/web/kustomization.yaml", []byte("resources: []"))
+	root, err := demandDirectoryRoot(fSys, "/app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	l := newLoaderAtConfirmedDir(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		root, fSys, nil, nil)
+	l.fetcher = doNothingFetcher("/bucketCopy")
+	return l
+}
+
+func TestLoaderAtBucketCopy(t *testing.T) {
+	l := makeLoaderWithBucket(t)
+	l1, err := l.New("s3://acme-bases")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l1.Root() != "/bucketCopy" {
+		t.Fatalf("unexpected root %s", l1.Root())
+	}
+	content, err := l1.Load("web/kustomization.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "resources: []" {
+		t.Fatalf("unexpected content %q", content)
+	}
+
+	// Bases below the copy are fine, but not above it.
+	l2, err := l1.New("web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = l2.New("../../app")
+	if err == nil || !strings.Contains(err.Error(), "outside '/bucketCopy'") {
+		t.Fatalf("expected containment error, got %v", err)
+	}
+
+	// A bucket base may not refer to the prefix holding it.
+	_, err = l2.New("s3://acme-bases")
+	if err == nil || !strings.Contains(err.Error(), "cycle detected") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestErrIfBucketCycle(t *testing.T) {
+	for _, c := range []struct {
+		previous string
+		next     string
+		cycle    bool
+	}{
+		{"s3://a/web", "s3://a/web", true},
+		{"s3://a/web/v2", "s3://a/web", true},
+		{"s3://a/web", "s3://a", true},
+		{"s3://a/web-v2", "s3://a/web", false},
+		{"s3://a/web", "s3://a/web/v2", false},
+		{"s3://ab", "s3://a", false},
+	} {
+		previous, err := newBucketSpec(c.previous)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		next, err := newBucketSpec(c.next)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		l := &fileLoader{bucket: previous}
+		if err = l.errIfBucketCycle(next); (err != nil) != c.cycle {
+			t.Fatalf("%s after %s: expected cycle %t, got %v",
+				c.next, c.previous, c.cycle, err)
+		}
+	}
+}
+
+func TestBucketCleanerRemovesDirOnDisk(t *testing.T) {
+	dir, err := fs.NewTmpConfirmedDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := &bucketSpec{Dir: dir}
+	if err = b.Cleaner(fs.MakeFsInMemory())(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = os.Stat(dir.String()); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, got %v", dir, err)
+	}
+}
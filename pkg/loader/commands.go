@@ -0,0 +1,95 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+const (
+	flagEnableExecSecretsName = "enable_exec_secrets"
+	flagEnableExecSecretsHelp = "if set, secret generators may run the " +
+		"commands they list, capturing the output as secret values."
+)
+
+// AddFlagEnableExecSecrets adds the flag allowing secret
+// generators to run commands.
+func AddFlagEnableExecSecrets(set *pflag.FlagSet, v *bool) {
+	set.BoolVar(
+		v, flagEnableExecSecretsName,
+		false, flagEnableExecSecretsHelp)
+}
+
+// EnableExecSecrets lets the loader, and the loaders it makes
+// for local kustomizations, run the commands of secret generators.
+// Remote kustomizations, e.g. those of git repos or archives,
+// can never run commands.
+func EnableExecSecrets(l ifc.Loader) {
+	if fl, ok := l.(*fileLoader); ok {
+		fl.execEnabled = true
+	}
+}
+
+// execAllowed returns an error unless some loader in
+// the referrer chain enabled commands, and none of them
+// holds a kustomization fetched from remote.
+func (fl *fileLoader) execAllowed() error {
+	if fl.fetchedTree() != "" {
+		return fmt.Errorf(
+			"remote kustomizations cannot run commands")
+	}
+	for l := fl; l != nil; l = l.referrer {
+		if l.execEnabled {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"running commands requires --%s", flagEnableExecSecretsName)
+}
+
+// keyValuesFromCommands runs each command with sh in the
+// root, and pairs the command's key with its stdout, less
+// one trailing newline.  Keys are taken in lexical order.
+func (fl *fileLoader) keyValuesFromCommands(
+	commands map[string]string) ([]types.Pair, error) {
+	if len(commands) == 0 {
+		return nil, nil
+	}
+	if err := fl.execAllowed(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for k := range commands {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var kvs []types.Pair
+	for _, k := range keys {
+		cmd := exec.Command("sh", "-c", commands[k])
+		if _, err := os.Stat(fl.Root()); err == nil {
+			cmd.Dir = fl.Root()
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "running command for key %s: %s", k, stderr.String())
+		}
+		value := strings.TrimSuffix(string(out), "\n")
+		value = strings.TrimSuffix(value, "\r")
+		kvs = append(kvs, types.Pair{Key: k, Value: value})
+	}
+	return kvs, nil
+}
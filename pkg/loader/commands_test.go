@@ -0,0 +1,126 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func commandArgs(commands map[string]string) types.GeneratorArgs {
+	return types.GeneratorArgs{
+		DataSources: types.DataSources{
+			LiteralSources: []string{"user=admin"},
+		},
+		Commands: commands,
+	}
+}
+
+func TestLoadKvPairsFromCommands(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.Mkdir("/base")
+	l := NewFileLoaderAtRoot(validators.MakeFakeValidator(), fSys)
+	EnableExecSecrets(l)
+	// Loaders made by l may run commands too.
+	child, err := l.New("base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kvs, err := child.LoadKvPairs(commandArgs(map[string]string{
+		"token":    "printf 'abc\\n'",
+		"password": "echo hunter2",
+		"lines":    "printf 'a\\nb'",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []types.Pair{
+		{Key: "user", Value: "admin"},
+		{Key: "lines", Value: "a\nb"},
+		{Key: "password", Value: "hunter2"},
+		{Key: "token", Value: "abc"},
+	}
+	if !reflect.DeepEqual(kvs, expected) {
+		t.Fatalf("expected %v, got %v", expected, kvs)
+	}
+
+	_, err = l.LoadKvPairs(commandArgs(map[string]string{
+		"password": "echo oops >&2; exit 3",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Fatalf("expected command failure, got %v", err)
+	}
+}
+
+func TestLoadKvPairsFromCommandsDisabled(t *testing.T) {
+	l := NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	_, err := l.LoadKvPairs(commandArgs(map[string]string{
+		"password": "echo hunter2",
+	}))
+	if err == nil || !strings.Contains(err.Error(), flagEnableExecSecretsName) {
+		t.Fatalf("expected error naming the flag, got %v", err)
+	}
+}
+
+// assertCommandsRefused checks that the loader
+// can't run commands, though they're enabled.
+func assertCommandsRefused(t *testing.T, l ifc.Loader) {
+	_, err := l.LoadKvPairs(commandArgs(map[string]string{
+		"password": "echo hunter2",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "remote") {
+		t.Fatalf("expected remote refusal, got %v", err)
+	}
+}
+
+func TestLoadKvPairsFromCommandsArchive(t *testing.T) {
+	l, server := makeLoaderWithArchives(t, map[string][]byte{
+		"/web.tar.gz": makeTarGz(t, map[string]string{
+			"kustomization.yaml": "secretGenerator:\n" +
+				"- name: db\n  commands:\n    password: echo hunter2\n",
+		}),
+	})
+	defer server.Close()
+	EnableExecSecrets(l)
+	archive, err := l.New(server.URL + "/web.tar.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer archive.Cleanup()
+	assertCommandsRefused(t, archive)
+}
+
+func TestLoadKvPairsFromCommandsBucket(t *testing.T) {
+	l := makeLoaderWithBucket(t)
+	EnableExecSecrets(l)
+	bucket, err := l.New("s3://acme-bases")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Nor may the kustomizations within the copy.
+	web, err := bucket.New("web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCommandsRefused(t, bucket)
+	assertCommandsRefused(t, web)
+}
+
+func TestLoadKvPairsFromCommandsSchemeCopy(t *testing.T) {
+	l := makeLoaderWithScheme(t)
+	EnableExecSecrets(l)
+	copied, err := l.New("fake://bases/web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer copied.Cleanup()
+	assertCommandsRefused(t, copied)
+}
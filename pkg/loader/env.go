@@ -0,0 +1,25 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+// EnvReadable returns an error if the loader holds a
+// kustomization fetched from remote, which mustn't copy
+// the environment of the build, e.g. the credentials in
+// it, into the resources it builds.
+func EnvReadable(l ifc.Loader) error {
+	if d, ok := l.(delegator); ok {
+		l = d.Delegate()
+	}
+	if fl, ok := l.(*fileLoader); ok && fl.fetchedTree() != "" {
+		return fmt.Errorf(
+			"remote kustomizations cannot read environment variables")
+	}
+	return nil
+}
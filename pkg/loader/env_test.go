@@ -0,0 +1,41 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestEnvReadable(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/tmp/base/sub")
+	l := NewFileLoaderAtRoot(validators.MakeFakeValidator(), fSys)
+	if err := EnvReadable(l); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repoSpec, err := git.NewRepoSpecFromUrl("github.com/someOrg/someRepo/base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	remote, err := newLoaderAtGitClone(
+		repoSpec, validators.MakeFakeValidator(), fSys, nil,
+		git.DoNothingCloner(fs.ConfirmedDir("/tmp")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Nor may the kustomizations within the remote one.
+	child, err := remote.New("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, l := range []*fileLoader{remote.(*fileLoader), child.(*fileLoader)} {
+		if err := EnvReadable(l); err == nil {
+			t.Fatalf("expected %s to be refused", l.Root())
+		}
+	}
+}
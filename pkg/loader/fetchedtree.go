@@ -0,0 +1,37 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+// FetchedTree returns the root of the tree the remote
+// base of the loader was fetched into, e.g. the clone
+// of its git repo, and the URL the tree was fetched
+// from, or false if the loader's base isn't remote.
+func FetchedTree(l ifc.Loader) (root string, url string, ok bool) {
+	fl, ok := l.(*fileLoader)
+	if !ok {
+		return "", "", false
+	}
+	switch {
+	case fl.repoSpec != nil:
+		url = strings.TrimSuffix(fl.repoSpec.CloneSpec(), ".git")
+		if fl.repoSpec.Ref != "" {
+			url += "/" + fl.repoSpec.Ref
+		}
+		return fl.repoSpec.CloneDir().String(), url, true
+	case fl.bucket != nil:
+		return fl.bucket.Dir.String(), fl.bucket.URL(), true
+	case fl.archive != nil:
+		url = strings.SplitN(fl.archive.raw, "#", 2)[0]
+		return fl.archive.Dir.String(), url, true
+	case fl.scheme != nil:
+		return fl.scheme.Dir.String(), fl.scheme.raw, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+)
+
+func TestFetchedTree(t *testing.T) {
+	l := makeLoaderWithBucket(t)
+	if _, _, ok := FetchedTree(l); ok {
+		t.Fatalf("expected a local base not to have a fetched tree")
+	}
+	l1, err := l.New("s3://acme-bases/rendered")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root, url, ok := FetchedTree(l1)
+	if !ok || root != "/bucketCopy" || url != "s3://acme-bases/rendered" {
+		t.Fatalf("unexpected tree %s fetched from %s", root, url)
+	}
+
+	l.fSys.MkdirAll("/clone/web")
+	l.cloner = func(rs *git.RepoSpec) error {
+		rs.Dir = "/clone"
+		return nil
+	}
+	l2, err := l.New("github.com/someOrg/someRepo/web?ref=v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root, url, ok = FetchedTree(l2)
+	if !ok || root != "/clone" || url != "https://github.com/someOrg/someRepo/v1" {
+		t.Fatalf("unexpected tree %s fetched from %s", root, url)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+const (
+	flagFetchTimeoutName = "fetch-timeout"
+	flagFetchTimeoutHelp = "how long fetching a remote resource or " +
+		"base, e.g. cloning a git repo, may take; 0 means forever."
+
+	flagFetchRetriesName = "fetch-retries"
+	flagFetchRetriesHelp = "how many times to retry a failed fetch of a " +
+		"remote resource or base, waiting twice as long before each retry."
+
+	flagFetchMaxConcurrentName = "fetch-max-concurrent"
+	flagFetchMaxConcurrentHelp = "how many remote resources and bases " +
+		"may be fetched at once; 0 means any number."
+)
+
+// retryBackoff is how long to wait before the first retry.
+var retryBackoff = time.Second
+
+// FetchLimits bound how remote files, archives,
+// buckets and git repos are fetched.
+type FetchLimits struct {
+	// Timeout bounds each attempt to fetch.
+	Timeout time.Duration
+
+	// Retries is how many times a failed fetch is retried.
+	Retries int
+
+	// MaxConcurrent bounds the fetches running at once.
+	MaxConcurrent int
+
+	// slots holds a value for each fetch running.
+	slots chan struct{}
+}
+
+// AddFlagsFetchLimits adds the flags bounding
+// how remote resources and bases are fetched.
+func AddFlagsFetchLimits(set *pflag.FlagSet, lim *FetchLimits) {
+	set.DurationVar(
+		&lim.Timeout, flagFetchTimeoutName,
+		0, flagFetchTimeoutHelp)
+	set.IntVar(
+		&lim.Retries, flagFetchRetriesName,
+		0, flagFetchRetriesHelp)
+	set.IntVar(
+		&lim.MaxConcurrent, flagFetchMaxConcurrentName,
+		0, flagFetchMaxConcurrentHelp)
+}
+
+// SetFetchLimits bounds the fetches of the
+// loader, and of the loaders it makes.
+func SetFetchLimits(l ifc.Loader, lim FetchLimits) {
+	fl, ok := l.(*fileLoader)
+	if !ok {
+		return
+	}
+	if lim.MaxConcurrent > 0 {
+		lim.slots = make(chan struct{}, lim.MaxConcurrent)
+	}
+	fl.limits = &lim
+}
+
+// inheritedFetchLimits returns the limits of the
+// nearest loader in the referrer chain that has some.
+func (fl *fileLoader) inheritedFetchLimits() *FetchLimits {
+	for l := fl; l != nil; l = l.referrer {
+		if l.limits != nil {
+			return l.limits
+		}
+	}
+	return nil
+}
+
+// permanentError is an error retrying can't fix,
+// e.g. a file missing from a server.
+type permanentError struct {
+	error
+}
+
+// fetchWithRetries runs fetch, passing it the timeout
+// of an attempt, within the limits of the loader, which
+// may be nil.  A failed attempt is retried, after twice
+// the wait before the previous retry.
+func (fl *fileLoader) fetchWithRetries(
+	fetch func(timeout time.Duration) error) error {
+	lim := fl.inheritedFetchLimits()
+	if lim == nil {
+		return fetch(0)
+	}
+	if lim.slots != nil {
+		lim.slots <- struct{}{}
+		defer func() { <-lim.slots }()
+	}
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fetch(lim.Timeout)
+		if p, ok := err.(permanentError); ok {
+			return p.error
+		}
+		if err == nil || attempt >= lim.Retries {
+			return err
+		}
+		log.Printf("warning: %v; retrying in %v", err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func makeLoaderWithLimits(
+	t *testing.T, h http.HandlerFunc, lim FetchLimits) (*fileLoader, *httptest.Server) {
+	retryBackoff = time.Millisecond
+	server := httptest.NewTLSServer(h)
+	l := NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	l.http = server.Client()
+	SetFetchLimits(l, lim)
+	return l, server
+}
+
+func TestFetchRetries(t *testing.T) {
+	defer func() { retryBackoff = time.Second }()
+	for _, c := range []struct {
+		retries  int
+		failures int
+		expected string
+	}{
+		{2, 2, ""},
+		{1, 2, "503 Service Unavailable"},
+		{3, 0, ""},
+	} {
+		var mu sync.Mutex
+		calls := 0
+		l, server := makeLoaderWithLimits(t, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			if calls <= c.failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("kind: ConfigMap\n"))
+		}, FetchLimits{Retries: c.retries})
+		_, err := l.Load(server.URL + "/cm.yaml")
+		server.Close()
+		if c.expected == "" && err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.expected != "" &&
+			(err == nil || !strings.Contains(err.Error(), c.expected)) {
+			t.Fatalf("expected error '%s', got %v", c.expected, err)
+		}
+	}
+}
+
+func TestFetchRetriesStopAtPermanentErrors(t *testing.T) {
+	defer func() { retryBackoff = time.Second }()
+	calls := 0
+	l, server := makeLoaderWithLimits(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.NotFound(w, r)
+	}, FetchLimits{Retries: 3})
+	defer server.Close()
+	if _, err := l.Load(server.URL + "/cm.yaml"); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestFetchTimeout(t *testing.T) {
+	defer func() { retryBackoff = time.Second }()
+	l, server := makeLoaderWithLimits(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}, FetchLimits{Timeout: 20 * time.Millisecond})
+	defer server.Close()
+	_, err := l.Load(server.URL + "/cm.yaml")
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func TestCloneRetries(t *testing.T) {
+	defer func() { retryBackoff = time.Second }()
+	retryBackoff = time.Millisecond
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app")
+	fSys.MkdirAll("/clone/base")
+	fSys.MkdirAll("/failedClone")
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+	SetFetchLimits(l, FetchLimits{Retries: 1, Timeout: time.Minute})
+	attempts := 0
+	l.cloner = func(rs *git.RepoSpec) error {
+		attempts++
+		if rs.Timeout != time.Minute {
+			return fmt.Errorf("unexpected timeout %v", rs.Timeout)
+		}
+		if attempts == 1 {
+			rs.Dir = "/failedClone"
+			return fmt.Errorf("connection reset")
+		}
+		rs.Dir = "/clone"
+		return nil
+	}
+	l1, err := l.New("github.com/someOrg/someRepo/base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l1.Root() != "/clone/base" || attempts != 2 {
+		t.Fatalf("unexpected root %s after %d attempts", l1.Root(), attempts)
+	}
+	if fSys.Exists("/failedClone") {
+		t.Fatalf("expected the failed clone to be removed")
+	}
+}
+
+func TestFetchMaxConcurrent(t *testing.T) {
+	l := NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	SetFetchLimits(l, FetchLimits{MaxConcurrent: 2})
+	var (
+		mu        sync.Mutex
+		running   int
+		maxSeen   int
+		waitGroup sync.WaitGroup
+	)
+	for i := 0; i < 6; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			l.fetchWithRetries(func(time.Duration) error {
+				mu.Lock()
+				running++
+				if running > maxSeen {
+					maxSeen = running
+				}
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	waitGroup.Wait()
+	if maxSeen != 2 {
+		t.Fatalf("expected 2 fetches at once, got %d", maxSeen)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+const (
+	flagFetchProxyName = "fetch-proxy"
+	flagFetchProxyHelp = "the URL of the proxy to fetch remote resources " +
+		"and bases through; defaults to the one HTTPS_PROXY specifies."
+
+	flagFetchCABundleName = "fetch-ca-bundle"
+	flagFetchCABundleHelp = "a file of PEM certificates of CAs to trust, " +
+		"on top of the system's, when fetching remote resources and bases."
+)
+
+// AddFlagsFetchOptions adds the flags
+// configuring how remote files are fetched.
+func AddFlagsFetchOptions(set *pflag.FlagSet, o *types.FetchOptions) {
+	set.StringVar(
+		&o.Proxy, flagFetchProxyName,
+		"", flagFetchProxyHelp)
+	set.StringVar(
+		&o.CABundle, flagFetchCABundleName,
+		"", flagFetchCABundleHelp)
+}
+
+// SetFetchOptions makes the loader, and the loaders it makes,
+// fetch remote files and archives, and clone git repos, through
+// the proxy, trusting the CAs of the bundle, that the options
+// specify.  Options left empty are those of the loader's
+// referrers.  A relative bundle path is relative to the root.
+func SetFetchOptions(l ifc.Loader, o types.FetchOptions) error {
+	fl, ok := l.(*fileLoader)
+	if !ok || o == (types.FetchOptions{}) {
+		return nil
+	}
+	merged := types.FetchOptions{}
+	if inherited := fl.inheritedFetchOptions(); inherited != nil {
+		merged = *inherited
+	}
+	if o.Proxy != "" {
+		merged.Proxy = o.Proxy
+	}
+	if o.CABundle != "" {
+		path := o.CABundle
+		var err error
+		if filepath.IsAbs(path) {
+			_, err = fl.fSys.ReadFile(path)
+		} else {
+			_, err = fl.Load(path)
+			path = fl.root.Join(path)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "reading CA bundle")
+		}
+		merged.CABundle = path
+	}
+	client, err := fl.fetchClient(merged)
+	if err != nil {
+		return err
+	}
+	fl.fetchOptions = &merged
+	fl.http = client
+	return nil
+}
+
+// SetKustomizationFetchOptions sets the fetchOptions of the
+// kustomization the loader holds, as SetFetchOptions does, unless
+// the kustomization was fetched from remote: a remote base mustn't
+// pick the proxy or the CAs that the build trusts.
+func SetKustomizationFetchOptions(l ifc.Loader, o types.FetchOptions) error {
+	if fl, ok := l.(*fileLoader); ok && fl.fetchedTree() != "" {
+		log.Printf(
+			"warning: ignoring the fetchOptions of the remote kustomization %s",
+			fl.Root())
+		return nil
+	}
+	return SetFetchOptions(l, o)
+}
+
+// inheritedFetchOptions returns the options of the
+// nearest loader in the referrer chain that has some.
+func (fl *fileLoader) inheritedFetchOptions() *types.FetchOptions {
+	for l := fl; l != nil; l = l.referrer {
+		if l.fetchOptions != nil {
+			return l.fetchOptions
+		}
+	}
+	return nil
+}
+
+// fetchClient returns a client fetching as the options say.
+func (fl *fileLoader) fetchClient(o types.FetchOptions) (*http.Client, error) {
+	proxy := http.ProxyFromEnvironment
+	if o.Proxy != "" {
+		u, err := url.Parse(o.Proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing proxy %s", o.Proxy)
+		}
+		proxy = http.ProxyURL(u)
+	}
+	tlsConfig := &tls.Config{}
+	if o.CABundle != "" {
+		pem, err := fl.fSys.ReadFile(o.CABundle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading CA bundle")
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA bundle %s", o.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           proxy,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// systemCABundles are the files the system's CA
+// certificates are found in, on the common systems.
+var systemCABundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/tls/cacert.pem",
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+}
+
+// systemCABundle returns the PEM certificates of the
+// system's CAs, from the file SSL_CERT_FILE names, or
+// else the first of systemCABundles there is, if any.
+func systemCABundle() []byte {
+	paths := systemCABundles
+	if f := os.Getenv("SSL_CERT_FILE"); f != "" {
+		paths = []string{f}
+	}
+	for _, p := range paths {
+		if pem, err := ioutil.ReadFile(p); err == nil {
+			return pem
+		}
+	}
+	return nil
+}
+
+// gitEnv returns the variables making git fetch as the
+// options of the nearest loader that has some say, and
+// a func removing what they refer to once git is done.
+// As git trusts only the CAs of the file GIT_SSL_CAINFO
+// names, it's pointed at a temporary file holding the
+// system's CAs and those of the bundle.
+func (fl *fileLoader) gitEnv() ([]string, func(), error) {
+	o := fl.inheritedFetchOptions()
+	cleanup := func() {}
+	if o == nil {
+		return nil, cleanup, nil
+	}
+	var env []string
+	if o.Proxy != "" {
+		env = append(env, "https_proxy="+o.Proxy, "HTTPS_PROXY="+o.Proxy)
+	}
+	if o.CABundle != "" {
+		custom, err := fl.fSys.ReadFile(o.CABundle)
+		if err != nil {
+			return nil, cleanup, errors.Wrapf(err, "reading CA bundle")
+		}
+		system := systemCABundle()
+		if system == nil {
+			log.Printf(
+				"warning: no CAs of the system found; "+
+					"git trusts only those of %s", o.CABundle)
+		}
+		f, err := ioutil.TempFile("", "kustomize-ca-")
+		if err != nil {
+			return nil, cleanup, err
+		}
+		cleanup = func() { os.Remove(f.Name()) }
+		_, err = f.Write(append(append(system, '\n'), custom...))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			cleanup()
+			return nil, func() {}, errors.Wrapf(err, "writing CA bundle for git")
+		}
+		env = append(env, "GIT_SSL_CAINFO="+f.Name())
+	}
+	return env, cleanup, nil
+}
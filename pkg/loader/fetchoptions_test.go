@@ -0,0 +1,167 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// writeCABundle writes the certificate of the
+// server, which is its own CA, to the path.
+func writeCABundle(fSys fs.FileSystem, path string, server *httptest.Server) {
+	fSys.WriteFile(path, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func TestSetFetchOptionsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("kind: ConfigMap\n"))
+		}))
+	defer server.Close()
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app/base")
+	writeCABundle(fSys, "/app/ca.pem", server)
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+
+	// The server's certificate is self-signed.
+	if _, err := l.Load(server.URL + "/cm.yaml"); err == nil {
+		t.Fatalf("expected an error for an unknown CA")
+	}
+	err := SetFetchOptions(l, types.FetchOptions{CABundle: "ca.pem"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	child, err := l.New("base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := child.Load(server.URL + "/cm.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "kind: ConfigMap\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+
+	err = SetFetchOptions(child, types.FetchOptions{CABundle: "nothere.pem"})
+	if err == nil {
+		t.Fatalf("expected an error for a missing bundle")
+	}
+	fSys.WriteFile("/app/base/empty.pem", []byte("nothing\n"))
+	err = SetFetchOptions(child, types.FetchOptions{CABundle: "empty.pem"})
+	if err == nil {
+		t.Fatalf("expected an error for a bundle without certificates")
+	}
+}
+
+func TestSetFetchOptionsGitEnv(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "kustomize-system-ca-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	systemCA := filepath.Join(dir, "system.pem")
+	if err = ioutil.WriteFile(systemCA, []byte("system CAs"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Setenv("SSL_CERT_FILE", os.Getenv("SSL_CERT_FILE"))
+	os.Setenv("SSL_CERT_FILE", systemCA)
+
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app/base")
+	writeCABundle(fSys, "/app/ca.pem", server)
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+	if env, _, err := l.gitEnv(); env != nil || err != nil {
+		t.Fatalf("unexpected env %v, %v", env, err)
+	}
+	l.fetchOptions = &types.FetchOptions{
+		Proxy: "http://proxy:3128", CABundle: "/app/ca.pem"}
+	child, err := l.New("base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Options the child leaves empty are inherited.
+	err = SetFetchOptions(child, types.FetchOptions{Proxy: "http://other:8080"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env, cleanup, err := child.(*fileLoader).gitEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(env) != 3 ||
+		env[0] != "https_proxy=http://other:8080" ||
+		env[1] != "HTTPS_PROXY=http://other:8080" ||
+		!strings.HasPrefix(env[2], "GIT_SSL_CAINFO=") {
+		t.Fatalf("unexpected env %v", env)
+	}
+	// Git trusts the system's CAs, and the bundle's.
+	bundle := strings.TrimPrefix(env[2], "GIT_SSL_CAINFO=")
+	content, err := ioutil.ReadFile(bundle)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	custom, _ := fSys.ReadFile("/app/ca.pem")
+	if expected := "system CAs\n" + string(custom); string(content) != expected {
+		t.Fatalf("expected bundle\n%s\ngot\n%s", expected, content)
+	}
+	cleanup()
+	if _, err = os.Stat(bundle); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, got %v", bundle, err)
+	}
+
+	err = SetFetchOptions(child, types.FetchOptions{Proxy: "http://%zz"})
+	if err == nil {
+		t.Fatalf("expected an error for a bad proxy url")
+	}
+}
+
+func TestSetKustomizationFetchOptionsRemote(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/tmp/base")
+	repoSpec, err := git.NewRepoSpecFromUrl("github.com/someOrg/someRepo/base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	l, err := newLoaderAtGitClone(
+		repoSpec, validators.MakeFakeValidator(), fSys, nil,
+		git.DoNothingCloner(fs.ConfirmedDir("/tmp")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = SetKustomizationFetchOptions(
+		l, types.FetchOptions{Proxy: "http://attacker:3128"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o := l.(*fileLoader).inheritedFetchOptions(); o != nil {
+		t.Fatalf("expected the remote options to be ignored, got %v", o)
+	}
+	// The flags of the build still apply.
+	err = SetFetchOptions(l, types.FetchOptions{Proxy: "http://proxy:3128"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o := l.(*fileLoader).inheritedFetchOptions(); o == nil ||
+		o.Proxy != "http://proxy:3128" {
+		t.Fatalf("expected the proxy of the flags, got %v", o)
+	}
+}
@@ -6,12 +6,15 @@ package loader
 import (
 	"fmt"
 	"log"
+	"net/http"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
 	"sigs.k8s.io/kustomize/v3/pkg/git"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 // fileLoader is a kustomization's interface to files.
@@ -39,11 +42,13 @@ import (
 //
 //   `New` is used to load bases.
 //
-//   A base can be either a remote git repo URL, or
-//   a directory specified relative to the current
-//   root. In the former case, the repo is locally
-//   cloned, and the new loader is rooted on a path
-//   in that clone.
+//   A base can be either a remote git repo URL, an
+//   S3 or GCS bucket URL, an archive URL, or a directory
+//   specified relative to the current root. In the first
+//   case, the repo is locally cloned, and the new loader
+//   is rooted on a path in that clone; in the second and
+//   third, the bucket's objects are copied, or the archive
+//   is extracted, likewise.
 //
 //   As loaders create new loaders, a root history
 //   is established, and used to disallow:
@@ -92,8 +97,72 @@ type fileLoader struct {
 	// Used to clone repositories.
 	cloner git.Cloner
 
+	// If this is non-nil, the files were copied
+	// from the given bucket.
+	bucket *bucketSpec
+
+	// Used to copy buckets.
+	fetcher bucketFetcher
+
+	// If this is non-nil, the files were
+	// extracted from the given archive.
+	archive *archiveSpec
+
+	// If this is non-nil, the files were fetched
+	// by the SchemeFetcher of a registered scheme.
+	scheme *schemeSpec
+
+	// Used to fetch remote files, if non-nil.
+	// Otherwise the referrer's client is used.
+	http *http.Client
+
+	// Used to fetch remote files and clone repos,
+	// if non-nil.  Otherwise the referrer's are used.
+	fetchOptions *types.FetchOptions
+
+	// Bound fetches, if non-nil.  Otherwise
+	// the referrer's bound them.
+	limits *FetchLimits
+
+	// Bound what is loaded, if non-nil.  Otherwise
+	// the referrer's limits bound it.
+	inputLimits *InputLimits
+
+	// If non-nil, remote references are refused,
+	// and recorded here.  Loaders made by this one
+	// refuse them too.
+	offline *offlineRefs
+
+	// If non-nil, what remote references resolve
+	// to is recorded, and verified, here.  Loaders
+	// made by this one record it here too.
+	lock *lockState
+
+	// Which symlinks out of the root Load follows,
+	// if set.  Otherwise the referrer's policy holds.
+	symlinks SymlinkPolicy
+
+	// If true, secret generators may run commands.
+	// Loaders made by this one inherit the setting.
+	execEnabled bool
+
+	// The sops command that decrypts sources, if
+	// set.  Otherwise the referrer's is used.
+	sopsCommand string
+
+	// The helm command that inflates helm charts, if
+	// set.  Loaders made by this one inherit it.
+	helmCommand string
+
 	// Used to clean up, as needed.
 	cleaner func() error
+
+	// The absolute paths of the files Load read.
+	read map[string]bool
+
+	// The roots of the loaders New made in
+	// the directory tree of this one.
+	children []fs.ConfirmedDir
 }
 
 const CWD = "."
@@ -142,6 +211,7 @@ func newLoaderAtConfirmedDir(
 		referrer:       referrer,
 		fSys:           fSys,
 		cloner:         cloner,
+		fetcher:        fetchBucketUsingCli,
 		cleaner:        func() error { return nil },
 	}
 }
@@ -167,20 +237,51 @@ func demandDirectoryRoot(
 }
 
 // New returns a new Loader, rooted relative to current loader,
-// or rooted in a temp directory holding a git repo clone.
+// or rooted in a temp directory holding a git repo clone, a
+// copy of a bucket or an extracted archive.
 func (fl *fileLoader) New(path string) (ifc.Loader, error) {
 	if path == "" {
 		return nil, fmt.Errorf("new root cannot be empty")
 	}
+	if err := fl.errIfTooDeep(path); err != nil {
+		return nil, err
+	}
+	if IsRemote(path) {
+		if err := fl.errIfOffline(path); err != nil {
+			return nil, err
+		}
+	}
+	if f := schemeFetcherFor(path); f != nil {
+		return fl.newLoaderAtSchemeCopy(path, f)
+	}
+	if isArchive(path) {
+		return fl.newLoaderAtArchive(path)
+	}
+	bucket, err := newBucketSpec(path)
+	if err == nil {
+		if err := fl.errIfBucketCycle(bucket); err != nil {
+			return nil, err
+		}
+		return newLoaderAtBucketCopy(
+			bucket, fl.validator, fl.fSys, fl, fl.cloner, fl.fetcher)
+	}
 	repoSpec, err := git.NewRepoSpecFromUrl(path)
 	if err == nil {
 		// Treat this as git repo clone request.
 		if err := fl.errIfRepoCycle(repoSpec); err != nil {
 			return nil, err
 		}
+		env, cleanup, err := fl.gitEnv()
+		if err != nil {
+			return nil, err
+		}
+		// The clone is made before newLoaderAtGitClone returns.
+		defer cleanup()
+		repoSpec.Env = env
 		return newLoaderAtGitClone(
 			repoSpec, fl.validator, fl.fSys, fl, fl.cloner)
 	}
+	path = fs.LocalPath(path)
 	if filepath.IsAbs(path) {
 		return nil, fmt.Errorf("new root '%s' cannot be absolute", path)
 	}
@@ -191,11 +292,23 @@ func (fl *fileLoader) New(path string) (ifc.Loader, error) {
 	if err := fl.errIfGitContainmentViolation(root); err != nil {
 		return nil, err
 	}
+	if err := fl.errIfBucketContainmentViolation(root); err != nil {
+		return nil, err
+	}
+	if err := fl.errIfArchiveContainmentViolation(root); err != nil {
+		return nil, err
+	}
+	if err := fl.errIfSchemeContainmentViolation(root); err != nil {
+		return nil, err
+	}
 	if err := fl.errIfArgEqualOrHigher(root); err != nil {
 		return nil, err
 	}
-	return newLoaderAtConfirmedDir(
-		fl.loadRestrictor, fl.validator, root, fl.fSys, fl, fl.cloner), nil
+	fl.children = append(fl.children, root)
+	l := newLoaderAtConfirmedDir(
+		fl.loadRestrictor, fl.validator, root, fl.fSys, fl, fl.cloner)
+	l.fetcher = fl.fetcher
+	return l, nil
 }
 
 // newLoaderAtGitClone returns a new Loader pinned to a temporary
@@ -204,7 +317,17 @@ func newLoaderAtGitClone(
 	repoSpec *git.RepoSpec,
 	v ifc.Validator, fSys fs.FileSystem,
 	referrer *fileLoader, cloner git.Cloner) (ifc.Loader, error) {
-	err := cloner(repoSpec)
+	err := referrer.fetchWithRetries(func(timeout time.Duration) error {
+		repoSpec.Timeout = timeout
+		dir := repoSpec.Dir
+		err := cloner(repoSpec)
+		if err != nil && repoSpec.Dir != dir {
+			// Don't leave a failed attempt behind.
+			repoSpec.Cleaner(fSys)()
+			repoSpec.Dir = dir
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -221,7 +344,7 @@ func newLoaderAtGitClone(
 			"'%s' refers to file '%s'; expecting directory",
 			repoSpec.AbsPath(), f)
 	}
-	return &fileLoader{
+	l := &fileLoader{
 		// Clones never allowed to escape root.
 		loadRestrictor: RestrictionRootOnly,
 		validator:      v,
@@ -230,8 +353,14 @@ func newLoaderAtGitClone(
 		repoSpec:       repoSpec,
 		fSys:           fSys,
 		cloner:         cloner,
+		fetcher:        fetchBucketUsingCli,
 		cleaner:        repoSpec.Cleaner(fSys),
-	}, nil
+	}
+	if err = l.lockRepo(); err != nil {
+		l.Cleanup()
+		return nil, err
+	}
+	return l, nil
 }
 
 func (fl *fileLoader) errIfGitContainmentViolation(
@@ -297,16 +426,65 @@ func (fl *fileLoader) errIfRepoCycle(newRepoSpec *git.RepoSpec) error {
 
 // Load returns the content of file at the given path,
 // else an error.  Relative paths are taken relative
-// to the root.
+// to the root.  An https URL is fetched instead.
 func (fl *fileLoader) Load(path string) ([]byte, error) {
+	if isRemoteFile(path) {
+		if err := fl.errIfOffline(path); err != nil {
+			return nil, err
+		}
+		return fl.loadRemoteFile(path)
+	}
+	if f := schemeFetcherFor(path); f != nil {
+		if err := fl.errIfOffline(path); err != nil {
+			return nil, err
+		}
+		return fl.loadSchemeFile(path, f)
+	}
+	path = fs.LocalPath(path)
 	if !filepath.IsAbs(path) {
 		path = fl.root.Join(path)
 	}
-	path, err := fl.loadRestrictor(fl.fSys, fl.root, path)
+	resolved, err := fl.loadRestrictor(fl.fSys, fl.root, path)
 	if err != nil {
+		resolved, err = fl.followSymlink(path, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+	path = resolved
+	content, err := fl.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if fl.read == nil {
+		fl.read = make(map[string]bool)
+	}
+	fl.read[path] = true
+	return content, nil
+}
+
+// readFile reads the local file, within the size limit,
+// checking its size before reading it where it can.
+func (fl *fileLoader) readFile(path string) ([]byte, error) {
+	max := fl.maxFileSize()
+	if max <= 0 {
+		return fl.fSys.ReadFile(path)
+	}
+	if f, err := fl.fSys.Open(path); err == nil {
+		fi, err := f.Stat()
+		f.Close()
+		if err == nil && fi != nil && fi.Size() > max {
+			return nil, tooLargeError(path, max)
+		}
+	}
+	content, err := fl.fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = errIfTooLarge(content, max, path); err != nil {
 		return nil, err
 	}
-	return fl.fSys.ReadFile(path)
+	return content, nil
 }
 
 // Cleanup runs the cleaner.
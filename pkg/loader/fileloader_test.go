@@ -129,6 +129,34 @@ func TestLoaderNewSubDir(t *testing.T) {
 	}
 }
 
+func TestLoaderBackslashes(t *testing.T) {
+	l1, err := makeLoader().New(`foo\project\subdir1`)
+	if err != nil {
+		t.Fatalf("unexpected err: %v\n", err)
+	}
+	if "/foo/project/subdir1" != l1.Root() {
+		t.Fatalf("incorrect root: %s\n", l1.Root())
+	}
+	l2, err := l1.New(`..\subdir2`)
+	if err != nil {
+		t.Fatalf("unexpected err: %v\n", err)
+	}
+	if "/foo/project/subdir2" != l2.Root() {
+		t.Fatalf("incorrect root: %s\n", l2.Root())
+	}
+	b, err := l2.Load(`..\fileA.yaml`)
+	if err == nil {
+		t.Fatalf("expected error loading a file above the root, got %s", b)
+	}
+	b, err = l1.Load(`..\..\project\subdir1\fileB.yaml`)
+	if err != nil {
+		t.Fatalf("unexpected load error %v", err)
+	}
+	if string(b) != testCases[1].expectedContent {
+		t.Fatalf("in load expected %s, but got %s", testCases[1].expectedContent, b)
+	}
+}
+
 func TestLoaderBadRelative(t *testing.T) {
 	l1, err := makeLoader().New("foo/project/subdir1")
 	if err != nil {
@@ -0,0 +1,69 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+// IsGlob returns true if the path is a pattern
+// matching files, e.g. "manifests/*.yaml".
+func IsGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// Glob returns the paths of the files matching the pattern,
+// in lexical order, relative to the root of the loader
+// unless the pattern is absolute.  Directories don't match.
+// A pattern matching no files is an error.
+func Glob(l ifc.Loader, pattern string) ([]string, error) {
+	if d, ok := l.(delegator); ok {
+		l = d.Delegate()
+	}
+	fl, ok := l.(*fileLoader)
+	if !ok {
+		return nil, fmt.Errorf("can't match %s in %s", pattern, l.Root())
+	}
+	matches, err := fl.glob(pattern)
+	if err != nil || filepath.IsAbs(fs.LocalPath(pattern)) {
+		return matches, err
+	}
+	for i, m := range matches {
+		if matches[i], err = filepath.Rel(fl.root.String(), m); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// glob returns the absolute paths of
+// the files matching the pattern.
+func (fl *fileLoader) glob(pattern string) ([]string, error) {
+	abs := fs.LocalPath(pattern)
+	if !filepath.IsAbs(abs) {
+		abs = fl.root.Join(abs)
+	}
+	matches, err := fl.fSys.Glob(abs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad glob %s", pattern)
+	}
+	var files []string
+	for _, m := range matches {
+		if !fl.fSys.IsDir(m) {
+			files = append(files, m)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+	sort.Strings(files)
+	return files, nil
+}
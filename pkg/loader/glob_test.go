@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestGlob(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app/manifests/sub.yaml")
+	for _, f := range []string{"b.yaml", "a.yaml", "c.json"} {
+		fSys.WriteFile("/app/manifests/"+f, []byte("kind: ConfigMap\n"))
+	}
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+
+	for pattern, expected := range map[string][]string{
+		"manifests/*.yaml": {"manifests/a.yaml", "manifests/b.yaml"},
+		"manifests/?.*":    {"manifests/a.yaml", "manifests/b.yaml", "manifests/c.json"},
+		"/app/*/[c]*":      {"/app/manifests/c.json"},
+	} {
+		matches, err := Glob(l, pattern)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(matches, expected) {
+			t.Fatalf("expected %v matching %s, got %v", expected, pattern, matches)
+		}
+	}
+
+	_, err := Glob(l, "manifests/*.yml")
+	if err == nil || !strings.Contains(err.Error(), "no files match") {
+		t.Fatalf("expected no match error, got %v", err)
+	}
+}
+
+func TestIsGlob(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"manifests/*.yaml": true,
+		"cm-?.yaml":        true,
+		"cm-[ab].yaml":     true,
+		"manifests/a.yaml": false,
+		"../base":          false,
+	} {
+		if IsGlob(path) != expected {
+			t.Fatalf("expected IsGlob(%s) to be %v", path, expected)
+		}
+	}
+}
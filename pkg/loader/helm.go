@@ -0,0 +1,72 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+const (
+	flagEnableHelmName = "enable_helm"
+	flagEnableHelmHelp = "if set, kustomizations may inflate helm " +
+		"charts, running the helm of --" + flagHelmCommandName + "."
+	flagHelmCommandName = "helm_command"
+	flagHelmCommandHelp = "the helm executable that inflates " +
+		"helm charts, with --" + flagEnableHelmName + "."
+	defaultHelmCommand = "helm"
+)
+
+// AddFlagsHelm adds the flags allowing kustomizations
+// to inflate helm charts, and naming the helm to run.
+func AddFlagsHelm(set *pflag.FlagSet, enable *bool, command *string) {
+	set.BoolVar(
+		enable, flagEnableHelmName,
+		false, flagEnableHelmHelp)
+	set.StringVar(
+		command, flagHelmCommandName,
+		defaultHelmCommand, flagHelmCommandHelp)
+}
+
+// EnableHelm lets the loader, and the loaders it makes for
+// local kustomizations, run the given helm command to inflate
+// charts.  Remote kustomizations can never run helm.
+func EnableHelm(l ifc.Loader, command string) {
+	if d, ok := l.(delegator); ok {
+		l = d.Delegate()
+	}
+	if fl, ok := l.(*fileLoader); ok {
+		if command == "" {
+			command = defaultHelmCommand
+		}
+		fl.helmCommand = command
+	}
+}
+
+// HelmCommand returns the helm command that the nearest loader
+// in the referrer chain enabled, or an error if none did, or
+// if the loader holds a kustomization fetched from remote.
+func HelmCommand(l ifc.Loader) (string, error) {
+	if d, ok := l.(delegator); ok {
+		l = d.Delegate()
+	}
+	fl, ok := l.(*fileLoader)
+	if !ok {
+		return "", fmt.Errorf(
+			"inflating helm charts requires --%s", flagEnableHelmName)
+	}
+	if fl.fetchedTree() != "" {
+		return "", fmt.Errorf(
+			"remote kustomizations cannot inflate helm charts")
+	}
+	for l := fl; l != nil; l = l.referrer {
+		if l.helmCommand != "" {
+			return l.helmCommand, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"inflating helm charts requires --%s", flagEnableHelmName)
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestHelmCommand(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.Mkdir("/base")
+	l := NewFileLoaderAtRoot(validators.MakeFakeValidator(), fSys)
+	if _, err := HelmCommand(l); err == nil ||
+		!strings.Contains(err.Error(), flagEnableHelmName) {
+		t.Fatalf("expected error naming the flag, got %v", err)
+	}
+	EnableHelm(l, "/opt/helm3")
+	// Loaders made by l may run helm too.
+	child, err := l.New("base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	command, err := HelmCommand(child)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if command != "/opt/helm3" {
+		t.Fatalf("expected /opt/helm3, got %s", command)
+	}
+}
+
+func TestHelmCommandRemote(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/tmp/base")
+	repoSpec, err := git.NewRepoSpecFromUrl("github.com/someOrg/someRepo/base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	l, err := newLoaderAtGitClone(
+		repoSpec, validators.MakeFakeValidator(), fSys, nil,
+		git.DoNothingCloner(fs.ConfirmedDir("/tmp")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	EnableHelm(l, "")
+	if _, err = HelmCommand(l); err == nil ||
+		!strings.Contains(err.Error(), "remote") {
+		t.Fatalf("expected remote refusal, got %v", err)
+	}
+}
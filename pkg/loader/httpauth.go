@@ -0,0 +1,149 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Remote files and archives may be fetched from servers
+// asking for credentials, found, per host, in
+//
+// * an environment variable holding a bearer token,
+//   named after the host, e.g. KUSTOMIZE_TOKEN_ARTIFACTS_EXAMPLE_COM
+//   for artifacts.example.com, or else
+//
+// * the netrc file at $NETRC, or ~/.netrc, holding a login
+//   and password for basic auth, like curl and git use.
+const (
+	tokenEnvPrefix = "KUSTOMIZE_TOKEN_"
+	netrcEnv       = "NETRC"
+)
+
+// tokenEnv returns the name of the variable holding
+// the bearer token for the host.
+func tokenEnv(host string) string {
+	return tokenEnvPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, host)
+}
+
+// authorize adds the credentials for the host
+// of the request to it, if there are any.
+func authorize(req *http.Request) error {
+	host := req.URL.Hostname()
+	if token := os.Getenv(tokenEnv(host)); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+	path := os.Getenv(netrcEnv)
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "reading netrc")
+	}
+	if login, password, ok := netrcLogin(string(content), host); ok {
+		req.SetBasicAuth(login, password)
+	}
+	return nil
+}
+
+// netrcEntry is the login of a machine in a netrc file,
+// or of any other machine, if it's the default.
+type netrcEntry struct {
+	machine   string
+	isDefault bool
+	login     string
+	password  string
+}
+
+// parseNetrc returns the entries of netrc file content.
+func parseNetrc(content string) []netrcEntry {
+	// Macros run to the next blank line,
+	// and have nothing to do with logins.
+	var kept []string
+	inMacro := false
+	for _, line := range strings.Split(content, "\n") {
+		if inMacro {
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "macdef") {
+			inMacro = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	var entries []netrcEntry
+	fields := strings.Fields(strings.Join(kept, "\n"))
+	next := func(i *int) string {
+		*i++
+		if *i < len(fields) {
+			return fields[*i]
+		}
+		return ""
+	}
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			entries = append(entries, netrcEntry{machine: next(&i)})
+		case "default":
+			entries = append(entries, netrcEntry{isDefault: true})
+		case "login", "password", "account":
+			key := fields[i]
+			value := next(&i)
+			if len(entries) == 0 {
+				continue
+			}
+			e := &entries[len(entries)-1]
+			if key == "login" {
+				e.login = value
+			} else if key == "password" {
+				e.password = value
+			}
+		}
+	}
+	return entries
+}
+
+// netrcLogin returns the login and password the netrc file
+// content holds for the host, or else for the default machine.
+func netrcLogin(content, host string) (login, password string, ok bool) {
+	var fallback *netrcEntry
+	entries := parseNetrc(content)
+	for i, e := range entries {
+		if e.machine == host {
+			return e.login, e.password, true
+		}
+		if e.isDefault && fallback == nil {
+			fallback = &entries[i]
+		}
+	}
+	if fallback != nil {
+		return fallback.login, fallback.password, true
+	}
+	return "", "", false
+}
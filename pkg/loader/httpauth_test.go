@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+const testNetrc = `machine artifacts.example.com
+  login alice
+  password s3cret
+
+macdef init
+machine bogus.example.com login bogus
+
+default login anonymous password guest
+`
+
+func TestNetrcLogin(t *testing.T) {
+	for host, expected := range map[string][2]string{
+		"artifacts.example.com": {"alice", "s3cret"},
+		"bogus.example.com":     {"anonymous", "guest"},
+		"other.example.com":     {"anonymous", "guest"},
+	} {
+		login, password, ok := netrcLogin(testNetrc, host)
+		if !ok || login != expected[0] || password != expected[1] {
+			t.Fatalf("expected %v for %s, got %s %s %v",
+				expected, host, login, password, ok)
+		}
+	}
+	if _, _, ok := netrcLogin("machine a.com login a", "b.com"); ok {
+		t.Fatalf("expected no login for b.com")
+	}
+}
+
+func TestTokenEnv(t *testing.T) {
+	if e := tokenEnv("artifacts.example-1.com"); e !=
+		"KUSTOMIZE_TOKEN_ARTIFACTS_EXAMPLE_1_COM" {
+		t.Fatalf("unexpected env %s", e)
+	}
+}
+
+func TestLoadRemoteFileWithAuth(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Header.Get("Authorization")))
+		}))
+	defer server.Close()
+	l := NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	l.http = server.Client()
+	u, _ := url.Parse(server.URL)
+
+	dir, err := ioutil.TempDir("", "kustomize-netrc-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	netrc := filepath.Join(dir, ".netrc")
+	err = ioutil.WriteFile(netrc, []byte(
+		"machine "+u.Hostname()+" login alice password s3cret\n"), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Setenv(netrcEnv, os.Getenv(netrcEnv))
+	os.Setenv(netrcEnv, netrc)
+
+	content, err := l.Load(server.URL + "/ca.pem")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "Basic YWxpY2U6czNjcmV0" {
+		t.Fatalf("unexpected authorization %q", content)
+	}
+
+	// A token takes precedence.
+	defer os.Unsetenv(tokenEnv(u.Hostname()))
+	os.Setenv(tokenEnv(u.Hostname()), "t0ken")
+	content, err = l.Load(server.URL + "/ca.pem")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "Bearer t0ken" {
+		t.Fatalf("unexpected authorization %q", content)
+	}
+}
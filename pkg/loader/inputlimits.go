@@ -0,0 +1,138 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+const (
+	flagMaxFileSizeName = "max-file-size"
+	flagMaxFileSizeHelp = "how many bytes a file may hold, be it " +
+		"local, remote, or in an archive; 0 means any number."
+
+	flagMaxArchiveSizeName = "max-archive-size"
+	flagMaxArchiveSizeHelp = "how many bytes the files of an archive " +
+		"may hold together, once extracted; 0 means any number."
+
+	flagMaxArchiveEntriesName = "max-archive-entries"
+	flagMaxArchiveEntriesHelp = "how many files and directories " +
+		"an archive may hold; 0 means any number."
+
+	flagMaxBaseDepthName = "max-base-depth"
+	flagMaxBaseDepthHelp = "how deep bases may nest, the " +
+		"kustomization built's being at depth 1; 0 means any depth."
+)
+
+// InputLimits bound what loaders load, for services
+// building kustomizations they don't trust.
+type InputLimits struct {
+	// MaxFileSize bounds the bytes of each file
+	// loaded, fetched, or extracted from an archive.
+	MaxFileSize int64
+
+	// MaxArchiveSize bounds the bytes of all the
+	// files extracted from an archive together.
+	MaxArchiveSize int64
+
+	// MaxArchiveEntries bounds the files and
+	// directories of an archive.
+	MaxArchiveEntries int
+
+	// MaxBaseDepth bounds how deep bases nest.
+	MaxBaseDepth int
+}
+
+// AddFlagsInputLimits adds the flags bounding what is loaded.
+func AddFlagsInputLimits(set *pflag.FlagSet, lim *InputLimits) {
+	set.Int64Var(
+		&lim.MaxFileSize, flagMaxFileSizeName,
+		0, flagMaxFileSizeHelp)
+	set.Int64Var(
+		&lim.MaxArchiveSize, flagMaxArchiveSizeName,
+		0, flagMaxArchiveSizeHelp)
+	set.IntVar(
+		&lim.MaxArchiveEntries, flagMaxArchiveEntriesName,
+		0, flagMaxArchiveEntriesHelp)
+	set.IntVar(
+		&lim.MaxBaseDepth, flagMaxBaseDepthName,
+		0, flagMaxBaseDepthHelp)
+}
+
+// SetInputLimits bounds what the loader,
+// and the loaders it makes, load.
+func SetInputLimits(l ifc.Loader, lim InputLimits) {
+	if fl, ok := l.(*fileLoader); ok {
+		fl.inputLimits = &lim
+	}
+}
+
+// inheritedInputLimits returns the limits of the nearest loader in
+// the referrer chain that has some, or no limits.
+func (fl *fileLoader) inheritedInputLimits() InputLimits {
+	for l := fl; l != nil; l = l.referrer {
+		if l.inputLimits != nil {
+			return *l.inputLimits
+		}
+	}
+	return InputLimits{}
+}
+
+// maxFileSize returns the bound on file sizes of the
+// nearest loader in the referrer chain that has limits.
+func (fl *fileLoader) maxFileSize() int64 {
+	return fl.inheritedInputLimits().MaxFileSize
+}
+
+// errIfTooDeep returns an error if a base of the
+// loader would nest deeper than the limit allows.
+func (fl *fileLoader) errIfTooDeep(path string) error {
+	depth := 1
+	for l := fl; l != nil; l = l.referrer {
+		if l.inputLimits != nil {
+			max := l.inputLimits.MaxBaseDepth
+			if max > 0 && depth >= max {
+				return fmt.Errorf(
+					"base '%s' of '%s' nests deeper than %d, the limit",
+					path, fl.root, max)
+			}
+			return nil
+		}
+		depth++
+	}
+	return nil
+}
+
+// readLimited reads r, failing if it holds
+// more than max bytes, unless max is 0.
+func readLimited(r io.Reader, max int64, name string) ([]byte, error) {
+	if max <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if err = errIfTooLarge(data, max, name); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func errIfTooLarge(data []byte, max int64, name string) error {
+	if max > 0 && int64(len(data)) > max {
+		return tooLargeError(name, max)
+	}
+	return nil
+}
+
+func tooLargeError(name string, max int64) error {
+	return fmt.Errorf(
+		"'%s' holds more than %d bytes, the limit", name, max)
+}
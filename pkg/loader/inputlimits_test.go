@@ -0,0 +1,141 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestMaxFileSize(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app/base")
+	fSys.WriteFile("/app/small.yaml", []byte("kind: ConfigMap\n"))
+	fSys.WriteFile("/app/base/large.yaml", []byte(strings.Repeat("#", 100)))
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+	SetInputLimits(l, InputLimits{MaxFileSize: 50})
+
+	if _, err := l.Load("small.yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	l1, err := l.New("base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = l1.Load("large.yaml")
+	if err == nil || !strings.Contains(err.Error(), "more than 50 bytes") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+}
+
+func TestMaxFileSizeOfRemoteFiles(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(strings.Repeat("#", 100)))
+		}))
+	defer server.Close()
+	l := NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	l.http = server.Client()
+	SetInputLimits(l, InputLimits{MaxFileSize: 50})
+
+	_, err := l.Load(server.URL + "/large.yaml")
+	if err == nil || !strings.Contains(err.Error(), "more than 50 bytes") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+}
+
+func TestMaxFileSizeOfArchivedFiles(t *testing.T) {
+	l, server := makeLoaderWithArchives(t, map[string][]byte{
+		"/bomb.tar.gz": makeTarGz(t, map[string]string{
+			"kustomization.yaml": strings.Repeat("#", 100000),
+		}),
+	})
+	defer server.Close()
+	SetInputLimits(l, InputLimits{MaxFileSize: 10000})
+
+	_, err := l.New(server.URL + "/bomb.tar.gz")
+	if err == nil || !strings.Contains(err.Error(), "more than 10000 bytes") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+}
+
+func TestMaxArchiveSize(t *testing.T) {
+	// Each file is below the bound on files,
+	// but not all of them together.
+	files := map[string]string{"kustomization.yaml": "resources: []\n"}
+	for i := 0; i < 20; i++ {
+		files[fmt.Sprintf("f%d.yaml", i)] = strings.Repeat("#", 1000)
+	}
+	l, server := makeLoaderWithArchives(t, map[string][]byte{
+		"/bomb.tar.gz": makeTarGz(t, files),
+		"/bomb.zip":    makeZip(t, files),
+	})
+	defer server.Close()
+	SetInputLimits(l, InputLimits{MaxFileSize: 5000, MaxArchiveSize: 10000})
+
+	for _, bomb := range []string{"/bomb.tar.gz", "/bomb.zip"} {
+		_, err := l.New(server.URL + bomb)
+		if err == nil || !strings.Contains(err.Error(),
+			"the files of the archive hold more than 10000 bytes") {
+			t.Fatalf("expected size error for %s, got %v", bomb, err)
+		}
+	}
+
+	SetInputLimits(l, InputLimits{MaxArchiveSize: 100000})
+	l1, err := l.New(server.URL + "/bomb.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	l1.Cleanup()
+}
+
+func TestMaxArchiveEntries(t *testing.T) {
+	files := map[string]string{"kustomization.yaml": "resources: []\n"}
+	for i := 0; i < 20; i++ {
+		files[fmt.Sprintf("f%d.yaml", i)] = ""
+	}
+	l, server := makeLoaderWithArchives(t, map[string][]byte{
+		"/bomb.tar.gz": makeTarGz(t, files),
+		"/bomb.zip":    makeZip(t, files),
+	})
+	defer server.Close()
+	SetInputLimits(l, InputLimits{MaxArchiveEntries: 10})
+
+	for _, bomb := range []string{"/bomb.tar.gz", "/bomb.zip"} {
+		_, err := l.New(server.URL + bomb)
+		if err == nil || !strings.Contains(err.Error(),
+			"the archive holds more than 10 entries") {
+			t.Fatalf("expected entries error for %s, got %v", bomb, err)
+		}
+	}
+}
+
+func TestMaxBaseDepth(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app/a/b/c")
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+	SetInputLimits(l, InputLimits{MaxBaseDepth: 3})
+
+	a, err := l.New("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := a.New("b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = b.New("c")
+	if err == nil || !strings.Contains(err.Error(), "deeper than 3") {
+		t.Fatalf("expected depth error, got %v", err)
+	}
+}
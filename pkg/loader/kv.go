@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -26,7 +27,11 @@ func (fl *fileLoader) Validator() ifc.Validator {
 
 func (fl *fileLoader) LoadKvPairs(
 	args types.GeneratorArgs) (all []types.Pair, err error) {
-	pairs, err := fl.keyValuesFromEnvFiles(args.EnvSources)
+	load := fl.Load
+	if args.Sops != nil {
+		load = fl.sopsDecryptingLoad(args.Sops)
+	}
+	pairs, err := fl.keyValuesFromEnvFiles(args.EnvSources, load)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf(
 			"env source files: %v",
@@ -41,11 +46,17 @@ func (fl *fileLoader) LoadKvPairs(
 	}
 	all = append(all, pairs...)
 
-	pairs, err = fl.keyValuesFromFileSources(args.FileSources)
+	pairs, err = fl.keyValuesFromFileSources(args.FileSources, load)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf(
 			"file sources: %v", args.FileSources))
 	}
+	all = append(all, pairs...)
+
+	pairs, err = fl.keyValuesFromCommands(args.Commands)
+	if err != nil {
+		return nil, errors.Wrap(err, "commands")
+	}
 	return append(all, pairs...), nil
 }
 
@@ -61,14 +72,30 @@ func keyValuesFromLiteralSources(sources []string) ([]types.Pair, error) {
 	return kvs, nil
 }
 
-func (fl *fileLoader) keyValuesFromFileSources(sources []string) ([]types.Pair, error) {
+// loadFunc returns the content at a location, like Load.
+type loadFunc func(location string) ([]byte, error)
+
+func (fl *fileLoader) keyValuesFromFileSources(
+	sources []string, load loadFunc) ([]types.Pair, error) {
 	var kvs []types.Pair
 	for _, s := range sources {
 		k, fPath, err := parseFileSource(s)
 		if err != nil {
 			return nil, err
 		}
-		content, err := fl.Load(fPath)
+		if IsGlob(fPath) && !isRemoteFile(fPath) {
+			if strings.Contains(s, "=") {
+				return nil, fmt.Errorf(
+					"key name %s cannot be given for glob %s", k, fPath)
+			}
+			more, err := fl.keyValuesFromGlob(fPath, load)
+			if err != nil {
+				return nil, err
+			}
+			kvs = append(kvs, more...)
+			continue
+		}
+		content, err := load(fPath)
 		if err != nil {
 			return nil, err
 		}
@@ -77,10 +104,30 @@ func (fl *fileLoader) keyValuesFromFileSources(sources []string) ([]types.Pair,
 	return kvs, nil
 }
 
-func (fl *fileLoader) keyValuesFromEnvFiles(paths []string) ([]types.Pair, error) {
+// keyValuesFromGlob returns a pair for each file matching the
+// pattern, in lexical order, keyed by the file's basename.
+func (fl *fileLoader) keyValuesFromGlob(
+	pattern string, load loadFunc) ([]types.Pair, error) {
+	matches, err := fl.glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var kvs []types.Pair
+	for _, m := range matches {
+		content, err := load(m)
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, types.Pair{Key: filepath.Base(m), Value: string(content)})
+	}
+	return kvs, nil
+}
+
+func (fl *fileLoader) keyValuesFromEnvFiles(
+	paths []string, load loadFunc) ([]types.Pair, error) {
 	var kvs []types.Pair
 	for _, p := range paths {
-		content, err := fl.Load(p)
+		content, err := load(p)
 		if err != nil {
 			return nil, err
 		}
@@ -165,8 +212,14 @@ func (fl *fileLoader) keyValuesFromLine(line []byte, currentLine int) (types.Pai
 //   2.  source-name=source-path: the source-name will become the key name and
 //       source-path is the path to the key file.
 //
+// The source-path may be an https URL, in which case the last
+// element of the URL path is the default key name.
+//
 // Key names cannot include '='.
 func parseFileSource(source string) (keyName, filePath string, err error) {
+	if i := strings.Index(source, remoteFileScheme); i >= 0 {
+		return parseRemoteFileSource(source, i)
+	}
 	numSeparators := strings.Count(source, "=")
 	switch {
 	case numSeparators == 0:
@@ -183,6 +236,22 @@ func parseFileSource(source string) (keyName, filePath string, err error) {
 	}
 }
 
+// parseRemoteFileSource parses a source whose path is the URL
+// starting at index i.  The URL may contain '='.
+func parseRemoteFileSource(source string, i int) (keyName, filePath string, err error) {
+	filePath = source[i:]
+	switch {
+	case i == 0:
+		return remoteFileBase(filePath), filePath, nil
+	case i == 1 && source[0] == '=':
+		return "", "", fmt.Errorf("key name for file path %v missing", filePath)
+	case source[i-1] != '=' || strings.Count(source[:i], "=") > 1:
+		return "", "", fmt.Errorf("invalid file source %v", source)
+	default:
+		return source[:i-1], filePath, nil
+	}
+}
+
 // ParseLiteralSource parses the source key=val pair into its component pieces.
 // This functionality is distinguished from strings.SplitN(source, "=", 2) since
 // it returns an error in the case of empty keys, values, or a missing equals sign.
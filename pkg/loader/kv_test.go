@@ -80,7 +80,7 @@ func TestKeyValuesFromFileSources(t *testing.T) {
 	fSys.WriteFile("/files/app-init.ini", []byte("FOO=bar"))
 	l := NewFileLoaderAtRoot(validators.MakeFakeValidator(), fSys)
 	for _, tc := range tests {
-		kvs, err := l.keyValuesFromFileSources(tc.sources)
+		kvs, err := l.keyValuesFromFileSources(tc.sources, l.Load)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -89,3 +89,33 @@ func TestKeyValuesFromFileSources(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyValuesFromFileSourceGlobs(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/configs/b.conf", []byte("b"))
+	fSys.WriteFile("/configs/a.conf", []byte("a"))
+	fSys.WriteFile("/configs/a.yaml", []byte("y"))
+	fSys.Mkdir("/configs/d.conf")
+	l := NewFileLoaderAtRoot(validators.MakeFakeValidator(), fSys)
+
+	kvs, err := l.keyValuesFromFileSources([]string{"configs/*.conf"}, l.Load)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []types.Pair{
+		{Key: "a.conf", Value: "a"},
+		{Key: "b.conf", Value: "b"},
+	}
+	if !reflect.DeepEqual(kvs, expected) {
+		t.Fatalf("expected %v, got %v", expected, kvs)
+	}
+
+	for _, source := range []string{
+		"configs/*.ini",
+		"key=configs/*.conf",
+	} {
+		if _, err := l.keyValuesFromFileSources([]string{source}, l.Load); err == nil {
+			t.Fatalf("expected error for %s", source)
+		}
+	}
+}
@@ -24,25 +24,50 @@ import (
 )
 
 // NewLoader returns a Loader pointed at the given target.
-// If the target is remote, the loader will be restricted
-// to the root and below only.  If the target is local, the
-// loader will have the restrictions passed in.  Regardless,
-// if a local target attempts to transitively load remote bases,
-// the remote bases will all be root-only restricted.
+// If the target is remote, a git repo or a bucket, the loader
+// will be restricted to the root and below only.  If the target
+// is local, the loader will have the restrictions passed in.
+// Regardless, if a local target attempts to transitively load
+// remote bases, the remote bases will all be root-only restricted.
 func NewLoader(
 	lr LoadRestrictorFunc,
 	v ifc.Validator,
 	target string, fSys fs.FileSystem) (ifc.Loader, error) {
+	return NewLoaderUsingCloner(lr, v, target, fSys, git.ClonerUsingGitExec)
+}
+
+// CachingCloner returns a cloner of remote bases that keeps
+// their clones in the cache, for later builds to use.
+func CachingCloner() git.Cloner {
+	return git.ClonerUsingCache(
+		git.DefaultCacheDir(), git.ClonerUsingGitExec)
+}
+
+// NewLoaderUsingCloner returns a Loader pointed at the given
+// target, as NewLoader does, cloning remote bases with the
+// given cloner, e.g. one that caches them.
+func NewLoaderUsingCloner(
+	lr LoadRestrictorFunc,
+	v ifc.Validator,
+	target string, fSys fs.FileSystem,
+	cloner git.Cloner) (ifc.Loader, error) {
+	bucket, err := newBucketSpec(target)
+	if err == nil {
+		// The target qualifies as a bucket's prefix.
+		return newLoaderAtBucketCopy(
+			bucket, v, fSys, nil, cloner,
+			fetchBucketUsingCli)
+	}
 	repoSpec, err := git.NewRepoSpecFromUrl(target)
 	if err == nil {
 		// The target qualifies as a remote git target.
 		return newLoaderAtGitClone(
-			repoSpec, v, fSys, nil, git.ClonerUsingGitExec)
+			repoSpec, v, fSys, nil, cloner)
 	}
 	root, err := demandDirectoryRoot(fSys, target)
 	if err != nil {
 		return nil, err
 	}
 	return newLoaderAtConfirmedDir(
-		lr, v, root, fSys, nil, git.ClonerUsingGitExec), nil
+		lr, v, root, fSys, nil, cloner), nil
 }
@@ -21,45 +21,78 @@ import (
 
 	"github.com/spf13/pflag"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
-//go:generate stringer -type=loadRestrictions
-type loadRestrictions int
-
 const (
-	unknown loadRestrictions = iota
-	rootOnly
-	none
-)
+	flagName = "load-restrictor"
 
-const (
-	flagName = "load_restrictor"
+	// flagNameLegacy is the flag's name before it
+	// was spelled like the other flags.
+	flagNameLegacy = "load_restrictor"
 )
 
 var (
-	flagValue = rootOnly.String()
-	flagHelp  = "if set to '" + none.String() +
+	flagValue = types.LoadRestrictionsRootOnly.String()
+	flagHelp  = "if set to '" + types.LoadRestrictionsNone.String() +
 		"', local kustomizations may load files from outside their root. " +
 		"This does, however, break the relocatability of the kustomization."
 )
 
+// legacyValues are the flag's values before they were
+// spelled like the values of types.LoadRestrictions.
+var legacyValues = map[string]types.LoadRestrictions{
+	"rootOnly": types.LoadRestrictionsRootOnly,
+	"none":     types.LoadRestrictionsNone,
+}
+
 func AddFlagLoadRestrictor(set *pflag.FlagSet) {
 	set.StringVar(
 		&flagValue, flagName,
-		rootOnly.String(), flagHelp)
+		types.LoadRestrictionsRootOnly.String(), flagHelp)
+	set.StringVar(
+		&flagValue, flagNameLegacy,
+		types.LoadRestrictionsRootOnly.String(), flagHelp)
+	set.MarkDeprecated(flagNameLegacy, "use --"+flagName)
 }
 
 func ValidateFlagLoadRestrictor() (LoadRestrictorFunc, error) {
-	switch flagValue {
-	case rootOnly.String():
-		return RestrictionRootOnly, nil
-	case none.String():
-		return RestrictionNone, nil
-	default:
+	r, ok := legacyValues[flagValue]
+	if !ok {
+		r = parseLoadRestrictions(flagValue)
+	}
+	f, err := RestrictorFor(r)
+	if err != nil {
 		return nil, fmt.Errorf(
 			"illegal flag value --%s %s; legal values: %v",
 			flagName, flagValue,
-			[]string{rootOnly.String(), none.String()})
+			[]string{
+				types.LoadRestrictionsRootOnly.String(),
+				types.LoadRestrictionsNone.String()})
+	}
+	return f, nil
+}
+
+func parseLoadRestrictions(s string) types.LoadRestrictions {
+	for _, r := range []types.LoadRestrictions{
+		types.LoadRestrictionsRootOnly, types.LoadRestrictionsNone} {
+		if s == r.String() {
+			return r
+		}
+	}
+	return types.LoadRestrictionsUnknown
+}
+
+// RestrictorFor returns the function enforcing the
+// restrictions, for use with NewLoader.
+func RestrictorFor(r types.LoadRestrictions) (LoadRestrictorFunc, error) {
+	switch r {
+	case types.LoadRestrictionsRootOnly:
+		return RestrictionRootOnly, nil
+	case types.LoadRestrictionsNone:
+		return RestrictionNone, nil
+	default:
+		return nil, fmt.Errorf("unknown load restrictions %v", r)
 	}
 }
 
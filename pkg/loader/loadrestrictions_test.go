@@ -17,10 +17,13 @@ limitations under the License.
 package loader
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/spf13/pflag"
 	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 )
 
 func TestRestrictionNone(t *testing.T) {
@@ -72,3 +75,55 @@ func TestRestrictionRootOnly(t *testing.T) {
 		t.Fatalf("unexpected err: %s", err)
 	}
 }
+
+func TestRestrictorFor(t *testing.T) {
+	for r, expected := range map[types.LoadRestrictions]LoadRestrictorFunc{
+		types.LoadRestrictionsRootOnly: RestrictionRootOnly,
+		types.LoadRestrictionsNone:     RestrictionNone,
+	} {
+		f, err := RestrictorFor(r)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reflect.ValueOf(f).Pointer() != reflect.ValueOf(expected).Pointer() {
+			t.Fatalf("unexpected restrictor for %v", r)
+		}
+	}
+	if _, err := RestrictorFor(types.LoadRestrictionsUnknown); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestValidateFlagLoadRestrictor(t *testing.T) {
+	defer func() { flagValue = types.LoadRestrictionsRootOnly.String() }()
+	for _, c := range []struct {
+		args     []string
+		expected LoadRestrictorFunc
+	}{
+		{nil, RestrictionRootOnly},
+		{[]string{"--load-restrictor", "LoadRestrictionsNone"}, RestrictionNone},
+		{[]string{"--load-restrictor", "LoadRestrictionsRootOnly"}, RestrictionRootOnly},
+		{[]string{"--load_restrictor", "none"}, RestrictionNone},
+		{[]string{"--load-restrictor", "rootOnly"}, RestrictionRootOnly},
+		{[]string{"--load-restrictor", "everything"}, nil},
+	} {
+		set := pflag.NewFlagSet("build", pflag.ContinueOnError)
+		AddFlagLoadRestrictor(set)
+		if err := set.Parse(c.args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f, err := ValidateFlagLoadRestrictor()
+		if c.expected == nil {
+			if err == nil || !strings.Contains(err.Error(), "legal values") {
+				t.Fatalf("%v: expected an error, got %v", c.args, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", c.args, err)
+		}
+		if reflect.ValueOf(f).Pointer() != reflect.ValueOf(c.expected).Pointer() {
+			t.Fatalf("%v: unexpected restrictor", c.args)
+		}
+	}
+}
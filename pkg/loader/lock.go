@@ -0,0 +1,222 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// LockFileName is the name of the file, next to
+	// a kustomization, locking its remote references.
+	LockFileName = "kustomization.lock"
+
+	flagUpdateLockName = "update-lock"
+	flagUpdateLockHelp = "if set, write " + LockFileName + " next to " +
+		"the kustomization built, recording the commits and digests " +
+		"its remote references resolved to.  Otherwise, if there is " +
+		"one, the build fails if they drifted from it."
+
+	digestPrefix = "sha256:"
+)
+
+// headCommit returns the commit checked out in a clone.
+var headCommit = git.HeadCommit
+
+// AddFlagUpdateLock adds the flag writing the lock file.
+func AddFlagUpdateLock(set *pflag.FlagSet, v *bool) {
+	set.BoolVar(v, flagUpdateLockName, false, flagUpdateLockHelp)
+}
+
+// ReadLock returns the lock file in the dir,
+// or nil if there's none.
+func ReadLock(fSys fs.FileSystem, dir string) (*types.Lock, error) {
+	path := filepath.Join(dir, LockFileName)
+	if !fSys.Exists(path) {
+		return nil, nil
+	}
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lock types.Lock
+	if err = yaml.Unmarshal(content, &lock); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+	return &lock, nil
+}
+
+// WriteLock writes the lock file in the dir.
+func WriteLock(fSys fs.FileSystem, dir string, lock *types.Lock) error {
+	content, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(filepath.Join(dir, LockFileName), content)
+}
+
+// lockState records what remote references resolved
+// to, verifying it against a lock file's record.
+type lockState struct {
+	mu sync.Mutex
+
+	// locked is what the lock file recorded, by
+	// URL, or nil if there's nothing to verify.
+	locked map[string]types.LockedRemote
+
+	// resolved is what the references resolved to, by URL.
+	resolved map[string]types.LockedRemote
+}
+
+// SetLock makes the loader, and the loaders it makes,
+// record what remote references resolve to, failing
+// if it drifted from the lock, unless that's nil.
+func SetLock(l ifc.Loader, lock *types.Lock) {
+	fl, ok := l.(*fileLoader)
+	if !ok {
+		return
+	}
+	s := &lockState{resolved: make(map[string]types.LockedRemote)}
+	if lock != nil {
+		s.locked = make(map[string]types.LockedRemote)
+		for _, r := range lock.Remotes {
+			s.locked[r.URL] = r
+		}
+	}
+	fl.lock = s
+}
+
+// Locked returns what the remote references the loader,
+// and the loaders it made, resolved to, ordered by URL.
+func Locked(l ifc.Loader) *types.Lock {
+	lock := &types.Lock{}
+	fl, ok := l.(*fileLoader)
+	if !ok || fl.lock == nil {
+		return lock
+	}
+	fl.lock.mu.Lock()
+	defer fl.lock.mu.Unlock()
+	for _, r := range fl.lock.resolved {
+		lock.Remotes = append(lock.Remotes, r)
+	}
+	sort.Slice(lock.Remotes, func(i, j int) bool {
+		return lock.Remotes[i].URL < lock.Remotes[j].URL
+	})
+	return lock
+}
+
+// inheritedLock returns the lock state of the nearest
+// loader in the referrer chain that has one.
+func (fl *fileLoader) inheritedLock() *lockState {
+	for l := fl; l != nil; l = l.referrer {
+		if l.lock != nil {
+			return l.lock
+		}
+	}
+	return nil
+}
+
+// lockRemote records what a remote reference resolved
+// to, returning an error if it drifted from the lock.
+func (fl *fileLoader) lockRemote(r types.LockedRemote) error {
+	s := fl.inheritedLock()
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolved[r.URL] = r
+	if s.locked == nil {
+		return nil
+	}
+	locked, ok := s.locked[r.URL]
+	if !ok {
+		return fmt.Errorf(
+			"%s isn't in %s; build with --%s to add it",
+			r.URL, LockFileName, flagUpdateLockName)
+	}
+	if locked != r {
+		return fmt.Errorf(
+			"%s drifted from %s: locked at %s, resolved to %s",
+			r.URL, LockFileName, resolution(locked), resolution(r))
+	}
+	return nil
+}
+
+func resolution(r types.LockedRemote) string {
+	if r.Commit != "" {
+		return r.Commit
+	}
+	return r.Digest
+}
+
+// lockRepo records the commit the loader's clone has.
+func (fl *fileLoader) lockRepo() error {
+	if fl.inheritedLock() == nil {
+		return nil
+	}
+	commit, err := headCommit(fl.repoSpec.CloneDir().String(), false)
+	if err != nil {
+		return err
+	}
+	return fl.lockRemote(
+		types.LockedRemote{URL: fl.repoSpec.Raw(), Commit: commit})
+}
+
+// lockBucket records the digest of the loader's bucket copy.
+func (fl *fileLoader) lockBucket() error {
+	return fl.lockTree(fl.bucket.raw, fl.bucket.Dir)
+}
+
+// lockTree records the digest of the tree fetched from the
+// URL, hashing the path and content of each file in it.
+func (fl *fileLoader) lockTree(url string, tree fs.ConfirmedDir) error {
+	if fl.inheritedLock() == nil {
+		return nil
+	}
+	root := tree.String()
+	var paths []string
+	err := fl.fSys.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, path := range paths {
+		content, err := fl.fSys.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(content))
+		h.Write(content)
+	}
+	return fl.lockRemote(types.LockedRemote{
+		URL: url, Digest: digestPrefix + hex.EncodeToString(h.Sum(nil))})
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+const someDigest = "sha256:" +
+	"2d6ed2d8e3bac2bd7e0b8f9e5c8d0cd30ba2cb0fa0a6ab0ef98ba0e8c5b79b4c"
+
+func TestLockRemoteFiles(t *testing.T) {
+	content := "kind: ConfigMap\n"
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(content))
+		}))
+	defer server.Close()
+	url := server.URL + "/cm.yaml"
+	makeLoader := func(lock *types.Lock) *fileLoader {
+		l := NewFileLoaderAtRoot(
+			validators.MakeFakeValidator(), fs.MakeFsInMemory())
+		l.http = server.Client()
+		SetLock(l, lock)
+		return l
+	}
+
+	l := makeLoader(nil)
+	if _, err := l.Load(url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lock := Locked(l)
+	if len(lock.Remotes) != 1 || lock.Remotes[0].URL != url ||
+		!strings.HasPrefix(lock.Remotes[0].Digest, "sha256:") {
+		t.Fatalf("unexpected lock %v", lock)
+	}
+
+	// The same content verifies.
+	if _, err := makeLoader(lock).Load(url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Changed content drifted.
+	content = "kind: Secret\n"
+	_, err := makeLoader(lock).Load(url)
+	if err == nil || !strings.Contains(err.Error(), "drifted from kustomization.lock") {
+		t.Fatalf("expected drift error, got %v", err)
+	}
+
+	// References missing from the lock fail.
+	_, err = makeLoader(lock).Load(server.URL + "/other.yaml")
+	if err == nil || !strings.Contains(err.Error(), "isn't in kustomization.lock") {
+		t.Fatalf("expected missing error, got %v", err)
+	}
+}
+
+func TestLockRepos(t *testing.T) {
+	defer func() { headCommit = git.HeadCommit }()
+	commit := "0123456789abcdef0123456789abcdef01234567"
+	headCommit = func(dir string, short bool) (string, error) {
+		return commit, nil
+	}
+	makeLoader := func(lock *types.Lock) *fileLoader {
+		fSys := fs.MakeFsInMemory()
+		fSys.MkdirAll("/app")
+		fSys.MkdirAll("/clone/base")
+		l := newLoaderOrDie(
+			RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+		l.cloner = func(rs *git.RepoSpec) error {
+			rs.Dir = "/clone"
+			return nil
+		}
+		SetLock(l, lock)
+		return l
+	}
+
+	l := makeLoader(nil)
+	if _, err := l.New("github.com/someOrg/someRepo/base?ref=master"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lock := Locked(l)
+	expected := &types.Lock{Remotes: []types.LockedRemote{{
+		URL:    "github.com/someOrg/someRepo/base?ref=master",
+		Commit: commit,
+	}}}
+	if !reflect.DeepEqual(lock, expected) {
+		t.Fatalf("expected %v, got %v", expected, lock)
+	}
+
+	commit = "76543210fedcba9876543210fedcba9876543210"
+	_, err := makeLoader(lock).New("github.com/someOrg/someRepo/base?ref=master")
+	if err == nil || !strings.Contains(err.Error(), "resolved to "+commit) {
+		t.Fatalf("expected drift error, got %v", err)
+	}
+}
+
+func TestLockBuckets(t *testing.T) {
+	l := makeLoaderWithBucket(t)
+	SetLock(l, nil)
+	if _, err := l.New("s3://acme-bases"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lock := Locked(l)
+	if len(lock.Remotes) != 1 || lock.Remotes[0].URL != "s3://acme-bases" {
+		t.Fatalf("unexpected lock %v", lock)
+	}
+
+	l = makeLoaderWithBucket(t)
+	SetLock(l, lock)
+	if _, err := l.New("s3://acme-bases"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	l = makeLoaderWithBucket(t)
+	l.fSys.WriteFile("/bucketCopy/web/deployment.yaml", []byte("kind: Deployment"))
+	SetLock(l, lock)
+	_, err := l.New("s3://acme-bases")
+	if err == nil || !strings.Contains(err.Error(), "drifted") {
+		t.Fatalf("expected drift error, got %v", err)
+	}
+}
+
+func TestReadAndWriteLock(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app")
+	lock, err := ReadLock(fSys, "/app")
+	if err != nil || lock != nil {
+		t.Fatalf("expected no lock, got %v, %v", lock, err)
+	}
+	expected := &types.Lock{Remotes: []types.LockedRemote{
+		{URL: "https://example.com/cm.yaml", Digest: someDigest},
+	}}
+	if err = WriteLock(fSys, "/app", expected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, _ := fSys.ReadFile("/app/kustomization.lock")
+	if string(content) != `remotes:
+- digest: `+someDigest+`
+  url: https://example.com/cm.yaml
+` {
+		t.Fatalf("unexpected content:\n%s", content)
+	}
+	if lock, err = ReadLock(fSys, "/app"); err != nil ||
+		!reflect.DeepEqual(lock, expected) {
+		t.Fatalf("expected %v, got %v, %v", expected, lock, err)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+const (
+	flagOfflineName = "offline"
+	flagOfflineHelp = "if set, refuse to fetch remote resources and " +
+		"bases, failing on the first one, e.g. to check that a " +
+		"hermetic build has everything vendored.  Defaults to the " +
+		"value of " + offlineEnv + "."
+
+	offlineEnv = "KUSTOMIZE_OFFLINE"
+)
+
+// AddFlagOffline adds the flag refusing remote references.
+func AddFlagOffline(set *pflag.FlagSet, v *bool) {
+	offline, _ := strconv.ParseBool(os.Getenv(offlineEnv))
+	set.BoolVar(v, flagOfflineName, offline, flagOfflineHelp)
+}
+
+// IsRemote returns true if the location is
+// fetched over the network, e.g. a git repo.
+func IsRemote(location string) bool {
+	if isRemoteFile(location) || schemeFetcherFor(location) != nil {
+		return true
+	}
+	if _, err := newBucketSpec(location); err == nil {
+		return true
+	}
+	_, err := git.NewRepoSpecFromUrl(location)
+	return err == nil
+}
+
+// offlineRefs records the remote references
+// refused by loaders in offline mode.
+type offlineRefs struct {
+	refs []string
+}
+
+// SetOffline makes the loader, and the loaders it
+// makes, refuse remote references.
+func SetOffline(l ifc.Loader) {
+	if fl, ok := l.(*fileLoader); ok {
+		fl.offline = &offlineRefs{}
+	}
+}
+
+// OfflineRefusals returns the remote references the loader,
+// and the loaders it made, refused, in the order they were.
+func OfflineRefusals(l ifc.Loader) []string {
+	if fl, ok := l.(*fileLoader); ok && fl.offline != nil {
+		return fl.offline.refs
+	}
+	return nil
+}
+
+// errIfOffline returns an error if some loader in the
+// referrer chain is offline, recording the reference.
+func (fl *fileLoader) errIfOffline(location string) error {
+	for l := fl; l != nil; l = l.referrer {
+		if l.offline == nil {
+			continue
+		}
+		seen := false
+		for _, r := range l.offline.refs {
+			seen = seen || r == location
+		}
+		if !seen {
+			l.offline.refs = append(l.offline.refs, location)
+		}
+		return fmt.Errorf(
+			"refusing to fetch %s; --%s is set", location, flagOfflineName)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+func TestIsRemote(t *testing.T) {
+	for location, expected := range map[string]bool{
+		"https://example.com/cm.yaml":               true,
+		"https://example.com/web.tar.gz":            true,
+		"s3://acme-bases/web":                       true,
+		"github.com/someOrg/someRepo//base?ref=v1":  true,
+		"git@github.com:someOrg/someRepo.git//base": true,
+		"../base":         false,
+		"deployment.yaml": false,
+		"/abs/base":       false,
+	} {
+		if IsRemote(location) != expected {
+			t.Fatalf("expected IsRemote(%s) to be %v", location, expected)
+		}
+	}
+}
+
+func TestOffline(t *testing.T) {
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app/base")
+	fSys.WriteFile("/app/base/cm.yaml", []byte("kind: ConfigMap\n"))
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+	SetOffline(l)
+
+	// Local files and bases are fine.
+	child, err := l.New("base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = child.Load("cm.yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, location := range []string{
+		"https://example.com/cm.yaml",
+		"https://example.com/cm.yaml",
+	} {
+		_, err = child.Load(location)
+		if err == nil || !strings.Contains(err.Error(), "--offline is set") {
+			t.Fatalf("expected offline error for %s, got %v", location, err)
+		}
+	}
+	for _, location := range []string{
+		"github.com/someOrg/someRepo//base",
+		"s3://acme-bases/web",
+	} {
+		_, err = child.New(location)
+		if err == nil || !strings.Contains(err.Error(), "--offline is set") {
+			t.Fatalf("expected offline error for %s, got %v", location, err)
+		}
+	}
+	expected := []string{
+		"https://example.com/cm.yaml",
+		"github.com/someOrg/someRepo//base",
+		"s3://acme-bases/web",
+	}
+	if refs := OfflineRefusals(l); !reflect.DeepEqual(refs, expected) {
+		t.Fatalf("expected %v, got %v", expected, refs)
+	}
+}
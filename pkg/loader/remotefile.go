@@ -0,0 +1,131 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// A remote file is an https URL, optionally followed
+// by the expected sha256 of the file's content, e.g.
+//
+//   https://example.com/certs/ca.pem#sha256=9f86d0...
+//
+// The fragment is never sent to the server.
+const (
+	remoteFileScheme = "https://"
+	checksumPrefix   = "sha256="
+)
+
+func isRemoteFile(location string) bool {
+	return strings.HasPrefix(location, remoteFileScheme)
+}
+
+// remoteFileBase returns the last element of the
+// path of the URL, ignoring query and fragment.
+func remoteFileBase(location string) string {
+	u, err := url.Parse(location)
+	if err != nil {
+		return path.Base(location)
+	}
+	return path.Base(u.Path)
+}
+
+// httpClient returns the client of the nearest loader
+// in the referrer chain that has one.
+func (fl *fileLoader) httpClient() *http.Client {
+	for l := fl; l != nil; l = l.referrer {
+		if l.http != nil {
+			return l.http
+		}
+	}
+	return http.DefaultClient
+}
+
+// loadRemoteFile fetches the file at the given URL,
+// verifying its checksum if the URL has one.
+func (fl *fileLoader) loadRemoteFile(location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing url %s", location)
+	}
+	checksum := ""
+	if u.Fragment != "" {
+		if !strings.HasPrefix(u.Fragment, checksumPrefix) {
+			return nil, fmt.Errorf(
+				"url %s has fragment %q; only %s<hex> is allowed",
+				location, u.Fragment, checksumPrefix)
+		}
+		checksum = strings.ToLower(
+			strings.TrimPrefix(u.Fragment, checksumPrefix))
+		u.Fragment = ""
+	}
+	var content []byte
+	err = fl.fetchWithRetries(func(timeout time.Duration) error {
+		content, err = fl.get(u, timeout)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+	if checksum != "" && actual != checksum {
+		return nil, fmt.Errorf(
+			"sha256 of %s is %s, expected %s", u, actual, checksum)
+	}
+	err = fl.lockRemote(types.LockedRemote{
+		URL: location, Digest: digestPrefix + actual})
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// get fetches the content at the URL, within the
+// timeout, if it's not 0.  Errors other than those
+// of the network and of busy servers are permanent.
+func (fl *fileLoader) get(u *url.URL, timeout time.Duration) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, permanentError{errors.Wrapf(err, "fetching %s", u)}
+	}
+	if err = authorize(req); err != nil {
+		return nil, permanentError{err}
+	}
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+	resp, err := fl.httpClient().Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", u)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("fetching %s: %s", u, resp.Status)
+		if resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusTooManyRequests {
+			err = permanentError{err}
+		}
+		return nil, err
+	}
+	content, err := readLimited(resp.Body, fl.maxFileSize(), u.String())
+	if err != nil {
+		return nil, permanentError{errors.Wrapf(err, "reading %s", u)}
+	}
+	return content, nil
+}
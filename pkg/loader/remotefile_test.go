@@ -0,0 +1,152 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+var remoteFiles = map[string]string{
+	"/certs/ca.pem":   "-----BEGIN CERTIFICATE-----\n",
+	"/config/app.env": "COLOR=red\nSIZE=2\n",
+}
+
+func makeLoaderWithServer(t *testing.T) (*fileLoader, *httptest.Server) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			content, ok := remoteFiles[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(content))
+		}))
+	l := NewFileLoaderAtRoot(
+		validators.MakeFakeValidator(), fs.MakeFsInMemory())
+	l.http = server.Client()
+	return l, server
+}
+
+func sha256Of(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestLoadRemoteFile(t *testing.T) {
+	l, server := makeLoaderWithServer(t)
+	defer server.Close()
+
+	url := server.URL + "/certs/ca.pem"
+	for _, location := range []string{
+		url,
+		url + "#sha256=" + sha256Of(remoteFiles["/certs/ca.pem"]),
+	} {
+		content, err := l.Load(location)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(content) != remoteFiles["/certs/ca.pem"] {
+			t.Fatalf("unexpected content %q", content)
+		}
+	}
+
+	// Loaders made by l use its client.
+	fSys := l.fSys
+	fSys.Mkdir("/base")
+	child, err := l.New("base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = child.Load(url); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadRemoteFileErrors(t *testing.T) {
+	l, server := makeLoaderWithServer(t)
+	defer server.Close()
+
+	url := server.URL + "/certs/ca.pem"
+	tests := map[string]string{
+		url + "#sha256=" + sha256Of("something else"): "expected " + sha256Of("something else"),
+		url + "#md5=abc":     "only sha256=<hex> is allowed",
+		server.URL + "/nope": "404 Not Found",
+	}
+	for location, expected := range tests {
+		_, err := l.Load(location)
+		if err == nil {
+			t.Fatalf("expected error loading %s", location)
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q in error, got %v", expected, err)
+		}
+	}
+}
+
+func TestKeyValuesFromRemoteSources(t *testing.T) {
+	l, server := makeLoaderWithServer(t)
+	defer server.Close()
+
+	ca := server.URL + "/certs/ca.pem"
+	kvs, err := l.LoadKvPairs(types.GeneratorArgs{
+		DataSources: types.DataSources{
+			EnvSources: []string{server.URL + "/config/app.env"},
+			FileSources: []string{
+				ca,
+				"bundle.pem=" + ca + "#sha256=" + sha256Of(remoteFiles["/certs/ca.pem"]),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []types.Pair{
+		{Key: "COLOR", Value: "red"},
+		{Key: "SIZE", Value: "2"},
+		{Key: "ca.pem", Value: remoteFiles["/certs/ca.pem"]},
+		{Key: "bundle.pem", Value: remoteFiles["/certs/ca.pem"]},
+	}
+	if !reflect.DeepEqual(kvs, expected) {
+		t.Fatalf("expected %v, got %v", expected, kvs)
+	}
+}
+
+func TestParseRemoteFileSource(t *testing.T) {
+	tests := []struct {
+		source, key, path string
+		err               bool
+	}{
+		{"https://x.com/a/b.pem?v=1", "b.pem", "https://x.com/a/b.pem?v=1", false},
+		{"k=https://x.com/b.pem#sha256=ab", "k", "https://x.com/b.pem#sha256=ab", false},
+		{"=https://x.com/b.pem", "", "", true},
+		{"a=b=https://x.com/b.pem", "", "", true},
+		{"ahttps://x.com/b.pem", "", "", true},
+	}
+	for _, tc := range tests {
+		key, path, err := parseFileSource(tc.source)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("expected error for %s", tc.source)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", tc.source, err)
+		}
+		if key != tc.key || path != tc.path {
+			t.Fatalf("%s: expected %s %s, got %s %s",
+				tc.source, tc.key, tc.path, key, path)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// SchemeFetcher fetches the files and directories named
+// by the URLs of a custom scheme, e.g. the config files
+// an artifactory:// or vault:// server holds.
+type SchemeFetcher interface {
+	// FetchFile returns the content of the file at the URL.
+	FetchFile(url string) ([]byte, error)
+
+	// FetchDir copies the directory at the URL, which
+	// should hold a kustomization, into the directory
+	// dir of fSys, failing if the URL names no directory.
+	FetchDir(url string, fSys fs.FileSystem, dir string) error
+}
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = make(map[string]SchemeFetcher)
+
+	// builtinSchemes are those loaders handle themselves.
+	builtinSchemes = []string{"file", "git", "gs", "http", "https", "s3", "ssh"}
+
+	validScheme = regexp.MustCompile("^[a-z][a-z0-9+.-]*$")
+)
+
+// RegisterScheme makes loaders fetch the files and
+// bases named by URLs with the scheme, e.g. "vault"
+// for vault://configs/app/config.env, with the fetcher.
+// Programs embedding kustomize register their schemes
+// before making loaders, e.g. in an init function.
+func RegisterScheme(scheme string, f SchemeFetcher) error {
+	if !validScheme.MatchString(scheme) {
+		return fmt.Errorf("illegal scheme '%s'", scheme)
+	}
+	for _, s := range builtinSchemes {
+		if s == scheme {
+			return fmt.Errorf("scheme '%s' is built in", scheme)
+		}
+	}
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	if _, ok := schemes[scheme]; ok {
+		return fmt.Errorf("scheme '%s' is already registered", scheme)
+	}
+	schemes[scheme] = f
+	return nil
+}
+
+// schemeFetcherFor returns the fetcher registered for
+// the scheme of the location, or nil if there's none.
+func schemeFetcherFor(location string) SchemeFetcher {
+	i := strings.Index(location, "://")
+	if i <= 0 {
+		return nil
+	}
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	return schemes[location[:i]]
+}
+
+// schemeSpec specifies a copy of a directory
+// fetched by a registered SchemeFetcher.
+type schemeSpec struct {
+	// raw is the URL of the directory.
+	raw string
+
+	// Dir is where the directory is copied to.
+	Dir fs.ConfirmedDir
+}
+
+func (s *schemeSpec) Cleaner(fSys fs.FileSystem) func() error {
+	return func() error {
+		if err := fSys.RemoveAll(s.Dir.String()); err != nil {
+			return err
+		}
+		// The directory was made on disk, even
+		// if the copy went to another fSys.
+		return os.RemoveAll(s.Dir.String())
+	}
+}
+
+// loadSchemeFile fetches the file at the URL
+// with the fetcher registered for its scheme.
+func (fl *fileLoader) loadSchemeFile(
+	location string, f SchemeFetcher) ([]byte, error) {
+	var content []byte
+	err := fl.fetchWithRetries(func(time.Duration) (err error) {
+		content, err = f.FetchFile(location)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", location)
+	}
+	if err = errIfTooLarge(content, fl.maxFileSize(), location); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	err = fl.lockRemote(types.LockedRemote{
+		URL: location, Digest: digestPrefix + hex.EncodeToString(sum[:])})
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// newLoaderAtSchemeCopy returns a new Loader pinned to a
+// temporary directory holding a copy of the directory at
+// the URL, fetched with the fetcher registered for its scheme.
+func (fl *fileLoader) newLoaderAtSchemeCopy(
+	location string, f SchemeFetcher) (ifc.Loader, error) {
+	if err := fl.errIfSchemeCycle(location); err != nil {
+		return nil, err
+	}
+	dir, err := fs.NewTmpConfirmedDir()
+	if err != nil {
+		return nil, err
+	}
+	s := &schemeSpec{raw: location, Dir: dir}
+	err = fl.fetchWithRetries(func(time.Duration) error {
+		if err := fl.fSys.MkdirAll(dir.String()); err != nil {
+			return err
+		}
+		return f.FetchDir(location, fl.fSys, dir.String())
+	})
+	if err != nil {
+		s.Cleaner(fl.fSys)()
+		return nil, errors.Wrapf(err, "fetching %s", location)
+	}
+	l := &fileLoader{
+		// Copies are never allowed to escape root.
+		loadRestrictor: RestrictionRootOnly,
+		validator:      fl.validator,
+		root:           dir,
+		referrer:       fl,
+		scheme:         s,
+		fSys:           fl.fSys,
+		cloner:         fl.cloner,
+		fetcher:        fl.fetcher,
+		cleaner:        s.Cleaner(fl.fSys),
+	}
+	if err = l.lockTree(location, dir); err != nil {
+		l.Cleanup()
+		return nil, err
+	}
+	return l, nil
+}
+
+// containingSchemeCopy looks back through referrers for
+// a scheme's copy, returning nil if none found.
+func (fl *fileLoader) containingSchemeCopy() *schemeSpec {
+	if fl.scheme != nil {
+		return fl.scheme
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.containingSchemeCopy()
+}
+
+func (fl *fileLoader) errIfSchemeContainmentViolation(
+	base fs.ConfirmedDir) error {
+	s := fl.containingSchemeCopy()
+	if s == nil {
+		return nil
+	}
+	if !base.HasPrefix(s.Dir) {
+		return fmt.Errorf(
+			"security; bases in kustomizations found in "+
+				"'%s' must be within its copy, "+
+				"but base '%s' is outside '%s'",
+			s.raw, base, s.Dir)
+	}
+	return nil
+}
+
+func (fl *fileLoader) errIfSchemeCycle(location string) error {
+	if fl.scheme != nil && fl.scheme.raw == location {
+		return fmt.Errorf(
+			"cycle detected: URI '%s' referenced by previous URI '%s'",
+			location, fl.scheme.raw)
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.errIfSchemeCycle(location)
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// fakeSchemeFetcher serves files from a map, by URL,
+// taking directories to be the URLs of the files below.
+type fakeSchemeFetcher map[string]string
+
+func (f fakeSchemeFetcher) FetchFile(url string) ([]byte, error) {
+	content, ok := f[url]
+	if !ok {
+		return nil, fmt.Errorf("no file %s", url)
+	}
+	return []byte(content), nil
+}
+
+func (f fakeSchemeFetcher) FetchDir(url string, fSys fs.FileSystem, dir string) error {
+	found := false
+	for u, content := range f {
+		if !strings.HasPrefix(u, url+"/") {
+			continue
+		}
+		found = true
+		path := filepath.Join(dir, strings.TrimPrefix(u, url+"/"))
+		if err := fSys.MkdirAll(filepath.Dir(path)); err != nil {
+			return err
+		}
+		if err := fSys.WriteFile(path, []byte(content)); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("no directory %s", url)
+	}
+	return nil
+}
+
+var registerFakeScheme sync.Once
+
+func makeLoaderWithScheme(t *testing.T) *fileLoader {
+	registerFakeScheme.Do(func() {
+		err := RegisterScheme("fake", fakeSchemeFetcher{
+			"fake://configs/app.env":                  "PORT=8080\n",
+			"fake://bases/web/kustomization.yaml":     "resources:\n- deployment.yaml\n",
+			"fake://bases/web/deployment.yaml":        "kind: Deployment\n",
+			"fake://bases/web/sub/kustomization.yaml": "resources: []\n",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	fSys := fs.MakeFsInMemory()
+	fSys.MkdirAll("/app")
+	return newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(), fSys, "/app")
+}
+
+func TestRegisterScheme(t *testing.T) {
+	makeLoaderWithScheme(t)
+	for scheme, expected := range map[string]string{
+		"fake":  "already registered",
+		"https": "built in",
+		"s3":    "built in",
+		"Bad_":  "illegal scheme",
+	} {
+		err := RegisterScheme(scheme, fakeSchemeFetcher{})
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("%s: expected error '%s', got %v", scheme, expected, err)
+		}
+	}
+	if !IsRemote("fake://configs/app.env") || IsRemote("unknown://configs") {
+		t.Fatalf("expected only registered schemes to be remote")
+	}
+}
+
+func TestLoadSchemeFile(t *testing.T) {
+	l := makeLoaderWithScheme(t)
+	content, err := l.Load("fake://configs/app.env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "PORT=8080\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+	if _, err = l.Load("fake://configs/missing.env"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	SetOffline(l)
+	_, err = l.Load("fake://configs/app.env")
+	if err == nil || !strings.Contains(err.Error(), "--offline is set") {
+		t.Fatalf("expected offline error, got %v", err)
+	}
+}
+
+func TestLoaderAtSchemeCopy(t *testing.T) {
+	l := makeLoaderWithScheme(t)
+	l1, err := l.New("fake://bases/web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer l1.Cleanup()
+	content, err := l1.Load("deployment.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "kind: Deployment\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+	if root, url, ok := FetchedTree(l1); !ok ||
+		root != l1.Root() || url != "fake://bases/web" {
+		t.Fatalf("unexpected tree %s fetched from %s", root, url)
+	}
+
+	// Bases below the copy are fine, but not above it.
+	l2, err := l1.New("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = l2.New("../../../app")
+	if err == nil || !strings.Contains(err.Error(), "must be within its copy") {
+		t.Fatalf("expected containment error, got %v", err)
+	}
+
+	// A copy may not refer to itself.
+	_, err = l2.New("fake://bases/web")
+	if err == nil || !strings.Contains(err.Error(), "cycle detected") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	if _, err = l.New("fake://bases/missing"); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
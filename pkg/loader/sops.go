@@ -0,0 +1,95 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+const (
+	flagSopsCommandName = "sops_command"
+	flagSopsCommandHelp = "the sops executable that decrypts " +
+		"the sources of secret generators asking for it."
+	defaultSopsCommand = "sops"
+)
+
+// AddFlagSopsCommand adds the flag naming the sops to run.
+func AddFlagSopsCommand(set *pflag.FlagSet, v *string) {
+	set.StringVar(
+		v, flagSopsCommandName,
+		defaultSopsCommand, flagSopsCommandHelp)
+}
+
+// SetSopsCommand sets the sops command that the loader,
+// and the loaders it makes, decrypt sources with.
+func SetSopsCommand(l ifc.Loader, command string) {
+	if fl, ok := l.(*fileLoader); ok {
+		fl.sopsCommand = command
+	}
+}
+
+// inheritedSopsCommand returns the sops command of the
+// nearest loader in the referrer chain that has one.
+func (fl *fileLoader) inheritedSopsCommand() string {
+	for l := fl; l != nil; l = l.referrer {
+		if l.sopsCommand != "" {
+			return l.sopsCommand
+		}
+	}
+	return defaultSopsCommand
+}
+
+// sopsDecryptingLoad returns a loadFunc that decrypts
+// what it loads with sops.
+func (fl *fileLoader) sopsDecryptingLoad(args *types.SopsArgs) loadFunc {
+	return func(location string) ([]byte, error) {
+		content, err := fl.Load(location)
+		if err != nil {
+			return nil, err
+		}
+		return sopsDecrypt(
+			fl.inheritedSopsCommand(), args, location, content)
+	}
+}
+
+// sopsDecrypt decrypts the content loaded from the given location.
+// Sops infers the format of a file (yaml, json, dotenv, ini or
+// binary) from its extension, so the content is decrypted from a
+// temporary file with the same extension.  Nothing decrypted is
+// written to disk.
+func sopsDecrypt(
+	bin string, args *types.SopsArgs,
+	location string, content []byte) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "kustomize-sops-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating tmp dir for sops")
+	}
+	defer os.RemoveAll(dir)
+	f := filepath.Join(dir, "encrypted"+filepath.Ext(location))
+	if err = ioutil.WriteFile(f, content, 0600); err != nil {
+		return nil, errors.Wrap(err, "writing file for sops")
+	}
+	flags := []string{"--decrypt"}
+	for _, ks := range args.KeyServices {
+		flags = append(flags, "--keyservice", ks)
+	}
+	cmd := exec.Command(bin, append(flags, f)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "decrypting %s with sops: %s", location, stderr.String())
+	}
+	return out, nil
+}
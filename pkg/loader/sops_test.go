@@ -0,0 +1,73 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// fakeSops "decrypts" by unwrapping ENC[...] values,
+// insisting on being given a key service.
+const fakeSops = `#!/bin/sh
+for last; do :; done
+case " $* " in
+  *" --keyservice tcp://keys:5000 "*) ;;
+  *) echo "no key service" >&2; exit 1 ;;
+esac
+sed 's/ENC\[\([^]]*\)\]/\1/g' "$last"
+`
+
+func TestLoadKvPairsWithSops(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fake-sops-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "sops")
+	if err = ioutil.WriteFile(bin, []byte(fakeSops), 0700); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/db.env", []byte("USER=ENC[admin]\nPASSWORD=ENC[hunter2]\n"))
+	fSys.WriteFile("/app/token.txt", []byte("ENC[s3cr3t]"))
+	l := NewFileLoaderAtRoot(validators.MakeFakeValidator(), fSys)
+	SetSopsCommand(l, bin)
+	args := types.GeneratorArgs{
+		DataSources: types.DataSources{
+			EnvSources:  []string{"app/db.env"},
+			FileSources: []string{"app/token.txt"},
+		},
+		Sops: &types.SopsArgs{
+			KeyServices: []string{"tcp://keys:5000"},
+		},
+	}
+	kvs, err := l.LoadKvPairs(args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []types.Pair{
+		{Key: "USER", Value: "admin"},
+		{Key: "PASSWORD", Value: "hunter2"},
+		{Key: "token.txt", Value: "s3cr3t"},
+	}
+	if !reflect.DeepEqual(kvs, expected) {
+		t.Fatalf("expected %v, got %v", expected, kvs)
+	}
+
+	args.Sops.KeyServices = nil
+	_, err = l.LoadKvPairs(args)
+	if err == nil || !strings.Contains(err.Error(), "no key service") {
+		t.Fatalf("expected sops failure, got %v", err)
+	}
+}
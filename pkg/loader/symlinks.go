@@ -0,0 +1,148 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+)
+
+// SymlinkPolicy says which symlinks out of its root
+// a root-only restricted loader follows.
+type SymlinkPolicy string
+
+const (
+	// SymlinkDeny follows no symlink out of the root,
+	// the default.
+	SymlinkDeny SymlinkPolicy = "deny"
+
+	// SymlinkFollowWithinRepo follows symlinks to files
+	// in the git work tree holding the root, e.g. shared
+	// files of a monorepo, or, for a remote base, in the
+	// tree it was fetched into.
+	SymlinkFollowWithinRepo SymlinkPolicy = "follow-within-repo"
+
+	// SymlinkFollowAll follows symlinks anywhere, except out
+	// of the trees remote bases were fetched into, which
+	// never may refer to the files of the machine building.
+	SymlinkFollowAll SymlinkPolicy = "follow-all"
+)
+
+const flagSymlinkPolicyName = "symlink-policy"
+
+var (
+	flagSymlinkPolicyValue = string(SymlinkDeny)
+	flagSymlinkPolicyHelp  = "which symlinks to files out of a " +
+		"kustomization's root to follow: '" + string(SymlinkDeny) +
+		"' follows none, '" + string(SymlinkFollowWithinRepo) +
+		"' those into the git repo holding the kustomization, and '" +
+		string(SymlinkFollowAll) + "' any."
+)
+
+func AddFlagSymlinkPolicy(set *pflag.FlagSet) {
+	set.StringVar(
+		&flagSymlinkPolicyValue, flagSymlinkPolicyName,
+		string(SymlinkDeny), flagSymlinkPolicyHelp)
+}
+
+func ValidateFlagSymlinkPolicy() (SymlinkPolicy, error) {
+	switch p := SymlinkPolicy(flagSymlinkPolicyValue); p {
+	case SymlinkDeny, SymlinkFollowWithinRepo, SymlinkFollowAll:
+		return p, nil
+	default:
+		return "", fmt.Errorf(
+			"illegal flag value --%s %s; legal values: %v",
+			flagSymlinkPolicyName, flagSymlinkPolicyValue,
+			[]SymlinkPolicy{
+				SymlinkDeny, SymlinkFollowWithinRepo, SymlinkFollowAll})
+	}
+}
+
+// SetSymlinkPolicy sets which symlinks out of their
+// roots the loader, and the loaders it makes, follow.
+func SetSymlinkPolicy(l ifc.Loader, p SymlinkPolicy) {
+	if fl, ok := l.(*fileLoader); ok {
+		fl.symlinks = p
+	}
+}
+
+// inheritedSymlinkPolicy returns the policy of the
+// nearest loader in the referrer chain that has one.
+func (fl *fileLoader) inheritedSymlinkPolicy() SymlinkPolicy {
+	for l := fl; l != nil; l = l.referrer {
+		if l.symlinks != "" {
+			return l.symlinks
+		}
+	}
+	return SymlinkDeny
+}
+
+// fetchedTree returns the tree the files of the loader
+// were fetched into, if they're remote, or "".
+func (fl *fileLoader) fetchedTree() fs.ConfirmedDir {
+	if repo := fl.containingRepo(); repo != nil {
+		return repo.CloneDir()
+	}
+	if b := fl.containingBucket(); b != nil {
+		return b.Dir
+	}
+	if a := fl.containingArchive(); a != nil {
+		return a.Dir
+	}
+	if s := fl.containingSchemeCopy(); s != nil {
+		return s.Dir
+	}
+	return ""
+}
+
+// workTree returns the nearest directory at or above
+// the loader's root that holds a .git, or "".
+func (fl *fileLoader) workTree() fs.ConfirmedDir {
+	for dir := fl.root.String(); ; dir = filepath.Dir(dir) {
+		if fl.fSys.Exists(filepath.Join(dir, ".git")) {
+			return fs.ConfirmedDir(dir)
+		}
+		if dir == filepath.Dir(dir) {
+			return ""
+		}
+	}
+}
+
+// followSymlink returns the file the path, which the
+// restrictor refused with the error, is a symlink to,
+// if it's one and the policy allows following it.
+func (fl *fileLoader) followSymlink(path string, refused error) (string, error) {
+	if !fs.ConfirmedDir(filepath.Dir(filepath.Clean(path))).HasPrefix(fl.root) {
+		// Out of the root even before following symlinks.
+		return "", refused
+	}
+	d, f, err := fl.fSys.CleanedAbs(path)
+	if err != nil || f == "" {
+		return "", refused
+	}
+	target := d.Join(f)
+	policy := fl.inheritedSymlinkPolicy()
+	tree := fl.fetchedTree()
+	if policy == SymlinkFollowAll && tree == "" {
+		return target, nil
+	}
+	if policy != SymlinkDeny {
+		if tree == "" {
+			tree = fl.workTree()
+		} else if t, _, err := fl.fSys.CleanedAbs(tree.String()); err == nil {
+			tree = t
+		}
+		if tree != "" && d.HasPrefix(tree) {
+			return target, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"security; file '%s' is a symlink to '%s', which is not in or "+
+			"below '%s', and --%s %s doesn't follow it",
+		path, target, fl.root, flagSymlinkPolicyName, policy)
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+// makeSymlinkTree makes, in a temp dir, a repo whose
+// app links to a file shared in the repo, and to a
+// file outside it, and a clone linking out of itself.
+func makeSymlinkTree(t *testing.T) string {
+	tmp, err := ioutil.TempDir("", "kustomize-symlinks")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tmp, _ = filepath.EvalSymlinks(tmp)
+	for _, dir := range []string{
+		"repo/.git", "repo/shared", "repo/app", "outside", "clone/base"} {
+		if err = os.MkdirAll(filepath.Join(tmp, dir), 0700); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, path := range []string{"repo/shared/cm.yaml", "outside/secret.yaml"} {
+		err = ioutil.WriteFile(
+			filepath.Join(tmp, path), []byte("kind: ConfigMap\n"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for link, target := range map[string]string{
+		"repo/app/cm.yaml":       "../shared/cm.yaml",
+		"repo/app/secret.yaml":   "../../outside/secret.yaml",
+		"clone/base/secret.yaml": "../../outside/secret.yaml",
+	} {
+		if err = os.Symlink(target, filepath.Join(tmp, link)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return tmp
+}
+
+func TestSymlinkPolicy(t *testing.T) {
+	tmp := makeSymlinkTree(t)
+	defer os.RemoveAll(tmp)
+	for _, c := range []struct {
+		policy SymlinkPolicy
+		path   string
+		ok     bool
+	}{
+		{SymlinkDeny, "cm.yaml", false},
+		{SymlinkDeny, "secret.yaml", false},
+		{SymlinkFollowWithinRepo, "cm.yaml", true},
+		{SymlinkFollowWithinRepo, "secret.yaml", false},
+		{SymlinkFollowAll, "cm.yaml", true},
+		{SymlinkFollowAll, "secret.yaml", true},
+		// Paths out of the root are refused regardless.
+		{SymlinkFollowAll, "../shared/cm.yaml", false},
+	} {
+		l := newLoaderOrDie(
+			RestrictionRootOnly, validators.MakeFakeValidator(),
+			fs.MakeFsOnDisk(), filepath.Join(tmp, "repo/app"))
+		SetSymlinkPolicy(l, c.policy)
+		_, err := l.Load(c.path)
+		if c.ok && err != nil {
+			t.Fatalf("%s %s: unexpected error: %v", c.policy, c.path, err)
+		}
+		if !c.ok && (err == nil || !strings.Contains(err.Error(), "security")) {
+			t.Fatalf("%s %s: expected security error, got %v", c.policy, c.path, err)
+		}
+	}
+}
+
+func TestSymlinkPolicyOfRemoteBases(t *testing.T) {
+	tmp := makeSymlinkTree(t)
+	defer os.RemoveAll(tmp)
+	l := newLoaderOrDie(
+		RestrictionRootOnly, validators.MakeFakeValidator(),
+		fs.MakeFsOnDisk(), filepath.Join(tmp, "repo/app"))
+	SetSymlinkPolicy(l, SymlinkFollowAll)
+	l.cloner = func(rs *git.RepoSpec) error {
+		rs.Dir = fs.ConfirmedDir(filepath.Join(tmp, "clone"))
+		rs.Cached = true
+		return nil
+	}
+	l1, err := l.New("github.com/someOrg/someRepo/base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = l1.Load("secret.yaml")
+	if err == nil || !strings.Contains(err.Error(), "is a symlink to") {
+		t.Fatalf("expected security error, got %v", err)
+	}
+}
+
+func TestValidateFlagSymlinkPolicy(t *testing.T) {
+	defer func() { flagSymlinkPolicyValue = string(SymlinkDeny) }()
+	flagSymlinkPolicyValue = "follow-within-repo"
+	if p, err := ValidateFlagSymlinkPolicy(); err != nil || p != SymlinkFollowWithinRepo {
+		t.Fatalf("unexpected policy %s, %v", p, err)
+	}
+	flagSymlinkPolicyValue = "follow-some"
+	if _, err := ValidateFlagSymlinkPolicy(); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
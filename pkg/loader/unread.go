@@ -0,0 +1,85 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
+)
+
+// delegator is a Loader wrapping another, e.g. a fake.
+type delegator interface {
+	Delegate() ifc.Loader
+}
+
+// UnreadFiles returns the paths, relative to the root of the
+// loader, of the files in its directory tree that it didn't
+// load.  Hidden files, the directories of the loaders it made,
+// directories holding other kustomizations, and the skipped
+// files and directories, relative to the root, are left out.  Loaders
+// of git clones have no unread files.
+func UnreadFiles(l ifc.Loader, skip ...string) ([]string, error) {
+	if d, ok := l.(delegator); ok {
+		l = d.Delegate()
+	}
+	fl, ok := l.(*fileLoader)
+	if !ok || fl.repoSpec != nil {
+		return nil, nil
+	}
+	root := fl.root.String()
+	skipped := make(map[string]bool)
+	for _, c := range fl.children {
+		skipped[c.String()] = true
+	}
+	for _, s := range skip {
+		skipped[fl.root.Join(fs.LocalPath(s))] = true
+	}
+	var unread []string
+	err := fl.fSys.Walk(root, func(
+		path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		if strings.HasPrefix(filepath.Base(path), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if skipped[path] || fl.holdsKustomization(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !fl.read[path] && !skipped[path] {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			unread = append(unread, rel)
+		}
+		return nil
+	})
+	sort.Strings(unread)
+	return unread, err
+}
+
+func (fl *fileLoader) holdsKustomization(dir string) bool {
+	for _, kf := range pgmconfig.RecognizedKustomizationFileNames() {
+		if fl.fSys.Exists(filepath.Join(dir, kf)) {
+			return true
+		}
+	}
+	return false
+}
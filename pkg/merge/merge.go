@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package merge detects and merges variants, i.e. resources
+// customized differently by overlays of a common base.
+//
+// Tools embedding kustomize can use it to check, before
+// combining two overlays of the same base, whether their
+// output can be combined.
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// ConflictDetector detects and merges conflicting
+// customizations of variants.  Variants remember
+// the pristine resource they were customized from
+// if accumulated by a KustTarget.
+//
+// The implementation requiring k8s dependencies is
+// k8sdeps/transformer.FactoryImpl.
+type ConflictDetector interface {
+	// HasConflict returns true if the customizations
+	// a and b made to the pristine resource they share
+	// conflict.  It fails if a and b aren't variants.
+	HasConflict(a, b *resource.Resource) (bool, error)
+
+	// MergeVariants merges into dst the customizations
+	// src made to the pristine resource they share,
+	// failing if those customizations conflict with dst's.
+	MergeVariants(dst, src *resource.Resource) error
+}
+
+// MergeResource merges src into dst.  Identical resources
+// merge trivially; otherwise dst and src must be variants
+// whose customizations don't conflict.
+func MergeResource(d ConflictDetector, dst, src *resource.Resource) error {
+	if dst.KunstructEqual(src) {
+		return nil
+	}
+	if !dst.SharesPristine(src) {
+		return fmt.Errorf(
+			"%s differs from %s, and they aren't variants of the same resource",
+			dst.CurId(), src.CurId())
+	}
+	return d.MergeVariants(dst, src)
+}
+
+// MergeResources returns a ResMap holding the resources of a
+// followed by those of b, except that any resource in b with
+// the same id as a resource in a is merged into it using
+// MergeResource.  Neither a nor b is modified.
+func MergeResources(
+	d ConflictDetector, a, b resmap.ResMap) (resmap.ResMap, error) {
+	result := a.DeepCopy()
+	for _, r := range b.Resources() {
+		matches := result.GetMatchingResourcesByCurrentId(r.CurId().Equals)
+		if len(matches) != 1 {
+			err := result.Append(r.DeepCopy())
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err := MergeResource(d, matches[0], r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package merge_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/merge"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+var rf = resource.NewFactory(
+	kunstruct.NewKunstructuredFactoryImpl())
+
+func deployment() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name": "dep",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+		},
+	}
+}
+
+// variant returns a customization of a pristine deployment.
+func variant(t *testing.T, customize func(spec map[string]interface{})) resmap.ResMap {
+	r := rf.FromMap(deployment())
+	r.RecordPristine()
+	customize(r.Map()["spec"].(map[string]interface{}))
+	m := resmap.New()
+	if err := m.Append(r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestMergeResources(t *testing.T) {
+	a := variant(t, func(spec map[string]interface{}) {
+		spec["replicas"] = int64(3)
+	})
+	b := variant(t, func(spec map[string]interface{}) {
+		spec["paused"] = true
+	})
+	d := transformer.NewFactoryImpl()
+
+	conflict, err := d.HasConflict(a.Resources()[0], b.Resources()[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conflict {
+		t.Fatalf("expected no conflict")
+	}
+	m, err := merge.MergeResources(d, a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"replicas": int64(3),
+		"paused":   true,
+	}
+	if len(m.Resources()) != 1 {
+		t.Fatalf("expected one resource, got %v", m)
+	}
+	actual := m.Resources()[0].Map()["spec"]
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+	if a.Resources()[0].Map()["spec"].(map[string]interface{})["paused"] != nil {
+		t.Fatalf("expected input left unmodified")
+	}
+}
+
+func TestMergeResourcesConflict(t *testing.T) {
+	a := variant(t, func(spec map[string]interface{}) {
+		spec["replicas"] = int64(3)
+	})
+	b := variant(t, func(spec map[string]interface{}) {
+		spec["replicas"] = int64(5)
+	})
+	d := transformer.NewFactoryImpl()
+
+	conflict, err := d.HasConflict(a.Resources()[0], b.Resources()[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !conflict {
+		t.Fatalf("expected conflict")
+	}
+	_, err = merge.MergeResources(d, a, b)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		"unable to merge variants of apps_v1_Deployment|~X|dep") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMergeResourcesNotVariants(t *testing.T) {
+	a := resmap.New()
+	b := resmap.New()
+	r := rf.FromMap(deployment())
+	if err := a.Append(r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := b.Append(r.DeepCopy()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := transformer.NewFactoryImpl()
+
+	m, err := merge.MergeResources(d, a, b)
+	if err != nil {
+		t.Fatalf("identical resources should merge: %v", err)
+	}
+	if len(m.Resources()) != 1 {
+		t.Fatalf("expected one resource, got %v", m)
+	}
+
+	b.Resources()[0].Map()["spec"] = map[string]interface{}{"replicas": int64(2)}
+	_, err = merge.MergeResources(d, a, b)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "aren't variants of the same resource") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
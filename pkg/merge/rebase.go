@@ -0,0 +1,60 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package merge
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// Rebaser carries the customizations an overlay made to
+// a base resource over to a new version of that resource.
+//
+// The implementation requiring k8s dependencies is
+// k8sdeps/transformer.FactoryImpl.
+type Rebaser interface {
+	// Rebase returns a patch that, applied to newBase,
+	// makes the customizations that overlay made to
+	// oldBase, or nil if no patch is needed.  It fails
+	// if the changes from oldBase to newBase conflict
+	// with those customizations.
+	Rebase(oldBase, newBase, overlay *resource.Resource) (*resource.Resource, error)
+}
+
+// RebasePatches returns patches that, applied to the resources
+// of newBase, make the customizations that the resources of
+// overlay made to the resources of oldBase.  Resources are
+// matched by id, so the overlay mustn't rename base resources.
+// Resources only in overlay aren't customizations of the base,
+// so yield no patch.
+func RebasePatches(
+	r Rebaser, oldBase, newBase, overlay resmap.ResMap) (resmap.ResMap, error) {
+	result := resmap.New()
+	for _, o := range overlay.Resources() {
+		old, err := oldBase.GetByCurrentId(o.CurId())
+		if err != nil {
+			continue
+		}
+		current, err := newBase.GetByCurrentId(o.CurId())
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%s is customized by the overlay, but not in the new base",
+				o.CurId())
+		}
+		p, err := r.Rebase(old, current, o)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			continue
+		}
+		err = result.Append(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
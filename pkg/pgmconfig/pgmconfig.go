@@ -29,6 +29,13 @@ const (
 	// Use this when XdgConfigHome not defined.
 	DefaultConfigSubdir = ".config"
 
+	// An environment variable to consult for the
+	// directory to cache data, e.g. remote bases, in.
+	XdgCacheHome = "XDG_CACHE_HOME"
+
+	// Use this when XdgCacheHome not defined.
+	DefaultCacheSubdir = ".cache"
+
 	// Program name, for help, finding the XDG_CONFIG_DIR, etc.
 	ProgramName = "kustomize"
 
@@ -23,11 +23,22 @@ func _() {
 	_ = x[HashTransformer-12]
 	_ = x[InventoryTransformer-13]
 	_ = x[LegacyOrderTransformer-14]
+	_ = x[HelmChartInflationGenerator-15]
+	_ = x[VaultSecretGenerator-16]
+	_ = x[ReplacementTransformer-17]
+	_ = x[ImageRegistryTransformer-18]
+	_ = x[SidecarTransformer-19]
+	_ = x[DefaultResourcesTransformer-20]
+	_ = x[DefaultSecurityContextTransformer-21]
+	_ = x[SchedulingTransformer-22]
+	_ = x[ApiVersionUpgradeTransformer-23]
+	_ = x[EnvSubstTransformer-24]
+	_ = x[StarlarkTransformer-25]
 }
 
-const _BuiltinPluginType_name = "UnknownSecretGeneratorConfigMapGeneratorReplicaCountTransformerNamespaceTransformerPatchJson6902TransformerPatchStrategicMergeTransformerPatchTransformerLabelTransformerAnnotationsTransformerPrefixSuffixTransformerImageTagTransformerHashTransformerInventoryTransformerLegacyOrderTransformer"
+const _BuiltinPluginType_name = "UnknownSecretGeneratorConfigMapGeneratorReplicaCountTransformerNamespaceTransformerPatchJson6902TransformerPatchStrategicMergeTransformerPatchTransformerLabelTransformerAnnotationsTransformerPrefixSuffixTransformerImageTagTransformerHashTransformerInventoryTransformerLegacyOrderTransformerHelmChartInflationGeneratorVaultSecretGeneratorReplacementTransformerImageRegistryTransformerSidecarTransformerDefaultResourcesTransformerDefaultSecurityContextTransformerSchedulingTransformerApiVersionUpgradeTransformerEnvSubstTransformerStarlarkTransformer"
 
-var _BuiltinPluginType_index = [...]uint16{0, 7, 22, 40, 63, 83, 107, 137, 153, 169, 191, 214, 233, 248, 268, 290}
+var _BuiltinPluginType_index = [...]uint16{0, 7, 22, 40, 63, 83, 107, 137, 153, 169, 191, 214, 233, 248, 268, 290, 317, 337, 359, 383, 401, 428, 461, 482, 510, 529, 548}
 
 func (i BuiltinPluginType) String() string {
 	if i < 0 || i >= BuiltinPluginType(len(_BuiltinPluginType_index)-1) {
@@ -27,6 +27,17 @@ const (
 	HashTransformer
 	InventoryTransformer
 	LegacyOrderTransformer
+	HelmChartInflationGenerator
+	VaultSecretGenerator
+	ReplacementTransformer
+	ImageRegistryTransformer
+	SidecarTransformer
+	DefaultResourcesTransformer
+	DefaultSecurityContextTransformer
+	SchedulingTransformer
+	ApiVersionUpgradeTransformer
+	EnvSubstTransformer
+	StarlarkTransformer
 )
 
 var stringToBuiltinPluginTypeMap map[string]BuiltinPluginType
@@ -55,21 +66,32 @@ func GetBuiltinPluginType(n string) BuiltinPluginType {
 }
 
 var GeneratorFactories = map[BuiltinPluginType]func() resmap.GeneratorPlugin{
-	SecretGenerator:    builtin.NewSecretGeneratorPlugin,
-	ConfigMapGenerator: builtin.NewConfigMapGeneratorPlugin,
+	SecretGenerator:             builtin.NewSecretGeneratorPlugin,
+	ConfigMapGenerator:          builtin.NewConfigMapGeneratorPlugin,
+	HelmChartInflationGenerator: builtin.NewHelmChartInflationGeneratorPlugin,
+	VaultSecretGenerator:        builtin.NewVaultSecretGeneratorPlugin,
 }
 
 var TransformerFactories = map[BuiltinPluginType]func() resmap.TransformerPlugin{
-	NamespaceTransformer:           builtin.NewNamespaceTransformerPlugin,
-	ReplicaCountTransformer:        builtin.NewReplicaCountTransformerPlugin,
-	PatchJson6902Transformer:       builtin.NewPatchJson6902TransformerPlugin,
-	PatchStrategicMergeTransformer: builtin.NewPatchStrategicMergeTransformerPlugin,
-	PatchTransformer:               builtin.NewPatchTransformerPlugin,
-	LabelTransformer:               builtin.NewLabelTransformerPlugin,
-	AnnotationsTransformer:         builtin.NewAnnotationsTransformerPlugin,
-	PrefixSuffixTransformer:        builtin.NewPrefixSuffixTransformerPlugin,
-	ImageTagTransformer:            builtin.NewImageTagTransformerPlugin,
-	HashTransformer:                builtin.NewHashTransformerPlugin,
-	InventoryTransformer:           builtin.NewInventoryTransformerPlugin,
-	LegacyOrderTransformer:         builtin.NewLegacyOrderTransformerPlugin,
+	NamespaceTransformer:              builtin.NewNamespaceTransformerPlugin,
+	ReplicaCountTransformer:           builtin.NewReplicaCountTransformerPlugin,
+	PatchJson6902Transformer:          builtin.NewPatchJson6902TransformerPlugin,
+	PatchStrategicMergeTransformer:    builtin.NewPatchStrategicMergeTransformerPlugin,
+	PatchTransformer:                  builtin.NewPatchTransformerPlugin,
+	LabelTransformer:                  builtin.NewLabelTransformerPlugin,
+	AnnotationsTransformer:            builtin.NewAnnotationsTransformerPlugin,
+	PrefixSuffixTransformer:           builtin.NewPrefixSuffixTransformerPlugin,
+	ImageTagTransformer:               builtin.NewImageTagTransformerPlugin,
+	HashTransformer:                   builtin.NewHashTransformerPlugin,
+	InventoryTransformer:              builtin.NewInventoryTransformerPlugin,
+	LegacyOrderTransformer:            builtin.NewLegacyOrderTransformerPlugin,
+	ReplacementTransformer:            builtin.NewReplacementTransformerPlugin,
+	ImageRegistryTransformer:          builtin.NewImageRegistryTransformerPlugin,
+	SidecarTransformer:                builtin.NewSidecarTransformerPlugin,
+	DefaultResourcesTransformer:       builtin.NewDefaultResourcesTransformerPlugin,
+	DefaultSecurityContextTransformer: builtin.NewDefaultSecurityContextTransformerPlugin,
+	SchedulingTransformer:             builtin.NewSchedulingTransformerPlugin,
+	ApiVersionUpgradeTransformer:      builtin.NewApiVersionUpgradeTransformerPlugin,
+	EnvSubstTransformer:               builtin.NewEnvSubstTransformerPlugin,
+	StarlarkTransformer:               builtin.NewStarlarkTransformerPlugin,
 }
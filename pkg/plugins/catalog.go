@@ -0,0 +1,171 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/yaml"
+)
+
+// CatalogFileName is the plugin catalog in
+// the plugin directory, unless flags name another.
+const CatalogFileName = "catalog.yaml"
+
+// catalog lists the plugins trusted to run, e.g.
+//   publicKey: |
+//     -----BEGIN PUBLIC KEY-----
+//     ...
+//   plugins:
+//   - apiVersion: someteam.example.com/v1
+//     kind: ChartInflator
+//     source: https://github.com/someteam/chartinflator
+//     digest: sha256:3b1f...
+//     signature: MEUCIQ...
+//     sandbox:
+//       network: true
+type catalog struct {
+	// PublicKey, a PEM encoded RSA or ECDSA key,
+	// if set, must verify the signature of each plugin.
+	PublicKey string `json:"publicKey,omitempty" yaml:"publicKey,omitempty"`
+
+	Plugins []catalogEntry `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+}
+
+type catalogEntry struct {
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty" yaml:"kind,omitempty"`
+
+	// Source says where the plugin comes from.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Digest is the sha256 digest of the executable or
+	// the .so file of the plugin, or the digest pinning
+	// its container image.  Functions served over gRPC
+	// have none.
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+
+	// Signature is the base64 encoded signature of the
+	// plugin by the key of the catalog, over its digest.
+	Signature string `json:"signature,omitempty" yaml:"signature,omitempty"`
+
+	// Sandbox, if set, allows the plugin more
+	// than other sandboxed plugins.
+	Sandbox *sandboxSpec `json:"sandbox,omitempty" yaml:"sandbox,omitempty"`
+}
+
+// loadCatalog reads the catalog at the path.
+// A missing catalog lists no plugins.
+func loadCatalog(path string) (*catalog, error) {
+	c := &catalog{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, errors.Wrapf(err, "parsing plugin catalog %s", path)
+	}
+	return c, nil
+}
+
+func (c *catalog) find(x gvk.Gvk) *catalogEntry {
+	apiVersion := x.Version
+	if x.Group != "" {
+		apiVersion = x.Group + "/" + x.Version
+	}
+	for i := range c.Plugins {
+		if c.Plugins[i].APIVersion == apiVersion &&
+			c.Plugins[i].Kind == x.Kind {
+			return &c.Plugins[i]
+		}
+	}
+	return nil
+}
+
+// verify returns an error unless the signature of the entry
+// is the catalog key's signature of the plugin, as made by
+//   openssl dgst -sha256 -sign key.pem plugin | base64
+func (c *catalog) verify(e *catalogEntry) error {
+	block, _ := pem.Decode([]byte(c.PublicKey))
+	if block == nil {
+		return fmt.Errorf("plugin catalog public key isn't PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parsing plugin catalog public key")
+	}
+	if e.Signature == "" {
+		return fmt.Errorf("it isn't signed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(e.Signature)
+	if err != nil {
+		return errors.Wrap(err, "decoding its signature")
+	}
+	sum, err := hex.DecodeString(strings.TrimPrefix(e.Digest, "sha256:"))
+	if err != nil || len(sum) != sha256.Size ||
+		!strings.HasPrefix(e.Digest, "sha256:") {
+		return fmt.Errorf("its digest %s isn't like sha256:3b1f...", e.Digest)
+	}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, sum, sig)
+	case *ecdsa.PublicKey:
+		var rs struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+			return errors.Wrap(err, "decoding its signature")
+		}
+		if !ecdsa.Verify(k, sum, rs.R, rs.S) {
+			return fmt.Errorf("its signature is invalid")
+		}
+		return nil
+	}
+	return fmt.Errorf(
+		"plugin catalog public key is a %T, not an RSA or ECDSA key", key)
+}
+
+// fileDigest returns the sha256 digest of the file,
+// like the digests of the catalog.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// imageDigest returns the digest pinning the image,
+// as in example.com/set-team@sha256:3b1f...
+func imageDigest(image string) (string, error) {
+	i := strings.LastIndex(image, "@")
+	if i < 0 {
+		return "", fmt.Errorf(
+			"function container %s isn't pinned by digest, "+
+				"like %s@sha256:3b1f...", image, image)
+	}
+	return image[i+1:], nil
+}
@@ -33,19 +33,65 @@ unable to load external plugin %s because plugins disabled
 specify the flag
   --%s
 to %s`
+
+	flagEnableExternalSecretsName = "enable-external-secrets"
+	flagEnableExternalSecretsHelp = "if set, builtin generators may read " +
+		"secret values from external stores, e.g. Vault."
+
+	flagContainerRuntimeName = "container-runtime"
+	flagContainerRuntimeHelp = "the command running containerized " +
+		"plugins, e.g. docker or podman."
+
+	flagNetworkName = "network"
+	flagNetworkHelp = "if set, containerized plugins asking for " +
+		"the network may use it."
+
+	flagMountName = "mount"
+	flagMountHelp = "a mount, like type=bind,src=/data,dst=/data, " +
+		"to add to the containers of containerized plugins. " +
+		"May be repeated."
+
+	flagEnableUntrustedPluginsName = "enable-untrusted-plugins"
+	flagEnableUntrustedPluginsHelp = "if set, plugins may run without " +
+		"being listed, with their digest, in the plugin catalog."
+
+	flagPluginCatalogName = "plugin-catalog"
+	flagPluginCatalogHelp = "the plugin catalog, listing the plugins " +
+		"trusted to run."
+
+	flagSandboxPluginsName = "sandbox-plugins"
+	flagSandboxPluginsHelp = "if set, exec plugins run without the " +
+		"environment, the network and most of the filesystem, " +
+		"unless the plugin catalog allows them. Requires Linux."
+
+	flagSandboxEnvName = "sandbox-env"
+	flagSandboxEnvHelp = "an environment variable sandboxed plugins " +
+		"keep. May be repeated."
+
+	flagSandboxPathName = "sandbox-path"
+	flagSandboxPathHelp = "a file or directory sandboxed plugins " +
+		"see. May be repeated."
+
+	// DefaultContainerRuntime runs containerized plugins.
+	DefaultContainerRuntime = "docker"
 )
 
+// ActivePluginConfig enables plugins, trusting
+// them all, as tests building their plugins do.
 func ActivePluginConfig() *types.PluginConfig {
 	pc := DefaultPluginConfig()
 	pc.Enabled = true
+	pc.UntrustedEnabled = true
 	return pc
 }
 
 func DefaultPluginConfig() *types.PluginConfig {
+	dir := filepath.Join(configRoot(), pgmconfig.PluginRoot)
 	return &types.PluginConfig{
-		Enabled: false,
-		DirectoryPath: filepath.Join(
-			configRoot(), pgmconfig.PluginRoot),
+		Enabled:          false,
+		DirectoryPath:    dir,
+		ContainerRuntime: DefaultContainerRuntime,
+		CatalogPath:      filepath.Join(dir, CatalogFileName),
 	}
 }
 
@@ -57,6 +103,65 @@ func notEnabledErr(name string) error {
 		flagEnablePluginsHelp)
 }
 
+func externalSecretsNotEnabledErr(name string) error {
+	return fmt.Errorf(
+		"builtin %s reads external secrets, which requires --%s",
+		name, flagEnableExternalSecretsName)
+}
+
+func untrustedErr(name, reason string) error {
+	return fmt.Errorf(
+		"refusing to run plugin %s, which %s; specify --%s to run it anyway",
+		name, reason, flagEnableUntrustedPluginsName)
+}
+
+// AddFlagEnableExternalSecrets adds the flag allowing builtin
+// generators to read secrets from external stores.
+func AddFlagEnableExternalSecrets(set *pflag.FlagSet, v *bool) {
+	set.BoolVar(
+		v, flagEnableExternalSecretsName,
+		false, flagEnableExternalSecretsHelp)
+}
+
+// AddFlagsContainerPlugins adds the flags choosing how
+// containerized plugins run.
+func AddFlagsContainerPlugins(set *pflag.FlagSet, pc *types.PluginConfig) {
+	set.StringVar(
+		&pc.ContainerRuntime, flagContainerRuntimeName,
+		DefaultContainerRuntime, flagContainerRuntimeHelp)
+	set.BoolVar(
+		&pc.NetworkEnabled, flagNetworkName,
+		false, flagNetworkHelp)
+	set.StringArrayVar(
+		&pc.Mounts, flagMountName,
+		nil, flagMountHelp)
+}
+
+// AddFlagsPluginTrust adds the flags choosing
+// which plugins may run.
+func AddFlagsPluginTrust(set *pflag.FlagSet, pc *types.PluginConfig) {
+	set.BoolVar(
+		&pc.UntrustedEnabled, flagEnableUntrustedPluginsName,
+		false, flagEnableUntrustedPluginsHelp)
+	set.StringVar(
+		&pc.CatalogPath, flagPluginCatalogName,
+		pc.CatalogPath, flagPluginCatalogHelp)
+}
+
+// AddFlagsSandboxPlugins adds the flags choosing
+// what sandboxed exec plugins may use.
+func AddFlagsSandboxPlugins(set *pflag.FlagSet, pc *types.PluginConfig) {
+	set.BoolVar(
+		&pc.Sandboxed, flagSandboxPluginsName,
+		false, flagSandboxPluginsHelp)
+	set.StringArrayVar(
+		&pc.SandboxEnv, flagSandboxEnvName,
+		nil, flagSandboxEnvHelp)
+	set.StringArrayVar(
+		&pc.SandboxPaths, flagSandboxPathName,
+		nil, flagSandboxPathHelp)
+}
+
 func AddFlagEnablePlugins(set *pflag.FlagSet, v *bool) {
 	set.BoolVar(
 		v, flagEnablePluginsName,
@@ -25,6 +25,12 @@ const (
 	hashAnnotation      = "kustomize.config.k8s.io/needs-hash"
 	behaviorAnnotation  = "kustomize.config.k8s.io/behavior"
 	tmpConfigFilePrefix = "kust-plugin-config-"
+
+	// resultsEnvVar names the file an exec plugin may
+	// write a YAML list of results to, e.g. problems it
+	// noticed in the resources it transformed.
+	resultsEnvVar        = "KUSTOMIZE_PLUGIN_RESULTS"
+	tmpResultsFilePrefix = "kust-plugin-results-"
 )
 
 // ExecPlugin record the name and args of an executable
@@ -46,6 +52,28 @@ type ExecPlugin struct {
 
 	// loader to load files
 	ldr ifc.Loader
+
+	// function is true if the executable speaks the KRM
+	// function protocol, reading and writing ResourceLists.
+	function bool
+
+	// container, if not nil, runs the function in place
+	// of an executable.
+	container *containerSpec
+
+	// grpc, if not nil, calls a server running the
+	// function in place of an executable.
+	grpc *grpcSpec
+
+	// pc says how to run containers.
+	pc *types.PluginConfig
+
+	// sandbox, if not nil, says what the
+	// sandboxed executable may use.
+	sandbox *sandboxSpec
+
+	// results are those of the last run.
+	results []resmap.Result
 }
 
 func NewExecPlugin(p string) *ExecPlugin {
@@ -61,11 +89,26 @@ func (p *ExecPlugin) isAvailable() bool {
 	return f.Mode()&0111 != 0000
 }
 
+// digest returns the digest the plugin catalog
+// must give the plugin.
+func (p *ExecPlugin) digest() (string, error) {
+	if p.grpc != nil {
+		return "", nil
+	}
+	if p.container != nil {
+		return imageDigest(p.container.Image)
+	}
+	return fileDigest(p.path)
+}
+
 func (p *ExecPlugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, config []byte) error {
 	p.rf = rf
 	p.ldr = ldr
 	p.cfg = config
+	if p.function {
+		return p.configureFunction()
+	}
 	return p.processOptionalArgsFields()
 }
 
@@ -96,6 +139,9 @@ func (p *ExecPlugin) processOptionalArgsFields() error {
 }
 
 func (p *ExecPlugin) Generate() (resmap.ResMap, error) {
+	if p.function {
+		return p.generateWithFunction()
+	}
 	output, err := p.invokePlugin(nil)
 	if err != nil {
 		return nil, err
@@ -108,6 +154,9 @@ func (p *ExecPlugin) Generate() (resmap.ResMap, error) {
 }
 
 func (p *ExecPlugin) Transform(rm resmap.ResMap) error {
+	if p.function {
+		return p.transformWithFunction(rm)
+	}
 	// add ResIds as annotations to all objects so that we can add them back
 	inputRM, err := p.getResMapWithIdAnnotation(rm)
 	if err != nil {
@@ -130,9 +179,39 @@ func (p *ExecPlugin) Transform(rm resmap.ResMap) error {
 	return p.updateResMapValues(output, rm)
 }
 
+// Results returns the results the executable
+// reported when it last ran.
+func (p *ExecPlugin) Results() []resmap.Result {
+	return p.results
+}
+
+// Validate passes the resources to the executable, which
+// emits a YAML list of results, each with a severity, a
+// message and, optionally, a resourceRef.
+func (p *ExecPlugin) Validate(rm resmap.ResMap) ([]resmap.Result, error) {
+	if p.function {
+		return p.validateWithFunction(rm)
+	}
+	resources, err := rm.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+	output, err := p.invokePlugin(resources)
+	if err != nil {
+		return nil, fmt.Errorf("%v %s", err, string(output))
+	}
+	var results []resmap.Result
+	if err := yaml.Unmarshal(output, &results); err != nil {
+		return nil, errors.Wrapf(err, "reading results of %s", p.path)
+	}
+	return results, nil
+}
+
 // invokePlugin writes plugin config to a temp file, then
 // passes the full temp file path as the first arg to a process
-// running the plugin binary.  Process output is returned.
+// running the plugin binary.  Process output is returned, and
+// the results it writes to the file named by resultsEnvVar
+// are kept.
 func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 	f, err := ioutil.TempFile("", tmpConfigFilePrefix)
 	if err != nil {
@@ -149,25 +228,69 @@ func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 		return nil, errors.Wrap(
 			err, "closing plugin config file "+f.Name())
 	}
-	cmd := exec.Command(
-		p.path, append([]string{f.Name()}, p.args...)...)
-	cmd.Env = p.getEnv()
+	r, err := ioutil.TempFile("", tmpResultsFilePrefix)
+	if err != nil {
+		return nil, errors.Wrap(
+			err, "creating tmp plugin results file")
+	}
+	r.Close()
+	defer os.Remove(r.Name())
+	cmd, cleanup, err := p.command(
+		append([]string{f.Name()}, p.args...), f.Name(), r.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	cmd.Env = append(cmd.Env, resultsEnvVar+"="+r.Name())
 	cmd.Stdin = bytes.NewReader(input)
 	cmd.Stderr = os.Stderr
-	if _, err := os.Stat(p.ldr.Root()); err == nil {
-		cmd.Dir = p.ldr.Root()
-	}
 	result, err := cmd.Output()
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "failure in plugin configured via %s; %v",
 			f.Name(), err.Error())
 	}
+	results, err := ioutil.ReadFile(r.Name())
+	if err != nil {
+		return nil, err
+	}
+	p.results = nil
+	if err := yaml.Unmarshal(results, &p.results); err != nil {
+		return nil, errors.Wrapf(err, "reading results of %s", p.path)
+	}
 	return result, os.Remove(f.Name())
 }
 
+// command returns the command running the executable with
+// the args in the kustomization root, sandboxed, seeing the
+// files too, if so configured, and a func cleaning up after.
+func (p *ExecPlugin) command(
+	args []string, files ...string) (*exec.Cmd, func(), error) {
+	var dir string
+	if _, err := os.Stat(p.ldr.Root()); err == nil {
+		dir = p.ldr.Root()
+	}
+	if p.sandbox == nil {
+		cmd := exec.Command(p.path, args...)
+		cmd.Env = p.getEnv()
+		cmd.Dir = dir
+		return cmd, func() {}, nil
+	}
+	cmd, cleanup, err := sandboxCommand(p.sandbox, dir,
+		p.sandbox.visible(append([]string{p.path, dir}, files...)...),
+		p.path, args...)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "sandboxing plugin %s", p.path)
+	}
+	cmd.Env = p.getEnv()
+	return cmd, cleanup, nil
+}
+
 func (p *ExecPlugin) getEnv() []string {
 	env := os.Environ()
+	if p.sandbox != nil {
+		env = p.sandbox.environ(env)
+	}
 	env = append(env,
 		"KUSTOMIZE_PLUGIN_CONFIG_STRING="+string(p.cfg),
 		"KUSTOMIZE_PLUGIN_CONFIG_ROOT="+p.ldr.Root())
@@ -0,0 +1,404 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// functionAnnotation marks the configuration of an exec
+	// plugin speaking the KRM function protocol.  Its value
+	// may say where the function is, e.g.
+	//   exec:
+	//     path: ./functions/set-team
+	// relative to the kustomization,
+	//   container:
+	//     image: example.com/set-team:v1
+	// for a function run in a container, or
+	//   grpc:
+	//     address: unix:///run/set-team.sock
+	// for a function served by a long-running local server.
+	// Without any, the function is looked up like any exec
+	// plugin.
+	functionAnnotation = "config.kubernetes.io/function"
+
+	resourceListApiVersion = "config.kubernetes.io/v1"
+	resourceListKind       = "ResourceList"
+)
+
+// functionSpec is the value of the function annotation.
+type functionSpec struct {
+	Exec      *execSpec      `json:"exec,omitempty" yaml:"exec,omitempty"`
+	Container *containerSpec `json:"container,omitempty" yaml:"container,omitempty"`
+	Grpc      *grpcSpec      `json:"grpc,omitempty" yaml:"grpc,omitempty"`
+}
+
+type execSpec struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+type containerSpec struct {
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+
+	// Network is true if the function needs the network,
+	// which the build must allow.
+	Network bool `json:"network,omitempty" yaml:"network,omitempty"`
+}
+
+// ResourceList is the input and output of a KRM function.
+type ResourceList struct {
+	APIVersion     string                   `json:"apiVersion" yaml:"apiVersion"`
+	Kind           string                   `json:"kind" yaml:"kind"`
+	Items          []map[string]interface{} `json:"items" yaml:"items"`
+	FunctionConfig map[string]interface{}   `json:"functionConfig,omitempty" yaml:"functionConfig,omitempty"`
+	Results        []FunctionResult         `json:"results,omitempty" yaml:"results,omitempty"`
+}
+
+// FunctionResult is a message a KRM function reports.
+type FunctionResult struct {
+	Message     string               `json:"message,omitempty" yaml:"message,omitempty"`
+	Severity    string               `json:"severity,omitempty" yaml:"severity,omitempty"`
+	ResourceRef *FunctionResourceRef `json:"resourceRef,omitempty" yaml:"resourceRef,omitempty"`
+	Field       *FunctionField       `json:"field,omitempty" yaml:"field,omitempty"`
+}
+
+// FunctionResourceRef is the resource a FunctionResult is about.
+type FunctionResourceRef struct {
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// FunctionField is the field a FunctionResult is about.
+type FunctionField struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// toResults converts the results a function
+// reports to those of kustomize.
+func toResults(in []FunctionResult) []resmap.Result {
+	var results []resmap.Result
+	for _, r := range in {
+		result := resmap.Result{
+			Severity: resmap.Severity(r.Severity),
+			Message:  r.Message,
+		}
+		if ref := r.ResourceRef; ref != nil {
+			x := gvk.Gvk{Version: ref.APIVersion, Kind: ref.Kind}
+			if i := strings.Index(ref.APIVersion, "/"); i >= 0 {
+				x.Group, x.Version = ref.APIVersion[:i], ref.APIVersion[i+1:]
+			}
+			id := resid.NewResIdWithNamespace(x, ref.Name, ref.Namespace)
+			result.ResourceRef = &id
+		}
+		if r.Field != nil {
+			result.FieldPath = r.Field.Path
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// isFunction returns true if the plugin configuration
+// asks for the KRM function protocol.
+func isFunction(res *resource.Resource) bool {
+	_, ok := res.GetAnnotations()[functionAnnotation]
+	return ok
+}
+
+// NewFunctionPlugin returns an ExecPlugin speaking the KRM
+// function protocol, running the executable at the path
+// unless its configuration names another, or a container.
+func NewFunctionPlugin(p string, pc *types.PluginConfig) *ExecPlugin {
+	return &ExecPlugin{path: p, function: true, pc: pc}
+}
+
+// configureFunction finds the executable, the container
+// image or the gRPC server of the function.
+func (p *ExecPlugin) configureFunction() error {
+	var c struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+		} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	}
+	if err := yaml.Unmarshal(p.cfg, &c); err != nil {
+		return err
+	}
+	var spec functionSpec
+	err := yaml.Unmarshal(
+		[]byte(c.Metadata.Annotations[functionAnnotation]), &spec)
+	if err != nil {
+		return errors.Wrapf(err, "parsing annotation %s", functionAnnotation)
+	}
+	if (spec.Exec != nil && spec.Container != nil) ||
+		(spec.Exec != nil && spec.Grpc != nil) ||
+		(spec.Container != nil && spec.Grpc != nil) {
+		return fmt.Errorf(
+			"function must have only one of exec, container and grpc")
+	}
+	if spec.Container != nil {
+		return p.configureContainer(spec.Container)
+	}
+	if spec.Grpc != nil {
+		return p.configureGrpc(spec.Grpc)
+	}
+	if spec.Exec != nil && spec.Exec.Path != "" {
+		p.path = spec.Exec.Path
+		if !filepath.IsAbs(p.path) {
+			p.path = filepath.Join(p.ldr.Root(), p.path)
+		}
+	}
+	if !p.isAvailable() {
+		return fmt.Errorf("function %s isn't an executable", p.path)
+	}
+	return nil
+}
+
+// configureContainer checks the container of the
+// function against what the build allows.
+func (p *ExecPlugin) configureContainer(c *containerSpec) error {
+	if c.Image == "" {
+		return fmt.Errorf("function container must have an image")
+	}
+	if c.Network && !p.pc.NetworkEnabled {
+		return fmt.Errorf(
+			"function %s needs the network, which requires --%s",
+			c.Image, flagNetworkName)
+	}
+	for _, m := range p.pc.Mounts {
+		if err := checkMount(m); err != nil {
+			return err
+		}
+	}
+	p.container = c
+	return nil
+}
+
+// checkMount returns an error if the mount isn't
+// like type=bind,src=/data,dst=/data.
+func checkMount(m string) error {
+	fields := make(map[string]bool)
+	for _, f := range strings.Split(m, ",") {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return fmt.Errorf(
+				"illegal mount %s; must be like type=bind,src=/data,dst=/data", m)
+		}
+		fields[kv[0]] = true
+	}
+	if !fields["type"] || !fields["src"] || !fields["dst"] {
+		return fmt.Errorf(
+			"illegal mount %s; must be like type=bind,src=/data,dst=/data", m)
+	}
+	return nil
+}
+
+// containerArgs returns the arguments of the container
+// runtime running the function's container, without
+// the network unless the function needs it.
+func (p *ExecPlugin) containerArgs() []string {
+	network := "none"
+	if p.container.Network {
+		network = "bridge"
+	}
+	args := []string{"run", "--rm", "-i", "--network", network}
+	for _, m := range p.pc.Mounts {
+		args = append(args, "--mount", m)
+	}
+	return append(args, p.container.Image)
+}
+
+// name returns the executable, the container image or
+// the server address of the function, for messages.
+func (p *ExecPlugin) name() string {
+	if p.container != nil {
+		return p.container.Image
+	}
+	if p.grpc != nil {
+		return p.grpc.Address
+	}
+	return p.path
+}
+
+// runFunction passes the resources, and the plugin
+// configuration as the function config, to the function,
+// returning the resources it emits, unless it fails.
+func (p *ExecPlugin) runFunction(
+	method string, items []*resource.Resource) ([]map[string]interface{}, error) {
+	out, err := p.callFunction(method, items)
+	if err != nil {
+		return nil, err
+	}
+	p.results = toResults(out.Results)
+	var failures []string
+	for _, r := range out.Results {
+		if r.Severity == "error" {
+			failures = append(failures, r.Message)
+		}
+	}
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("function %s failed: %s",
+			p.name(), strings.Join(failures, "; "))
+	}
+	return out.Items, nil
+}
+
+// callFunction passes the resources, and the plugin
+// configuration as the function config, to the function,
+// returning the ResourceList it emits.  The method, e.g.
+// Generate, is the RPC of a function served over gRPC.
+func (p *ExecPlugin) callFunction(
+	method string, items []*resource.Resource) (*ResourceList, error) {
+	in := ResourceList{
+		APIVersion: resourceListApiVersion,
+		Kind:       resourceListKind,
+		Items:      []map[string]interface{}{},
+	}
+	for _, r := range items {
+		in.Items = append(in.Items, r.Map())
+	}
+	if err := yaml.Unmarshal(p.cfg, &in.FunctionConfig); err != nil {
+		return nil, err
+	}
+	var out *ResourceList
+	var err error
+	if p.grpc != nil {
+		out, err = callGrpcFunction(p.grpc.Address, method, &in)
+	} else {
+		out, err = p.execFunction(&in)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failure in function %s", p.name())
+	}
+	if out.Kind != resourceListKind {
+		return nil, fmt.Errorf(
+			"function %s emitted a %s rather than a %s",
+			p.name(), out.Kind, resourceListKind)
+	}
+	return out, nil
+}
+
+// execFunction pipes the ResourceList through the
+// executable or the container of the function.
+func (p *ExecPlugin) execFunction(in *ResourceList) (*ResourceList, error) {
+	input, err := yaml.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var cmd *exec.Cmd
+	if p.container != nil {
+		cmd = exec.Command(p.pc.ContainerRuntime, p.containerArgs()...)
+		cmd.Env = p.getEnv()
+		if _, err := os.Stat(p.ldr.Root()); err == nil {
+			cmd.Dir = p.ldr.Root()
+		}
+	} else {
+		var cleanup func()
+		cmd, cleanup, err = p.command(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+	}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var out ResourceList
+	if err := yaml.Unmarshal(output, &out); err != nil {
+		return nil, errors.Wrap(err, "reading its output")
+	}
+	return &out, nil
+}
+
+// generateWithFunction returns the resources the
+// function emits given no resources.
+func (p *ExecPlugin) generateWithFunction() (resmap.ResMap, error) {
+	items, err := p.runFunction("Generate", nil)
+	if err != nil {
+		return nil, err
+	}
+	rm := resmap.New()
+	for _, item := range items {
+		if err := rm.Append(p.rf.RF().FromMap(item)); err != nil {
+			return nil, err
+		}
+	}
+	return p.updateResourceOptions(rm)
+}
+
+// validateWithFunction returns the results the
+// function reports about the resources.
+func (p *ExecPlugin) validateWithFunction(
+	rm resmap.ResMap) ([]resmap.Result, error) {
+	out, err := p.callFunction("Validate", rm.Resources())
+	if err != nil {
+		return nil, err
+	}
+	return toResults(out.Results), nil
+}
+
+// transformWithFunction replaces the resources of rm by
+// those the function emits.  Emitted resources keeping
+// the id annotation update the resources they came from,
+// others are added, and resources not emitted are removed.
+func (p *ExecPlugin) transformWithFunction(rm resmap.ResMap) error {
+	inputRM, err := p.getResMapWithIdAnnotation(rm)
+	if err != nil {
+		return err
+	}
+	items, err := p.runFunction("Transform", inputRM.Resources())
+	if err != nil {
+		return err
+	}
+	var result []*resource.Resource
+	for _, item := range items {
+		r := p.rf.RF().FromMap(item)
+		annotations := r.GetAnnotations()
+		idString, ok := annotations[idAnnotation]
+		if !ok {
+			result = append(result, r)
+			continue
+		}
+		delete(annotations, idAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		r.SetAnnotations(annotations)
+		id := resid.ResId{}
+		if err := yaml.Unmarshal([]byte(idString), &id); err != nil {
+			return err
+		}
+		res, err := rm.GetByCurrentId(id)
+		if err != nil {
+			return fmt.Errorf("unable to find unique match to %s", id.String())
+		}
+		res.Kunstructured = r.Kunstructured
+		result = append(result, res)
+	}
+	rm.Clear()
+	for _, r := range result {
+		if err := rm.Append(r); err != nil {
+			return errors.Wrapf(err, "function %s", p.name())
+		}
+	}
+	return nil
+}
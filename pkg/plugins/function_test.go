@@ -0,0 +1,26 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import "testing"
+
+func TestCheckMount(t *testing.T) {
+	for m, ok := range map[string]bool{
+		"type=bind,src=/data,dst=/data":          true,
+		"type=volume,src=cache,dst=/cache,ro=1":  true,
+		"type=bind,src=/data":                    false,
+		"src=/data,dst=/data":                    false,
+		"type=bind,src=,dst=/data":               false,
+		"/data:/data":                            false,
+		"type=bind,src=/data,dst=/data,readonly": false,
+	} {
+		err := checkMount(m)
+		if ok && err != nil {
+			t.Errorf("unexpected error for %s: %v", m, err)
+		}
+		if !ok && err == nil {
+			t.Errorf("expected an error for %s", m)
+		}
+	}
+}
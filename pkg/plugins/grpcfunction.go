@@ -0,0 +1,184 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	// grpcServiceName is the service of functions
+	// served over gRPC.
+	grpcServiceName = "kustomize.plugin.v1.Function"
+
+	// grpcCodecName is the content subtype of the
+	// JSON encoded ResourceLists of the service.
+	grpcCodecName = "json"
+)
+
+type grpcSpec struct {
+	// Address is a unix socket, like unix:///run/fn.sock,
+	// or a localhost port, like localhost:9000.
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+}
+
+// FunctionServer is a long-running function, serving
+// Generate and Transform RPCs over gRPC so builds don't
+// start a process for each use of it.
+type FunctionServer interface {
+	// Generate returns the resources generated
+	// by the function config.
+	Generate(context.Context, *ResourceList) (*ResourceList, error)
+
+	// Transform returns the items, transformed
+	// by the function config.
+	Transform(context.Context, *ResourceList) (*ResourceList, error)
+}
+
+// RegisterFunctionServer registers the function
+// with the gRPC server.
+func RegisterFunctionServer(s *grpc.Server, f FunctionServer) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: grpcServiceName,
+		HandlerType: (*FunctionServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "Generate", Handler: grpcHandler(
+				func(f FunctionServer) grpcMethod { return f.Generate })},
+			{MethodName: "Transform", Handler: grpcHandler(
+				func(f FunctionServer) grpcMethod { return f.Transform })},
+		},
+	}, f)
+}
+
+type grpcMethod func(context.Context, *ResourceList) (*ResourceList, error)
+
+// grpcHandler returns the handler of the method
+// the selector picks from the server.
+func grpcHandler(selector func(FunctionServer) grpcMethod) func(
+	interface{}, context.Context, func(interface{}) error,
+	grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context,
+		dec func(interface{}) error,
+		interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(ResourceList)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		method := selector(srv.(FunctionServer))
+		if interceptor == nil {
+			return method(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return method(ctx, req.(*ResourceList))
+			})
+	}
+}
+
+// jsonCodec encodes the messages of the service as JSON,
+// so it needs no generated protocol buffer code.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return grpcCodecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// configureGrpc checks that the server of the
+// function is local.
+func (p *ExecPlugin) configureGrpc(g *grpcSpec) error {
+	if _, _, err := grpcDialAddress(g.Address); err != nil {
+		return err
+	}
+	p.grpc = g
+	return nil
+}
+
+// grpcDialAddress returns the network and address to
+// dial for the function address, which must be local.
+func grpcDialAddress(address string) (string, string, error) {
+	if strings.HasPrefix(address, "unix://") {
+		return "unix", strings.TrimPrefix(address, "unix://"), nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err == nil {
+		switch host {
+		case "localhost", "127.0.0.1", "::1":
+			return "tcp", address, nil
+		}
+	}
+	return "", "", fmt.Errorf(
+		"function grpc address '%s' must be a unix socket, "+
+			"like unix:///run/fn.sock, or a localhost port", address)
+}
+
+// grpcConns holds a connection to each server, for
+// all the functions of the build it serves.  Like the
+// registry of Go plugins, it outlives loaders.
+var (
+	grpcConns   = make(map[string]*grpc.ClientConn)
+	grpcConnsMu sync.Mutex
+)
+
+// grpcConn returns the connection to the server.
+func grpcConn(address string) (*grpc.ClientConn, error) {
+	grpcConnsMu.Lock()
+	defer grpcConnsMu.Unlock()
+	if c, ok := grpcConns[address]; ok {
+		return c, nil
+	}
+	network, dialAddress, err := grpcDialAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	c, err := grpc.Dial("passthrough:///"+address,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(
+			func(ctx context.Context, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, dialAddress)
+			}))
+	if err != nil {
+		return nil, err
+	}
+	grpcConns[address] = c
+	return c, nil
+}
+
+// callGrpcFunction calls the method of the
+// function served at the address.
+func callGrpcFunction(
+	address, method string, in *ResourceList) (*ResourceList, error) {
+	c, err := grpcConn(address)
+	if err != nil {
+		return nil, err
+	}
+	out := new(ResourceList)
+	err = c.Invoke(context.Background(),
+		"/"+grpcServiceName+"/"+method, in, out,
+		grpc.CallContentSubtype(grpcCodecName))
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
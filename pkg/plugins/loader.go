@@ -22,6 +22,9 @@ import (
 type Loader struct {
 	pc *types.PluginConfig
 	rf *resmap.Factory
+
+	// catalog, once read, lists the plugins trusted to run.
+	catalog *catalog
 }
 
 func NewLoader(
@@ -81,6 +84,32 @@ func (l *Loader) LoadTransformer(
 	return t, nil
 }
 
+func (l *Loader) LoadValidators(
+	ldr ifc.Loader, rm resmap.ResMap) ([]resmap.Validator, error) {
+	var result []resmap.Validator
+	for _, res := range rm.Resources() {
+		v, err := l.LoadValidator(ldr, res)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+func (l *Loader) LoadValidator(
+	ldr ifc.Loader, res *resource.Resource) (resmap.Validator, error) {
+	c, err := l.loadAndConfigurePlugin(ldr, res)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := c.(resmap.Validator)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s not a validator", res.OrgId())
+	}
+	return v, nil
+}
+
 func relativePluginPath(id resid.ResId) string {
 	return filepath.Join(
 		id.Group,
@@ -111,6 +140,8 @@ func (l *Loader) loadAndConfigurePlugin(
 		// function (see "pluginator").  Being able to do this
 		// is what makes a plugin "builtin".
 		c, err = l.makeBuiltinPlugin(res.GetGvk())
+	} else if l.pc.Enabled && isFunction(res) {
+		c = NewFunctionPlugin(l.absolutePluginPath(res.OrgId()), l.pc)
 	} else if l.pc.Enabled {
 		c, err = l.loadPlugin(res.OrgId())
 	} else {
@@ -128,11 +159,104 @@ func (l *Loader) loadAndConfigurePlugin(
 		return nil, errors.Wrapf(
 			err, "plugin %s fails configuration", res.OrgId())
 	}
+	if p, ok := c.(*ExecPlugin); ok {
+		// Configuration says where a function is,
+		// so only now can it be checked.
+		if err := l.errIfUntrusted(res.OrgId(), p.digest); err != nil {
+			return nil, err
+		}
+		// Containers have sandboxes of their own.
+		if p.container == nil && p.grpc == nil {
+			p.sandbox, err = l.sandbox(res.OrgId())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 	return c, nil
 }
 
+// loadCatalog returns the plugin catalog,
+// reading it the first time.
+func (l *Loader) loadCatalog() (*catalog, error) {
+	if l.catalog == nil {
+		c, err := loadCatalog(l.pc.CatalogPath)
+		if err != nil {
+			return nil, err
+		}
+		l.catalog = c
+	}
+	return l.catalog, nil
+}
+
+// sandbox returns what the exec plugin may use if
+// the config sandboxes plugins, or nil if it doesn't.
+func (l *Loader) sandbox(id resid.ResId) (*sandboxSpec, error) {
+	if !l.pc.Sandboxed {
+		return nil, nil
+	}
+	c, err := l.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	s := sandboxSpec{Env: l.pc.SandboxEnv, Paths: l.pc.SandboxPaths}
+	if e := c.find(id.Gvk); e != nil {
+		return s.merge(e.Sandbox), nil
+	}
+	return &s, nil
+}
+
+// errIfUntrusted returns an error unless the plugin catalog
+// lists the plugin with the digest, and, if the catalog has
+// a key, a valid signature, or the config trusts all plugins.
+func (l *Loader) errIfUntrusted(
+	id resid.ResId, digest func() (string, error)) error {
+	if l.pc.UntrustedEnabled {
+		return nil
+	}
+	c, err := l.loadCatalog()
+	if err != nil {
+		return err
+	}
+	e := c.find(id.Gvk)
+	if e == nil {
+		return untrustedErr(id.Kind,
+			"isn't in the plugin catalog "+l.pc.CatalogPath)
+	}
+	d, err := digest()
+	if err != nil {
+		return errors.Wrapf(err, "plugin %s", id.Kind)
+	}
+	if d != e.Digest {
+		return untrustedErr(id.Kind, fmt.Sprintf(
+			"has digest %s rather than the %s of %s in the plugin "+
+				"catalog, so may have been tampered with",
+			d, e.Digest, e.Source))
+	}
+	if c.PublicKey == "" || d == "" {
+		return nil
+	}
+	if err := c.verify(e); err != nil {
+		return untrustedErr(id.Kind, "can't be verified: "+err.Error())
+	}
+	return nil
+}
+
+// ErrIfExternalSecretsNotEnabled returns an error if the builtin
+// reads secrets from external stores, and the config doesn't
+// allow that.
+func (l *Loader) ErrIfExternalSecretsNotEnabled(bpt BuiltinPluginType) error {
+	if bpt == VaultSecretGenerator && !l.pc.ExternalSecretsEnabled {
+		return externalSecretsNotEnabledErr(bpt.String())
+	}
+	return nil
+}
+
 func (l *Loader) makeBuiltinPlugin(r gvk.Gvk) (resmap.Configurable, error) {
 	bpt := GetBuiltinPluginType(r.Kind)
+	if err := l.ErrIfExternalSecretsNotEnabled(bpt); err != nil {
+		return nil, err
+	}
 	if f, ok := GeneratorFactories[bpt]; ok {
 		return f(), nil
 	}
@@ -164,10 +288,16 @@ var registry = make(map[string]resmap.Configurable)
 
 func (l *Loader) loadGoPlugin(id resid.ResId) (resmap.Configurable, error) {
 	regId := relativePluginPath(id)
+	absPath := l.absolutePluginPath(id)
+	err := l.errIfUntrusted(id, func() (string, error) {
+		return fileDigest(absPath + ".so")
+	})
+	if err != nil {
+		return nil, err
+	}
 	if c, ok := registry[regId]; ok {
 		return copyPlugin(c), nil
 	}
-	absPath := l.absolutePluginPath(id)
 	p, err := plugin.Open(absPath + ".so")
 	if err != nil {
 		return nil, errors.Wrapf(err, "plugin %s fails to load", absPath)
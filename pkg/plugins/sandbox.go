@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"os"
+	"strings"
+)
+
+// sandboxSpec says what a sandboxed exec plugin may use,
+// beyond the system directories, its executable, its
+// configuration and the kustomization root.
+type sandboxSpec struct {
+	// Env names the environment variables kept,
+	// besides PATH.
+	Env []string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Network is true if the plugin may use the network.
+	Network bool `json:"network,omitempty" yaml:"network,omitempty"`
+
+	// Paths are more files and directories the plugin sees.
+	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+}
+
+// sandboxDirs are the system directories
+// sandboxed plugins see, if they exist.
+var sandboxDirs = []string{
+	"/bin", "/sbin", "/usr", "/lib", "/lib32", "/lib64", "/etc", "/dev",
+}
+
+// merge returns the spec widened by the override,
+// from the plugin's entry in the plugin catalog.
+func (s sandboxSpec) merge(o *sandboxSpec) *sandboxSpec {
+	if o == nil {
+		return &s
+	}
+	return &sandboxSpec{
+		Env:     append(append([]string{}, s.Env...), o.Env...),
+		Network: s.Network || o.Network,
+		Paths:   append(append([]string{}, s.Paths...), o.Paths...),
+	}
+}
+
+// environ returns the variables of the
+// environment the plugin may have.
+func (s *sandboxSpec) environ(env []string) []string {
+	var result []string
+	for _, kv := range env {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if name == "PATH" {
+			result = append(result, kv)
+			continue
+		}
+		for _, n := range s.Env {
+			if n == name {
+				result = append(result, kv)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// visible returns the files and directories the plugin
+// sees, given those it needs, leaving out missing ones.
+func (s *sandboxSpec) visible(needed ...string) []string {
+	var result []string
+	for _, p := range append(append(sandboxDirs, s.Paths...), needed...) {
+		if _, err := os.Stat(p); err == nil {
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// sandboxInitName is the name kustomize runs itself as to
+// set up the sandbox of a plugin, before running the plugin.
+const sandboxInitName = "kustomize-plugin-sandbox"
+
+// sandboxInitConfig is passed to the sandbox init.
+type sandboxInitConfig struct {
+	// Root is an empty directory, where the
+	// init mounts the root of the sandbox.
+	Root string `json:"root"`
+
+	// Paths are the files and directories
+	// mounted into the sandbox.
+	Paths []string `json:"paths"`
+
+	// Dir is the working directory of the plugin.
+	Dir string `json:"dir,omitempty"`
+}
+
+func init() {
+	// Like the init of a container runtime, this must run
+	// before anything else, in the process the sandbox
+	// command starts.
+	if len(os.Args) < 3 || os.Args[0] != sandboxInitName {
+		return
+	}
+	err := sandboxInit(os.Args[1], os.Args[2:])
+	fmt.Fprintf(os.Stderr, "%s: %v\n", sandboxInitName, err)
+	os.Exit(1)
+}
+
+// sandboxCommand returns the command running the executable
+// with the args in new user and mount namespaces, seeing only
+// the paths, and in a new network namespace unless the spec
+// allows the network.  The returned func removes what the
+// sandbox leaves behind.
+func sandboxCommand(
+	s *sandboxSpec, dir string, paths []string,
+	name string, args ...string) (*exec.Cmd, func(), error) {
+	root, err := ioutil.TempDir("", "kustomize-sandbox-")
+	if err != nil {
+		return nil, nil, err
+	}
+	config, err := json.Marshal(
+		sandboxInitConfig{Root: root, Paths: paths, Dir: dir})
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd := &exec.Cmd{
+		Path: "/proc/self/exe",
+		Args: append([]string{sandboxInitName, string(config), name}, args...),
+	}
+	flags := syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS
+	if !s.Network {
+		flags |= syscall.CLONE_NEWNET
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: uintptr(flags),
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+	}
+	return cmd, func() { os.Remove(root) }, nil
+}
+
+// sandboxInit mounts the paths into a new root, makes it the
+// root, so nothing else can be reached, and runs the plugin.
+func sandboxInit(config string, argv []string) error {
+	var c sandboxInitConfig
+	if err := json.Unmarshal([]byte(config), &c); err != nil {
+		return err
+	}
+	err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, "")
+	if err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", c.Root, "tmpfs", 0, "mode=0755"); err != nil {
+		return err
+	}
+	for _, p := range c.Paths {
+		if err := bindIntoSandbox(c.Root, p); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(c.Root, "tmp"), 01777); err != nil {
+		return err
+	}
+	oldRoot := filepath.Join(c.Root, ".oldroot")
+	if err := os.Mkdir(oldRoot, 0700); err != nil {
+		return err
+	}
+	if err := syscall.PivotRoot(c.Root, oldRoot); err != nil {
+		return err
+	}
+	if err := os.Chdir("/"); err != nil {
+		return err
+	}
+	if err := syscall.Unmount("/.oldroot", syscall.MNT_DETACH); err != nil {
+		return err
+	}
+	if err := os.Remove("/.oldroot"); err != nil {
+		return err
+	}
+	if c.Dir != "" {
+		if err := os.Chdir(c.Dir); err != nil {
+			return err
+		}
+	}
+	return syscall.Exec(argv[0], argv, os.Environ())
+}
+
+// bindIntoSandbox mounts the file or directory
+// at the same path below the root.
+func bindIntoSandbox(root, p string) error {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(root, p)
+	if fi.IsDir() {
+		err = os.MkdirAll(target, 0755)
+	} else if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+		err = ioutil.WriteFile(target, nil, 0600)
+	}
+	if err != nil {
+		return err
+	}
+	return syscall.Mount(p, target, "", syscall.MS_BIND|syscall.MS_REC, "")
+}
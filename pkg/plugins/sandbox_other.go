@@ -0,0 +1,21 @@
+// +build !linux
+
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// sandboxCommand returns an error, as sandboxes
+// are made of Linux namespaces.
+func sandboxCommand(
+	s *sandboxSpec, dir string, paths []string,
+	name string, args ...string) (*exec.Cmd, func(), error) {
+	return nil, nil, fmt.Errorf(
+		"sandboxing plugins requires Linux, not %s", runtime.GOOS)
+}
@@ -19,6 +19,7 @@ package resmap
 import (
 	"sort"
 
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 )
 
@@ -35,3 +36,14 @@ func (a IdSlice) Less(i, j int) bool {
 	}
 	return a[i].String() < a[j].String()
 }
+
+// SortIdsIn sorts the ids as IdSlice
+// does, ranking kinds by the given order.
+func SortIdsIn(ids []resid.ResId, order gvk.KindOrder) {
+	sort.Slice(ids, func(i, j int) bool {
+		if !ids[i].Gvk.Equals(ids[j].Gvk) {
+			return ids[i].Gvk.IsLessThanIn(ids[j].Gvk, order)
+		}
+		return ids[i].String() < ids[j].String()
+	})
+}
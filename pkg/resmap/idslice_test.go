@@ -21,6 +21,7 @@ import (
 	"sort"
 	"testing"
 
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 )
 
@@ -50,3 +51,25 @@ func TestLess(t *testing.T) {
 		t.Fatalf("expected %+v but got %+v", expected, ids)
 	}
 }
+
+func TestSortIdsIn(t *testing.T) {
+	ids := []resid.ResId{
+		resid.NewResIdKindOnly("ConfigMap", "cm"),
+		resid.NewResIdKindOnly("ValidatingWebhookConfiguration", "vwc"),
+		resid.NewResIdKindOnly("Namespace", "ns"),
+		resid.NewResIdKindOnly("CustomResourceDefinition", "crd"),
+		resid.NewResIdKindOnly("Pod", "pod"),
+	}
+	expected := []resid.ResId{
+		resid.NewResIdKindOnly("CustomResourceDefinition", "crd"),
+		resid.NewResIdKindOnly("ConfigMap", "cm"),
+		resid.NewResIdKindOnly("Namespace", "ns"),
+		resid.NewResIdKindOnly("ValidatingWebhookConfiguration", "vwc"),
+		resid.NewResIdKindOnly("Pod", "pod"),
+	}
+	SortIdsIn(ids, gvk.NewKindOrder(
+		[]string{"CustomResourceDefinition"}, []string{"Pod"}))
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected %+v but got %+v", expected, ids)
+	}
+}
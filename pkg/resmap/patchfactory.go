@@ -12,4 +12,14 @@ import (
 type PatchFactory interface {
 	MergePatches(patches []*resource.Resource,
 		rf *resource.Factory) (ResMap, error)
+
+	// MergeVariants merges into dst the customizations src
+	// made to the pristine resource they share, failing
+	// if those customizations conflict with dst's.
+	MergeVariants(dst, src *resource.Resource) error
+
+	// HasConflict returns true if the customizations
+	// a and b made to the pristine resource they share
+	// conflict.
+	HasConflict(a, b *resource.Resource) (bool, error)
 }
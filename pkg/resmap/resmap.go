@@ -7,14 +7,17 @@ package resmap
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/yamlcomments"
 	"sigs.k8s.io/yaml"
 )
 
@@ -50,6 +53,62 @@ type TransformerPlugin interface {
 	Configurable
 }
 
+// A Validator checks an instance of ResMap, which it
+// mustn't modify, e.g. against an organization's policies.
+type Validator interface {
+	// Validate returns the problems found in the
+	// argument, or an error if it can't check it.
+	Validate(m ResMap) ([]Result, error)
+}
+
+type ValidatorPlugin interface {
+	Validator
+	Configurable
+}
+
+// A Reporter reports results of its last run, e.g. the
+// problems a generator or transformer plugin noticed.
+type Reporter interface {
+	Results() []Result
+}
+
+// Severity says whether a Result fails the build.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// A Result is a problem, or a note, from a plugin,
+// e.g. one found by a Validator.
+type Result struct {
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Message  string   `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// ResourceRef, if set, is the resource with the problem.
+	ResourceRef *resid.ResId `json:"resourceRef,omitempty" yaml:"resourceRef,omitempty"`
+
+	// FieldPath, if set, is the field of the
+	// resource with the problem, e.g. spec.replicas.
+	FieldPath string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
+}
+
+func (r Result) String() string {
+	var where []string
+	if r.ResourceRef != nil {
+		where = append(where, r.ResourceRef.String())
+	}
+	if r.FieldPath != "" {
+		where = append(where, r.FieldPath)
+	}
+	if len(where) == 0 {
+		return r.Message
+	}
+	return strings.Join(where, " ") + ": " + r.Message
+}
+
 // ResMap is an interface describing operations on the
 // core kustomize data structure, a list of Resources.
 //
@@ -110,6 +169,18 @@ type ResMap interface {
 	// AsYaml returns the yaml form of resources.
 	AsYaml() ([]byte, error)
 
+	// AsJson returns the resources as a JSON array.
+	AsJson() ([]byte, error)
+
+	// AsNdJson returns the resources as newline
+	// delimited JSON, a resource per line.
+	AsNdJson() ([]byte, error)
+
+	// AsList returns the yaml form of a list object,
+	// of the given apiVersion and kind, e.g. v1 and
+	// List, holding the resources as its items.
+	AsList(apiVersion, kind string) ([]byte, error)
+
 	// GetByIndex returns a resource at the given index,
 	// nil if out of range.
 	GetByIndex(int) *resource.Resource
@@ -480,6 +551,10 @@ func (m *resWrangler) AsYaml() ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
+		out, err = yamlcomments.Apply(out, res.Comments())
+		if err != nil {
+			return nil, err
+		}
 		if firstObj {
 			firstObj = false
 		} else {
@@ -494,6 +569,46 @@ func (m *resWrangler) AsYaml() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// AsJson implements ResMap.
+func (m *resWrangler) AsJson() ([]byte, error) {
+	list := make([]map[string]interface{}, 0, m.Size())
+	for _, res := range m.Resources() {
+		list = append(list, res.Map())
+	}
+	out, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// AsNdJson implements ResMap.
+func (m *resWrangler) AsNdJson() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for _, res := range m.Resources() {
+		out, err := json.Marshal(res.Map())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(out)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// AsList implements ResMap.
+func (m *resWrangler) AsList(apiVersion, kind string) ([]byte, error) {
+	items := make([]interface{}, 0, m.Size())
+	for _, res := range m.Resources() {
+		items = append(items, res.Map())
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"items":      items,
+	})
+}
+
 // ErrorIfNotEqualSets implements ResMap.
 func (m *resWrangler) ErrorIfNotEqualSets(other ResMap) error {
 	m2, ok := other.(*resWrangler)
@@ -189,6 +189,99 @@ metadata:
 	}
 }
 
+func TestEncodeAsJson(t *testing.T) {
+	input := resmaptest_test.NewRmBuilder(t, rf).Add(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "cm1",
+			},
+		}).Add(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "cm2",
+			},
+		}).ResMap()
+	out, err := input.AsJson()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded := `[
+  {
+    "apiVersion": "v1",
+    "kind": "ConfigMap",
+    "metadata": {
+      "name": "cm1"
+    }
+  },
+  {
+    "apiVersion": "v1",
+    "kind": "ConfigMap",
+    "metadata": {
+      "name": "cm2"
+    }
+  }
+]
+`
+	if string(out) != encoded {
+		t.Fatalf("%s doesn't match expected %s", out, encoded)
+	}
+	out, err = input.AsNdJson()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm1"}}
+{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm2"}}
+`
+	if string(out) != encoded {
+		t.Fatalf("%s doesn't match expected %s", out, encoded)
+	}
+	out, err = New().AsJson()
+	if err != nil || string(out) != "[]\n" {
+		t.Fatalf("expected an empty array, got %s, %v", out, err)
+	}
+}
+
+func TestEncodeAsList(t *testing.T) {
+	input := resmaptest_test.NewRmBuilder(t, rf).Add(
+		map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "cm1",
+			},
+		}).ResMap()
+	out, err := input.AsList("v1", "List")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded := `apiVersion: v1
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: cm1
+kind: List
+`
+	if string(out) != encoded {
+		t.Fatalf("%s doesn't match expected %s", out, encoded)
+	}
+	out, err = New().AsList("config.kubernetes.io/v1", "ResourceList")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded = `apiVersion: config.kubernetes.io/v1
+items: []
+kind: ResourceList
+`
+	if string(out) != encoded {
+		t.Fatalf("%s doesn't match expected %s", out, encoded)
+	}
+}
+
 func TestGetMatchingResourcesByCurrentId(t *testing.T) {
 	r1 := rf.FromMap(
 		map[string]interface{}{
@@ -9,9 +9,11 @@ import (
 	"log"
 	"strings"
 
+	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/internal/kusterr"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/yamlcomments"
 )
 
 // Factory makes instances of Resource.
@@ -143,18 +145,47 @@ func (rf *Factory) SliceFromBytes(in []byte) ([]*Resource, error) {
 			result = append(result, rf.FromKunstructured(u))
 		}
 	}
+	attachComments(in, result)
 	return result, nil
 }
 
+// attachComments gives the resources the comments of
+// their documents in the YAML.  Comments are a nicety,
+// so YAML that yaml.v3 can't read keeps none.
+func attachComments(in []byte, resources []*Resource) {
+	docs, err := yamlcomments.Extract(in)
+	if err != nil {
+		return
+	}
+	for _, d := range docs {
+		for _, r := range resources {
+			apiVersion := r.GetGvk().Version
+			if g := r.GetGvk().Group; g != "" {
+				apiVersion = g + "/" + apiVersion
+			}
+			if apiVersion == d.APIVersion && r.GetKind() == d.Kind &&
+				r.GetName() == d.Name && r.GetNamespace() == d.Namespace {
+				r.comments = d.Comments
+				break
+			}
+		}
+	}
+}
+
 // MakeConfigMap makes an instance of Resource for ConfigMap
 func (rf *Factory) MakeConfigMap(
 	ldr ifc.Loader,
 	options *types.GeneratorOptions,
 	args *types.ConfigMapArgs) (*Resource, error) {
+	if err := types.ValidateGenerationBehavior(args.Behavior); err != nil {
+		return nil, errors.Wrapf(err, "generating %s", args.Name)
+	}
+	options = types.MergeGlobalOptionsIntoLocal(args.Options, options)
 	u, err := rf.kf.MakeConfigMap(ldr, options, args)
 	if err != nil {
 		return nil, err
 	}
+	markImmutable(u, options)
 	return rf.makeOne(
 		u,
 		types.NewGenArgs(
@@ -167,13 +198,29 @@ func (rf *Factory) MakeSecret(
 	ldr ifc.Loader,
 	options *types.GeneratorOptions,
 	args *types.SecretArgs) (*Resource, error) {
+	if err := types.ValidateGenerationBehavior(args.Behavior); err != nil {
+		return nil, errors.Wrapf(err, "generating %s", args.Name)
+	}
+	options = types.MergeGlobalOptionsIntoLocal(args.Options, options)
 	u, err := rf.kf.MakeSecret(ldr, options, args)
 	if err != nil {
 		return nil, err
 	}
+	markImmutable(u, options)
 	return rf.makeOne(
 		u,
 		types.NewGenArgs(
 			&types.GeneratorArgs{Behavior: args.Behavior},
 			options)), nil
 }
+
+// markImmutable sets the immutable field of a generated
+// resource if the options ask for it.
+func markImmutable(u ifc.Kunstructured, options *types.GeneratorOptions) {
+	if options == nil || !options.Immutable {
+		return
+	}
+	m := u.Map()
+	m["immutable"] = true
+	u.SetMap(m)
+}
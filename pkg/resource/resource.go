@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/kustomize/v3/pkg/yamlcomments"
 	"sigs.k8s.io/yaml"
 )
 
@@ -23,8 +24,13 @@ type Resource struct {
 	options      *types.GenArgs
 	refBy        []resid.ResId
 	refVarNames  []string
+	exports      map[string]string
+	parameters   []Parameter
 	namePrefixes []string
 	nameSuffixes []string
+	pristine     ifc.Kunstructured
+	provenance   []string
+	comments     yamlcomments.Comments
 }
 
 // ResCtx is an interface describing the contextual added
@@ -68,8 +74,13 @@ func (r *Resource) copyOtherFields(other *Resource) {
 	r.options = other.options
 	r.refBy = other.copyRefBy()
 	r.refVarNames = copyStringSlice(other.refVarNames)
+	r.exports = copyStringMap(other.exports)
+	r.parameters = append([]Parameter(nil), other.parameters...)
 	r.namePrefixes = copyStringSlice(other.namePrefixes)
 	r.nameSuffixes = copyStringSlice(other.nameSuffixes)
+	r.pristine = other.pristine
+	r.provenance = copyStringSlice(other.provenance)
+	r.comments = other.comments
 }
 
 func (r *Resource) Equals(o *Resource) bool {
@@ -92,6 +103,63 @@ func (r *Resource) ReferencesEqual(o *Resource) bool {
 	return len(setSelf) == len(setOther)
 }
 
+// RecordPristine saves a copy of the resource's current
+// content as its pristine content, i.e. the content
+// before any customization.
+func (r *Resource) RecordPristine() {
+	r.pristine = r.Kunstructured.Copy()
+}
+
+// Pristine returns the content saved by RecordPristine,
+// or nil if none was saved.
+func (r *Resource) Pristine() ifc.Kunstructured {
+	return r.pristine
+}
+
+// SharesPristine returns true if both resources are
+// customized variants of the same pristine resource,
+// e.g. one base resource reached via two overlays.
+func (r *Resource) SharesPristine(o *Resource) bool {
+	return r.pristine != nil && o.pristine != nil &&
+		r.OrgId().Equals(o.OrgId()) &&
+		reflect.DeepEqual(r.pristine, o.pristine)
+}
+
+// Provenance returns the paths of the file the resource
+// was read from and of the patch files applied to it.
+func (r *Resource) Provenance() []string {
+	return r.provenance
+}
+
+// Comments returns the comments of the manifest the
+// resource was read from and of the patches applied to it.
+func (r *Resource) Comments() yamlcomments.Comments {
+	return r.comments
+}
+
+// MergeComments adds comments to those of the resource,
+// replacing any on the same field.
+func (r *Resource) MergeComments(c yamlcomments.Comments) {
+	r.comments = r.comments.Merge(c)
+}
+
+// AppendProvenance records paths of files that
+// contributed to the resource, ignoring duplicates.
+func (r *Resource) AppendProvenance(paths ...string) {
+	for _, p := range paths {
+		found := false
+		for _, q := range r.provenance {
+			if p == q {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.provenance = append(r.provenance, p)
+		}
+	}
+}
+
 func (r *Resource) KunstructEqual(o *Resource) bool {
 	return reflect.DeepEqual(r.Kunstructured, o.Kunstructured)
 }
@@ -120,6 +188,17 @@ func copyStringSlice(s []string) []string {
 	return c
 }
 
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
 // Implements ResCtx AddNamePrefix
 func (r *Resource) AddNamePrefix(p string) {
 	r.namePrefixes = append(r.namePrefixes, p)
@@ -232,14 +311,19 @@ func (r *Resource) String() string {
 	return strings.TrimSpace(string(bs)) + r.options.String()
 }
 
-// AsYAML returns the resource in Yaml form.
+// AsYAML returns the resource in Yaml form, with
+// the comments of its manifest and patches.
 // Easier to read than JSON.
 func (r *Resource) AsYAML() ([]byte, error) {
 	json, err := r.MarshalJSON()
 	if err != nil {
 		return nil, err
 	}
-	return yaml.JSONToYAML(json)
+	y, err := yaml.JSONToYAML(json)
+	if err != nil {
+		return nil, err
+	}
+	return yamlcomments.Apply(y, r.comments)
 }
 
 // SetOptions updates the generator options for the resource.
@@ -257,6 +341,30 @@ func (r *Resource) NeedHashSuffix() bool {
 	return r.options != nil && r.options.NeedsHashSuffix()
 }
 
+// HashAlgorithm returns the name of the hash function for the
+// resource's name suffix, or the empty string for the default.
+func (r *Resource) HashAlgorithm() string {
+	if r.options == nil {
+		return ""
+	}
+	return r.options.HashAlgorithm()
+}
+
+// HashLength returns the length of the resource's name
+// suffix hash, or zero for the default.
+func (r *Resource) HashLength() int {
+	if r.options == nil {
+		return 0
+	}
+	return r.options.HashLength()
+}
+
+// HashNamespace returns true if the resource's
+// namespace is part of its name suffix hash.
+func (r *Resource) HashNamespace() bool {
+	return r.options != nil && r.options.HashNamespace()
+}
+
 // GetNamespace returns the namespace the resource thinks it's in.
 func (r *Resource) GetNamespace() string {
 	namespace, _ := r.GetString("metadata.namespace")
@@ -300,20 +408,71 @@ func (r *Resource) AppendRefVarName(variable types.Var) {
 	r.refVarNames = append(r.refVarNames, variable.Name)
 }
 
-// TODO: Add BinaryData once we sync to new k8s.io/api
+// GetExports returns the paths of the fields of the
+// resource that are the values of exports, by name.
+func (r *Resource) GetExports() map[string]string {
+	return r.exports
+}
+
+// Export makes the field at the path the value
+// of the export with the given name.
+func (r *Resource) Export(name, fieldPath string) {
+	if r.exports == nil {
+		r.exports = make(map[string]string)
+	}
+	r.exports[name] = fieldPath
+}
+
+// Parameter is a field of the resource that an including
+// kustomization must set, with the field's value, as JSON,
+// when the parameter was declared.  The value is empty if
+// the field was missing.
+type Parameter struct {
+	Name        string
+	FieldPath   string
+	Description string
+	Declared    string
+}
+
+// GetParameters returns the parameters of the resource.
+func (r *Resource) GetParameters() []Parameter {
+	return r.parameters
+}
+
+// AddParameter adds a parameter to the resource.
+func (r *Resource) AddParameter(p Parameter) {
+	r.parameters = append(r.parameters, p)
+}
+
+// mergeConfigmap merges the data and binaryData of the
+// maps into mergedTo, later maps winning.  A key moves
+// between data and binaryData if a later map has it in
+// the other one.
 func mergeConfigmap(
 	mergedTo map[string]interface{},
 	maps ...map[string]interface{}) {
-	mergedMap := map[string]interface{}{}
+	mergedData := map[string]interface{}{}
+	mergedBinaryData := map[string]interface{}{}
 	for _, m := range maps {
-		datamap, ok := m["data"].(map[string]interface{})
-		if ok {
+		if datamap, ok := m["data"].(map[string]interface{}); ok {
+			for key, value := range datamap {
+				mergedData[key] = value
+				delete(mergedBinaryData, key)
+			}
+		}
+		if datamap, ok := m["binaryData"].(map[string]interface{}); ok {
 			for key, value := range datamap {
-				mergedMap[key] = value
+				mergedBinaryData[key] = value
+				delete(mergedData, key)
 			}
 		}
 	}
-	mergedTo["data"] = mergedMap
+	mergedTo["data"] = mergedData
+	if len(mergedBinaryData) > 0 {
+		mergedTo["binaryData"] = mergedBinaryData
+	} else {
+		delete(mergedTo, "binaryData")
+	}
 }
 
 func mergeStringMaps(maps ...map[string]string) map[string]string {
@@ -137,3 +137,41 @@ func TestDeepCopy(t *testing.T) {
 		t.Errorf("expected %v\nbut got%v", r, cr)
 	}
 }
+
+func TestMergeConfigMapBinaryData(t *testing.T) {
+	makeConfigMap := func(data, binaryData map[string]interface{}) *Resource {
+		m := map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": "winnie",
+			},
+			"data": data,
+		}
+		if binaryData != nil {
+			m["binaryData"] = binaryData
+		}
+		return factory.FromMap(m)
+	}
+	old := makeConfigMap(
+		map[string]interface{}{"a": "x", "b": "y"},
+		map[string]interface{}{"c": "AAE=", "d": "AAI="})
+	r := makeConfigMap(
+		map[string]interface{}{"b": "z", "c": "text"},
+		map[string]interface{}{"a": "AAM="})
+	r.Merge(old)
+	expected := map[string]interface{}{
+		"b": "z",
+		"c": "text",
+	}
+	if actual := r.Map()["data"]; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected data %v\nbut got %v", expected, actual)
+	}
+	expected = map[string]interface{}{
+		"a": "AAM=",
+		"d": "AAI=",
+	}
+	if actual := r.Map()["binaryData"]; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected binaryData %v\nbut got %v", expected, actual)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"os"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestBuildMetadataTimestamp(t *testing.T) {
+	old, wasSet := os.LookupEnv("SOURCE_DATE_EPOCH")
+	os.Setenv("SOURCE_DATE_EPOCH", "1571140800")
+	defer func() {
+		if wasSet {
+			os.Setenv("SOURCE_DATE_EPOCH", old)
+		} else {
+			os.Unsetenv("SOURCE_DATE_EPOCH")
+		}
+	}()
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+nameSuffix: -canary
+buildMetadata:
+  nameSuffix: timestamp
+  annotations:
+    example.com/built-at: timestamp
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    example.com/built-at: "1571140800"
+  name: web-canary-1571140800
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+`)
+}
+
+func TestBuildMetadataTimestampSharedByBases(t *testing.T) {
+	old, wasSet := os.LookupEnv("SOURCE_DATE_EPOCH")
+	os.Unsetenv("SOURCE_DATE_EPOCH")
+	defer func() {
+		if wasSet {
+			os.Setenv("SOURCE_DATE_EPOCH", old)
+		}
+	}()
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+buildMetadata:
+  annotations:
+    example.com/base-built-at: timestamp
+resources:
+- configmap.yaml
+`)
+	th.WriteF("/app/base/configmap.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`)
+	th.WriteK("/app/overlay", `
+buildMetadata:
+  annotations:
+    example.com/built-at: timestamp
+resources:
+- ../base
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	a := m.Resources()[0].GetAnnotations()
+	if a["example.com/built-at"] == "" ||
+		a["example.com/built-at"] != a["example.com/base-built-at"] {
+		t.Fatalf("expected one timestamp per build, got %v", a)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestCommentsSurvive(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+# The web frontend.
+
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1 # Scaled by the autoscaler.
+  template:
+    spec:
+      containers:
+      # Serves the site.
+      - name: web
+        image: web:1.0
+      - name: proxy
+        image: proxy:1.0
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: prod-
+resources:
+- ../base
+patchesStrategicMerge:
+- patch.yaml
+`)
+	th.WriteF("/app/overlay/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: proxy
+        # Pinned until the TLS fix ships.
+        image: proxy:0.9
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+# The web frontend.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-web
+spec:
+  replicas: 1 # Scaled by the autoscaler.
+  template:
+    spec:
+      containers:
+      # Serves the site.
+      - image: web:1.0
+        name: web
+      # Pinned until the TLS fix ships.
+      - image: proxy:0.9
+        name: proxy
+`)
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestCommonAnnotationsSelected(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+commonAnnotations:
+  pairs:
+    vault.hashicorp.com/agent-inject: "true"
+  include:
+  - kind: Deployment
+  - kind: StatefulSet
+  exclude:
+  - name: migrations
+`)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: migrations
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    vault.hashicorp.com/agent-inject: "true"
+  name: web
+spec:
+  template:
+    metadata:
+      annotations:
+        vault.hashicorp.com/agent-inject: "true"
+    spec:
+      containers:
+      - image: web
+        name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: migrations
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  annotations:
+    vault.hashicorp.com/agent-inject: "true"
+  name: db
+spec:
+  template:
+    metadata:
+      annotations:
+        vault.hashicorp.com/agent-inject: "true"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+}
@@ -140,6 +140,23 @@ patchesJson6902:
 `)
 }
 
+func writeOtherOverlay(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/other", `
+resources:
+- ../base
+patchesStrategicMerge:
+- sts-patch.yaml
+`)
+	th.WriteF("/app/other/sts-patch.yaml", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  serviceName: my-other-svc
+`)
+}
+
 func writePatchConfig(th *kusttest_test.KustTestHarness) {
 	writeStorageOverlay(th)
 	writeConfigOverlay(th)
@@ -307,3 +324,274 @@ spec:
       storageClassName: default
   `
 }
+
+func TestComplexComposition_Dev_Merge(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/dev")
+	writeStatefulSetBase(th)
+	writePatchConfig(th)
+	th.WriteK("/app/dev", `
+mergeStrategy: merge
+resources:
+- ../storage
+- ../config
+`)
+
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  serviceName: my-svc
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: my-config
+        image: my-image
+        name: app
+  volumeClaimTemplates:
+  - spec:
+      storageClassName: my-sc
+---
+apiVersion: v1
+data:
+  MY_ENV: foo
+kind: ConfigMap
+metadata:
+  name: my-config
+`)
+}
+
+func TestComplexComposition_Prod_Merge(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeStatefulSetBase(th)
+	writePatchConfig(th)
+	th.WriteK("/app/prod", `
+mergeStrategy: merge
+resources:
+- ../config
+- ../tolerations
+- ../https
+`)
+
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  serviceName: my-https-svc
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: my-config
+        image: my-image
+        name: app
+      tolerations:
+      - effect: NoExecute
+        key: node.kubernetes.io/not-ready
+        tolerationSeconds: 30
+  volumeClaimTemplates:
+  - spec:
+      storageClassName: default
+---
+apiVersion: v1
+data:
+  MY_ENV: foo
+kind: ConfigMap
+metadata:
+  name: my-config
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-https-svc
+spec:
+  ports:
+  - name: https
+    port: 443
+    protocol: TCP
+  selector:
+    app: my-app
+`)
+}
+
+func TestComplexComposition_Merge_Conflict(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeStatefulSetBase(th)
+	writePatchConfig(th)
+	writeOtherOverlay(th)
+	th.WriteK("/app/prod", `
+mergeStrategy: merge
+resources:
+- ../https
+- ../other
+`)
+
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("Expected merge conflict error")
+	}
+	if !strings.Contains(
+		err.Error(), "unable to merge variants of apps_v1_StatefulSet|~X|my-sts") {
+		t.Fatalf("Unexpected err: %v", err)
+	}
+	if !strings.Contains(err.Error(), `
+  accumulated resource from: /app/base/statefulset.yaml, /app/https/sts-patch.yaml
+  conflicting resource from: /app/base/statefulset.yaml, /app/other/sts-patch.yaml`) {
+		t.Fatalf("Expected contributing paths in err: %v", err)
+	}
+}
+
+func TestComplexComposition_PerEntry_Merge(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/dev")
+	writeStatefulSetBase(th)
+	writePatchConfig(th)
+	th.WriteK("/app/dev", `
+resources:
+- ../storage
+- path: ../config
+  mergeStrategy: merge
+`)
+
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  serviceName: my-svc
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: my-config
+        image: my-image
+        name: app
+  volumeClaimTemplates:
+  - spec:
+      storageClassName: my-sc
+---
+apiVersion: v1
+data:
+  MY_ENV: foo
+kind: ConfigMap
+metadata:
+  name: my-config
+`)
+}
+
+func TestComplexComposition_PerEntry_Error(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/dev")
+	writeStatefulSetBase(th)
+	writePatchConfig(th)
+	th.WriteK("/app/dev", `
+mergeStrategy: merge
+resources:
+- ../storage
+- path: ../config
+  mergeStrategy: error
+`)
+
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("Expected resource accumulation error")
+	}
+	if !strings.Contains(
+		err.Error(), "may not add resource with an already registered id") {
+		t.Fatalf("Unexpected err: %v", err)
+	}
+	if !strings.Contains(err.Error(), `
+  accumulated resource from: /app/base/statefulset.yaml, /app/storage/sts-patch.json
+  conflicting resource from: /app/base/statefulset.yaml, /app/config/sts-patch.yaml`) {
+		t.Fatalf("Expected contributing paths in err: %v", err)
+	}
+}
+
+func TestComplexComposition_PerEntry_Replace(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeStatefulSetBase(th)
+	writePatchConfig(th)
+	writeOtherOverlay(th)
+	th.WriteK("/app/prod", `
+mergeStrategy: merge
+resources:
+- ../https
+- path: ../other
+  mergeStrategy: replace
+`)
+
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  serviceName: my-other-svc
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+      - image: my-image
+        name: app
+  volumeClaimTemplates:
+  - spec:
+      storageClassName: default
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-https-svc
+spec:
+  ports:
+  - name: https
+    port: 443
+    protocol: TCP
+  selector:
+    app: my-app
+`)
+}
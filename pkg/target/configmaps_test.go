@@ -311,3 +311,37 @@ metadata:
   name: cm-o2-gfcc59fg5m
 `)
 }
+
+func TestConfigMapGeneratorGlobWithBinaryData(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+generatorOptions:
+  disableNameSuffixHash: true
+configMapGenerator:
+- name: configs
+  files:
+  - configs/*.conf
+  - logo.png
+`)
+	th.WriteF("/app/configs/a.conf", "a=1\n")
+	th.WriteF("/app/configs/b.conf", "b=2\n")
+	th.WriteF("/app/configs/c.yaml", "c: 3\n")
+	th.WriteF("/app/logo.png", "\x89PNG\r\n\x1a\n\xff")
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+binaryData:
+  logo.png: iVBORw0KGgr/
+data:
+  a.conf: |
+    a=1
+  b.conf: |
+    b=2
+kind: ConfigMap
+metadata:
+  name: configs
+`)
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+// pickServiceName resolves conflicts by keeping the
+// resource whose serviceName is wanted, recording
+// the conflicts it sees.
+type pickServiceName struct {
+	wanted    string
+	conflicts []string
+}
+
+func (p *pickServiceName) Resolve(
+	c target.Conflict) (*resource.Resource, error) {
+	p.conflicts = append(p.conflicts, fmt.Sprintf(
+		"%s %s %s", c.Accumulated.CurId(), c.AccumulatedPath, c.IncomingPath))
+	for _, r := range []*resource.Resource{c.Accumulated, c.Incoming} {
+		n, err := r.GetString("spec.serviceName")
+		if err != nil {
+			return nil, err
+		}
+		if n == p.wanted {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s in %s", p.wanted, c.Root)
+}
+
+func TestConflictResolver(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeStatefulSetBase(th)
+	writeHTTPSOverlay(th)
+	writeOtherOverlay(th)
+	th.WriteK("/app/prod", `
+resources:
+- ../https
+- ../other
+`)
+
+	kt := th.MakeKustTarget()
+	r := &pickServiceName{wanted: "my-other-svc"}
+	kt.SetConflictResolver(r)
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	expected := "apps_v1_StatefulSet|~X|my-sts ../https ../other"
+	if len(r.conflicts) != 1 || r.conflicts[0] != expected {
+		t.Fatalf("expected conflicts [%s], got %v", expected, r.conflicts)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  serviceName: my-other-svc
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+      - image: my-image
+        name: app
+  volumeClaimTemplates:
+  - spec:
+      storageClassName: default
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-https-svc
+spec:
+  ports:
+  - name: https
+    port: 443
+    protocol: TCP
+  selector:
+    app: my-app
+`)
+}
+
+func TestConflictResolver_Error(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeStatefulSetBase(th)
+	writeHTTPSOverlay(th)
+	writeOtherOverlay(th)
+	th.WriteK("/app/prod", `
+resources:
+- ../https
+- ../other
+`)
+
+	kt := th.MakeKustTarget()
+	kt.SetConflictResolver(&pickServiceName{wanted: "my-svc"})
+	_, err := kt.MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("Expected resolver error")
+	}
+	if !strings.Contains(err.Error(), "no my-svc in /app/prod") {
+		t.Fatalf("Unexpected err: %v", err)
+	}
+}
+
+func TestConflictResolver_MergeStrategyWins(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeStatefulSetBase(th)
+	writeHTTPSOverlay(th)
+	writeOtherOverlay(th)
+	th.WriteK("/app/prod", `
+resources:
+- ../https
+- path: ../other
+  mergeStrategy: error
+`)
+
+	kt := th.MakeKustTarget()
+	r := &pickServiceName{wanted: "my-other-svc"}
+	kt.SetConflictResolver(r)
+	_, err := kt.MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("Expected resource accumulation error")
+	}
+	if !strings.Contains(
+		err.Error(), "may not add resource with an already registered id") {
+		t.Fatalf("Unexpected err: %v", err)
+	}
+	if len(r.conflicts) != 0 {
+		t.Fatalf("expected resolver not to be called, got %v", r.conflicts)
+	}
+}
+
+func TestAllowIdConflictsLastWins(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeStatefulSetBase(th)
+	writeHTTPSOverlay(th)
+	writeOtherOverlay(th)
+	th.WriteK("/app/prod", `
+allowIdConflicts: last-wins
+resources:
+- ../https
+- ../other
+`)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	expected := "warning: in /app/prod, apps_v1_StatefulSet|~X|my-sts " +
+		"from '../other' replaces the one from '../https'"
+	if !strings.Contains(buf.String(), expected) {
+		t.Fatalf("expected log containing '%s', got '%s'", expected, buf.String())
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  selector:
+    matchLabels:
+      app: my-app
+  serviceName: my-other-svc
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+      - image: my-image
+        name: app
+  volumeClaimTemplates:
+  - spec:
+      storageClassName: default
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-https-svc
+spec:
+  ports:
+  - name: https
+    port: 443
+    protocol: TCP
+  selector:
+    app: my-app
+`)
+}
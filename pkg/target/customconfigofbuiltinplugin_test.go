@@ -215,3 +215,76 @@ metadata:
   name: zzz-myService
 `)
 }
+
+// Demo custom configuration given inline, in place
+// of the path of a file holding it.
+func TestInlineCustomNamePrefixer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PrefixSuffixTransformer")
+	tc.BuildGoPlugin(
+		"builtin", "", "LabelTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+- service.yaml
+transformers:
+- apiVersion: builtin
+  kind: PrefixSuffixTransformer
+  metadata:
+    name: inlinePrefixer
+  prefix: zzz-
+  fieldSpecs:
+  - kind: Deployment
+    path: metadata/name
+- labeller.yaml
+`)
+	th.WriteF("/app/labeller.yaml", `
+apiVersion: builtin
+kind: LabelTransformer
+metadata:
+  name: myLabeller
+labels:
+  company: acmeCorp
+fieldSpecs:
+- path: metadata/labels
+  create: true
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeployment
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`)
+
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    company: acmeCorp
+  name: zzz-myDeployment
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    company: acmeCorp
+  name: myService
+`)
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestDefaultSecurityContext(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	th.WriteK("/app/base", `
+resources:
+- cronjob.yaml
+`)
+	th.WriteF("/app/base/cronjob.yaml", `
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            image: backup
+            securityContext:
+              runAsUser: 1000
+`)
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+defaultSecurityContext:
+- pod:
+    runAsNonRoot: true
+  container:
+    runAsUser: 65534
+    readOnlyRootFilesystem: true
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - image: backup
+            name: backup
+            securityContext:
+              readOnlyRootFilesystem: true
+              runAsUser: 1000
+          securityContext:
+            runAsNonRoot: true
+`)
+}
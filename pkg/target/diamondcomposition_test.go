@@ -144,6 +144,11 @@ resources:
 		err.Error(), "already registered id: apps_v1_Deployment|~X|my-deployment") {
 		t.Fatalf("Unexpected err: %v", err)
 	}
+	if !strings.Contains(err.Error(), `
+  accumulated resource from: /app/base/deployment.yaml, /app/probe/dep-patch.yaml
+  conflicting resource from: /app/base/deployment.yaml, /app/dns/dep-patch.yaml`) {
+		t.Fatalf("Expected contributing paths in err: %v", err)
+	}
 }
 
 const expectedPatchedDeployment = `
@@ -165,6 +170,91 @@ spec:
       restartPolicy: Always
 `
 
+func TestIssue1251_CompositeDiamond_Merge(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/composite")
+	writeDeploymentBase(th)
+	writeProbeOverlay(th)
+	writeDNSOverlay(th)
+	writeRestartOverlay(th)
+
+	th.WriteK("/app/composite", `
+mergeStrategy: merge
+resources:
+- ../probe
+- ../dns
+- ../restart
+`)
+
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, expectedPatchedDeployment)
+}
+
+// Overlays that leave the base resource untouched yield
+// identical copies of it, which are silently deduplicated.
+func TestIssue1251_CompositeDiamond_Identical(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/composite")
+	writeDeploymentBase(th)
+	th.WriteK("/app/left", `
+resources:
+- ../base
+- left.yaml
+`)
+	th.WriteF("/app/left/left.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: left
+`)
+	th.WriteK("/app/right", `
+resources:
+- ../base
+- right.yaml
+`)
+	th.WriteF("/app/right/right.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: right
+`)
+
+	th.WriteK("/app/composite", `
+resources:
+- ../left
+- ../right
+`)
+
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-deployment
+spec:
+  template:
+    spec:
+      containers:
+      - image: my-image
+        name: my-deployment
+      dnsPolicy: None
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: left
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: right
+`)
+}
+
 // This test reuses some methods from TestIssue1251_CompositeDiamond,
 // but overwrites the kustomization files in the overlays.
 func TestIssue1251_Patches_Overlayed(t *testing.T) {
@@ -290,7 +380,7 @@ spec:
 // This test does what
 //    TestIssue1251_Patches_ProdVsDev_Failure
 // failed to do, because this test does the equivalent
-// os specifying `--load_restrictor none` on the build.
+// os specifying `--load-restrictor LoadRestrictionsNone` on the build.
 //
 // This allows the use patch files located outside the
 // kustomization root, and not in a kustomization
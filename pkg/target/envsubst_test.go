@@ -0,0 +1,74 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeEnvSubst(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- ingress.yaml
+`)
+	th.WriteF("/app/base/ingress.yaml", `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - host: web.${KUSTOMIZE_TEST_DOMAIN}
+`)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../base
+envSubst:
+  vars:
+  - KUSTOMIZE_TEST_DOMAIN
+  targets:
+  - select:
+      kind: Ingress
+    fieldPaths:
+    - spec.rules.host
+`)
+}
+
+func TestEnvSubst(t *testing.T) {
+	os.Setenv("KUSTOMIZE_TEST_DOMAIN", "prod.example.com")
+	defer os.Unsetenv("KUSTOMIZE_TEST_DOMAIN")
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeEnvSubst(th)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: prod-web
+spec:
+  rules:
+  - host: web.prod.example.com
+`)
+}
+
+func TestEnvSubstUnset(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeEnvSubst(th)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"environment variable KUSTOMIZE_TEST_DOMAIN of envSubst isn't set") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestExcludeResources(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+resources:
+- job.yaml
+- cm.yaml
+`)
+	th.WriteF("/app/base/job.yaml", `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrations
+`)
+	th.WriteF("/app/base/cm.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: base
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+- manifests/*.yaml
+excludeResources:
+- manifests/debug-*.yaml
+- kind: Job
+  name: migrations
+`)
+	for _, name := range []string{"app", "debug-a", "debug-b"} {
+		th.WriteF("/app/overlay/manifests/"+name+".yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: `+name+`
+`)
+	}
+	th.WriteF("/app/overlay/manifests/debug-c.yaml", "not even yaml: [")
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: base
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+`)
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeExportingBases(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/web", `
+namePrefix: web-
+resources:
+- service.yaml
+exports:
+- name: https-service
+  select:
+    kind: Service
+    name: https
+`)
+	th.WriteF("/app/web/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: https
+spec:
+  ports:
+  - port: 443
+`)
+	th.WriteK("/app/gateway", `
+resources:
+- ingress.yaml
+`)
+	th.WriteF("/app/gateway/ingress.yaml", `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: gateway
+spec:
+  defaultBackend:
+    service:
+      name: unset
+`)
+}
+
+func TestExportsBetweenSiblings(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeExportingBases(th)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../web
+- ../gateway
+replacements:
+- source:
+    export: https-service
+  targets:
+  - select:
+      kind: Ingress
+    fieldPaths:
+    - spec.defaultBackend.service.name
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: prod-web-https
+spec:
+  ports:
+  - port: 443
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: prod-gateway
+spec:
+  defaultBackend:
+    service:
+      name: prod-web-https
+`)
+}
+
+func TestExportedByTwoResources(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeExportingBases(th)
+	th.WriteK("/app/prod", `
+resources:
+- path: ../web
+  namePrefix: a-
+- path: ../web
+  namePrefix: b-
+- ../gateway
+replacements:
+- source:
+    export: https-service
+  targets:
+  - select:
+      kind: Ingress
+    fieldPaths:
+    - spec.defaultBackend.service.name
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"replacement source export https-service must be exported "+
+			"by one resource, exported by 2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExportSelectingNothing(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/web")
+	writeExportingBases(th)
+	th.WriteF("/app/web/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: http
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"export https-service must select one resource, selected 0") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -1246,3 +1246,197 @@ spec:
     app: busybox
 `)
 }
+
+func TestExtendedPatchAnnotationLabelSelector(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+patches:
+- path: patch.yaml
+  target:
+    kind: Deployment
+    labelSelector: tier=web
+    annotationSelector: zone=west
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-west
+  labels:
+    tier: web
+  annotations:
+    zone: west
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-east
+  labels:
+    tier: web
+  annotations:
+    zone: east
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db-west
+  labels:
+    tier: db
+  annotations:
+    zone: west
+`)
+	th.WriteF("/app/base/patch.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: any
+spec:
+  replicas: 3
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    zone: west
+  labels:
+    tier: web
+  name: web-west
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    zone: east
+  labels:
+    tier: web
+  name: web-east
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    zone: west
+  labels:
+    tier: db
+  name: db-west
+`)
+}
+
+func TestExtendedPatchExclude(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	makeCommonFileForExtendedPatchTest(th)
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+- service.yaml
+patches:
+- path: patch.yaml
+  target:
+    name: .*
+  exclude:
+  - kind: Service
+    name: busybox
+  - kind: Deployment
+    labelSelector: app=nginx
+`)
+	th.WriteF("/app/base/patch.yaml", `
+apiVersion: v1
+kind: Any
+metadata:
+  name: any
+  annotations:
+    new-key: new-value
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1beta2
+kind: Deployment
+metadata:
+  labels:
+    app: nginx
+  name: nginx
+spec:
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+        volumeMounts:
+        - mountPath: /tmp/ps
+          name: nginx-persistent-storage
+      volumes:
+      - emptyDir: {}
+        name: nginx-persistent-storage
+      - configMap:
+          name: configmap-in-base
+        name: configmap-in-base
+---
+apiVersion: apps/v1beta2
+kind: Deployment
+metadata:
+  annotations:
+    new-key: new-value
+  labels:
+    app: busybox
+  name: busybox
+spec:
+  template:
+    metadata:
+      labels:
+        app: busybox
+    spec:
+      containers:
+      - image: busybox
+        name: busybox
+        volumeMounts:
+        - mountPath: /tmp/ps
+          name: busybox-persistent-storage
+      volumes:
+      - emptyDir: {}
+        name: busybox-persistent-storage
+      - configMap:
+          name: configmap-in-base
+        name: configmap-in-base
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    new-key: new-value
+  labels:
+    app: nginx
+  name: nginx
+spec:
+  ports:
+  - port: 80
+  selector:
+    app: nginx
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: busybox
+  name: busybox
+spec:
+  ports:
+  - port: 8080
+  selector:
+    app: busybox
+`)
+}
@@ -0,0 +1,382 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+)
+
+// scaleFunction is a KRM function setting the replicas of
+// the deployments to those of its function config, and
+// adding a ConfigMap recording them.
+const scaleFunction = `#!/bin/sh
+input=$(cat)
+replicas=$(echo "$input" | sed -n 's/^  replicas: //p')
+echo "$input" | sed \
+  -e "s/^    replicas: .*/    replicas: $replicas/" \
+  -e "s/^items:$/items:\n- apiVersion: v1\n  kind: ConfigMap\n  metadata:\n    name: scaled\n  data:\n    replicas: \"$replicas\"/"
+`
+
+// configMapFunction is a KRM function generating a ConfigMap.
+const configMapFunction = `#!/bin/sh
+echo 'apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: generated
+  data:
+    from: function'
+`
+
+// failingFunction is a KRM function reporting an error.
+const failingFunction = `#!/bin/sh
+echo 'apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+results:
+- message: replicas must be set
+  severity: error'
+`
+
+func writeFunction(t *testing.T, dir, name, script string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(script), 0700)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	return path
+}
+
+func TestFunctionPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-functions-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	scale := writeFunction(t, dir, "scale", scaleFunction)
+	configMap := writeFunction(t, dir, "configmap", configMapFunction)
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+generators:
+- configmap.yaml
+transformers:
+- scale.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/configmap.yaml", `
+apiVersion: example.com/v1
+kind: ConfigMapFunction
+metadata:
+  name: generated
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: `+configMap+`
+`)
+	th.WriteF("/app/scale.yaml", `
+apiVersion: example.com/v1
+kind: Scale
+metadata:
+  name: scale
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: `+scale+`
+replicas: 3
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  replicas: "3"
+kind: ConfigMap
+metadata:
+  name: scaled
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+---
+apiVersion: v1
+data:
+  from: function
+kind: ConfigMap
+metadata:
+  name: generated
+`)
+}
+
+func TestFunctionPluginResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-functions-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	failing := writeFunction(t, dir, "failing", failingFunction)
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.WriteK("/app", `
+transformers:
+- failing.yaml
+`)
+	th.WriteF("/app/failing.yaml", `
+apiVersion: example.com/v1
+kind: Failing
+metadata:
+  name: failing
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: `+failing+`
+`)
+	_, err = th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"function "+failing+" failed: replicas must be set") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// fakeContainerRuntime records its arguments, and
+// runs a container of the identity function.
+const fakeContainerRuntime = `#!/bin/sh
+echo "$@" > $(dirname $0)/args
+cat
+`
+
+func writeContainerFunction(th *kusttest_test.KustTestHarness, network bool) {
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+transformers:
+- set-team.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	n := "false"
+	if network {
+		n = "true"
+	}
+	th.WriteF("/app/set-team.yaml", `
+apiVersion: example.com/v1
+kind: SetTeam
+metadata:
+  name: set-team
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: example.com/set-team:v1
+        network: `+n+`
+`)
+}
+
+func TestContainerFunctionPlugin(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-functions-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	pc := plugins.ActivePluginConfig()
+	pc.ContainerRuntime = writeFunction(
+		t, dir, "runtime", fakeContainerRuntime)
+	pc.Mounts = []string{"type=bind,src=/data,dst=/data"}
+
+	th := kusttest_test.NewKustTestHarnessFull(
+		t, "/app", loader.RestrictionRootOnly, pc)
+	writeContainerFunction(th, false)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	args, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	expected := "run --rm -i --network none " +
+		"--mount type=bind,src=/data,dst=/data example.com/set-team:v1\n"
+	if string(args) != expected {
+		t.Fatalf("expected args '%s', got '%s'", expected, string(args))
+	}
+}
+
+func TestContainerFunctionPluginNetwork(t *testing.T) {
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	writeContainerFunction(th, true)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"function example.com/set-team:v1 needs the network, "+
+			"which requires --network") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// scaleServer is a function served over gRPC, generating a
+// ConfigMap, and setting the replicas of the deployments to
+// those of its function config.
+type scaleServer struct{}
+
+func (scaleServer) Generate(
+	_ context.Context, in *plugins.ResourceList) (*plugins.ResourceList, error) {
+	in.Items = []map[string]interface{}{{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "served"},
+	}}
+	return in, nil
+}
+
+func (scaleServer) Transform(
+	_ context.Context, in *plugins.ResourceList) (*plugins.ResourceList, error) {
+	for _, item := range in.Items {
+		if item["kind"] == "Deployment" {
+			item["spec"] = map[string]interface{}{
+				"replicas": in.FunctionConfig["replicas"],
+			}
+		}
+	}
+	return in, nil
+}
+
+func TestGrpcFunctionPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-functions-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "scale.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	s := grpc.NewServer()
+	plugins.RegisterFunctionServer(s, scaleServer{})
+	go s.Serve(l)
+	defer s.Stop()
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+generators:
+- served.yaml
+transformers:
+- scale.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/served.yaml", `
+apiVersion: example.com/v1
+kind: Served
+metadata:
+  name: served
+  annotations:
+    config.kubernetes.io/function: |
+      grpc:
+        address: unix://`+socket+`
+`)
+	th.WriteF("/app/scale.yaml", `
+apiVersion: example.com/v1
+kind: Scale
+metadata:
+  name: scale
+  annotations:
+    config.kubernetes.io/function: |
+      grpc:
+        address: unix://`+socket+`
+replicas: 3
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: served
+`)
+}
+
+func TestGrpcFunctionPluginAddress(t *testing.T) {
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.WriteK("/app", `
+transformers:
+- scale.yaml
+`)
+	th.WriteF("/app/scale.yaml", `
+apiVersion: example.com/v1
+kind: Scale
+metadata:
+  name: scale
+  annotations:
+    config.kubernetes.io/function: |
+      grpc:
+        address: example.com:9000
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"function grpc address 'example.com:9000' must be a unix socket") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
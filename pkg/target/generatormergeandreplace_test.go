@@ -595,3 +595,64 @@ secretGenerator:
 		t.Fatalf("unexpected error %v", err)
 	}
 }
+
+func TestMergeGeneratorWithBinaryData(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/overlay")
+	th.WriteK("/base", `
+configMapGenerator:
+- name: my-config
+  literals:
+  - color=blue
+  - shape=square
+  files:
+  - logo.bin
+`)
+	th.WriteF("/base/logo.bin", "\xff\xfe")
+	th.WriteK("/overlay", `
+resources:
+- ../base
+configMapGenerator:
+- name: my-config
+  behavior: merge
+  literals:
+  - color=red
+  - size=large
+  - logo.bin=text
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  color: red
+  logo.bin: text
+  shape: square
+  size: large
+kind: ConfigMap
+metadata:
+  annotations: {}
+  labels: {}
+  name: my-config-fcmf9ddmdk
+`)
+}
+
+func TestGeneratorUnknownBehavior(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+configMapGenerator:
+- name: my-config
+  behavior: mrege
+  literals:
+  - color=red
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(),
+		`generating my-config: unknown behavior "mrege"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package target_test
 
 import (
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
@@ -113,3 +114,190 @@ metadata:
   name: shouldHaveHash-2k9hc848ff
 `)
 }
+
+func TestGeneratorOptionsImmutable(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+generatorOptions:
+  immutable: true
+  labels:
+    foo: bar
+configMapGenerator:
+- name: immutableMap
+  literals:
+  - fruit=apple
+- name: labeledMap
+  literals:
+  - fruit=apple
+  options:
+    labels:
+      foo: baz
+      color: red
+secretGenerator:
+- name: immutableSecret
+  literals:
+  - password=secret
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  fruit: apple
+immutable: true
+kind: ConfigMap
+metadata:
+  labels:
+    foo: bar
+  name: immutableMap-5m7gccmc2f
+---
+apiVersion: v1
+data:
+  fruit: apple
+immutable: true
+kind: ConfigMap
+metadata:
+  labels:
+    color: red
+    foo: baz
+  name: labeledMap-h4hg249dgg
+---
+apiVersion: v1
+data:
+  password: c2VjcmV0
+immutable: true
+kind: Secret
+metadata:
+  labels:
+    foo: bar
+  name: immutableSecret-mhg4h6cm69
+type: Opaque
+`)
+}
+
+func TestGeneratorOptionsPerGenerator(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+configMapGenerator:
+- name: immutableMap
+  literals:
+  - fruit=apple
+  options:
+    immutable: true
+    disableNameSuffixHash: true
+- name: mutableMap
+  literals:
+  - fruit=apple
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  fruit: apple
+immutable: true
+kind: ConfigMap
+metadata:
+  name: immutableMap
+---
+apiVersion: v1
+data:
+  fruit: apple
+kind: ConfigMap
+metadata:
+  name: mutableMap-5tkc9mm594
+`)
+}
+
+func TestGeneratorOptionsHash(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+generatorOptions:
+  hashAlgorithm: sha512
+  hashLength: 6
+configMapGenerator:
+- name: shortHash
+  literals:
+  - fruit=apple
+- name: longHash
+  literals:
+  - fruit=apple
+  options:
+    hashAlgorithm: sha256
+    hashLength: 16
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  fruit: apple
+kind: ConfigMap
+metadata:
+  name: shortHash-8m2h74
+---
+apiVersion: v1
+data:
+  fruit: apple
+kind: ConfigMap
+metadata:
+  name: longHash-kb676bh757mh6989
+`)
+}
+
+func TestGeneratorOptionsBadHashAlgorithm(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+generatorOptions:
+  hashAlgorithm: md5
+configMapGenerator:
+- name: cm
+  literals:
+  - fruit=apple
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), `unknown hash algorithm "md5"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGeneratorOptionsHashNamespace(t *testing.T) {
+	for ns, name := range map[string]string{
+		"":        "app-config-dd56746m6c",
+		"staging": "app-config-4f889td267",
+		"prod":    "app-config-hhcbd5c4md",
+	} {
+		th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+		th.WriteK("/app/base", `
+generatorOptions:
+  hashNamespace: true
+configMapGenerator:
+- name: app-config
+  literals:
+  - fruit=apple
+`)
+		th.WriteK("/app/overlay", `
+namespace: `+ns+`
+resources:
+- ../base
+`)
+		m, err := th.MakeKustTarget().MakeCustomizedResMap()
+		if err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		res := m.Resources()[0]
+		if res.GetName() != name || res.GetNamespace() != ns {
+			t.Errorf("namespace %q: expected name %s, got %s in %q",
+				ns, name, res.GetName(), res.GetNamespace())
+		}
+	}
+}
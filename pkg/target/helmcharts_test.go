@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+// A stand-in for helm, rendering a Deployment
+// named after the release and chart.
+const fakeHelmRenderingDeployment = `#!/bin/sh
+[ "$1" = pull ] && exit 0
+chart=$(basename $3)
+cat <<EOF
+---
+# Source: $chart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: $2-$chart
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: server
+        image: $chart:1.0
+EOF
+`
+
+func TestHelmChartsPatched(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fake-helm-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "helm")
+	err = ioutil.WriteFile(bin, []byte(fakeHelmRenderingDeployment), 0700)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.EnableHelm(bin)
+	th.WriteK("/app", `
+namePrefix: prod-
+helmCharts:
+- name: minecraft
+  repo: https://example.com/charts
+  releaseName: moria
+patchesStrategicMerge:
+- replicas.yaml
+`)
+	th.WriteF("/app/replicas.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: moria-minecraft
+spec:
+  replicas: 3
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+# Source: minecraft/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-moria-minecraft
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - image: minecraft:1.0
+        name: server
+`)
+}
+
+func TestHelmChartsNotEnabled(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+helmCharts:
+- name: minecraft
+  repo: https://example.com/charts
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "--enable_helm") {
+		t.Fatalf("expected error naming the flag, got %v", err)
+	}
+}
+
+func TestHelmChartsOutOfRoot(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name: "absolute chart home",
+			content: `
+helmGlobals:
+  chartHome: /etc/charts
+helmCharts:
+- name: minecraft
+`,
+			err: "must be relative",
+		},
+		{
+			name: "chart home above root",
+			content: `
+helmGlobals:
+  chartHome: ../charts
+helmCharts:
+- name: minecraft
+`,
+			err: "outside the kustomization root",
+		},
+		{
+			name: "chart name with separator",
+			content: `
+helmCharts:
+- name: ../../minecraft
+`,
+			err: "must not be a path",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			th := kusttest_test.NewKustTestHarness(t, "/app")
+			th.EnableHelm("helm")
+			th.WriteK("/app", tc.content)
+			_, err := th.MakeKustTarget().MakeCustomizedResMap()
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
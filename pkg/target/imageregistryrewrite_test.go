@@ -0,0 +1,63 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestImageRegistryRewriteAfterImages(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/airgapped")
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+images:
+- name: nginx
+  newTag: 1.17.4
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+      - name: exporter
+        image: quay.io/prometheus/nginx-exporter:v0.4
+`)
+	th.WriteK("/app/airgapped", `
+resources:
+- ../base
+images:
+- name: quay.io/prometheus/nginx-exporter
+  newName: docker.io/prometheus/nginx-exporter
+imageRegistryRewrite:
+- from: docker.io
+  to: internal-mirror.example.com
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: internal-mirror.example.com/library/nginx:1.17.4
+        name: nginx
+      - image: internal-mirror.example.com/prometheus/nginx-exporter:v0.4
+        name: exporter
+`)
+}
@@ -241,3 +241,89 @@ spec:
         name: configmap-in-base
 `)
 }
+
+func TestExtendedPatchInlineWithoutTarget(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	th.WriteK("/app/base", `
+resources:
+- statefulset.yaml
+
+patches:
+- patch: |-
+    apiVersion: apps/v1
+    kind: StatefulSet
+    metadata:
+      name: my-sts
+    spec:
+      serviceName: my-other-svc
+`)
+	th.WriteF("/app/base/statefulset.yaml", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  serviceName: my-svc
+  template:
+    spec:
+      containers:
+      - name: app
+        image: my-image
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  serviceName: my-other-svc
+  template:
+    spec:
+      containers:
+      - image: my-image
+        name: app
+`)
+}
+
+func TestStrategicMergePatchInlineDeleteCustomResource(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	th.WriteK("/app/base", `
+resources:
+- resources.yaml
+
+patchesStrategicMerge:
+- |-
+  apiVersion: example.com/v1
+  kind: MyKind
+  metadata:
+    name: mine
+  $patch: delete
+`)
+	th.WriteF("/app/base/resources.yaml", `
+apiVersion: example.com/v1
+kind: MyKind
+metadata:
+  name: mine
+spec:
+  size: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-svc
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-svc
+`)
+}
@@ -10,13 +10,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/accumulator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
 	"sigs.k8s.io/kustomize/v3/pkg/pgmconfig"
 	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
@@ -32,6 +38,20 @@ type KustTarget struct {
 	rFactory      *resmap.Factory
 	tFactory      resmap.PatchFactory
 	pLdr          *plugins.Loader
+	resolver      ConflictResolver
+	allowedEnv    []string
+	settings      *settingValues
+	maxResources  int
+	warnUnused    bool
+
+	// results are those the plugins of the build,
+	// including those of bases, reported.
+	results *[]resmap.Result
+
+	// started is when the build started; the targets
+	// it recurses into share it, so that the timestamps
+	// of the build metadata agree.
+	started time.Time
 }
 
 // NewKustTarget returns a new instance of KustTarget primed with a Loader.
@@ -44,9 +64,22 @@ func NewKustTarget(
 	if err != nil {
 		return nil, err
 	}
+	return NewKustTargetFromContent(ldr, content, rFactory, tFactory, pLdr)
+}
+
+// NewKustTargetFromContent returns a new instance of KustTarget
+// for the given kustomization, e.g. one read from stdin, rather
+// than the one in the root of the Loader, which the paths of
+// the kustomization are relative to nonetheless.
+func NewKustTargetFromContent(
+	ldr ifc.Loader,
+	content []byte,
+	rFactory *resmap.Factory,
+	tFactory resmap.PatchFactory,
+	pLdr *plugins.Loader) (*KustTarget, error) {
 	content = types.FixKustomizationPreUnmarshalling(content)
 	var k types.Kustomization
-	err = unmarshal(content, &k)
+	err := unmarshal(content, &k)
 	if err != nil {
 		return nil, err
 	}
@@ -57,15 +90,39 @@ func NewKustTarget(
 			"Failed to read kustomization file under %s:\n"+
 				strings.Join(errs, "\n"), ldr.Root())
 	}
+	if k.FetchOptions != nil {
+		if err := loader.SetKustomizationFetchOptions(ldr, *k.FetchOptions); err != nil {
+			return nil, errors.Wrapf(
+				err, "setting fetchOptions under %s", ldr.Root())
+		}
+	}
 	return &KustTarget{
 		kustomization: &k,
 		ldr:           ldr,
 		rFactory:      rFactory,
 		tFactory:      tFactory,
 		pLdr:          pLdr,
+		results:       &[]resmap.Result{},
+		started:       time.Now(),
 	}, nil
 }
 
+// Results returns the results the plugins reported
+// while the resources were built, in the order they
+// ran, e.g. the warnings of validators.
+func (kt *KustTarget) Results() []resmap.Result {
+	return *kt.results
+}
+
+// report keeps the results of the plugins reporting them.
+func (kt *KustTarget) report(ps ...interface{}) {
+	for _, p := range ps {
+		if r, ok := p.(resmap.Reporter); ok {
+			*kt.results = append(*kt.results, r.Results()...)
+		}
+	}
+}
+
 func quoted(l []string) []string {
 	r := make([]string, len(l))
 	for i, v := range l {
@@ -112,6 +169,32 @@ func unmarshal(y []byte, o interface{}) error {
 	return dec.Decode(o)
 }
 
+// AllowEnvVars lets vars take their values from
+// the environment variables with the given names.
+func (kt *KustTarget) AllowEnvVars(names []string) {
+	kt.allowedEnv = names
+}
+
+// SetMaxResources bounds how many resources this target,
+// and each target it recurses into, may accumulate;
+// 0 means any number.
+func (kt *KustTarget) SetMaxResources(n int) {
+	kt.maxResources = n
+}
+
+// WarnUnusedFiles makes this target, and each target it
+// recurses into, log a warning for each file in their
+// directory trees that their kustomizations don't use.
+func (kt *KustTarget) WarnUnusedFiles(warn bool) {
+	kt.warnUnused = warn
+}
+
+// SortOptions returns the sort options of the
+// kustomization, if any, for its build's output.
+func (kt *KustTarget) SortOptions() *types.SortOptions {
+	return kt.kustomization.SortOptions
+}
+
 // MakeCustomizedResMap creates a ResMap per kustomization instructions.
 // The Resources in the returned ResMap are fully customized.
 func (kt *KustTarget) MakeCustomizedResMap() (resmap.ResMap, error) {
@@ -145,19 +228,63 @@ func (kt *KustTarget) makeCustomizedResMap(
 	}
 
 	// With all the back references fixed, it's OK to resolve Vars.
+	ra.AllowEnvVars(kt.allowedEnv)
 	err = ra.ResolveVars()
 	if err != nil {
 		return nil, err
 	}
 
+	err = ra.CheckParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	err = kt.checkSettingValues()
+	if err != nil {
+		return nil, err
+	}
+
 	err = kt.computeInventory(ra, garbagePolicy)
 	if err != nil {
 		return nil, err
 	}
 
+	lintAutoscaled(ra.ResMap())
+	err = kt.runValidators(ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
 	return ra.ResMap(), nil
 }
 
+// runValidators passes a copy of the resources to each
+// validator of the kustomization, keeping the results
+// they report, and failing on the errors.
+func (kt *KustTarget) runValidators(m resmap.ResMap) error {
+	vs, err := kt.configureExternalValidators()
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, v := range vs {
+		results, err := v.Validate(m.DeepCopy())
+		if err != nil {
+			return errors.Wrapf(err, "running validator %v", v)
+		}
+		*kt.results = append(*kt.results, results...)
+		for _, r := range results {
+			if r.Severity == resmap.SeverityError {
+				errs = append(errs, r.String())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"validation failed:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
 func (kt *KustTarget) addHashesToNames(
 	ra *accumulator.ResAccumulator) error {
 	p := builtin.NewHashTransformerPlugin()
@@ -209,44 +336,99 @@ func (kt *KustTarget) shouldAddHashSuffixesToGeneratedResources() bool {
 func (kt *KustTarget) AccumulateTarget() (
 	ra *accumulator.ResAccumulator, err error) {
 	ra = accumulator.MakeEmptyAccumulator()
-	err = kt.accumulateResources(ra, kt.kustomization.Resources)
+	ra.SetMaxResources(kt.maxResources)
+	err = kt.accumulateTarget(ra)
 	if err != nil {
-		return nil, errors.Wrap(err, "accumulating resources")
+		return nil, err
+	}
+	return ra, nil
+}
+
+// accumulateTarget fills the given ResAccumulator with the
+// kustomization's resources, then customizes everything in
+// it.  For a Component, the accumulator already holds the
+// resources of the kustomization that lists the component.
+func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) error {
+	entries, err := kt.expandGlobs(kt.kustomization.Resources)
+	if err != nil {
+		return err
+	}
+	err = kt.accumulateResources(ra, kt.withoutExcluded(entries))
+	if err != nil {
+		return errors.Wrap(err, "accumulating resources")
+	}
+	err = kt.excludeResources(ra)
+	if err != nil {
+		return err
 	}
 	tConfig, err := config.MakeTransformerConfig(
 		kt.ldr, kt.kustomization.Configurations)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	err = ra.MergeConfig(tConfig)
 	if err != nil {
-		return nil, errors.Wrapf(
+		return errors.Wrapf(
 			err, "merging config %v", tConfig)
 	}
 	crdTc, err := config.LoadConfigFromCRDs(kt.ldr, kt.kustomization.Crds)
 	if err != nil {
-		return nil, errors.Wrapf(
+		return errors.Wrapf(
 			err, "loading CRDs %v", kt.kustomization.Crds)
 	}
 	err = ra.MergeConfig(crdTc)
 	if err != nil {
-		return nil, errors.Wrapf(
+		return errors.Wrapf(
 			err, "merging CRDs %v", crdTc)
 	}
+	openAPITc, err := config.LoadConfigFromOpenAPI(
+		kt.ldr, kt.kustomization.OpenAPI)
+	if err != nil {
+		return errors.Wrapf(
+			err, "loading openapi schema '%s'", kt.kustomization.OpenAPI)
+	}
+	err = ra.MergeConfig(openAPITc)
+	if err != nil {
+		return errors.Wrapf(
+			err, "merging openapi schema %v", openAPITc)
+	}
+	err = kt.accumulateComponents(ra, kt.kustomization.Components)
+	if err != nil {
+		return errors.Wrap(err, "accumulating components")
+	}
+	err = kt.runPatches(ra, types.PatchStageBeforeGenerators)
+	if err != nil {
+		return err
+	}
 	err = kt.runGenerators(ra)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	err = kt.runTransformers(ra)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	err = kt.addNamespace(ra)
+	if err != nil {
+		return err
+	}
+	err = ra.Export(kt.kustomization.Exports)
+	if err != nil {
+		return errors.Wrap(err, "exporting")
+	}
+	err = ra.DeclareParameters(kt.kustomization.Parameters)
+	if err != nil {
+		return errors.Wrap(err, "declaring parameters")
 	}
 	err = ra.MergeVars(kt.kustomization.Vars)
 	if err != nil {
-		return nil, errors.Wrapf(
+		return errors.Wrapf(
 			err, "merging vars %v", kt.kustomization.Vars)
 	}
-	return ra, nil
+	if o := kt.kustomization.VarOptions; o != nil && o.AllFields {
+		ra.ExpandVarsInAllFields()
+	}
+	return kt.lintUnused(ra)
 }
 
 func (kt *KustTarget) runGenerators(
@@ -264,9 +446,11 @@ func (kt *KustTarget) runGenerators(
 	generators = append(generators, gs...)
 	for _, g := range generators {
 		resMap, err := g.Generate()
+		kt.report(g)
 		if err != nil {
 			return err
 		}
+		recordPristine(resMap)
 		err = ra.AbsorbAll(resMap)
 		if err != nil {
 			return errors.Wrapf(err, "merging from generator %v", g)
@@ -277,7 +461,7 @@ func (kt *KustTarget) runGenerators(
 
 func (kt *KustTarget) configureExternalGenerators() ([]resmap.Generator, error) {
 	ra := accumulator.MakeEmptyAccumulator()
-	err := kt.accumulateResources(ra, kt.kustomization.Generators)
+	err := kt.accumulatePluginConfigs(ra, kt.kustomization.Generators)
 	if err != nil {
 		return nil, err
 	}
@@ -292,61 +476,223 @@ func (kt *KustTarget) runTransformers(ra *accumulator.ResAccumulator) error {
 		return err
 	}
 	r = append(r, lts...)
+	bpt := plugins.PatchTransformer
+	lts, err = kt.configurePatches(
+		bpt, plugins.TransformerFactories[bpt], tConfig,
+		types.PatchStageAfterTransformers)
+	if err != nil {
+		return err
+	}
+	r = append(r, lts...)
 	lts, err = kt.configureExternalTransformers()
 	if err != nil {
 		return err
 	}
 	r = append(r, lts...)
 	t := transformers.NewMultiTransformer(r)
-	return ra.Transform(t)
+	err = ra.Transform(t)
+	for _, x := range r {
+		kt.report(x)
+	}
+	return err
+}
+
+// lintAutoscaled logs a warning for each workload that has its
+// replicas set, though a HorizontalPodAutoscaler scales it.
+// Applying such a workload resets its replicas, undoing the
+// autoscaler's scaling until it scales the workload again.
+func lintAutoscaled(m resmap.ResMap) {
+	for _, r := range m.Resources() {
+		if _, err := r.GetFieldValue("spec.replicas"); err != nil {
+			continue
+		}
+		if hpa := transformers.Autoscaler(m, r); hpa != nil {
+			log.Printf(
+				"warning: %s sets spec.replicas, but it's scaled by %s",
+				r.CurId(), hpa.CurId())
+		}
+	}
+}
+
+// addNamespace adds the Namespace resource of the kustomization's
+// namespace, if createNamespace asks for it and the resources don't
+// hold it already.  It's added after the transformers run, so that
+// e.g. a namePrefix doesn't rename it away from the namespace the
+// resources are in.
+func (kt *KustTarget) addNamespace(ra *accumulator.ResAccumulator) error {
+	if !kt.kustomization.CreateNamespace {
+		return nil
+	}
+	name := kt.kustomization.Namespace
+	id := resid.NewResId(gvk.Gvk{Version: "v1", Kind: "Namespace"}, name)
+	if len(ra.ResMap().GetMatchingResourcesByCurrentId(id.GvknEquals)) > 0 {
+		return nil
+	}
+	ns := kt.rFactory.RF().FromMap(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+	})
+	return ra.AppendAll(kt.rFactory.FromResource(ns))
+}
+
+// runPatches applies the kustomization's patches
+// for the given stage to the accumulated resources.
+func (kt *KustTarget) runPatches(
+	ra *accumulator.ResAccumulator, stage types.PatchStage) error {
+	bpt := plugins.PatchTransformer
+	ts, err := kt.configurePatches(
+		bpt, plugins.TransformerFactories[bpt],
+		ra.GetTransformerConfig(), stage)
+	if err != nil {
+		return err
+	}
+	return ra.Transform(transformers.NewMultiTransformer(ts))
 }
 
 func (kt *KustTarget) configureExternalTransformers() ([]resmap.Transformer, error) {
 	ra := accumulator.MakeEmptyAccumulator()
-	err := kt.accumulateResources(ra, kt.kustomization.Transformers)
+	err := kt.accumulatePluginConfigs(ra, kt.kustomization.Transformers)
 	if err != nil {
 		return nil, err
 	}
 	return kt.pLdr.LoadTransformers(kt.ldr, ra.ResMap())
 }
 
+func (kt *KustTarget) configureExternalValidators() ([]resmap.Validator, error) {
+	ra := accumulator.MakeEmptyAccumulator()
+	err := kt.accumulateResources(
+		ra, pathEntries(kt.kustomization.Validators))
+	if err != nil {
+		return nil, err
+	}
+	return kt.pLdr.LoadValidators(kt.ldr, ra.ResMap())
+}
+
 // accumulateResources fills the given resourceAccumulator
-// with resources read from the given list of paths.
+// with resources read from the given list of entries.
 func (kt *KustTarget) accumulateResources(
-	ra *accumulator.ResAccumulator, paths []string) error {
-	for _, path := range paths {
+	ra *accumulator.ResAccumulator, entries []types.ResourceEntry) error {
+	// Resources already accumulated, e.g. when accumulating
+	// a component, have no origin among the entries.
+	origins := make(map[resid.ResId]string)
+	for _, r := range ra.ResMap().Resources() {
+		origins[r.CurId()] = ""
+	}
+	for _, entry := range entries {
+		path := entry.Path
+		resolve := kt.resolverFor(entry, origins)
 		ldr, err := kt.ldr.New(path)
 		if err == nil {
-			err = kt.accumulateDirectory(ra, ldr, path)
+			err = kt.accumulateDirectory(ra, ldr, entry, resolve)
 			if err != nil {
 				return err
 			}
 		} else {
-			err2 := kt.accumulateFile(ra, path)
+			err2 := kt.accumulateFile(ra, entry, resolve)
 			if err2 != nil {
 				// Log ldr.New() error to highlight git failures.
 				log.Print(err.Error())
 				return err2
 			}
 		}
+		for _, r := range ra.ResMap().Resources() {
+			if _, ok := origins[r.CurId()]; !ok {
+				origins[r.CurId()] = path
+			}
+		}
 	}
 	return nil
 }
 
+// expandGlobs replaces each entry whose path is a glob
+// pattern, e.g. "manifests/*.yaml", by an entry for each
+// file matching it, in lexical order, with its options.
+// Kustomization files, like the one listing the pattern,
+// are never resources, so they don't match.
+func (kt *KustTarget) expandGlobs(
+	entries []types.ResourceEntry) ([]types.ResourceEntry, error) {
+	var result []types.ResourceEntry
+	for _, entry := range entries {
+		if !loader.IsGlob(entry.Path) || loader.IsRemote(entry.Path) {
+			result = append(result, entry)
+			continue
+		}
+		paths, err := loader.Glob(kt.ldr, entry.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "expanding resources")
+		}
+		matched := false
+		for _, p := range paths {
+			if isKustomizationFile(p) {
+				continue
+			}
+			e := entry
+			e.Path = p
+			result = append(result, e)
+			matched = true
+		}
+		if !matched {
+			return nil, fmt.Errorf(
+				"expanding resources: no files but kustomization files match %s",
+				entry.Path)
+		}
+	}
+	return result, nil
+}
+
+// isKustomizationFile returns true if the file at the
+// path has the name of a kustomization file.
+func isKustomizationFile(path string) bool {
+	for _, kf := range pgmconfig.RecognizedKustomizationFileNames() {
+		if filepath.Base(path) == kf {
+			return true
+		}
+	}
+	return false
+}
+
 func (kt *KustTarget) accumulateDirectory(
-	ra *accumulator.ResAccumulator, ldr ifc.Loader, path string) error {
+	ra *accumulator.ResAccumulator, ldr ifc.Loader, entry types.ResourceEntry,
+	resolve accumulator.ConflictResolver) error {
 	defer ldr.Cleanup()
+	path := entry.Path
 	subKt, err := NewKustTarget(
 		ldr, kt.rFactory, kt.tFactory, kt.pLdr)
 	if err != nil {
 		return errors.Wrapf(err, "couldn't make target for path '%s'", path)
 	}
+	if subKt.kustomization.Kind == types.ComponentKind {
+		return fmt.Errorf(
+			"'%s' is a %s; list it in components, not resources",
+			path, types.ComponentKind)
+	}
+	subKt.resolver = kt.resolver
+	subKt.settings = kt.settings
+	subKt.maxResources = kt.maxResources
+	subKt.warnUnused = kt.warnUnused
+	subKt.results = kt.results
+	subKt.started = kt.started
 	subRa, err := subKt.AccumulateTarget()
 	if err != nil {
 		return errors.Wrapf(
 			err, "recursed accumulation of path '%s'", path)
 	}
-	err = ra.MergeAccumulator(subRa)
+	if entry.NamePrefix != "" || entry.NameSuffix != "" {
+		t, err := kt.configureEntryPrefixSuffix(
+			entry, subRa.GetTransformerConfig())
+		if err != nil {
+			return err
+		}
+		err = subRa.Transform(t)
+		if err != nil {
+			return errors.Wrapf(
+				err, "adding name prefix or suffix to path '%s'", path)
+		}
+	}
+	err = ra.MergeAccumulatorResolvingConflicts(subRa, resolve)
 	if err != nil {
 		return errors.Wrapf(
 			err, "recursed merging from path '%s'", path)
@@ -354,19 +700,131 @@ func (kt *KustTarget) accumulateDirectory(
 	return nil
 }
 
+// accumulateComponents applies the components at the
+// given paths, in order, to the given ResAccumulator.
+func (kt *KustTarget) accumulateComponents(
+	ra *accumulator.ResAccumulator, paths []string) error {
+	for _, path := range paths {
+		ldr, err := kt.ldr.New(path)
+		if err != nil {
+			return errors.Wrapf(err, "loading component '%s'", path)
+		}
+		err = kt.accumulateComponent(ra, ldr, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kt *KustTarget) accumulateComponent(
+	ra *accumulator.ResAccumulator, ldr ifc.Loader, path string) error {
+	defer ldr.Cleanup()
+	subKt, err := NewKustTarget(
+		ldr, kt.rFactory, kt.tFactory, kt.pLdr)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't make target for path '%s'", path)
+	}
+	if subKt.kustomization.Kind != types.ComponentKind {
+		return fmt.Errorf(
+			"'%s' is not a %s; list it in resources, not components",
+			path, types.ComponentKind)
+	}
+	subKt.resolver = kt.resolver
+	subKt.settings = kt.settings
+	subKt.maxResources = kt.maxResources
+	subKt.warnUnused = kt.warnUnused
+	subKt.results = kt.results
+	subKt.started = kt.started
+	err = subKt.accumulateTarget(ra)
+	if err != nil {
+		return errors.Wrapf(
+			err, "applying component from path '%s'", path)
+	}
+	return nil
+}
+
 func (kt *KustTarget) accumulateFile(
-	ra *accumulator.ResAccumulator, path string) error {
+	ra *accumulator.ResAccumulator, entry types.ResourceEntry,
+	resolve accumulator.ConflictResolver) error {
+	path := entry.Path
 	resources, err := kt.rFactory.FromFile(kt.ldr, path)
 	if err != nil {
 		return errors.Wrapf(err, "accumulating resources from '%s'", path)
 	}
-	err = ra.AppendAll(resources)
+	recordPristine(resources)
+	origin := path
+	if !filepath.IsAbs(origin) {
+		origin = filepath.Join(kt.ldr.Root(), fs.LocalPath(path))
+	}
+	for _, r := range resources.Resources() {
+		r.AppendProvenance(origin)
+	}
+	if entry.NamePrefix != "" || entry.NameSuffix != "" {
+		// The kustomization's configurations aren't in ra yet.
+		tc, err := config.MakeTransformerConfig(
+			kt.ldr, kt.kustomization.Configurations)
+		if err != nil {
+			return err
+		}
+		t, err := kt.configureEntryPrefixSuffix(entry, tc)
+		if err != nil {
+			return err
+		}
+		err = t.Transform(resources)
+		if err != nil {
+			return errors.Wrapf(
+				err, "adding name prefix or suffix to '%s'", path)
+		}
+	}
+	err = ra.AppendAllResolvingConflicts(resources, resolve)
 	if err != nil {
 		return errors.Wrapf(err, "merging resources from '%s'", path)
 	}
 	return nil
 }
 
+// accumulatePluginConfigs fills the given resourceAccumulator
+// with the plugin configs read from, or given inline in, the
+// given list of entries, in the order of the entries.
+func (kt *KustTarget) accumulatePluginConfigs(
+	ra *accumulator.ResAccumulator, entries []types.PluginEntry) error {
+	for _, e := range entries {
+		if e.Inline == nil {
+			err := kt.accumulateResources(
+				ra, []types.ResourceEntry{{Path: e.Path}})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		r := kt.rFactory.RF().FromMap(e.Inline)
+		if err := ra.AppendAll(kt.rFactory.FromResource(r)); err != nil {
+			return errors.Wrapf(err, "adding inline plugin config %s", r.CurId())
+		}
+	}
+	return nil
+}
+
+// pathEntries converts a list of paths to a list
+// of entries holding nothing but those paths.
+func pathEntries(paths []string) []types.ResourceEntry {
+	var result []types.ResourceEntry
+	for _, p := range paths {
+		result = append(result, types.ResourceEntry{Path: p})
+	}
+	return result
+}
+
+// recordPristine marks the current content of the given
+// resources as their uncustomized content, so that variants
+// of them produced by different overlays can later be merged.
+func recordPristine(m resmap.ResMap) {
+	for _, r := range m.Resources() {
+		r.RecordPristine()
+	}
+}
+
 func (kt *KustTarget) configureBuiltinPlugin(
 	p resmap.Configurable, c interface{}, bpt plugins.BuiltinPluginType) (err error) {
 	var y []byte
@@ -0,0 +1,73 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// buildMetadataAnnotationFieldSpecs put build metadata annotations
+// on resources only, not on their templates, so that rebuilding
+// doesn't roll out new pods just for a new timestamp.
+var buildMetadataAnnotationFieldSpecs = []config.FieldSpec{
+	{Path: "metadata/annotations", CreateIfNotPresent: true},
+}
+
+// buildValue computes the given build metadata value.
+func (kt *KustTarget) buildValue(v types.BuildValue) (string, error) {
+	switch v {
+	case types.BuildGitCommit, types.BuildGitShortCommit:
+		return git.HeadCommit(kt.ldr.Root(), v == types.BuildGitShortCommit)
+	case types.BuildTimestamp:
+		epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH")
+		if !ok {
+			return strconv.FormatInt(kt.started.Unix(), 10), nil
+		}
+		if _, err := strconv.ParseInt(epoch, 10, 64); err != nil {
+			return "", fmt.Errorf(
+				"SOURCE_DATE_EPOCH %q is not a number of seconds", epoch)
+		}
+		return epoch, nil
+	default:
+		return "", fmt.Errorf("unknown buildMetadata value %s", v)
+	}
+}
+
+// buildNameSuffix returns the suffix the build metadata
+// adds to names, if any.
+func (kt *KustTarget) buildNameSuffix() (string, error) {
+	md := kt.kustomization.BuildMetadata
+	if md == nil || md.NameSuffix == "" {
+		return "", nil
+	}
+	value, err := kt.buildValue(md.NameSuffix)
+	if err != nil {
+		return "", err
+	}
+	return "-" + value, nil
+}
+
+// buildAnnotations returns the annotations the build
+// metadata adds, if any.
+func (kt *KustTarget) buildAnnotations() (map[string]string, error) {
+	md := kt.kustomization.BuildMetadata
+	if md == nil || len(md.Annotations) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string, len(md.Annotations))
+	for k, v := range md.Annotations {
+		value, err := kt.buildValue(v)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = value
+	}
+	return result, nil
+}
@@ -29,8 +29,10 @@ import (
 func (kt *KustTarget) configureBuiltinGenerators() (
 	result []resmap.Generator, err error) {
 	for _, bpt := range []plugins.BuiltinPluginType{
+		plugins.HelmChartInflationGenerator,
 		plugins.ConfigMapGenerator,
 		plugins.SecretGenerator,
+		plugins.VaultSecretGenerator,
 	} {
 		r, err := generatorConfigurators[bpt](
 			kt, bpt, plugins.GeneratorFactories[bpt])
@@ -48,6 +50,11 @@ func (kt *KustTarget) configureBuiltinTransformers(
 	for _, bpt := range []plugins.BuiltinPluginType{
 		plugins.PatchStrategicMergeTransformer,
 		plugins.PatchTransformer,
+		plugins.ApiVersionUpgradeTransformer,
+		plugins.SidecarTransformer,
+		plugins.DefaultResourcesTransformer,
+		plugins.DefaultSecurityContextTransformer,
+		plugins.SchedulingTransformer,
 		plugins.NamespaceTransformer,
 		plugins.PrefixSuffixTransformer,
 		plugins.LabelTransformer,
@@ -55,6 +62,9 @@ func (kt *KustTarget) configureBuiltinTransformers(
 		plugins.PatchJson6902Transformer,
 		plugins.ReplicaCountTransformer,
 		plugins.ImageTagTransformer,
+		plugins.ImageRegistryTransformer,
+		plugins.ReplacementTransformer,
+		plugins.EnvSubstTransformer,
 	} {
 		r, err := transformerConfigurators[bpt](
 			kt, bpt, plugins.TransformerFactories[bpt], tc)
@@ -93,6 +103,54 @@ var generatorConfigurators = map[plugins.BuiltinPluginType]func(
 		return
 	},
 
+	plugins.VaultSecretGenerator: func(kt *KustTarget, bpt plugins.BuiltinPluginType, f gFactory) (
+		result []resmap.Generator, err error) {
+		if len(kt.kustomization.VaultSecretGenerator) == 0 {
+			return
+		}
+		if err = kt.pLdr.ErrIfExternalSecretsNotEnabled(bpt); err != nil {
+			return nil, err
+		}
+		var c struct {
+			types.GeneratorOptions
+			types.VaultSecretArgs
+		}
+		if kt.kustomization.GeneratorOptions != nil {
+			c.GeneratorOptions = *kt.kustomization.GeneratorOptions
+		}
+		for _, args := range kt.kustomization.VaultSecretGenerator {
+			c.VaultSecretArgs = args
+			p := f()
+			err := kt.configureBuiltinPlugin(p, c, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
+
+	plugins.HelmChartInflationGenerator: func(kt *KustTarget, bpt plugins.BuiltinPluginType, f gFactory) (
+		result []resmap.Generator, err error) {
+		var c struct {
+			types.HelmGlobals
+			types.HelmChart
+		}
+		if kt.kustomization.HelmGlobals != nil {
+			c.HelmGlobals = *kt.kustomization.HelmGlobals
+		}
+		for _, chart := range kt.kustomization.HelmCharts {
+			c.HelmChart = chart
+			p := f()
+			err := kt.configureBuiltinPlugin(p, c, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
+
 	plugins.ConfigMapGenerator: func(kt *KustTarget, bpt plugins.BuiltinPluginType, f gFactory) (
 		result []resmap.Generator, err error) {
 		var c struct {
@@ -128,9 +186,15 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 		var c struct {
 			types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 			FieldSpecs       []config.FieldSpec
+			UnsetOnly        bool
+			Subjects         types.SubjectsPolicy
 		}
 		c.Namespace = kt.kustomization.Namespace
 		c.FieldSpecs = tc.NameSpace
+		if opts := kt.kustomization.NamespaceOptions; opts != nil {
+			c.UnsetOnly = opts.UnsetOnly
+			c.Subjects = opts.Subjects
+		}
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
 		if err != nil {
@@ -162,16 +226,18 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 		return
 	},
 	plugins.PatchStrategicMergeTransformer: func(
-		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, tc *config.TransformerConfig) (
 		result []resmap.Transformer, err error) {
 		if len(kt.kustomization.PatchesStrategicMerge) == 0 {
 			return
 		}
 		var c struct {
-			Paths   []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
-			Patches string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+			Paths          []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
+			Patches        string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+			PatchMergeKeys []config.PatchMergeKey      `json:"patchMergeKeys,omitempty" yaml:"patchMergeKeys,omitempty"`
 		}
 		c.Paths = kt.kustomization.PatchesStrategicMerge
+		c.PatchMergeKeys = tc.PatchMergeKeys
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
 		if err != nil {
@@ -181,22 +247,30 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 		return
 	},
 	plugins.PatchTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, tc *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		return kt.configurePatches(
+			bpt, f, tc, types.PatchStageBeforeTransformers)
+	},
+	plugins.SidecarTransformer: func(
 		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
 		result []resmap.Transformer, err error) {
-		if len(kt.kustomization.Patches) == 0 {
-			return
+		for _, args := range kt.kustomization.Sidecars {
+			p := f()
+			err = kt.configureBuiltinPlugin(p, args, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
 		}
-		var c struct {
-			Path   string          `json:"path,omitempty" yaml:"path,omitempty"`
-			Patch  string          `json:"patch,omitempty" yaml:"patch,omitempty"`
-			Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
-		}
-		for _, pc := range kt.kustomization.Patches {
-			c.Target = pc.Target
-			c.Patch = pc.Patch
-			c.Path = pc.Path
+		return
+	},
+	plugins.DefaultResourcesTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		for _, args := range kt.kustomization.DefaultResources {
 			p := f()
-			err = kt.configureBuiltinPlugin(p, c, bpt)
+			err = kt.configureBuiltinPlugin(p, args, bpt)
 			if err != nil {
 				return nil, err
 			}
@@ -204,6 +278,50 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 		}
 		return
 	},
+	plugins.DefaultSecurityContextTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		for _, args := range kt.kustomization.DefaultSecurityContext {
+			p := f()
+			err = kt.configureBuiltinPlugin(p, args, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
+	plugins.SchedulingTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		for _, args := range kt.kustomization.Scheduling {
+			p := f()
+			err = kt.configureBuiltinPlugin(p, args, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
+	plugins.ApiVersionUpgradeTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		if kt.kustomization.KubeVersion == "" {
+			return
+		}
+		var c struct {
+			KubeVersion types.KubeVersion
+		}
+		c.KubeVersion = kt.kustomization.KubeVersion
+		p := f()
+		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+		return
+	},
 	plugins.LabelTransformer: func(
 		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, tc *config.TransformerConfig) (
 		result []resmap.Transformer, err error) {
@@ -219,6 +337,17 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 			return nil, err
 		}
 		result = append(result, p)
+		for _, label := range kt.kustomization.Labels {
+			c.Labels = label.Pairs
+			c.FieldSpecs = tc.LabelFieldSpecs(
+				label.IncludeSelectors, label.IncludeTemplates)
+			p := f()
+			err = kt.configureBuiltinPlugin(p, c, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
 		return
 	},
 	plugins.AnnotationsTransformer: func(
@@ -227,8 +356,14 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 		var c struct {
 			Annotations map[string]string
 			FieldSpecs  []config.FieldSpec
+			Include     []types.Selector
+			Exclude     []types.Selector
+		}
+		if a := kt.kustomization.CommonAnnotations; a != nil {
+			c.Annotations = a.Pairs
+			c.Include = a.Include
+			c.Exclude = a.Exclude
 		}
-		c.Annotations = kt.kustomization.CommonAnnotations
 		c.FieldSpecs = tc.CommonAnnotations
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
@@ -236,6 +371,21 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 			return nil, err
 		}
 		result = append(result, p)
+		c.Annotations, err = kt.buildAnnotations()
+		if err != nil {
+			return nil, err
+		}
+		if len(c.Annotations) > 0 {
+			c.FieldSpecs = buildMetadataAnnotationFieldSpecs
+			c.Include = nil
+			c.Exclude = nil
+			p := f()
+			err = kt.configureBuiltinPlugin(p, c, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
 		return
 	},
 	plugins.PrefixSuffixTransformer: func(
@@ -245,10 +395,20 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 			Prefix     string
 			Suffix     string
 			FieldSpecs []config.FieldSpec
+			SkipKinds  []string
+			Target     *types.Selector
+		}
+		suffix, err := kt.buildNameSuffix()
+		if err != nil {
+			return nil, err
 		}
 		c.Prefix = kt.kustomization.NamePrefix
-		c.Suffix = kt.kustomization.NameSuffix
+		c.Suffix = kt.kustomization.NameSuffix + suffix
 		c.FieldSpecs = tc.NamePrefix
+		if opts := kt.kustomization.NameOptions; opts != nil {
+			c.SkipKinds = opts.SkipKinds
+			c.Target = opts.Target
+		}
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
 		if err != nil {
@@ -276,6 +436,26 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 		}
 		return
 	},
+	plugins.ImageRegistryTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, tc *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		if len(kt.kustomization.ImageRegistryRewrite) == 0 {
+			return
+		}
+		var c struct {
+			Rewrites   []image.RegistryRewrite
+			FieldSpecs []config.FieldSpec
+		}
+		c.Rewrites = kt.kustomization.ImageRegistryRewrite
+		c.FieldSpecs = tc.Images
+		p := f()
+		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+		return
+	},
 	plugins.ReplicaCountTransformer: func(
 		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, tc *config.TransformerConfig) (
 		result []resmap.Transformer, err error) {
@@ -295,4 +475,95 @@ var transformerConfigurators = map[plugins.BuiltinPluginType]func(
 		}
 		return
 	},
+	plugins.ReplacementTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		for _, args := range kt.kustomization.Replacements {
+			p := f()
+			err = kt.configureBuiltinPlugin(p, args, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		settings, err := kt.configureSettings(bpt, f)
+		if err != nil {
+			return nil, err
+		}
+		return append(result, settings...), nil
+	},
+	plugins.EnvSubstTransformer: func(
+		kt *KustTarget, bpt plugins.BuiltinPluginType, f tFactory, _ *config.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		if kt.kustomization.EnvSubst == nil {
+			return
+		}
+		p := f()
+		err = kt.configureBuiltinPlugin(p, kt.kustomization.EnvSubst, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+		return
+	},
+}
+
+// configurePatches returns a PatchTransformer for each
+// of the kustomization's patches applied at the given stage.
+func (kt *KustTarget) configurePatches(
+	bpt plugins.BuiltinPluginType, f tFactory,
+	tc *config.TransformerConfig, stage types.PatchStage) (
+	result []resmap.Transformer, err error) {
+	var c struct {
+		Path           string                 `json:"path,omitempty" yaml:"path,omitempty"`
+		Patch          string                 `json:"patch,omitempty" yaml:"patch,omitempty"`
+		Target         *types.Selector        `json:"target,omitempty" yaml:"target,omitempty"`
+		Exclude        []types.Selector       `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+		PatchMergeKeys []config.PatchMergeKey `json:"patchMergeKeys,omitempty" yaml:"patchMergeKeys,omitempty"`
+	}
+	c.PatchMergeKeys = tc.PatchMergeKeys
+	for _, pc := range kt.kustomization.Patches {
+		if pc.Stage.Effective() != stage {
+			continue
+		}
+		c.Target = pc.Target
+		c.Exclude = pc.Exclude
+		c.Patch = pc.Patch
+		c.Path = pc.Path
+		p := f()
+		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return
+}
+
+// configureEntryPrefixSuffix returns a transformer adding the
+// name prefix and suffix of the given resources list entry.
+func (kt *KustTarget) configureEntryPrefixSuffix(
+	entry types.ResourceEntry, tc *config.TransformerConfig) (
+	resmap.Transformer, error) {
+	var c struct {
+		Prefix     string
+		Suffix     string
+		FieldSpecs []config.FieldSpec
+		SkipKinds  []string
+		Target     *types.Selector
+	}
+	c.Prefix = entry.NamePrefix
+	c.Suffix = entry.NameSuffix
+	c.FieldSpecs = tc.NamePrefix
+	if opts := kt.kustomization.NameOptions; opts != nil {
+		c.SkipKinds = opts.SkipKinds
+		c.Target = opts.Target
+	}
+	bpt := plugins.PrefixSuffixTransformer
+	p := plugins.TransformerFactories[bpt]()
+	err := kt.configureBuiltinPlugin(p, c, bpt)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
 }
@@ -0,0 +1,131 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"log"
+
+	"sigs.k8s.io/kustomize/v3/pkg/accumulator"
+	"sigs.k8s.io/kustomize/v3/pkg/merge"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// Conflict describes two resources with the same id,
+// yielded by different entries of a kustomization's
+// resources list.
+type Conflict struct {
+	// Root is the root of the kustomization holding the entries.
+	Root string
+	// Accumulated is the resource yielded by an earlier entry.
+	Accumulated *resource.Resource
+	// AccumulatedPath is the path of the earlier entry, or
+	// empty if Accumulated was accumulated before any entry,
+	// as when the kustomization is a component.
+	AccumulatedPath string
+	// Incoming is the resource yielded by a later entry.
+	Incoming *resource.Resource
+	// IncomingPath is the path of the later entry.
+	IncomingPath string
+}
+
+// ConflictResolver resolves conflicts that the kustomization
+// being built doesn't resolve with a mergeStrategy.
+type ConflictResolver interface {
+	// Resolve returns the resource to keep in place of
+	// c.Accumulated.  It may return either of the two resources,
+	// or one of them modified to absorb the other, but the
+	// resource returned must have the same id.  Returning nil
+	// and no error declines to resolve the conflict, which
+	// is then reported as an error.
+	Resolve(c Conflict) (*resource.Resource, error)
+}
+
+// SetConflictResolver installs a resolver for conflicts
+// in this target and all the targets it recurses into.
+func (kt *KustTarget) SetConflictResolver(r ConflictResolver) {
+	kt.resolver = r
+}
+
+// resolverFor returns the resolver for id collisions
+// between resources accumulated from the given entry and
+// resources accumulated from earlier entries, whose paths
+// are recorded by id in origins.
+func (kt *KustTarget) resolverFor(
+	entry types.ResourceEntry,
+	origins map[resid.ResId]string) accumulator.ConflictResolver {
+	s := entry.MergeStrategy
+	if s == types.MergeStrategyUnspecified {
+		s = kt.kustomization.MergeStrategy
+	}
+	switch s {
+	case types.MergeStrategyMerge:
+		return kt.mergeVariants
+	case types.MergeStrategyReplace:
+		return func(
+			accumulated, incoming *resource.Resource) (*resource.Resource, error) {
+			origins[incoming.CurId()] = entry.Path
+			return incoming, nil
+		}
+	case types.MergeStrategyUnspecified:
+		if kt.resolver != nil {
+			return kt.customResolver(kt.resolver, entry, origins)
+		}
+		if kt.kustomization.AllowIdConflicts == types.IdConflictsLastWins {
+			return kt.customResolver(LastWinsResolver{}, entry, origins)
+		}
+	}
+	return nil
+}
+
+// LastWinsResolver resolves every conflict in favor of
+// the later resource, logging a warning.
+type LastWinsResolver struct{}
+
+// Resolve implements ConflictResolver.
+func (LastWinsResolver) Resolve(c Conflict) (*resource.Resource, error) {
+	log.Printf(
+		"warning: in %s, %s from '%s' replaces the one from '%s'",
+		c.Root, c.Incoming.CurId(), c.IncomingPath, c.AccumulatedPath)
+	return c.Incoming, nil
+}
+
+func (kt *KustTarget) customResolver(
+	r ConflictResolver, entry types.ResourceEntry,
+	origins map[resid.ResId]string) accumulator.ConflictResolver {
+	return func(
+		accumulated, incoming *resource.Resource) (*resource.Resource, error) {
+		kept, err := r.Resolve(Conflict{
+			Root:            kt.ldr.Root(),
+			Accumulated:     accumulated,
+			AccumulatedPath: origins[accumulated.CurId()],
+			Incoming:        incoming,
+			IncomingPath:    entry.Path,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if kept == incoming {
+			origins[kept.CurId()] = entry.Path
+		}
+		return kept, nil
+	}
+}
+
+// mergeVariants merges the incoming resource into the
+// accumulated one if both are variants of the same pristine
+// resource, as happens when two overlays of a common base
+// are accumulated.
+func (kt *KustTarget) mergeVariants(
+	accumulated, incoming *resource.Resource) (*resource.Resource, error) {
+	if !accumulated.SharesPristine(incoming) {
+		return nil, nil
+	}
+	err := merge.MergeResource(kt.tFactory, accumulated, incoming)
+	if err != nil {
+		return nil, err
+	}
+	return accumulated, nil
+}
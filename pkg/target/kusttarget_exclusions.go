@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/accumulator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// withoutExcluded returns the entries whose paths
+// no path of the excludeResources list matches,
+// logging a warning for each path matching none.
+func (kt *KustTarget) withoutExcluded(
+	entries []types.ResourceEntry) []types.ResourceEntry {
+	matched := make(map[string]bool)
+	var result []types.ResourceEntry
+	for _, entry := range entries {
+		if x, ok := kt.excludingPath(entry.Path); ok {
+			matched[x] = true
+			continue
+		}
+		result = append(result, entry)
+	}
+	for _, x := range kt.kustomization.ExcludeResources {
+		if x.Path != "" && !matched[x.Path] {
+			log.Printf(
+				"warning: excluded resource %s of %s matched no resources",
+				x.Path, kt.ldr.Root())
+		}
+	}
+	return result
+}
+
+// excludingPath returns the path of the excludeResources
+// list matching the given path, if any.
+func (kt *KustTarget) excludingPath(path string) (string, bool) {
+	path = filepath.Clean(fs.LocalPath(path))
+	for _, x := range kt.kustomization.ExcludeResources {
+		if x.Path == "" {
+			continue
+		}
+		pattern := filepath.Clean(fs.LocalPath(x.Path))
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return x.Path, true
+		}
+	}
+	return "", false
+}
+
+// excludeResources drops the accumulated resources
+// that the selectors of the excludeResources list
+// select, logging a warning for each selecting none.
+func (kt *KustTarget) excludeResources(ra *accumulator.ResAccumulator) error {
+	for _, x := range kt.kustomization.ExcludeResources {
+		if x.Path != "" {
+			continue
+		}
+		n, err := ra.Exclude(x.Selector)
+		if err != nil {
+			return errors.Wrapf(err, "excluding resources %v", x.Selector)
+		}
+		if n == 0 {
+			log.Printf(
+				"warning: excluded resources %v of %s matched no resources",
+				x.Selector, kt.ldr.Root())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+)
+
+// settingValues holds the values given for the settings of
+// the kustomizations of a build, shared by their targets.
+type settingValues struct {
+	values map[string]string
+	// declared holds the names of the settings
+	// the kustomizations built so far declare.
+	declared map[string]bool
+}
+
+// SetValues gives the settings of this target, and of all
+// the targets it recurses into, values by name.
+func (kt *KustTarget) SetValues(values map[string]string) {
+	kt.settings = &settingValues{
+		values:   values,
+		declared: make(map[string]bool),
+	}
+}
+
+// configureSettings returns a ReplacementTransformer for
+// each setting of the kustomization that's given a value.
+func (kt *KustTarget) configureSettings(
+	bpt plugins.BuiltinPluginType, f tFactory) (
+	result []resmap.Transformer, err error) {
+	if kt.settings == nil {
+		return nil, nil
+	}
+	for _, s := range kt.kustomization.Settings {
+		kt.settings.declared[s.Name] = true
+		value, ok := kt.settings.values[s.Name]
+		if !ok {
+			continue
+		}
+		var c struct {
+			types.Replacement `json:",inline,omitempty" yaml:",inline,omitempty"`
+			Value             *string `json:"value,omitempty" yaml:"value,omitempty"`
+		}
+		c.Targets = s.Targets
+		c.Value = &value
+		p := f()
+		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// checkSettingValues returns an error if a value was given
+// for a setting that none of the kustomizations declares.
+func (kt *KustTarget) checkSettingValues() error {
+	if kt.settings == nil {
+		return nil
+	}
+	var unknown []string
+	for name := range kt.settings.values {
+		if !kt.settings.declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf(
+			"no kustomization of the build has the settings %v", unknown)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"log"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/v3/pkg/accumulator"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+)
+
+// defaultChartHome is the directory holding the helm
+// charts of a kustomization that doesn't name one.
+const defaultChartHome = "charts"
+
+// lintUnused logs a warning for each patch file of the
+// kustomization that patched nothing, and, if asked to,
+// each file in the kustomization's directory tree that
+// it didn't use.
+func (kt *KustTarget) lintUnused(ra *accumulator.ResAccumulator) error {
+	patched := make(map[string]bool)
+	for _, r := range ra.ResMap().Resources() {
+		for _, path := range r.Provenance() {
+			patched[path] = true
+		}
+	}
+	for _, path := range kt.patchFiles() {
+		if patched[filepath.Join(kt.ldr.Root(), fs.LocalPath(path))] ||
+			kt.deletes(path) {
+			continue
+		}
+		log.Printf(
+			"warning: patch %s of %s matched no resources",
+			path, kt.ldr.Root())
+	}
+	if !kt.warnUnused {
+		return nil
+	}
+	var skip []string
+	if len(kt.kustomization.HelmCharts) > 0 {
+		home := defaultChartHome
+		if g := kt.kustomization.HelmGlobals; g != nil && g.ChartHome != "" {
+			home = g.ChartHome
+		}
+		skip = append(skip, home)
+	}
+	// Validators are read once the resources are built.
+	skip = append(skip, kt.kustomization.Validators...)
+	unread, err := loader.UnreadFiles(kt.ldr, skip...)
+	if err != nil {
+		return err
+	}
+	for _, path := range unread {
+		if _, ok := kt.excludingPath(path); ok {
+			continue
+		}
+		log.Printf(
+			"warning: file %s of %s isn't used by its kustomization",
+			path, kt.ldr.Root())
+	}
+	return nil
+}
+
+// patchFiles returns the paths of the patch files of
+// the kustomization, leaving out the inline patches.
+func (kt *KustTarget) patchFiles() []string {
+	var paths []string
+	for _, p := range kt.kustomization.Patches {
+		if p.Path != "" {
+			paths = append(paths, p.Path)
+		}
+	}
+	for _, p := range kt.kustomization.PatchesStrategicMerge {
+		// As for the transformer, what parses as
+		// resources is a patch, not a path.
+		if _, err := kt.rFactory.RF().SliceFromBytes([]byte(p)); err == nil {
+			continue
+		}
+		paths = append(paths, string(p))
+	}
+	for _, p := range kt.kustomization.PatchesJson6902 {
+		if p.Path != "" {
+			paths = append(paths, p.Path)
+		}
+	}
+	return paths
+}
+
+// deletes returns true if the patch file deletes resources,
+// which then aren't left to record that it patched them.
+func (kt *KustTarget) deletes(path string) bool {
+	content, err := kt.ldr.Load(path)
+	if err != nil {
+		return false
+	}
+	patches, err := kt.rFactory.RF().SliceFromBytes(content)
+	if err != nil {
+		return false
+	}
+	for _, p := range patches {
+		if p.Map()["$patch"] == "delete" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeMaxResourcesBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- cms.yaml
+`)
+	th.WriteF("/app/base/cms.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+- cm.yaml
+`)
+	th.WriteF("/app/overlay/cm.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: c
+`)
+}
+
+func TestMaxResources(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeMaxResourcesBase(th)
+	kt := th.MakeKustTarget()
+	kt.SetMaxResources(3)
+	if _, err := kt.MakeCustomizedResMap(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	kt = th.MakeKustTarget()
+	kt.SetMaxResources(2)
+	_, err := kt.MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "exceed the limit of 2") {
+		t.Fatalf("expected limit error, got %v", err)
+	}
+
+	// Each base is held to the limit too.
+	kt = th.MakeKustTarget()
+	kt.SetMaxResources(1)
+	_, err = kt.MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "'../base'") {
+		t.Fatalf("expected limit error in the base, got %v", err)
+	}
+}
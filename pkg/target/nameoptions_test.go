@@ -0,0 +1,141 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestNameOptionsSkipKinds(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+namePrefix: team-
+nameOptions:
+  skipKinds:
+  - ClusterRole
+resources:
+- rbac.yaml
+`)
+	th.WriteF("/app/rbac.yaml", `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: metrics-reader
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: exporter
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: exporter-metrics
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: metrics-reader
+subjects:
+- kind: ServiceAccount
+  name: exporter
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: metrics-reader
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: team-exporter
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: team-exporter-metrics
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: metrics-reader
+subjects:
+- kind: ServiceAccount
+  name: team-exporter
+`)
+}
+
+func TestNameOptionsTarget(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+resources:
+- resources.yaml
+`)
+	th.WriteF("/app/base/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+        envFrom:
+        - configMapRef:
+            name: shared-config
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared-config
+  labels:
+    tier: infra
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: app-
+nameSuffix: -v2
+nameOptions:
+  target:
+    labelSelector: tier=app
+resources:
+- ../base
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: app
+  name: app-web-v2
+spec:
+  template:
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: shared-config
+        image: web
+        name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    tier: infra
+  name: shared-config
+`)
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeNamespaceBases(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/team-a", `
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/team-a/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	th.WriteK("/app/team-b", `
+namespace: monitoring
+resources:
+- service.yaml
+`)
+	th.WriteF("/app/team-b/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: prometheus
+`)
+}
+
+func TestNamespaceUnsetOnly(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeNamespaceBases(th)
+	th.WriteK("/app/prod", `
+namespace: prod
+namespaceOptions:
+  unsetOnly: true
+resources:
+- ../team-a
+- ../team-b
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: prometheus
+  namespace: monitoring
+`)
+}
+
+func TestCreateNamespace(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeNamespaceBases(th)
+	th.WriteK("/app/prod", `
+namespace: prod
+createNamespace: true
+namePrefix: prod-
+commonLabels:
+  env: prod
+resources:
+- ../team-a
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    env: prod
+  name: prod-web
+  namespace: prod
+spec:
+  selector:
+    matchLabels:
+      env: prod
+  template:
+    metadata:
+      labels:
+        env: prod
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+`)
+}
+
+func TestCreateNamespaceHeldByResources(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	th.WriteK("/app/prod", `
+namespace: prod
+createNamespace: true
+resources:
+- namespace.yaml
+`)
+	th.WriteF("/app/prod/namespace.yaml", `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+  labels:
+    istio-injection: enabled
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  labels:
+    istio-injection: enabled
+  name: prod
+`)
+}
+
+func TestNamespaceOptionsSubjectsNone(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	th.WriteK("/app/prod", `
+namespace: prod
+namespaceOptions:
+  subjects: none
+resources:
+- binding.yaml
+`)
+	th.WriteF("/app/prod/binding.yaml", `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: read-metrics
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: monitoring
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: read-metrics
+  namespace: prod
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: monitoring
+`)
+}
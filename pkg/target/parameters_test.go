@@ -0,0 +1,133 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeParameterizedBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- ingress.yaml
+parameters:
+- name: host
+  select:
+    kind: Ingress
+    name: web
+  fieldPath: spec.rules[0].host
+  description: the domain the web app is served on
+- name: tls-secret
+  select:
+    kind: Ingress
+    name: web
+  fieldPath: spec.tls[0].secretName
+`)
+	th.WriteF("/app/base/ingress.yaml", `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - host: example.com
+  tls:
+  - {}
+`)
+}
+
+func TestParametersSet(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeParameterizedBase(th)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../base
+- certificate.yaml
+patchesJson6902:
+- target:
+    group: networking.k8s.io
+    version: v1
+    kind: Ingress
+    name: web
+  path: host.yaml
+replacements:
+- source:
+    kind: Certificate
+  targets:
+  - select:
+      kind: Ingress
+    fieldPaths:
+    - spec.tls.secretName
+    create: true
+`)
+	th.WriteF("/app/prod/host.yaml", `
+- op: replace
+  path: /spec/rules/0/host
+  value: shop.example.com
+`)
+	th.WriteF("/app/prod/certificate.yaml", `
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: shop
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: prod-web
+spec:
+  rules:
+  - host: shop.example.com
+  tls:
+  - secretName: prod-shop
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: prod-shop
+`)
+}
+
+func TestParametersNotSet(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeParameterizedBase(th)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../base
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	expected := "parameter host, spec.rules[0].host of " +
+		"networking.k8s.io_v1_Ingress|~X|prod-web, isn't set: " +
+		"the domain the web app is served on\n" +
+		"parameter tls-secret, spec.tls[0].secretName of " +
+		"networking.k8s.io_v1_Ingress|~X|prod-web, isn't set"
+	if err.Error() != expected {
+		t.Fatalf("expected error '%s', got '%v'", expected, err)
+	}
+}
+
+func TestParametersOfBaseBuiltAlone(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	writeParameterizedBase(th)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "parameter host, ") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeWorkersBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- mykind.yaml
+`)
+	th.WriteF("/app/base/mykind.yaml", `
+apiVersion: example.com/v1
+kind: MyKind
+metadata:
+  name: mine
+spec:
+  workers:
+  - name: a
+    size: small
+  - name: b
+    size: small
+`)
+	th.WriteF("/app/overlay/mykind-patch.yaml", `
+apiVersion: example.com/v1
+kind: MyKind
+metadata:
+  name: mine
+spec:
+  workers:
+  - name: b
+    size: large
+`)
+}
+
+func TestPatchMergeKeysFromConfigurations(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeWorkersBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+configurations:
+- config.yaml
+patchesStrategicMerge:
+- mykind-patch.yaml
+`)
+	th.WriteF("/app/overlay/config.yaml", `
+patchMergeKeys:
+- kind: MyKind
+  path: spec/workers
+  mergeKey: name
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: MyKind
+metadata:
+  name: mine
+spec:
+  workers:
+  - name: a
+    size: small
+  - name: b
+    size: large
+`)
+}
+
+func TestPatchMergeKeysUndeclared(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeWorkersBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+patches:
+- path: mykind-patch.yaml
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: MyKind
+metadata:
+  name: mine
+spec:
+  workers:
+  - name: b
+    size: large
+`)
+}
+
+func TestPatchMergeKeysFromOpenAPI(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	writeWorkersBase(th)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+openapi: schema.json
+patchesStrategicMerge:
+- mykind-patch.yaml
+`)
+	th.WriteF("/app/overlay/schema.json", `
+{
+  "definitions": {
+    "com.example.v1.MyKind": {
+      "x-kubernetes-group-version-kind": [
+        {"group": "example.com", "version": "v1", "kind": "MyKind"}
+      ],
+      "properties": {
+        "spec": {
+          "properties": {
+            "workers": {
+              "type": "array",
+              "x-kubernetes-patch-strategy": "merge",
+              "x-kubernetes-patch-merge-key": "name"
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: MyKind
+metadata:
+  name: mine
+spec:
+  workers:
+  - name: a
+    size: small
+  - name: b
+    size: large
+`)
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writePatchStageBase(
+	th *kusttest_test.KustTestHarness, stage, labelSelector string) {
+	th.WriteK("/app/base", `
+namePrefix: p-
+commonLabels:
+  team: web
+resources:
+- configmap.yaml
+configMapGenerator:
+- name: generated
+  literals:
+  - FOO=bar
+generatorOptions:
+  disableNameSuffixHash: true
+patches:
+- target:
+    kind: ConfigMap
+    labelSelector: "`+labelSelector+`"
+  stage: `+stage+`
+  patch: |-
+    - op: add
+      path: /data/PATCHED
+      value: "true"
+`)
+	th.WriteF("/app/base/configmap.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: listed
+  labels:
+    team: web
+data:
+  FOO: bar
+`)
+}
+
+func TestPatchStageAfterTransformers(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	writePatchStageBase(th, "afterTransformers", "team=web")
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	// The generated ConfigMap gets the label it's
+	// selected by from commonLabels.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  FOO: bar
+  PATCHED: "true"
+kind: ConfigMap
+metadata:
+  labels:
+    team: web
+  name: p-listed
+---
+apiVersion: v1
+data:
+  FOO: bar
+  PATCHED: "true"
+kind: ConfigMap
+metadata:
+  labels:
+    team: web
+  name: p-generated
+`)
+}
+
+func TestPatchStageBeforeGenerators(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	writePatchStageBase(th, "beforeGenerators", "")
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	// Every ConfigMap is selected, but the generated
+	// one doesn't exist yet.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  FOO: bar
+  PATCHED: "true"
+kind: ConfigMap
+metadata:
+  labels:
+    team: web
+  name: p-listed
+---
+apiVersion: v1
+data:
+  FOO: bar
+kind: ConfigMap
+metadata:
+  labels:
+    team: web
+  name: p-generated
+`)
+}
+
+func TestPatchStageBeforeTransformers(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	writePatchStageBase(th, "beforeTransformers", "team=web")
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	// The generated ConfigMap doesn't have the label yet.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  FOO: bar
+  PATCHED: "true"
+kind: ConfigMap
+metadata:
+  labels:
+    team: web
+  name: p-listed
+---
+apiVersion: v1
+data:
+  FOO: bar
+kind: ConfigMap
+metadata:
+  labels:
+    team: web
+  name: p-generated
+`)
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+)
+
+// identityFunction is a KRM function changing nothing.
+const identityFunction = `#!/bin/sh
+cat
+`
+
+func writeCatalogTarget(
+	t *testing.T, dir, catalog string) *kusttest_test.KustTestHarness {
+	pc := plugins.ActivePluginConfig()
+	pc.UntrustedEnabled = false
+	pc.CatalogPath = filepath.Join(dir, plugins.CatalogFileName)
+	err := ioutil.WriteFile(pc.CatalogPath, []byte(catalog), 0600)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th := kusttest_test.NewKustTestHarnessFull(
+		t, "/app", loader.RestrictionRootOnly, pc)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+transformers:
+- identity.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	th.WriteF("/app/identity.yaml", `
+apiVersion: example.com/v1
+kind: Identity
+metadata:
+  name: identity
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: `+filepath.Join(dir, "identity")+`
+`)
+	return th
+}
+
+func TestPluginCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-catalog-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFunction(t, dir, "identity", identityFunction)
+	sum := sha256.Sum256([]byte(identityFunction))
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	publicKey := "publicKey: |\n  " + strings.Replace(
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		"\n", "\n  ", -1) + "\n"
+	entry := `
+plugins:
+- apiVersion: example.com/v1
+  kind: Identity
+  source: https://example.com/identity
+`
+
+	for _, c := range []struct {
+		catalog  string
+		expected string
+	}{
+		{"", "refusing to run plugin Identity, which isn't in " +
+			"the plugin catalog " + filepath.Join(dir, "catalog.yaml") +
+			"; specify --enable-untrusted-plugins to run it anyway"},
+		{entry + "  digest: " + digest, ""},
+		{entry + "  digest: sha256:3b1f", "refusing to run plugin Identity, " +
+			"which has digest " + digest + " rather than the sha256:3b1f " +
+			"of https://example.com/identity in the plugin catalog"},
+		{publicKey + entry + "  digest: " + digest + "\n  signature: " +
+			base64.StdEncoding.EncodeToString(sig), ""},
+		{publicKey + entry + "  digest: " + digest,
+			"refusing to run plugin Identity, which can't be verified: " +
+				"it isn't signed"},
+		{publicKey + entry + "  digest: " + digest + "\n  signature: " +
+			base64.StdEncoding.EncodeToString(sig[1:]),
+			"refusing to run plugin Identity, which can't be verified"},
+	} {
+		th := writeCatalogTarget(t, dir, c.catalog)
+		m, err := th.MakeKustTarget().MakeCustomizedResMap()
+		if c.expected == "" {
+			if err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+			continue
+		}
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if !strings.Contains(err.Error(), c.expected) {
+			t.Fatalf("expected error containing '%s', got '%v'",
+				c.expected, err)
+		}
+	}
+}
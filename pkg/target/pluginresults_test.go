@@ -0,0 +1,122 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resid"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// noticingFunction is a KRM function changing nothing,
+// but reporting a warning about a field.
+const noticingFunction = `#!/bin/sh
+cat
+echo 'results:
+- message: image has no tag
+  severity: warning
+  resourceRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  field:
+    path: spec.template.spec.containers[0].image'
+`
+
+// noticingExecPlugin is an exec plugin changing nothing,
+// but writing a result to the results file.
+const noticingExecPlugin = `#!/bin/sh
+cat
+echo '- severity: info
+  message: nothing to do' > $KUSTOMIZE_PLUGIN_RESULTS
+`
+
+func TestPluginResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-results-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	noticing := writeFunction(t, dir, "noticing", noticingFunction)
+	pluginDir := filepath.Join(dir, "example.com", "v1", "noticingexec")
+	if err := os.MkdirAll(pluginDir, 0700); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	writeFunction(t, pluginDir, "NoticingExec", noticingExecPlugin)
+	pc := plugins.ActivePluginConfig()
+	pc.DirectoryPath = dir
+
+	th := kusttest_test.NewKustTestHarnessFull(
+		t, "/app", loader.RestrictionRootOnly, pc)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+transformers:
+- noticing.yaml
+- noticingExec.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	th.WriteF("/app/noticing.yaml", `
+apiVersion: example.com/v1
+kind: Noticing
+metadata:
+  name: noticing
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: `+noticing+`
+`)
+	th.WriteF("/app/noticingExec.yaml", `
+apiVersion: example.com/v1
+kind: NoticingExec
+metadata:
+  name: noticing
+`)
+	kt := th.MakeKustTarget()
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	id := resid.NewResId(
+		gvk.Gvk{Group: "apps", Version: "v1", Kind: "Deployment"}, "web")
+	expected := []resmap.Result{
+		{
+			Severity:    resmap.SeverityWarning,
+			Message:     "image has no tag",
+			ResourceRef: &id,
+			FieldPath:   "spec.template.spec.containers[0].image",
+		},
+		{
+			Severity: resmap.SeverityInfo,
+			Message:  "nothing to do",
+		},
+	}
+	if !reflect.DeepEqual(kt.Results(), expected) {
+		t.Fatalf("expected results %v, got %v", expected, kt.Results())
+	}
+	if s := kt.Results()[0].String(); s != "apps_v1_Deployment|~X|web "+
+		"spec.template.spec.containers[0].image: image has no tag" {
+		t.Fatalf("unexpected result string '%s'", s)
+	}
+}
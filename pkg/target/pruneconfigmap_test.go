@@ -159,6 +159,7 @@ spec:
 ---
 apiVersion: v1
 data:
+  # Default password is "admin".
   password: YWRtaW4=
   username: jingfang
 kind: Secret
@@ -0,0 +1,176 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeReplicasBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+- worker.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/base/worker.yaml", `
+apiVersion: example.com/v1
+kind: WorkerPool
+metadata:
+  name: workers
+spec:
+  pool:
+    size: 1
+`)
+}
+
+func TestReplicasOfCustomKind(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeReplicasBase(th)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../base
+configurations:
+- replicas-config.yaml
+replicas:
+- name: web
+  count: 3
+- name: workers
+  count: 5
+`)
+	th.WriteF("/app/prod/replicas-config.yaml", `
+replicas:
+- path: spec/pool/size
+  group: example.com
+  kind: WorkerPool
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-web
+spec:
+  replicas: 3
+---
+apiVersion: example.com/v1
+kind: WorkerPool
+metadata:
+  name: prod-workers
+spec:
+  pool:
+    size: 5
+`)
+}
+
+func TestReplicasOfUnconfiguredKind(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeReplicasBase(th)
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+replicas:
+- name: workers
+  count: 5
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"resource with name workers does not match a config") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReplicasOfAutoscaledWorkload(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeReplicasBase(th)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../base
+- hpa.yaml
+replicas:
+- name: web
+  count: 3
+`)
+	th.WriteF("/app/prod/hpa.yaml", `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  minReplicas: 2
+  maxReplicas: 10
+`)
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	for _, expected := range []string{
+		"warning: not setting the replicas of apps_v1_Deployment|~X|prod-web, " +
+			"it's scaled by autoscaling_v2_HorizontalPodAutoscaler|~X|prod-web",
+		"warning: apps_v1_Deployment|~X|prod-web sets spec.replicas, " +
+			"but it's scaled by autoscaling_v2_HorizontalPodAutoscaler|~X|prod-web",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("expected log containing '%s', got '%s'", expected, buf.String())
+		}
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-web
+spec:
+  replicas: 1
+---
+apiVersion: example.com/v1
+kind: WorkerPool
+metadata:
+  name: prod-workers
+spec:
+  pool:
+    size: 1
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: prod-web
+spec:
+  maxReplicas: 10
+  minReplicas: 2
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: prod-web
+`)
+}
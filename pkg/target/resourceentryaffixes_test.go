@@ -0,0 +1,147 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeConfiguredStatefulSetBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- statefulset.yaml
+configMapGenerator:
+- name: my-config
+  literals:
+  - MY_ENV=foo
+generatorOptions:
+  disableNameSuffixHash: true
+`)
+	th.WriteF("/app/base/statefulset.yaml", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  serviceName: my-svc
+  template:
+    spec:
+      containers:
+      - name: app
+        image: my-image
+        envFrom:
+        - configMapRef:
+            name: my-config
+`)
+}
+
+func TestResourceEntryNamePrefix_BaseTwice(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeConfiguredStatefulSetBase(th)
+	th.WriteK("/app/prod", `
+resources:
+- path: ../base
+  namePrefix: primary-
+- path: ../base
+  namePrefix: replica-
+  nameSuffix: -b
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: primary-my-sts
+spec:
+  serviceName: my-svc
+  template:
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: primary-my-config
+        image: my-image
+        name: app
+---
+apiVersion: v1
+data:
+  MY_ENV: foo
+kind: ConfigMap
+metadata:
+  name: primary-my-config
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: replica-my-sts-b
+spec:
+  serviceName: my-svc
+  template:
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: replica-my-config-b
+        image: my-image
+        name: app
+---
+apiVersion: v1
+data:
+  MY_ENV: foo
+kind: ConfigMap
+metadata:
+  name: replica-my-config-b
+`)
+}
+
+func TestResourceEntryNamePrefix_File(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/base")
+	writeConfiguredStatefulSetBase(th)
+	th.WriteK("/app/base", `
+resources:
+- statefulset.yaml
+- path: statefulset.yaml
+  namePrefix: other-
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts
+spec:
+  serviceName: my-svc
+  template:
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: my-config
+        image: my-image
+        name: app
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: other-my-sts
+spec:
+  serviceName: my-svc
+  template:
+    spec:
+      containers:
+      - envFrom:
+        - configMapRef:
+            name: my-config
+        image: my-image
+        name: app
+`)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestResourceGlobs(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- manifests/*.yaml
+- path: extra/cm-?.yaml
+  namePrefix: extra-
+`)
+	for _, name := range []string{"b", "a"} {
+		th.WriteF("/app/manifests/"+name+".yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: `+name+`
+`)
+	}
+	th.WriteF("/app/manifests/notes.txt", "not a resource")
+	th.WriteF("/app/extra/cm-c.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: c
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra-c
+`)
+}
+
+func TestResourceGlobMatchingNothing(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- manifests/*.yaml
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "no files match manifests/*.yaml") {
+		t.Fatalf("expected no match error, got %v", err)
+	}
+}
+
+func TestResourceGlobSkipsKustomizationFiles(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- '*.yaml'
+`)
+	th.WriteF("/app/cm.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`)
+}
+
+func TestResourceGlobMatchingOnlyKustomizationFiles(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+resources:
+- '*.yaml'
+`)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(), "no files but kustomization files match *.yaml") {
+		t.Fatalf("expected no match error, got %v", err)
+	}
+}
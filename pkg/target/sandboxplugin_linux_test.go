@@ -0,0 +1,116 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+)
+
+// probeFunction is a KRM function generating a ConfigMap
+// saying whether it has a secret environment variable,
+// and whether it sees the files of SECRETS.
+const probeFunction = `#!/bin/sh
+cat >/dev/null
+cat <<EOF
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: probe
+  data:
+    env: "${KUSTOMIZE_TEST_SECRET:-unset}"
+    files: "$(ls SECRETS 2>/dev/null || echo hidden)"
+EOF
+`
+
+func TestSandboxedPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-sandbox-test-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	secrets := filepath.Join(dir, "secrets")
+	if err := os.Mkdir(secrets, 0700); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	err = ioutil.WriteFile(filepath.Join(secrets, "token"), nil, 0600)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	probe := writeFunction(t, dir, "probe",
+		strings.Replace(probeFunction, "SECRETS", secrets, 1))
+	os.Setenv("KUSTOMIZE_TEST_SECRET", "s3cr3t")
+	defer os.Unsetenv("KUSTOMIZE_TEST_SECRET")
+
+	for _, c := range []struct {
+		sandboxed bool
+		catalog   string
+		env       string
+		files     string
+	}{
+		{false, "", "s3cr3t", "token"},
+		{true, "", "unset", "hidden"},
+		{true, `
+plugins:
+- apiVersion: example.com/v1
+  kind: Probe
+  sandbox:
+    env:
+    - KUSTOMIZE_TEST_SECRET
+    paths:
+    - ` + secrets + `
+`, "s3cr3t", "token"},
+	} {
+		pc := plugins.ActivePluginConfig()
+		pc.Sandboxed = c.sandboxed
+		pc.CatalogPath = filepath.Join(dir, plugins.CatalogFileName)
+		err := ioutil.WriteFile(pc.CatalogPath, []byte(c.catalog), 0600)
+		if err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		th := kusttest_test.NewKustTestHarnessFull(
+			t, "/app", loader.RestrictionRootOnly, pc)
+		th.WriteK("/app", `
+generators:
+- probe.yaml
+`)
+		th.WriteF("/app/probe.yaml", `
+apiVersion: example.com/v1
+kind: Probe
+metadata:
+  name: probe
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: `+probe+`
+`)
+		m, err := th.MakeKustTarget().MakeCustomizedResMap()
+		if err != nil && strings.Contains(
+			err.Error(), "operation not permitted") {
+			t.Skipf("user namespaces aren't available: %v", err)
+		}
+		if err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  env: `+c.env+`
+  files: `+c.files+`
+kind: ConfigMap
+metadata:
+  name: probe
+`)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func writeSettings(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+settings:
+- name: image-tag
+  targets:
+  - select:
+      kind: Deployment
+      name: web
+    fieldPaths:
+    - spec.template.spec.containers.image
+    options:
+      delimiter: ":"
+      index: 1
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:latest
+`)
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+settings:
+- name: environment
+  targets:
+  - select:
+      kind: Deployment
+    fieldPaths:
+    - metadata.labels.environment
+    create: true
+`)
+}
+
+func TestSettings(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeSettings(th)
+	kt := th.MakeKustTarget()
+	kt.SetValues(map[string]string{
+		"image-tag":   "v1.2.3",
+		"environment": "prod",
+	})
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    environment: prod
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: web:v1.2.3
+        name: web
+`)
+}
+
+func TestSettingsWithoutValues(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeSettings(th)
+	kt := th.MakeKustTarget()
+	kt.SetValues(map[string]string{"environment": "prod"})
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    environment: prod
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: web:latest
+        name: web
+`)
+}
+
+func TestSettingsUnknown(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeSettings(th)
+	kt := th.MakeKustTarget()
+	kt.SetValues(map[string]string{
+		"image-tag": "v1.2.3",
+		"replicas":  "3",
+		"namespace": "prod",
+	})
+	_, err := kt.MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	expected := "no kustomization of the build has the settings " +
+		"[namespace replicas]"
+	if err.Error() != expected {
+		t.Fatalf("expected error '%s', got '%v'", expected, err)
+	}
+}
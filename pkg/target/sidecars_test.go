@@ -0,0 +1,100 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestSidecarsInjectedBeforeImages(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+- statefulset.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`)
+	th.WriteF("/app/base/statefulset.yaml", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  template:
+    spec:
+      containers:
+      - name: postgres
+        image: postgres
+`)
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+sidecars:
+- path: logging.yaml
+  target:
+    kind: Deployment
+images:
+- name: fluent/fluent-bit
+  newTag: "1.3"
+`)
+	th.WriteF("/app/prod/logging.yaml", `
+containers:
+- name: fluent-bit
+  image: fluent/fluent-bit
+  volumeMounts:
+  - name: logs
+    mountPath: /logs
+volumes:
+- name: logs
+  emptyDir: {}
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+      - image: fluent/fluent-bit:1.3
+        name: fluent-bit
+        volumeMounts:
+        - mountPath: /logs
+          name: logs
+      volumes:
+      - emptyDir: {}
+        name: logs
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  template:
+    spec:
+      containers:
+      - image: postgres
+        name: postgres
+`)
+}
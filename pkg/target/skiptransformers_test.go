@@ -0,0 +1,100 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+func TestSkipTransformersAnnotation(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", `
+namePrefix: p-
+namespace: apps
+commonLabels:
+  team: web
+commonAnnotations:
+  owner: alice
+images:
+- name: nginx
+  newTag: "1.17"
+resources:
+- deployments.yaml
+`)
+	th.WriteF("/app/deployments.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: agent
+  namespace: kube-system
+  annotations:
+    kustomize.config.k8s.io/skip: commonLabels, namespace,namePrefix,images
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    owner: alice
+  labels:
+    team: web
+  name: p-web
+  namespace: apps
+spec:
+  selector:
+    matchLabels:
+      team: web
+  template:
+    metadata:
+      annotations:
+        owner: alice
+      labels:
+        team: web
+    spec:
+      containers:
+      - image: nginx:1.17
+        name: nginx
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    kustomize.config.k8s.io/skip: commonLabels, namespace,namePrefix,images
+    owner: alice
+  name: agent
+  namespace: kube-system
+spec:
+  template:
+    metadata:
+      annotations:
+        owner: alice
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+`)
+}
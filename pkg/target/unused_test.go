@@ -0,0 +1,213 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+)
+
+// writeUnused writes kustomizations with patches
+// matching nothing, and files that aren't used.
+func writeUnused(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/app/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	th.WriteF("/app/base/.gitignore", `
+*.swp
+`)
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+- staging
+patches:
+- path: patches/replicas.yaml
+  target:
+    kind: Deployment
+- path: patches/worker.yaml
+  target:
+    kind: Deployment
+    name: worker
+`)
+	th.WriteF("/app/prod/patches/replicas.yaml", `
+- op: add
+  path: /spec
+  value:
+    replicas: 3
+`)
+	th.WriteF("/app/prod/patches/worker.yaml", `
+- op: add
+  path: /spec
+  value:
+    replicas: 5
+`)
+	th.WriteF("/app/prod/patches/old.yaml", `
+- op: remove
+  path: /spec
+`)
+	th.WriteF("/app/prod/NOTES.md", `
+Deploy with care.
+`)
+	th.WriteK("/app/prod/staging", `
+resources:
+- configmap.yaml
+`)
+	th.WriteF("/app/prod/staging/configmap.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`)
+	th.WriteK("/app/prod/canary", `
+resources:
+- ../staging
+`)
+}
+
+// buildLogging builds the target, returning what it logged.
+func buildLogging(t *testing.T, kt *target.KustTarget) string {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	if _, err := kt.MakeCustomizedResMap(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	return buf.String()
+}
+
+// assertWarnings checks that the log holds
+// the expected warnings, and no others.
+func assertWarnings(t *testing.T, logged string, expected []string) {
+	for _, e := range expected {
+		if !strings.Contains(logged, e) {
+			t.Errorf("expected log containing '%s', got '%s'", e, logged)
+		}
+	}
+	if n := strings.Count(logged, "warning: "); n != len(expected) {
+		t.Errorf("expected %d warnings, got '%s'", len(expected), logged)
+	}
+}
+
+func TestUnusedPatchesAndFiles(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeUnused(th)
+	kt := th.MakeKustTarget()
+	kt.WarnUnusedFiles(true)
+	assertWarnings(t, buildLogging(t, kt), []string{
+		"warning: patch patches/worker.yaml of /app/prod matched no resources",
+		"warning: file NOTES.md of /app/prod isn't used by its kustomization",
+		"warning: file patches/old.yaml of /app/prod isn't used by its kustomization",
+	})
+}
+
+func TestUnusedFilesNotWarnedByDefault(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writeUnused(th)
+	assertWarnings(t, buildLogging(t, th.MakeKustTarget()), []string{
+		"warning: patch patches/worker.yaml of /app/prod matched no resources",
+	})
+}
+
+// writePatchedBase writes a base of a deployment and a config map.
+func writePatchedBase(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app/base", `
+resources:
+- resources.yaml
+`)
+	th.WriteF("/app/base/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`)
+}
+
+func TestUnusedPatchesStrategicMerge(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writePatchedBase(th)
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+patchesStrategicMerge:
+- replicas.yaml
+- delete.yaml
+- empty.yaml
+- |-
+  apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+  spec:
+    paused: true
+`)
+	th.WriteF("/app/prod/replicas.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	th.WriteF("/app/prod/delete.yaml", `
+$patch: delete
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`)
+	th.WriteF("/app/prod/empty.yaml", `
+# Nothing to patch yet.
+`)
+	assertWarnings(t, buildLogging(t, th.MakeKustTarget()), []string{
+		"warning: patch empty.yaml of /app/prod matched no resources",
+	})
+}
+
+func TestUnusedPatchesJson6902(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	writePatchedBase(th)
+	th.WriteK("/app/prod", `
+resources:
+- ../base
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web
+  path: replicas.yaml
+- target:
+    version: v1
+    kind: ConfigMap
+    name: settings
+  patch: '[{"op": "add", "path": "/data", "value": {"a": "b"}}]'
+`)
+	th.WriteF("/app/prod/replicas.yaml", `
+- op: add
+  path: /spec
+  value:
+    replicas: 3
+`)
+	assertWarnings(t, buildLogging(t, th.MakeKustTarget()), nil)
+}
@@ -0,0 +1,180 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+)
+
+// replicasValidator is an exec validator reporting an
+// error if a deployment has a single replica, and a
+// warning if it doesn't say.
+const replicasValidator = `#!/bin/sh
+input=$(cat)
+if echo "$input" | grep -q "replicas: 1$"; then
+  echo '- severity: error
+  message: one replica has no redundancy
+  resourceRef:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web'
+elif ! echo "$input" | grep -q "replicas:"; then
+  echo '- severity: warning
+  message: replicas unset'
+else
+  echo '[]'
+fi
+`
+
+// replicasFunction is a KRM function validator reporting
+// an error, and returning no items, which mustn't matter.
+const replicasFunction = `#!/bin/sh
+cat >/dev/null
+echo 'apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items: []
+results:
+- message: replicas must be at least 2
+  severity: error
+- message: consider an autoscaler
+  severity: info'
+`
+
+func writeValidatorTarget(
+	th *kusttest_test.KustTestHarness, path, replicas string) {
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+validators:
+- replicas.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+`+replicas)
+	th.WriteF("/app/replicas.yaml", `
+apiVersion: example.com/v1
+kind: ReplicasValidator
+metadata:
+  name: replicas
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: `+path+`
+`)
+}
+
+func TestValidatorPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-validators-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	validator := writeFunction(t, dir, "validator", replicasFunction)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	writeValidatorTarget(th, validator, "  replicas: 1\n")
+	kt := th.MakeKustTarget()
+	_, err = kt.MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"validation failed:\n  replicas must be at least 2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []resmap.Result{
+		{Severity: resmap.SeverityError, Message: "replicas must be at least 2"},
+		{Severity: resmap.SeverityInfo, Message: "consider an autoscaler"},
+	}
+	if !reflect.DeepEqual(kt.Results(), expected) {
+		t.Fatalf("expected results %v, got %v", expected, kt.Results())
+	}
+	if strings.Contains(buf.String(), "replicas.yaml") {
+		t.Fatalf("unexpected warning: '%s'", buf.String())
+	}
+}
+
+func TestExecValidatorPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kustomize-validators-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	pc := plugins.ActivePluginConfig()
+	pc.DirectoryPath = dir
+	pluginDir := filepath.Join(dir, "example.com", "v1", "replicasvalidator")
+	if err := os.MkdirAll(pluginDir, 0700); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	writeFunction(t, pluginDir, "ReplicasValidator", replicasValidator)
+
+	for _, c := range []struct {
+		replicas string
+		expected string
+		warning  string
+	}{
+		{"  replicas: 1\n", "validation failed:\n  " +
+			"apps_v1_Deployment|~X|web: one replica has no redundancy", ""},
+		{"  replicas: 2\n", "", ""},
+		{"  paused: true\n", "", "replicas unset"},
+	} {
+		th := kusttest_test.NewKustTestHarnessFull(
+			t, "/app", loader.RestrictionRootOnly, pc)
+		writeValidatorTarget(th, "", c.replicas)
+		// Without the function annotation, the
+		// validator is an exec plugin.
+		th.WriteF("/app/replicas.yaml", `
+apiVersion: example.com/v1
+kind: ReplicasValidator
+metadata:
+  name: replicas
+`)
+		kt := th.MakeKustTarget()
+		m, err := kt.MakeCustomizedResMap()
+		if c.expected != "" {
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Fatalf("expected error '%s', got '%v'", c.expected, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Err: %v", err)
+		}
+		th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+`+c.replicas)
+		var warnings []string
+		for _, r := range kt.Results() {
+			warnings = append(warnings, r.Message)
+		}
+		if c.warning != "" && !reflect.DeepEqual(warnings, []string{c.warning}) {
+			t.Fatalf("expected warning '%s', got %v", c.warning, warnings)
+		}
+	}
+}
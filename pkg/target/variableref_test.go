@@ -17,6 +17,7 @@ limitations under the License.
 package target_test
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -774,6 +775,7 @@ metadata:
   annotations:
     prometheus.io/path: _status/vars
     prometheus.io/port: "8080"
+    # Enable automatic monitoring of all instances when Prometheus is running in the cluster.
     prometheus.io/scrape: "true"
     service.alpha.kubernetes.io/tolerate-unready-endpoints: "true"
   labels:
@@ -796,12 +798,17 @@ kind: Service
 metadata:
   labels:
     app: cockroachdb
+  # This service is meant to be used by clients of the database. It exposes a ClusterIP that will
+  # automatically load balance connections to the different database pods.
   name: dev-base-cockroachdb-public
 spec:
   ports:
+  # The main port, served by gRPC, serves Postgres-flavor SQL, internode
+  # traffic and the cli.
   - name: grpc
     port: 26257
     targetPort: 26257
+  # The secondary port serves the UI as well as health and debug endpoints.
   - name: http
     port: 8080
     targetPort: 8080
@@ -856,7 +863,19 @@ spec:
           name: datadir
         - mountPath: /cockroach/cockroach-certs
           name: certs
+      # Init containers are run only once in the lifetime of a pod, before
+      # it's started up for the first time. It has to exit successfully
+      # before the pod's main containers are allowed to start.
       initContainers:
+      # The init-certs container sends a certificate signing request to the
+      # kubernetes cluster.
+      # You can see pending requests using: kubectl get csr
+      # CSRs can be approved using:         kubectl certificate approve <csr name>
+      #
+      # All addresses used to contact a node must be specified in the --addresses arg.
+      #
+      # In addition to the node certificate and key, the init-certs entrypoint will symlink
+      # the cluster CA to the certs directory.
       - command:
         - /bin/ash
         - -ecx
@@ -883,6 +902,8 @@ spec:
         - mountPath: /cockroach-certs
           name: certs
       serviceAccountName: dev-base-cockroachdb
+      # No pre-stop hook is required, a SIGTERM plus some time is all that's
+      # needed for graceful shutdown of a node.
       terminationGracePeriodSeconds: 60
       volumes:
       - name: datadir
@@ -1365,3 +1386,254 @@ spec:
     protocol: TCP
 `)
 }
+
+func TestVariableRefAllFields(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/prod")
+	th.WriteK("/app/base", `
+resources:
+- service.yaml
+- database.yaml
+vars:
+- name: DB_SERVICE
+  objref:
+    apiVersion: v1
+    kind: Service
+    name: db
+varOptions:
+  allFields: true
+`)
+	th.WriteF("/app/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+`)
+	th.WriteF("/app/base/database.yaml", `
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+spec:
+  endpoint: $(DB_SERVICE).svc:5432
+  backup:
+    targets:
+    - $(DB_SERVICE)-backup
+`)
+	th.WriteK("/app/prod", `
+namePrefix: prod-
+resources:
+- ../base
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: prod-db
+---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: prod-db
+spec:
+  backup:
+    targets:
+    - prod-db-backup
+  endpoint: prod-db.svc:5432
+`)
+}
+
+func writeLiteralAndEnvVars(th *kusttest_test.KustTestHarness) {
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+vars:
+- name: LOG_LEVEL
+  literal: debug
+- name: REGION
+  env: KUSTOMIZE_TEST_REGION
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+        args:
+        - --log-level=$(LOG_LEVEL)
+        - --region=$(REGION)
+`)
+}
+
+func TestVariableRefLiteralAndEnv(t *testing.T) {
+	os.Setenv("KUSTOMIZE_TEST_REGION", "eu-west-1")
+	defer os.Unsetenv("KUSTOMIZE_TEST_REGION")
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeLiteralAndEnvVars(th)
+	kt := th.MakeKustTarget()
+	kt.AllowEnvVars([]string{"KUSTOMIZE_TEST_REGION"})
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - args:
+        - --log-level=debug
+        - --region=eu-west-1
+        image: web
+        name: web
+`)
+}
+
+func TestVariableRefEnvNotAllowed(t *testing.T) {
+	os.Setenv("KUSTOMIZE_TEST_REGION", "eu-west-1")
+	defer os.Unsetenv("KUSTOMIZE_TEST_REGION")
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeLiteralAndEnvVars(th)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"var 'REGION' reads environment variable KUSTOMIZE_TEST_REGION, "+
+			"which isn't allowed") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestVariableRefEnvNotSet(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	writeLiteralAndEnvVars(th)
+	kt := th.MakeKustTarget()
+	kt.AllowEnvVars([]string{"KUSTOMIZE_TEST_REGION"})
+	_, err := kt.MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"environment variable KUSTOMIZE_TEST_REGION of var 'REGION' isn't set") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestVariableRefGeneratedData(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlay")
+	th.WriteK("/app/base", `
+configMapGenerator:
+- name: app-config
+  literals:
+  - DB_HOST=db.local
+  - app.properties=verbose=true
+secretGenerator:
+- name: creds
+  literals:
+  - user=admin
+vars:
+- name: CONFIG_NAME
+  objref:
+    kind: ConfigMap
+    name: app-config
+    apiVersion: v1
+- name: DB_HOST
+  objref:
+    kind: ConfigMap
+    name: app-config
+    apiVersion: v1
+  fieldref:
+    fieldpath: data.DB_HOST
+- name: PROPERTIES
+  objref:
+    kind: ConfigMap
+    name: app-config
+    apiVersion: v1
+  fieldref:
+    fieldpath: data[app.properties]
+- name: DB_USER
+  objref:
+    kind: Secret
+    name: creds
+    apiVersion: v1
+  fieldref:
+    fieldpath: data.user
+resources:
+- pod.yaml
+`)
+	th.WriteF("/app/base/pod.yaml", `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    image: app
+    args:
+    - --config=$(CONFIG_NAME)
+    - --db-host=$(DB_HOST)
+    - --db-user=$(DB_USER)
+    - --properties=$(PROPERTIES)
+`)
+	th.WriteK("/app/overlay", `
+namePrefix: prod-
+resources:
+- ../base
+configMapGenerator:
+- name: app-config
+  behavior: merge
+  literals:
+  - DB_HOST=db.prod
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: prod-app
+spec:
+  containers:
+  - args:
+    - --config=prod-app-config-tc2dggb7c7
+    - --db-host=db.prod
+    - --db-user=admin
+    - --properties=verbose=true
+    image: app
+    name: app
+---
+apiVersion: v1
+data:
+  DB_HOST: db.prod
+  app.properties: verbose=true
+kind: ConfigMap
+metadata:
+  annotations: {}
+  labels: {}
+  name: prod-app-config-tc2dggb7c7
+---
+apiVersion: v1
+data:
+  user: YWRtaW4=
+kind: Secret
+metadata:
+  name: prod-creds-9kghhh8tf9
+type: Opaque
+`)
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/k8sdeps/kunstruct"
+	"sigs.k8s.io/kustomize/v3/k8sdeps/transformer"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/git"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/target"
+	"sigs.k8s.io/kustomize/v3/pkg/validators"
+)
+
+const vaultSecretKustomization = `
+namePrefix: prod-
+vaultSecretGenerator:
+- name: db
+  refs:
+  - path: secret/data/db
+    key: password
+`
+
+// fakeVault serves a secret, counting the requests
+// for it in calls.
+func fakeVault(calls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			*calls++
+			w.Write([]byte(`{"data": {
+  "data": {"password": "hunter2"}, "metadata": {}}}`))
+		}))
+}
+
+// setVaultEnv sets the address and token of
+// the vault, returning a func resetting them.
+func setVaultEnv(address string) func() {
+	os.Setenv("VAULT_ADDR", address)
+	os.Setenv("VAULT_TOKEN", "root")
+	return func() {
+		os.Unsetenv("VAULT_ADDR")
+		os.Unsetenv("VAULT_TOKEN")
+	}
+}
+
+func TestVaultSecretGenerator(t *testing.T) {
+	calls := 0
+	vault := fakeVault(&calls)
+	defer vault.Close()
+	defer setVaultEnv(vault.URL)()
+
+	pc := plugins.DefaultPluginConfig()
+	pc.ExternalSecretsEnabled = true
+	th := kusttest_test.NewKustTestHarnessFull(
+		t, "/app", loader.RestrictionRootOnly, pc)
+	th.WriteK("/app", vaultSecretKustomization)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  password: aHVudGVyMg==
+kind: Secret
+metadata:
+  name: prod-db-49fk5tg267
+type: Opaque
+`)
+}
+
+func TestVaultSecretGeneratorNotEnabled(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app")
+	th.WriteK("/app", vaultSecretKustomization)
+	_, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"builtin VaultSecretGenerator reads external secrets, "+
+			"which requires --enable-external-secrets") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// makeVaultTarget returns a target of the kustomization
+// the loader holds, with external secrets enabled.
+func makeVaultTarget(ldr ifc.Loader) (*target.KustTarget, error) {
+	rf := resmap.NewFactory(resource.NewFactory(
+		kunstruct.NewKunstructuredFactoryImpl()), nil)
+	pc := plugins.DefaultPluginConfig()
+	pc.ExternalSecretsEnabled = true
+	return target.NewKustTarget(
+		ldr, rf, transformer.NewFactoryImpl(), plugins.NewLoader(pc, rf))
+}
+
+func TestVaultSecretGeneratorAddress(t *testing.T) {
+	calls := 0
+	attacker := fakeVault(&calls)
+	defer attacker.Close()
+	defer setVaultEnv("https://vault.example.com")()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/app/kustomization.yaml", []byte(`
+vaultSecretGenerator:
+- name: db
+  address: `+attacker.URL+`
+  refs:
+  - path: secret/data/db
+    key: password
+`))
+	ldr, err := loader.NewLoader(
+		loader.RestrictionRootOnly, validators.MakeFakeValidator(),
+		"/app", fSys)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	_, err = makeVaultTarget(ldr)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "address"`) {
+		t.Fatalf("expected an unknown address field, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected no request to the kustomization's address")
+	}
+}
+
+func TestVaultSecretGeneratorRemoteBase(t *testing.T) {
+	calls := 0
+	vault := fakeVault(&calls)
+	defer vault.Close()
+	defer setVaultEnv(vault.URL)()
+
+	fSys := fs.MakeFsInMemory()
+	fSys.WriteFile("/tmp/base/kustomization.yaml",
+		[]byte(vaultSecretKustomization))
+	ldr, err := loader.NewLoaderUsingCloner(
+		loader.RestrictionRootOnly, validators.MakeFakeValidator(),
+		"github.com/someOrg/someRepo/base", fSys,
+		git.DoNothingCloner(fs.ConfirmedDir("/tmp")))
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	kt, err := makeVaultTarget(ldr)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	_, err = kt.MakeCustomizedResMap()
+	if err == nil || !strings.Contains(err.Error(),
+		"remote kustomizations cannot read environment variables") {
+		t.Fatalf("expected the remote base to be refused, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected the token not to be sent")
+	}
+}
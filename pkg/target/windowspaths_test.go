@@ -0,0 +1,53 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+)
+
+// Kustomizations written on Windows may separate
+// the elements of paths with backslashes.
+func TestBackslashesInPaths(t *testing.T) {
+	th := kusttest_test.NewKustTestHarness(t, "/app/overlays/prod")
+	th.WriteK("/app/base", `
+resources:
+- manifests\service.yaml
+`)
+	th.WriteF("/app/base/manifests/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`)
+	th.WriteK("/app/overlays/prod", `
+namePrefix: p-
+resources:
+- ..\..\base
+patchesStrategicMerge:
+- patches\service.yaml
+`)
+	th.WriteF("/app/overlays/prod/patches/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+  labels:
+    env: prod
+`)
+	m, err := th.MakeKustTarget().MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    env: prod
+  name: p-myService
+`)
+}
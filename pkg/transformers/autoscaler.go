@@ -0,0 +1,53 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// Autoscaler returns the HorizontalPodAutoscaler in m whose
+// scaleTargetRef refers to r, or nil if there's none.  The
+// reference may use the current or the original name of r,
+// since references are fixed only after all transformers ran.
+// An autoscaled workload shouldn't have its spec.replicas set,
+// since applying it would undo the autoscaler's scaling.
+func Autoscaler(m resmap.ResMap, r *resource.Resource) *resource.Resource {
+	for _, hpa := range m.Resources() {
+		if hpa.GetKind() != "HorizontalPodAutoscaler" ||
+			hpa.GetNamespace() != r.GetNamespace() {
+			continue
+		}
+		ref, ok := hpa.Map()["spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref, ok = ref["scaleTargetRef"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ref["kind"] != r.GetKind() || (ref["name"] != r.GetName() &&
+			ref["name"] != r.GetOriginalName()) {
+			continue
+		}
+		if v, ok := ref["apiVersion"].(string); ok &&
+			group(v) != r.GetGvk().Group {
+			continue
+		}
+		return hpa
+	}
+	return nil
+}
+
+// group returns the group of the given apiVersion,
+// the empty string for the core group.
+func group(apiVersion string) string {
+	if i := strings.Index(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i]
+	}
+	return ""
+}
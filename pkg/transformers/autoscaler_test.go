@@ -0,0 +1,57 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmaptest"
+)
+
+func autoscaler(name, apiVersion, kind, target string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "autoscaling/v2",
+		"kind":       "HorizontalPodAutoscaler",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       kind,
+				"name":       target,
+			},
+		},
+	}
+}
+
+func TestAutoscaler(t *testing.T) {
+	m := resmaptest_test.NewRmBuilder(t, rf).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web"},
+		}).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "StatefulSet",
+			"metadata":   map[string]interface{}{"name": "db"},
+		}).
+		Add(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "worker"},
+		}).
+		Add(autoscaler("web", "apps/v1", "Deployment", "web")).
+		Add(autoscaler("db", "apps/v1", "Deployment", "db")).
+		Add(autoscaler("worker", "example.com/v1", "Deployment", "worker")).
+		ResMap()
+	if hpa := Autoscaler(m, m.GetByIndex(0)); hpa != m.GetByIndex(3) {
+		t.Fatalf("expected web to be scaled by hpa web, got %v", hpa)
+	}
+	if hpa := Autoscaler(m, m.GetByIndex(1)); hpa != nil {
+		t.Fatalf("expected db not to be scaled, got %v", hpa)
+	}
+	if hpa := Autoscaler(m, m.GetByIndex(2)); hpa != nil {
+		t.Fatalf("expected worker not to be scaled, got %v", hpa)
+	}
+}
@@ -17,7 +17,8 @@ limitations under the License.
 package defaultconfig
 
 const (
-	// imageFieldSpecs is left empty since `containers` and `initContainers`
-	// of *ANY* kind in *ANY* path are builtin supported in code
+	// imageFieldSpecs is left empty since `containers`, `initContainers`
+	// and `ephemeralContainers` of *ANY* kind in *ANY* path are builtin
+	// supported in code
 	imagesFieldSpecs = ``
 )
@@ -129,6 +129,12 @@ const (
 	// "x-kubernetes-object-ref-name-key": "name"
 	// default is "name"
 	xNameKey = "x-kubernetes-object-ref-name-key"
+
+	// "x-kubernetes-patch-strategy": "merge"
+	xPatchStrategy = "x-kubernetes-patch-strategy"
+
+	// "x-kubernetes-patch-merge-key": <key name>
+	xPatchMergeKey = "x-kubernetes-patch-merge-key"
 )
 
 // loadCrdIntoConfig loads a CRD spec into a TransformerConfig
@@ -183,6 +189,24 @@ func loadCrdIntoConfig(
 				}
 			}
 		}
+		strategy, _ := property.Extensions.GetString(xPatchStrategy)
+		mergeKey, ok := property.Extensions.GetString(xPatchMergeKey)
+		if ok && hasMergeStrategy(strategy) {
+			err = theConfig.AddPatchMergeKey(PatchMergeKey{
+				Gvk:      theGvk,
+				Path:     strings.Join(append(path, propName), "/"),
+				MergeKey: mergeKey,
+			})
+			if err != nil {
+				return
+			}
+		}
+		if property.Items != nil && property.Items.Schema != nil &&
+			property.Items.Schema.Ref.GetURL() != nil {
+			loadCrdIntoConfig(
+				theConfig, theGvk, theMap,
+				property.Items.Schema.Ref.String(), append(path, propName))
+		}
 		if property.Ref.GetURL() != nil {
 			loadCrdIntoConfig(
 				theConfig, theGvk, theMap,
@@ -192,6 +216,17 @@ func loadCrdIntoConfig(
 	return nil
 }
 
+// hasMergeStrategy returns true if the comma separated
+// patch strategies include "merge".
+func hasMergeStrategy(strategies string) bool {
+	for _, s := range strings.Split(strategies, ",") {
+		if s == "merge" {
+			return true
+		}
+	}
+	return false
+}
+
 func makeFs(in gvk.Gvk, path []string) FieldSpec {
 	return FieldSpec{
 		CreateIfNotPresent: false,
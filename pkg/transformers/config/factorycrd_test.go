@@ -127,14 +127,40 @@ If it is not set we generate a secret dynamically",
 					"x-kubernetes-object-ref-api-version": "v1",
 					"x-kubernetes-object-ref-kind": "Secret",
 					"$ref": "k8s.io/api/core/v1.LocalObjectReference"
+				},
+				"workers": {
+					"type": "array",
+					"x-kubernetes-patch-strategy": "merge",
+					"x-kubernetes-patch-merge-key": "name",
+					"items": {
+						"$ref": "github.com/example/pkg/apis/jingfang/v1beta1.Worker"
+					}
 				}
 			}
 		},
 		"Dependencies": [
 			"github.com/example/pkg/apis/jingfang/v1beta1.Bee",
+			"github.com/example/pkg/apis/jingfang/v1beta1.Worker",
 			"k8s.io/api/core/v1.LocalObjectReference"
 		]
 	},
+	"github.com/example/pkg/apis/jingfang/v1beta1.Worker": {
+		"Schema": {
+			"description": "Worker defines a worker of MyKind",
+			"properties": {
+				"ports": {
+					"type": "array",
+					"x-kubernetes-patch-strategy": "merge,retainKeys",
+					"x-kubernetes-patch-merge-key": "port"
+				},
+				"volumes": {
+					"type": "array",
+					"x-kubernetes-patch-merge-key": "name"
+				}
+			}
+		},
+		"Dependencies": []
+	},
 	"github.com/example/pkg/apis/jingfang/v1beta1.MyKindStatus": {
 		"Schema": {
 			"description": "MyKindStatus defines the observed state of MyKind"
@@ -180,6 +206,18 @@ func TestLoadCRDs(t *testing.T) {
 
 	expectedTc := &TransformerConfig{
 		NameReference: nbrs,
+		PatchMergeKeys: []PatchMergeKey{
+			{
+				Gvk:      gvk.Gvk{Kind: "MyKind"},
+				Path:     "spec/workers",
+				MergeKey: "name",
+			},
+			{
+				Gvk:      gvk.Gvk{Kind: "MyKind"},
+				Path:     "spec/workers/ports",
+				MergeKey: "port",
+			},
+		},
 	}
 
 	actualTc, err := LoadConfigFromCRDs(makeLoader(t), []string{"crd.json"})
@@ -0,0 +1,136 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// "x-kubernetes-group-version-kind":
+	//   [{"group": <group>, "version": <version>, "kind": <kind>}]
+	xGroupVersionKind = "x-kubernetes-group-version-kind"
+
+	definitionsPrefix = "#/definitions/"
+)
+
+// LoadConfigFromOpenAPI reads the patch strategies and merge
+// keys of the types defined in the OpenAPI (swagger 2.0) schema
+// at the given path into a TransformerConfig.  Only definitions
+// carrying an x-kubernetes-group-version-kind are treated as
+// types of objects that may be patched.
+func LoadConfigFromOpenAPI(
+	ldr ifc.Loader, path string) (*TransformerConfig, error) {
+	tc := MakeEmptyConfig()
+	if path == "" {
+		return tc, nil
+	}
+	content, err := ldr.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > 0 && content[0] != '{' {
+		content, err = yaml.YAMLToJSON(content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var swagger spec.Swagger
+	err = json.Unmarshal(content, &swagger)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "unable to parse open API schema from '%s'", path)
+	}
+	for _, def := range swagger.Definitions {
+		for _, x := range gvksOf(def) {
+			err = loadSchemaIntoConfig(
+				tc, x, swagger.Definitions, def, []string{}, map[string]bool{})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	tc.sortFields()
+	return tc, nil
+}
+
+// gvksOf returns the Gvks listed in the definition's
+// x-kubernetes-group-version-kind extension.
+func gvksOf(def spec.Schema) []gvk.Gvk {
+	list, ok := def.Extensions[xGroupVersionKind].([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []gvk.Gvk
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		g, _ := m["group"].(string)
+		v, _ := m["version"].(string)
+		k, _ := m["kind"].(string)
+		result = append(result, gvk.Gvk{Group: g, Version: v, Kind: k})
+	}
+	return result
+}
+
+// loadSchemaIntoConfig adds the merge keys of the lists found
+// in the given schema, at the given path, to the config.
+// Definitions on the stack of references being followed are
+// in seen, so that recursive types are visited once.
+func loadSchemaIntoConfig(
+	theConfig *TransformerConfig, theGvk gvk.Gvk, defs spec.Definitions,
+	schema spec.Schema, path []string, seen map[string]bool) error {
+	for propName, property := range schema.Properties {
+		propPath := append(path[:len(path):len(path)], propName)
+		strategy, _ := property.Extensions.GetString(xPatchStrategy)
+		mergeKey, ok := property.Extensions.GetString(xPatchMergeKey)
+		if ok && hasMergeStrategy(strategy) {
+			err := theConfig.AddPatchMergeKey(PatchMergeKey{
+				Gvk:      theGvk,
+				Path:     strings.Join(propPath, "/"),
+				MergeKey: mergeKey,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		sub := property
+		if property.Items != nil && property.Items.Schema != nil {
+			sub = *property.Items.Schema
+		}
+		ref := strings.TrimPrefix(sub.Ref.String(), definitionsPrefix)
+		if ref != "" {
+			if seen[ref] {
+				continue
+			}
+			def, ok := defs[ref]
+			if !ok {
+				continue
+			}
+			seen[ref] = true
+			err := loadSchemaIntoConfig(
+				theConfig, theGvk, defs, def, propPath, seen)
+			delete(seen, ref)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := loadSchemaIntoConfig(
+			theConfig, theGvk, defs, sub, propPath, seen)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/internal/loadertest"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+)
+
+const openAPIContent = `
+definitions:
+  com.example.v1.MyKind:
+    x-kubernetes-group-version-kind:
+    - group: example.com
+      version: v1
+      kind: MyKind
+    properties:
+      apiVersion:
+        type: string
+      kind:
+        type: string
+      spec:
+        $ref: '#/definitions/com.example.v1.MyKindSpec'
+  com.example.v1.MyKindSpec:
+    properties:
+      workers:
+        type: array
+        x-kubernetes-patch-strategy: merge
+        x-kubernetes-patch-merge-key: name
+        items:
+          $ref: '#/definitions/com.example.v1.Worker'
+      tags:
+        type: array
+        x-kubernetes-patch-merge-key: name
+  com.example.v1.Worker:
+    properties:
+      ports:
+        type: array
+        x-kubernetes-patch-strategy: merge
+        x-kubernetes-patch-merge-key: port
+      helper:
+        $ref: '#/definitions/com.example.v1.Worker'
+`
+
+func TestLoadConfigFromOpenAPI(t *testing.T) {
+	ldr := loadertest.NewFakeLoader("/testpath")
+	err := ldr.AddFile("/testpath/schema.yaml", []byte(openAPIContent))
+	if err != nil {
+		t.Fatalf("Failed to setup fake ldr.")
+	}
+	myKind := gvk.Gvk{Group: "example.com", Version: "v1", Kind: "MyKind"}
+	expectedTc := &TransformerConfig{
+		PatchMergeKeys: []PatchMergeKey{
+			{Gvk: myKind, Path: "spec/workers", MergeKey: "name"},
+			{Gvk: myKind, Path: "spec/workers/ports", MergeKey: "port"},
+		},
+	}
+	actualTc, err := LoadConfigFromOpenAPI(ldr, "schema.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error:%v", err)
+	}
+	if !reflect.DeepEqual(actualTc, expectedTc) {
+		t.Fatalf("expected\n %v\n but got\n %v\n", expectedTc, actualTc)
+	}
+}
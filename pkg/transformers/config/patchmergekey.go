@@ -0,0 +1,84 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+)
+
+// PatchMergeKey declares that strategic merge patches
+// should merge, rather than replace, the list at a field
+// path in an object of a type with no built-in schema,
+// e.g. a custom resource, matching list elements by the
+// value of the merge key.
+//
+// For example, to merge the workers of a 'MyKind' object
+// by name, the way containers are merged in a Deployment:
+// {
+//   kind: MyKind
+//   path: spec/workers
+//   mergeKey: name
+// }
+type PatchMergeKey struct {
+	gvk.Gvk  `json:",inline,omitempty" yaml:",inline,omitempty"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	MergeKey string `json:"mergeKey,omitempty" yaml:"mergeKey,omitempty"`
+}
+
+func (k PatchMergeKey) String() string {
+	return fmt.Sprintf("%s:%s:%s", k.Gvk.String(), k.Path, k.MergeKey)
+}
+
+// MergeKeysFor returns the merge keys that apply
+// to an object with the given Gvk, by field path.
+func MergeKeysFor(keys []PatchMergeKey, x gvk.Gvk) map[string]string {
+	result := make(map[string]string)
+	for _, k := range keys {
+		if x.IsSelected(&k.Gvk) {
+			result[k.Path] = k.MergeKey
+		}
+	}
+	return result
+}
+
+type pmkSlice []PatchMergeKey
+
+func (s pmkSlice) Len() int      { return len(s) }
+func (s pmkSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s pmkSlice) Less(i, j int) bool {
+	if !s[i].Gvk.Equals(s[j].Gvk) {
+		return s[i].Gvk.IsLessThan(s[j].Gvk)
+	}
+	return s[i].Path < s[j].Path
+}
+
+// mergeAll merges the argument into this, returning the result.
+// Items already present are ignored.
+// Items for the same field with a different merge key
+// result in an error.
+func (s pmkSlice) mergeAll(incoming pmkSlice) (result pmkSlice, err error) {
+	result = s
+	for _, x := range incoming {
+		result, err = result.mergeOne(x)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s pmkSlice) mergeOne(x PatchMergeKey) (pmkSlice, error) {
+	for _, k := range s {
+		if k.Gvk.Equals(x.Gvk) && k.Path == x.Path {
+			if k.MergeKey != x.MergeKey {
+				return nil, fmt.Errorf(
+					"conflicting merge keys %s and %s", k, x)
+			}
+			return s, nil
+		}
+	}
+	return append(s, x), nil
+}
@@ -21,6 +21,7 @@ package config
 import (
 	"log"
 	"sort"
+	"strings"
 
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config/defaultconfig"
 )
@@ -36,6 +37,7 @@ type TransformerConfig struct {
 	VarReference      fsSlice  `json:"varReference,omitempty" yaml:"varReference,omitempty"`
 	Images            fsSlice  `json:"images,omitempty" yaml:"images,omitempty"`
 	Replicas          fsSlice  `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	PatchMergeKeys    pmkSlice `json:"patchMergeKeys,omitempty" yaml:"patchMergeKeys,omitempty"`
 }
 
 // MakeEmptyConfig returns an empty TransformerConfig object
@@ -63,6 +65,7 @@ func (t *TransformerConfig) sortFields() {
 	sort.Sort(t.VarReference)
 	sort.Sort(t.Images)
 	sort.Sort(t.Replicas)
+	sort.Sort(t.PatchMergeKeys)
 }
 
 // AddPrefixFieldSpec adds a FieldSpec to NamePrefix
@@ -83,12 +86,41 @@ func (t *TransformerConfig) AddLabelFieldSpec(fs FieldSpec) (err error) {
 	return err
 }
 
+// LabelFieldSpecs returns the CommonLabels field specs,
+// less the selectors unless includeSelectors, and less
+// the templates, e.g. pod templates, unless includeSelectors
+// or includeTemplates.  The fields of templates, like the
+// field of the object's own labels, end with metadata/labels.
+func (t *TransformerConfig) LabelFieldSpecs(
+	includeSelectors, includeTemplates bool) []FieldSpec {
+	if includeSelectors {
+		return t.CommonLabels
+	}
+	var result []FieldSpec
+	for _, fs := range t.CommonLabels {
+		switch {
+		case fs.Path == "metadata/labels":
+		case includeTemplates && strings.HasSuffix(fs.Path, "/metadata/labels"):
+		default:
+			continue
+		}
+		result = append(result, fs)
+	}
+	return result
+}
+
 // AddAnnotationFieldSpec adds a FieldSpec to CommonAnnotations
 func (t *TransformerConfig) AddAnnotationFieldSpec(fs FieldSpec) (err error) {
 	t.CommonAnnotations, err = t.CommonAnnotations.mergeOne(fs)
 	return err
 }
 
+// AddPatchMergeKey adds a PatchMergeKey to PatchMergeKeys
+func (t *TransformerConfig) AddPatchMergeKey(k PatchMergeKey) (err error) {
+	t.PatchMergeKeys, err = t.PatchMergeKeys.mergeOne(k)
+	return err
+}
+
 // AddNamereferenceFieldSpec adds a NameBackReferences to NameReference
 func (t *TransformerConfig) AddNamereferenceFieldSpec(
 	nbrs NameBackReferences) (err error) {
@@ -141,6 +173,10 @@ func (t *TransformerConfig) Merge(input *TransformerConfig) (
 	if err != nil {
 		return nil, err
 	}
+	merged.PatchMergeKeys, err = t.PatchMergeKeys.mergeAll(input.PatchMergeKeys)
+	if err != nil {
+		return nil, err
+	}
 	merged.sortFields()
 	return merged, nil
 }
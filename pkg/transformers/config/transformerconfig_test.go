@@ -180,3 +180,32 @@ func TestMerge(t *testing.T) {
 		t.Fatalf("expected: %v\n but got: %v\n", cfga, actual)
 	}
 }
+
+func TestLabelFieldSpecs(t *testing.T) {
+	own := FieldSpec{Path: "metadata/labels", CreateIfNotPresent: true}
+	selector := FieldSpec{
+		Gvk:  gvk.Gvk{Kind: "Deployment"},
+		Path: "spec/selector/matchLabels", CreateIfNotPresent: true}
+	template := FieldSpec{
+		Gvk:  gvk.Gvk{Kind: "Deployment"},
+		Path: "spec/template/metadata/labels", CreateIfNotPresent: true}
+	cfg := &TransformerConfig{
+		CommonLabels: []FieldSpec{own, selector, template},
+	}
+	for _, tc := range []struct {
+		includeSelectors bool
+		includeTemplates bool
+		expected         []FieldSpec
+	}{
+		{false, false, []FieldSpec{own}},
+		{false, true, []FieldSpec{own, template}},
+		{true, false, []FieldSpec{own, selector, template}},
+		{true, true, []FieldSpec{own, selector, template}},
+	} {
+		actual := cfg.LabelFieldSpecs(tc.includeSelectors, tc.includeTemplates)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("selectors %v, templates %v: expected %v, got %v",
+				tc.includeSelectors, tc.includeTemplates, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+// PodSpecs returns the pod specs in the given object, i.e.
+// the maps holding a containers list, at any depth.  E.g.
+// the spec of a Pod, the template spec of a Deployment or
+// the job template's template spec of a CronJob.
+// The pod specs are shared with the object, so changing
+// them changes the object.
+func PodSpecs(obj map[string]interface{}) []map[string]interface{} {
+	if _, ok := obj["containers"].([]interface{}); ok {
+		return []map[string]interface{}{obj}
+	}
+	var result []map[string]interface{}
+	for _, v := range obj {
+		switch typedV := v.(type) {
+		case map[string]interface{}:
+			result = append(result, PodSpecs(typedV)...)
+		case []interface{}:
+			for _, item := range typedV {
+				if typedItem, ok := item.(map[string]interface{}); ok {
+					result = append(result, PodSpecs(typedItem)...)
+				}
+			}
+		}
+	}
+	return result
+}
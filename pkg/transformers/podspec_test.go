@@ -0,0 +1,76 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestPodSpecs(t *testing.T) {
+	for name, tc := range map[string]struct {
+		obj      string
+		expected []string
+	}{
+		"pod": {
+			obj: `
+kind: Pod
+spec:
+  containers:
+  - name: app
+`,
+			expected: []string{"app"},
+		},
+		"deployment": {
+			obj: `
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+`,
+			expected: []string{"web"},
+		},
+		"cronJob": {
+			obj: `
+kind: CronJob
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+`,
+			expected: []string{"backup"},
+		},
+		"configMap": {
+			obj: `
+kind: ConfigMap
+data:
+  containers: none
+`,
+		},
+	} {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(tc.obj), &obj); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		specs := PodSpecs(obj)
+		if len(specs) != len(tc.expected) {
+			t.Fatalf("%s: expected %d pod specs, got %v",
+				name, len(tc.expected), specs)
+		}
+		for i, spec := range specs {
+			container := spec["containers"].([]interface{})[0]
+			actual := container.(map[string]interface{})["name"]
+			if actual != tc.expected[i] {
+				t.Fatalf("%s: expected container %s, got %v",
+					name, tc.expected[i], actual)
+			}
+		}
+	}
+}
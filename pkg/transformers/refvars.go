@@ -27,6 +27,7 @@ type RefVarTransformer struct {
 	varMap            map[string]interface{}
 	replacementCounts map[string]int
 	fieldSpecs        []config.FieldSpec
+	allFields         bool
 	mappingFunc       func(string) interface{}
 }
 
@@ -41,6 +42,36 @@ func NewRefVarTransformer(
 	}
 }
 
+// NewAllFieldsRefVarTransformer returns a new RefVarTransformer
+// that replaces $(VAR) style variables with values in all the
+// string fields of the resources, wherever they are.
+func NewAllFieldsRefVarTransformer(
+	varMap map[string]interface{}) *RefVarTransformer {
+	return &RefVarTransformer{
+		varMap:    varMap,
+		allFields: true,
+	}
+}
+
+// replaceVarsInAllFields returns the given value with the
+// variables in all the strings it holds, at any depth,
+// expanded.  Maps and lists are changed in place.
+func (rv *RefVarTransformer) replaceVarsInAllFields(in interface{}) interface{} {
+	switch typedIn := in.(type) {
+	case string:
+		return expansion.Expand(typedIn, rv.mappingFunc)
+	case map[string]interface{}:
+		for k, v := range typedIn {
+			typedIn[k] = rv.replaceVarsInAllFields(v)
+		}
+	case []interface{}:
+		for i, v := range typedIn {
+			typedIn[i] = rv.replaceVarsInAllFields(v)
+		}
+	}
+	return in
+}
+
 // replaceVars accepts as 'in' a string, or string array, which can have
 // embedded instances of $VAR style variables, e.g. a container command string.
 // The function returns the string with the variables expanded to their final
@@ -106,6 +137,10 @@ func (rv *RefVarTransformer) Transform(m resmap.ResMap) error {
 	rv.mappingFunc = expansion.MappingFuncFor(
 		rv.replacementCounts, rv.varMap)
 	for _, res := range m.Resources() {
+		if rv.allFields {
+			rv.replaceVarsInAllFields(res.Map())
+			continue
+		}
 		for _, fieldSpec := range rv.fieldSpecs {
 			if res.OrgId().IsSelected(&fieldSpec.Gvk) {
 				if err := MutateField(
@@ -131,3 +131,48 @@ func TestVarRef(t *testing.T) {
 		})
 	}
 }
+
+func TestVarRefAllFields(t *testing.T) {
+	m := resmaptest_test.NewRmBuilder(t, rf).
+		Add(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Database",
+			"metadata": map[string]interface{}{
+				"name": "db",
+			},
+			"spec": map[string]interface{}{
+				"service": "$(SERVICE)",
+				"replicas": []interface{}{
+					map[string]interface{}{"host": "$(SERVICE)-0", "port": 5432},
+				},
+				"size": "$(SIZE)",
+			}}).ResMap()
+	expected := resmaptest_test.NewRmBuilder(t, rf).
+		Add(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Database",
+			"metadata": map[string]interface{}{
+				"name": "db",
+			},
+			"spec": map[string]interface{}{
+				"service": "prod-db",
+				"replicas": []interface{}{
+					map[string]interface{}{"host": "prod-db-0", "port": 5432},
+				},
+				"size": int64(3),
+			}}).ResMap()
+	tr := NewAllFieldsRefVarTransformer(map[string]interface{}{
+		"SERVICE": "prod-db",
+		"SIZE":    int64(3),
+		"UNUSED":  "x",
+	})
+	if err := tr.Transform(m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("actual doesn't match expected: %v", expected.ErrorIfNotEqualLists(m))
+	}
+	if unused := tr.UnusedVars(); !reflect.DeepEqual(unused, []string{"UNUSED"}) {
+		t.Fatalf("expected UNUSED to be unused, got %v", unused)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+)
+
+// SkipAnnotation holds a comma separated list of kustomization
+// fields, e.g. "commonLabels,namespace". The builtin transformers
+// that implement those fields leave the annotated resource alone.
+const SkipAnnotation = "kustomize.config.k8s.io/skip"
+
+// Skips returns true if the resource opts out of the
+// builtin transformer implementing the given field.
+func Skips(r *resource.Resource, field string) bool {
+	value, ok := r.GetAnnotations()[SkipAnnotation]
+	if !ok {
+		return false
+	}
+	for _, f := range strings.Split(value, ",") {
+		if strings.TrimSpace(f) == field {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutSkipping returns the resources of m that don't opt out
+// of the builtin transformer implementing the given field.
+// The resources are shared with m, so transforming the result
+// transforms them in m too.
+func WithoutSkipping(m resmap.ResMap, field string) resmap.ResMap {
+	var kept []*resource.Resource
+	for _, r := range m.Resources() {
+		if !Skips(r, field) {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == m.Size() {
+		return m
+	}
+	result := resmap.New()
+	for _, r := range kept {
+		// Can't fail, as the resources of m have distinct ids.
+		_ = result.Append(r)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/resmaptest"
+)
+
+func configMapSkipping(name, fields string) map[string]interface{} {
+	metadata := map[string]interface{}{"name": name}
+	if fields != "" {
+		metadata["annotations"] = map[string]interface{}{
+			SkipAnnotation: fields,
+		}
+	}
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   metadata,
+	}
+}
+
+func TestSkips(t *testing.T) {
+	m := resmaptest_test.NewRmBuilder(t, rf).
+		Add(configMapSkipping("cm1", "")).
+		Add(configMapSkipping("cm2", "namespace, commonLabels")).
+		ResMap()
+	cm1, cm2 := m.GetByIndex(0), m.GetByIndex(1)
+	if Skips(cm1, "namespace") {
+		t.Fatalf("cm1 shouldn't skip namespace")
+	}
+	if !Skips(cm2, "namespace") || !Skips(cm2, "commonLabels") {
+		t.Fatalf("cm2 should skip namespace and commonLabels")
+	}
+	if Skips(cm2, "images") {
+		t.Fatalf("cm2 shouldn't skip images")
+	}
+}
+
+func TestWithoutSkipping(t *testing.T) {
+	m := resmaptest_test.NewRmBuilder(t, rf).
+		Add(configMapSkipping("cm1", "")).
+		Add(configMapSkipping("cm2", "commonLabels")).
+		ResMap()
+	if kept := WithoutSkipping(m, "namespace"); kept != m {
+		t.Fatalf("expected m itself when nothing skips")
+	}
+	kept := WithoutSkipping(m, "commonLabels")
+	if kept.Size() != 1 || kept.GetByIndex(0) != m.GetByIndex(0) {
+		t.Fatalf("expected only cm1, got %v", kept.AllIds())
+	}
+}
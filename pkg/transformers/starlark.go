@@ -0,0 +1,177 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+func init() {
+	// Let scripts loop and branch at the top level,
+	// as the scripts of KRM functions do.
+	resolve.AllowGlobalReassign = true
+}
+
+// RunStarlark runs the Starlark script, with the items and
+// the function config in ctx.resource_list, as in a KRM
+// function, and returns the items the script leaves there.
+// The script may change the items in place, or replace
+// them.  Its print statements are logged.
+func RunStarlark(
+	name, source string, items []map[string]interface{},
+	functionConfig map[string]interface{}) ([]map[string]interface{}, error) {
+	in := make([]interface{}, len(items))
+	for i, item := range items {
+		in[i] = item
+	}
+	resourceList, err := toStarlark(map[string]interface{}{
+		"items":          in,
+		"functionConfig": functionConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Printf("%s: %s", name, msg)
+		},
+	}
+	ctx := starlarkstruct.FromStringDict(starlarkstruct.Default,
+		starlark.StringDict{"resource_list": resourceList})
+	_, err = starlark.ExecFile(
+		thread, name, source, starlark.StringDict{"ctx": ctx})
+	if err != nil {
+		if e, ok := err.(*starlark.EvalError); ok {
+			return nil, fmt.Errorf("%s", e.Backtrace())
+		}
+		return nil, err
+	}
+	v, found, err := resourceList.(*starlark.Dict).Get(starlark.String("items"))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s removed resource_list items", name)
+	}
+	out, err := fromStarlark(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s left items that %v", name, err)
+	}
+	list, ok := out.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf(
+			"%s left items that aren't a list but %s", name, v.Type())
+	}
+	result := make([]map[string]interface{}, len(list))
+	for i, item := range list {
+		result[i], ok = item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf(
+				"%s left an item that isn't a dict but %T", name, item)
+		}
+	}
+	return result, nil
+}
+
+// toStarlark converts a value of an unstructured
+// object to a Starlark value.
+func toStarlark(in interface{}) (starlark.Value, error) {
+	switch v := in.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case string:
+		return starlark.String(v), nil
+	case int:
+		return starlark.MakeInt(v), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case float64:
+		return starlark.Float(v), nil
+	case []interface{}:
+		elems := make([]starlark.Value, len(v))
+		for i, e := range v {
+			var err error
+			elems[i], err = toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return starlark.NewList(elems), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		d := starlark.NewDict(len(v))
+		for _, k := range keys {
+			e, err := toStarlark(v[k])
+			if err != nil {
+				return nil, err
+			}
+			if err = d.SetKey(starlark.String(k), e); err != nil {
+				return nil, err
+			}
+		}
+		return d, nil
+	default:
+		return nil, fmt.Errorf("can't convert %T to Starlark", in)
+	}
+}
+
+// fromStarlark converts a Starlark value to a value
+// of an unstructured object.
+func fromStarlark(in starlark.Value) (interface{}, error) {
+	switch v := in.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, fmt.Errorf("hold the too large int %s", v)
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case starlark.Indexable:
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			var err error
+			out[i], err = fromStarlark(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case *starlark.Dict:
+		out := make(map[string]interface{}, v.Len())
+		for _, kv := range v.Items() {
+			k, ok := kv[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf(
+					"hold a dict with the %s key %s", kv[0].Type(), kv[0])
+			}
+			e, err := fromStarlark(kv[1])
+			if err != nil {
+				return nil, err
+			}
+			out[string(k)] = e
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("hold a %s", in.Type())
+}
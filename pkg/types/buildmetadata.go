@@ -0,0 +1,54 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// BuildValue names a value computed at build time.
+type BuildValue string
+
+const (
+	// BuildGitCommit is the commit of the git
+	// work tree holding the kustomization.
+	BuildGitCommit BuildValue = "gitCommit"
+	// BuildGitShortCommit is that commit, abbreviated.
+	BuildGitShortCommit BuildValue = "gitShortCommit"
+	// BuildTimestamp is the time of the build in seconds
+	// since the Unix epoch, or SOURCE_DATE_EPOCH if set,
+	// for reproducible builds.
+	BuildTimestamp BuildValue = "timestamp"
+)
+
+// IsValid returns true if the value is a known one.
+func (v BuildValue) IsValid() bool {
+	switch v {
+	case BuildGitCommit, BuildGitShortCommit, BuildTimestamp:
+		return true
+	default:
+		return false
+	}
+}
+
+// BuildMetadata specifies where to put values computed
+// at build time, e.g. to produce per-commit variants.
+type BuildMetadata struct {
+	// NameSuffix, if not empty, is appended, after a
+	// dash, to the names of all resources, after
+	// the kustomization's NameSuffix.
+	NameSuffix BuildValue `json:"nameSuffix,omitempty" yaml:"nameSuffix,omitempty"`
+
+	// Annotations maps the keys of annotations to add
+	// to all resources to their values.
+	Annotations map[string]BuildValue `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// Values returns the values the metadata uses.
+func (m *BuildMetadata) Values() []BuildValue {
+	var result []BuildValue
+	if m.NameSuffix != "" {
+		result = append(result, m.NameSuffix)
+	}
+	for _, v := range m.Annotations {
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,23 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildMetadataUnknownValue(t *testing.T) {
+	k := Kustomization{BuildMetadata: &BuildMetadata{
+		NameSuffix: BuildGitShortCommit,
+		Annotations: map[string]BuildValue{
+			"example.com/commit": "gitBranch",
+		},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{"unknown buildMetadata value gitBranch"}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
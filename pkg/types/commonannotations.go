@@ -0,0 +1,63 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CommonAnnotations are annotations to add to objects.
+// In a kustomization file it's either a plain map holding
+// annotations to add to all objects, e.g.
+//
+//	commonAnnotations:
+//	  oncallPager: 800-555-1212
+//
+// or an object holding the annotations and selectors of
+// the objects to add them to, e.g.
+//
+//	commonAnnotations:
+//	  pairs:
+//	    vault.hashicorp.com/agent-inject: "true"
+//	  include:
+//	  - kind: Deployment
+//	  exclude:
+//	  - name: migrations
+type CommonAnnotations struct {
+	// Pairs are the annotations to add.
+	Pairs map[string]string `json:"pairs,omitempty" yaml:"pairs,omitempty"`
+
+	// Include, if not empty, limits the objects to
+	// those that any of its selectors selects.
+	Include []Selector `json:"include,omitempty" yaml:"include,omitempty"`
+
+	// Exclude lists selectors of objects that don't get
+	// the annotations, even though Include selects them.
+	Exclude []Selector `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+// UnmarshalJSON accepts either a map or an object.
+func (a *CommonAnnotations) UnmarshalJSON(data []byte) error {
+	var pairs map[string]string
+	if err := json.Unmarshal(data, &pairs); err == nil {
+		*a = CommonAnnotations{Pairs: pairs}
+		return nil
+	}
+	// Alias the type to avoid recursing into this method.
+	type annotations CommonAnnotations
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*annotations)(a))
+}
+
+// MarshalJSON writes a map if there are no selectors, so that
+// rewriting a kustomization file leaves plain maps plain.
+func (a CommonAnnotations) MarshalJSON() ([]byte, error) {
+	if len(a.Include) == 0 && len(a.Exclude) == 0 {
+		return json.Marshal(a.Pairs)
+	}
+	type annotations CommonAnnotations
+	return json.Marshal(annotations(a))
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/yaml"
+)
+
+func TestCommonAnnotationsRoundTrip(t *testing.T) {
+	for _, data := range []string{`commonAnnotations:
+  oncallPager: 800-555-1212
+`, `commonAnnotations:
+  exclude:
+  - name: migrations
+  include:
+  - kind: Deployment
+  pairs:
+    vault.hashicorp.com/agent-inject: "true"
+`} {
+		var k Kustomization
+		if err := yaml.Unmarshal([]byte(data), &k); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out, err := yaml.Marshal(Kustomization{
+			CommonAnnotations: k.CommonAnnotations})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(out) != data {
+			t.Fatalf("expected\n%s\ngot\n%s", data, out)
+		}
+	}
+}
+
+func TestCommonAnnotationsObjectForm(t *testing.T) {
+	var k Kustomization
+	err := yaml.Unmarshal([]byte(`commonAnnotations:
+  pairs:
+    vault.hashicorp.com/agent-inject: "true"
+  include:
+  - kind: Deployment
+`), &k)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &CommonAnnotations{
+		Pairs:   map[string]string{"vault.hashicorp.com/agent-inject": "true"},
+		Include: []Selector{{Gvk: gvk.Gvk{Kind: "Deployment"}}},
+	}
+	if !reflect.DeepEqual(k.CommonAnnotations, expected) {
+		t.Fatalf("expected %v, got %v", expected, k.CommonAnnotations)
+	}
+}
+
+func TestCommonAnnotationsUnknownField(t *testing.T) {
+	var k Kustomization
+	err := yaml.Unmarshal([]byte(`commonAnnotations:
+  pairs:
+    a: b
+  bogus:
+  - kind: Deployment
+`), &k)
+	if err == nil {
+		t.Fatalf("expected error for unknown field")
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// DefaultResources specifies resource requests and limits,
+// e.g. {cpu: 100m, memory: 128Mi}, for the containers of
+// selected workloads that don't set them.
+type DefaultResources struct {
+	// Target selects the workloads whose containers
+	// get the defaults.  If nil, all workloads do.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Requests are the default resource requests.
+	Requests map[string]string `json:"requests,omitempty" yaml:"requests,omitempty"`
+
+	// Limits are the default resource limits.
+	Limits map[string]string `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
@@ -0,0 +1,21 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// DefaultSecurityContext specifies security contexts, e.g.
+// {runAsNonRoot: true}, for the pods and containers of
+// selected workloads.  Fields the workloads set already,
+// at any depth, are kept.
+type DefaultSecurityContext struct {
+	// Target selects the workloads that get the
+	// defaults.  If nil, all workloads do.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Pod is the default security context of pods.
+	Pod map[string]interface{} `json:"pod,omitempty" yaml:"pod,omitempty"`
+
+	// Container is the default security context of
+	// containers, init containers and ephemeral containers.
+	Container map[string]interface{} `json:"container,omitempty" yaml:"container,omitempty"`
+}
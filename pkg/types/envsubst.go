@@ -0,0 +1,56 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "regexp"
+
+// EnvSubst substitutes the values of environment
+// variables for references like "${CLUSTER}" in fields
+// of resources.  Only the variables it lists are
+// substituted, and they must be set.
+type EnvSubst struct {
+	// Vars are the names of the environment variables
+	// that may be substituted, e.g. "CLUSTER".
+	Vars []string `json:"vars,omitempty" yaml:"vars,omitempty"`
+
+	// Targets are the resources and fields to substitute
+	// the variables into.
+	Targets []EnvSubstTarget `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
+// EnvSubstTarget selects resources, and the string
+// fields of them to substitute variables into.
+type EnvSubstTarget struct {
+	Select *Selector `json:"select,omitempty" yaml:"select,omitempty"`
+
+	// FieldPaths are dot separated paths to the fields,
+	// e.g. "spec.template.spec.containers.env.value".
+	FieldPaths []string `json:"fieldPaths,omitempty" yaml:"fieldPaths,omitempty"`
+}
+
+var envVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnforceFields returns the problems of the EnvSubst.
+func (e *EnvSubst) EnforceFields() []string {
+	var errs []string
+	if len(e.Vars) == 0 {
+		errs = append(errs, "envSubst must have vars")
+	}
+	for _, v := range e.Vars {
+		if !envVarName.MatchString(v) {
+			errs = append(errs,
+				"envSubst var '"+v+"' isn't an environment variable name")
+		}
+	}
+	if len(e.Targets) == 0 {
+		errs = append(errs, "envSubst must have targets")
+	}
+	for _, t := range e.Targets {
+		if t.Select == nil || len(t.FieldPaths) == 0 {
+			errs = append(errs,
+				"envSubst target must have select and fieldPaths")
+		}
+	}
+	return errs
+}
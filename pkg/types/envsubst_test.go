@@ -0,0 +1,38 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvSubstFields(t *testing.T) {
+	k := Kustomization{EnvSubst: &EnvSubst{
+		Vars: []string{"CLUSTER", "2FA", "REGION-NAME"},
+		Targets: []EnvSubstTarget{
+			{Select: &Selector{}, FieldPaths: []string{"spec.host"}},
+			{FieldPaths: []string{"spec.host"}},
+			{Select: &Selector{}},
+		},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{
+		"envSubst var '2FA' isn't an environment variable name",
+		"envSubst var 'REGION-NAME' isn't an environment variable name",
+		"envSubst target must have select and fieldPaths",
+		"envSubst target must have select and fieldPaths",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+	errs = (&Kustomization{EnvSubst: &EnvSubst{}}).EnforceFields()
+	expected = []string{
+		"envSubst must have vars",
+		"envSubst must have targets",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
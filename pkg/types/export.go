@@ -0,0 +1,20 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Export names a field of a resource, so that kustomizations
+// including the one exporting it, directly or through other
+// kustomizations, can copy the field by name rather than by
+// the kind and name of the resource, e.g. with a replacement.
+type Export struct {
+	// Name is the name of the export, e.g. "https-service".
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Select must select exactly one resource.
+	Select *Selector `json:"select,omitempty" yaml:"select,omitempty"`
+
+	// FieldPath is a dotted path to the field.
+	// Defaults to "metadata.name".
+	FieldPath string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
+}
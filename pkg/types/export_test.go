@@ -0,0 +1,27 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportFields(t *testing.T) {
+	k := Kustomization{Exports: []Export{
+		{Name: "a", Select: &Selector{Name: "a"}},
+		{Name: "b"},
+		{Select: &Selector{Name: "c"}},
+		{Name: "a", Select: &Selector{Name: "d"}},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{
+		"export b must have select",
+		"export must have a name",
+		"export a is repeated",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// FetchOptions configure how the remote files, archives,
+// buckets and git repos of a kustomization's resources,
+// and of the kustomizations those hold, are fetched.
+type FetchOptions struct {
+	// Proxy is the URL of the proxy to fetch through,
+	// e.g. http://proxy.corp.example.com:3128.  Defaults
+	// to the proxy that HTTPS_PROXY and NO_PROXY specify.
+	Proxy string `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+
+	// CABundle is a relative path to a file holding the
+	// PEM certificates of CAs to trust, on top of the
+	// system's, e.g. the CA of a self-signed server.
+	CABundle string `json:"caBundle,omitempty" yaml:"caBundle,omitempty"`
+}
@@ -55,6 +55,30 @@ func (g *GenArgs) NeedsHashSuffix() bool {
 	return g.args != nil && (g.opts == nil || g.opts.DisableNameSuffixHash == false)
 }
 
+// HashAlgorithm returns the name of the hash function
+// for the name suffix, or the empty string for the default.
+func (g *GenArgs) HashAlgorithm() string {
+	if g.opts == nil {
+		return ""
+	}
+	return g.opts.HashAlgorithm
+}
+
+// HashLength returns the length of the name suffix
+// hash, or zero for the default.
+func (g *GenArgs) HashLength() int {
+	if g.opts == nil {
+		return 0
+	}
+	return g.opts.HashLength
+}
+
+// HashNamespace returns true if the namespace is
+// part of the name suffix hash.
+func (g *GenArgs) HashNamespace() bool {
+	return g.opts != nil && g.opts.HashNamespace
+}
+
 // Behavior returns Behavior field of GeneratorArgs
 func (g *GenArgs) Behavior() GenerationBehavior {
 	if g.args == nil {
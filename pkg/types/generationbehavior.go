@@ -16,6 +16,8 @@ limitations under the License.
 
 package types
 
+import "fmt"
+
 // GenerationBehavior specifies generation behavior of configmaps, secrets and maybe other resources.
 type GenerationBehavior int
 
@@ -57,3 +59,13 @@ func NewGenerationBehavior(s string) GenerationBehavior {
 		return BehaviorUnspecified
 	}
 }
+
+// ValidateGenerationBehavior returns an error unless s
+// is empty or the name of a GenerationBehavior.
+func ValidateGenerationBehavior(s string) error {
+	if s != "" && NewGenerationBehavior(s) == BehaviorUnspecified {
+		return fmt.Errorf(
+			"unknown behavior %q, must be one of create, merge or replace", s)
+	}
+	return nil
+}
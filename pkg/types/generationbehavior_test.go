@@ -0,0 +1,21 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGenerationBehavior(t *testing.T) {
+	for _, s := range []string{"", "create", "merge", "replace"} {
+		if err := ValidateGenerationBehavior(s); err != nil {
+			t.Fatalf("unexpected error for %q: %v", s, err)
+		}
+	}
+	err := ValidateGenerationBehavior("Merge")
+	if err == nil || !strings.Contains(err.Error(), `unknown behavior "Merge"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -21,6 +21,20 @@ type GeneratorArgs struct {
 
 	// DataSources for the generator.
 	DataSources `json:",inline,omitempty" yaml:",inline,omitempty"`
+
+	// Sops, if specified, decrypts the file and env
+	// sources with sops.  Only secrets may use it.
+	Sops *SopsArgs `json:"sops,omitempty" yaml:"sops,omitempty"`
+
+	// Commands maps keys to shell commands, run in the
+	// kustomization root, whose output becomes the values,
+	// e.g. {"password": "pass show db/admin"}.  Only secrets
+	// may use it, and only if the build enables it.
+	Commands map[string]string `json:"commands,omitempty" yaml:"commands,omitempty"`
+
+	// Options, if specified, apply to this generator on top
+	// of the generatorOptions of the kustomization.
+	Options *GeneratorOptions `json:"options,omitempty" yaml:"options,omitempty"`
 }
 
 // DataSources contains some generic sources for generators.
@@ -37,6 +51,13 @@ type DataSources struct {
 	// path's basename. If they "key=" part is present,
 	// it becomes the key (replacing the basename).
 	// In either case, the value is the file contents.
+	// The path may be an https URL, optionally followed
+	// by the expected checksum, e.g.
+	// https://example.com/ca.pem#sha256={hex}
+	// A path may also be a glob, e.g. configs/*.conf, taking
+	// each matching file's basename as its key; a glob can't
+	// have a "key=" part.  Files that aren't valid UTF-8 go
+	// to the binaryData of a ConfigMap rather than its data.
 	// Specifying a directory will iterate each named
 	// file in the directory whose basename is a
 	// valid configmap key.
@@ -47,6 +68,7 @@ type DataSources struct {
 	// key=value pair per line, e.g. a Docker
 	// or npm ".env" file or a ".ini" file
 	// (wikipedia.org/wiki/INI_file)
+	// Like a file source path, a path may be an https URL.
 	EnvSources []string `json:"envs,omitempty" yaml:"envs,omitempty"`
 
 	// Deprecated.  Use EnvSources instead.
@@ -3,7 +3,8 @@
 
 package types
 
-// GeneratorOptions modify behavior of all ConfigMap and Secret generators.
+// GeneratorOptions modify behavior of all ConfigMap and Secret generators,
+// or, as the options of a single generator, of just that generator.
 type GeneratorOptions struct {
 	// Labels to add to all generated resources.
 	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
@@ -15,4 +16,69 @@ type GeneratorOptions struct {
 	// suffix to the names of generated resources that is a hash of the
 	// resource contents.
 	DisableNameSuffixHash bool `json:"disableNameSuffixHash,omitempty" yaml:"disableNameSuffixHash,omitempty"`
+
+	// HashAlgorithm names the function computing the name suffix
+	// hash: sha256 (the default), sha512 or sha1.
+	HashAlgorithm string `json:"hashAlgorithm,omitempty" yaml:"hashAlgorithm,omitempty"`
+
+	// HashLength is the number of characters in the name
+	// suffix hash, 10 by default and at least 5.
+	HashLength int `json:"hashLength,omitempty" yaml:"hashLength,omitempty"`
+
+	// HashNamespace if true includes the final namespace of
+	// generated resources in the name suffix hash, so the same
+	// content deployed to several namespaces gets distinct names.
+	HashNamespace bool `json:"hashNamespace,omitempty" yaml:"hashNamespace,omitempty"`
+
+	// Immutable if true sets immutable: true on generated resources,
+	// so the cluster rejects updates to their data.  Combined with
+	// the name suffix hash, changed content yields a new resource.
+	Immutable bool `json:"immutable,omitempty" yaml:"immutable,omitempty"`
+}
+
+// MergeGlobalOptionsIntoLocal merges the options of the
+// kustomization into those of a single generator.
+// Labels and annotations of the generator win over global
+// ones with the same key; the boolean options are true if
+// either has them true, and the hash settings of the
+// generator win if it has them.  Neither argument is modified.
+func MergeGlobalOptionsIntoLocal(
+	local, global *GeneratorOptions) *GeneratorOptions {
+	if local == nil {
+		return global
+	}
+	if global == nil {
+		return local
+	}
+	result := &GeneratorOptions{
+		Labels:      mergeStringMaps(global.Labels, local.Labels),
+		Annotations: mergeStringMaps(global.Annotations, local.Annotations),
+		DisableNameSuffixHash: global.DisableNameSuffixHash ||
+			local.DisableNameSuffixHash,
+		HashAlgorithm: global.HashAlgorithm,
+		HashLength:    global.HashLength,
+		HashNamespace: global.HashNamespace || local.HashNamespace,
+		Immutable:     global.Immutable || local.Immutable,
+	}
+	if local.HashAlgorithm != "" {
+		result.HashAlgorithm = local.HashAlgorithm
+	}
+	if local.HashLength != 0 {
+		result.HashLength = local.HashLength
+	}
+	return result
+}
+
+func mergeStringMaps(global, local map[string]string) map[string]string {
+	if len(global) == 0 && len(local) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(global)+len(local))
+	for k, v := range global {
+		result[k] = v
+	}
+	for k, v := range local {
+		result[k] = v
+	}
+	return result
 }
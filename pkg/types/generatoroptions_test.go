@@ -0,0 +1,47 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeGlobalOptionsIntoLocal(t *testing.T) {
+	global := &GeneratorOptions{
+		Labels:        map[string]string{"foo": "bar", "fruit": "apple"},
+		Immutable:     true,
+		HashAlgorithm: "sha512",
+		HashLength:    6,
+		HashNamespace: true,
+	}
+	local := &GeneratorOptions{
+		Labels:                map[string]string{"foo": "baz"},
+		Annotations:           map[string]string{"note": "x"},
+		DisableNameSuffixHash: true,
+		HashLength:            8,
+	}
+	expected := &GeneratorOptions{
+		Labels:                map[string]string{"foo": "baz", "fruit": "apple"},
+		Annotations:           map[string]string{"note": "x"},
+		DisableNameSuffixHash: true,
+		HashAlgorithm:         "sha512",
+		HashLength:            8,
+		HashNamespace:         true,
+		Immutable:             true,
+	}
+	actual := MergeGlobalOptionsIntoLocal(local, global)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+	if global.Labels["foo"] != "bar" || len(local.Labels) != 1 {
+		t.Fatalf("arguments modified: %v, %v", global, local)
+	}
+	if MergeGlobalOptionsIntoLocal(nil, global) != global {
+		t.Fatalf("expected global options")
+	}
+	if MergeGlobalOptionsIntoLocal(local, nil) != local {
+		t.Fatalf("expected local options")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// HelmGlobals holds settings shared by all the entries
+// of a kustomization's helmCharts list.
+type HelmGlobals struct {
+	// ChartHome is a directory, relative to the kustomization
+	// root, holding unpacked charts.  Charts fetched from a
+	// repo are unpacked there too.  Defaults to "charts".
+	ChartHome string `json:"chartHome,omitempty" yaml:"chartHome,omitempty"`
+}
+
+// HelmChart specifies a helm chart to inflate, i.e. to render
+// with "helm template", into resources of the kustomization.
+type HelmChart struct {
+	// Name is the name of the chart, and the name of
+	// its directory in the chart home.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Version of the chart to fetch from Repo.
+	// Ignored if the chart is already in the chart home.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Repo is the URL of the repo to fetch the chart from,
+	// if the chart isn't already in the chart home.
+	Repo string `json:"repo,omitempty" yaml:"repo,omitempty"`
+
+	// ReleaseName is passed to helm as the name of the
+	// release.  Defaults to "release-name".
+	ReleaseName string `json:"releaseName,omitempty" yaml:"releaseName,omitempty"`
+
+	// Namespace is passed to helm as the namespace of the release.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// ValuesFiles are values files, relative to the kustomization
+	// root, handed to helm in order.
+	ValuesFiles []string `json:"valuesFiles,omitempty" yaml:"valuesFiles,omitempty"`
+
+	// ValuesInline are values handed to helm after the
+	// values files, so they take precedence.
+	ValuesInline map[string]interface{} `json:"valuesInline,omitempty" yaml:"valuesInline,omitempty"`
+
+	// IncludeCRDs asks helm to render the chart's
+	// CustomResourceDefinitions too.
+	IncludeCRDs bool `json:"includeCRDs,omitempty" yaml:"includeCRDs,omitempty"`
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// IdConflictPolicy specifies what to do about resources
+// with the same id that no MergeStrategy resolves.
+type IdConflictPolicy string
+
+const (
+	// IdConflictsDisallowed fails the build on such a conflict.
+	IdConflictsDisallowed IdConflictPolicy = ""
+	// IdConflictsLastWins keeps the later resource, with a
+	// warning.  This eases migrating overlay trees that
+	// don't build yet.
+	IdConflictsLastWins IdConflictPolicy = "last-wins"
+)
+
+// IsValid returns true if the policy is a known value.
+func (p IdConflictPolicy) IsValid() bool {
+	switch p {
+	case IdConflictsDisallowed, IdConflictsLastWins:
+		return true
+	default:
+		return false
+	}
+}
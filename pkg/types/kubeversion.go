@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// KubeVersion is a Kubernetes version, e.g. "1.22",
+// optionally prefixed with "v" or followed by a patch
+// version, e.g. "v1.22.3".
+type KubeVersion string
+
+// Minor returns the minor version, and true, if the
+// version is well formed.  Otherwise it returns false.
+func (v KubeVersion) Minor() (int, bool) {
+	parts := strings.Split(strings.TrimPrefix(string(v), "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "1" {
+		return 0, false
+	}
+	for _, p := range parts[1:] {
+		if _, err := strconv.ParseUint(p, 10, 16); err != nil {
+			return 0, false
+		}
+	}
+	minor, _ := strconv.Atoi(parts[1])
+	return minor, true
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKubeVersionMinor(t *testing.T) {
+	for v, expected := range map[KubeVersion]int{
+		"1.16":    16,
+		"v1.22":   22,
+		"1.25.3":  25,
+		"v1.9.0":  9,
+		"1":       -1,
+		"2.1":     -1,
+		"1.x":     -1,
+		"1.22.3.": -1,
+		"1.-2":    -1,
+	} {
+		minor, ok := v.Minor()
+		if expected < 0 {
+			if ok {
+				t.Errorf("expected %q to be invalid, got %d", v, minor)
+			}
+			continue
+		}
+		if !ok || minor != expected {
+			t.Errorf("expected %q to have minor %d, got %d %v",
+				v, expected, minor, ok)
+		}
+	}
+}
+
+func TestKubeVersionInvalid(t *testing.T) {
+	k := Kustomization{KubeVersion: "1.x"}
+	errs := k.EnforceFields()
+	expected := []string{"invalid kubeVersion 1.x, must be like 1.22"}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
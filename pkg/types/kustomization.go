@@ -5,12 +5,16 @@
 package types
 
 import (
+	"strings"
+
 	"sigs.k8s.io/kustomize/v3/pkg/image"
 )
 
 const (
 	KustomizationVersion = "kustomize.config.k8s.io/v1beta1"
 	KustomizationKind    = "Kustomization"
+	ComponentVersion     = "kustomize.config.k8s.io/v1alpha1"
+	ComponentKind        = "Component"
 )
 
 // TypeMeta partially copies apimachinery/pkg/apis/meta/v1.TypeMeta
@@ -36,14 +40,34 @@ type Kustomization struct {
 	// file including generated configmaps and secrets.
 	NameSuffix string `json:"nameSuffix,omitempty" yaml:"nameSuffix,omitempty"`
 
+	// NameOptions modify which resources NamePrefix
+	// and NameSuffix apply to.
+	NameOptions *NameOptions `json:"nameOptions,omitempty" yaml:"nameOptions,omitempty"`
+
+	// BuildMetadata adds values computed at build time, e.g.
+	// the git commit, to the names or annotations of resources.
+	BuildMetadata *BuildMetadata `json:"buildMetadata,omitempty" yaml:"buildMetadata,omitempty"`
+
 	// Namespace to add to all objects.
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 
+	// NamespaceOptions modify how Namespace is set.
+	NamespaceOptions *NamespaceOptions `json:"namespaceOptions,omitempty" yaml:"namespaceOptions,omitempty"`
+
+	// CreateNamespace if true adds a Namespace resource
+	// named Namespace, unless the resources hold one.
+	CreateNamespace bool `json:"createNamespace,omitempty" yaml:"createNamespace,omitempty"`
+
 	// CommonLabels to add to all objects and selectors.
 	CommonLabels map[string]string `json:"commonLabels,omitempty" yaml:"commonLabels,omitempty"`
 
-	// CommonAnnotations to add to all objects.
-	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty" yaml:"commonAnnotations,omitempty"`
+	// Labels to add to all objects, and optionally
+	// to templates and selectors.
+	Labels []Label `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// CommonAnnotations to add to all objects,
+	// or to those that its selectors select.
+	CommonAnnotations *CommonAnnotations `json:"commonAnnotations,omitempty" yaml:"commonAnnotations,omitempty"`
 
 	// PatchesStrategicMerge specifies the relative path to a file
 	// containing a strategic merge patch.  Format documented at
@@ -61,11 +85,41 @@ type Kustomization struct {
 	// Each patch can be applied to multiple target objects.
 	Patches []Patch `json:"patches,omitempty" yaml:"patches,omitempty"`
 
+	// Sidecars is a list of pod spec fragments, e.g. sidecar
+	// containers and their volumes, to merge into the pod
+	// specs of all, or selected, workloads.
+	Sidecars []Sidecar `json:"sidecars,omitempty" yaml:"sidecars,omitempty"`
+
+	// DefaultResources is a list of resource requests and limits
+	// to set on the containers of all, or selected, workloads
+	// that don't set them.
+	DefaultResources []DefaultResources `json:"defaultResources,omitempty" yaml:"defaultResources,omitempty"`
+
+	// DefaultSecurityContext is a list of pod and container
+	// security contexts to merge into those of all, or selected,
+	// workloads, keeping the fields the workloads set.
+	DefaultSecurityContext []DefaultSecurityContext `json:"defaultSecurityContext,omitempty" yaml:"defaultSecurityContext,omitempty"`
+
+	// Scheduling is a list of node selectors, tolerations and
+	// topology spread constraints to add to the pod specs of
+	// all, or selected, workloads.
+	Scheduling []Scheduling `json:"scheduling,omitempty" yaml:"scheduling,omitempty"`
+
+	// KubeVersion is the Kubernetes version, e.g. 1.22, the
+	// resources are built for.  Resources of apiVersions that
+	// it no longer serves are upgraded to their replacements.
+	KubeVersion KubeVersion `json:"kubeVersion,omitempty" yaml:"kubeVersion,omitempty"`
+
 	// Images is a list of (image name, new name, new tag or digest)
 	// for changing image names, tags or digests. This can also be achieved with a
 	// patch, but this operator is simpler to specify.
 	Images []image.Image `json:"images,omitempty" yaml:"images,omitempty"`
 
+	// ImageRegistryRewrite is a list of (registry prefix, replacement)
+	// for pulling every image of the build from another registry,
+	// e.g. a mirror.  The first matching rewrite applies to an image.
+	ImageRegistryRewrite []image.RegistryRewrite `json:"imageRegistryRewrite,omitempty" yaml:"imageRegistryRewrite,omitempty"`
+
 	// Replicas is a list of {resourcename, count} that allows for simpler replica
 	// specification. This can also be done with a patch.
 	Replicas []Replica `json:"replicas,omitempty" yaml:"replicas,omitempty"`
@@ -79,14 +133,50 @@ type Kustomization struct {
 	// value of the specified field has been determined.
 	Vars []Var `json:"vars,omitempty" yaml:"vars,omitempty"`
 
+	// VarOptions modify where Vars are expanded.
+	VarOptions *VarOptions `json:"varOptions,omitempty" yaml:"varOptions,omitempty"`
+
+	// Replacements copy the value of a field of a source
+	// resource into fields of target resources, e.g. a
+	// Service's name into a StatefulSet's serviceName.
+	Replacements []Replacement `json:"replacements,omitempty" yaml:"replacements,omitempty"`
+
+	// Exports name fields of resources, so that kustomizations
+	// including this one can refer to them, e.g. as the source
+	// of a replacement, without knowing the resources.
+	Exports []Export `json:"exports,omitempty" yaml:"exports,omitempty"`
+
+	// Parameters are fields of resources that kustomizations
+	// including this one must set, e.g. with patches or
+	// replacements.  Builds that don't set them fail.
+	Parameters []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	// Settings name fields of resources that values given
+	// at build time, e.g. with `kustomize build --set`,
+	// replace.  Only the fields settings name can be set.
+	Settings []Setting `json:"settings,omitempty" yaml:"settings,omitempty"`
+
+	// EnvSubst substitutes the environment variables it
+	// allows into fields of resources, e.g. to inject
+	// cluster-specific values in a pipeline.
+	EnvSubst *EnvSubst `json:"envSubst,omitempty" yaml:"envSubst,omitempty"`
+
 	//
 	// Operands - what kustomize operates on.
 	//
 
 	// Resources specifies relative paths to files holding YAML representations
 	// of kubernetes API objects, or specifcations of other kustomizations
-	// via relative paths, absolute paths, or URLs.
-	Resources []string `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// via relative paths, absolute paths, or URLs.  A glob pattern,
+	// e.g. manifests/*.yaml, stands for the files matching it, in
+	// lexical order.
+	// An entry may carry options applying only to its resources.
+	Resources []ResourceEntry `json:"resources,omitempty" yaml:"resources,omitempty"`
+
+	// ExcludeResources lists paths of the resources list, or
+	// selectors of resources, to drop once the resources are
+	// accumulated, e.g. a few unwanted resources of a base.
+	ExcludeResources []ResourceExclusion `json:"excludeResources,omitempty" yaml:"excludeResources,omitempty"`
 
 	// Crds specifies relative paths to Custom Resource Definition files.
 	// This allows custom resources to be recognized as operands, making
@@ -94,11 +184,44 @@ type Kustomization struct {
 	// CRDs themselves are not modified.
 	Crds []string `json:"crds,omitempty" yaml:"crds,omitempty"`
 
+	// OpenAPI is a relative path to an OpenAPI (swagger 2.0) schema
+	// defining the types of custom resources.  Strategic merge
+	// patches honor the patch strategies and merge keys it holds.
+	OpenAPI string `json:"openapi,omitempty" yaml:"openapi,omitempty"`
+
 	// Deprecated.
 	// Anything that would have been specified here should
 	// be specified in the Resources field instead.
 	Bases []string `json:"bases,omitempty" yaml:"bases,omitempty"`
 
+	// Components specifies relative paths to directories holding
+	// kustomizations of kind Component.  A component contributes
+	// resources, generators, transformers and patches that are
+	// applied, in order, to the resources accumulated so far,
+	// without re-declaring the bases those resources came from.
+	Components []string `json:"components,omitempty" yaml:"components,omitempty"`
+
+	// MergeStrategy determines what happens when two entries
+	// in Resources yield resources with the same id, as when
+	// two overlays of a common base are both listed.
+	// By default this is an error.  With "merge", the
+	// customizations each overlay made to the common base
+	// resource are combined into one resource, failing only
+	// if those customizations conflict.  With "replace",
+	// the resource from the later entry wins.
+	// An entry in Resources may override this.
+	MergeStrategy MergeStrategy `json:"mergeStrategy,omitempty" yaml:"mergeStrategy,omitempty"`
+
+	// AllowIdConflicts, if "last-wins", resolves id
+	// collisions between entries in Resources that no
+	// MergeStrategy resolves in favor of the later entry,
+	// with a warning, instead of failing.
+	AllowIdConflicts IdConflictPolicy `json:"allowIdConflicts,omitempty" yaml:"allowIdConflicts,omitempty"`
+
+	// FetchOptions configure the proxy and the CAs
+	// used to fetch remote resources and bases.
+	FetchOptions *FetchOptions `json:"fetchOptions,omitempty" yaml:"fetchOptions,omitempty"`
+
 	//
 	// Generators (operators that create operands)
 	//
@@ -117,17 +240,42 @@ type Kustomization struct {
 	// the map will have a suffix hash generated from its contents.
 	SecretGenerator []SecretArgs `json:"secretGenerator,omitempty" yaml:"secretGenerator,omitempty"`
 
+	// VaultSecretGenerator is a list of secrets to generate
+	// from values read from HashiCorp Vault (one secret per
+	// list item).  Builds must enable external secrets.
+	VaultSecretGenerator []VaultSecretArgs `json:"vaultSecretGenerator,omitempty" yaml:"vaultSecretGenerator,omitempty"`
+
 	// GeneratorOptions modify behavior of all ConfigMap and Secret generators.
 	GeneratorOptions *GeneratorOptions `json:"generatorOptions,omitempty" yaml:"generatorOptions,omitempty"`
 
+	// HelmGlobals holds settings shared by all HelmCharts.
+	HelmGlobals *HelmGlobals `json:"helmGlobals,omitempty" yaml:"helmGlobals,omitempty"`
+
+	// HelmCharts is a list of helm charts to inflate with
+	// "helm template" (one inflation per list item).
+	// The resulting resources are normal operands, subject
+	// to name prefixing, patching, etc.
+	HelmCharts []HelmChart `json:"helmCharts,omitempty" yaml:"helmCharts,omitempty"`
+
 	// Configurations is a list of transformer configuration files
 	Configurations []string `json:"configurations,omitempty" yaml:"configurations,omitempty"`
 
-	// Generators is a list of files containing custom generators
-	Generators []string `json:"generators,omitempty" yaml:"generators,omitempty"`
+	// Generators is a list of files containing custom generators,
+	// or of the generators' configs, inline.
+	Generators []PluginEntry `json:"generators,omitempty" yaml:"generators,omitempty"`
+
+	// Transformers is a list of files containing transformers,
+	// or of the transformers' configs, inline.
+	Transformers []PluginEntry `json:"transformers,omitempty" yaml:"transformers,omitempty"`
+
+	// Validators is a list of files containing validators,
+	// which check the resources built without changing them.
+	Validators []string `json:"validators,omitempty" yaml:"validators,omitempty"`
 
-	// Transformers is a list of files containing transformers
-	Transformers []string `json:"transformers,omitempty" yaml:"transformers,omitempty"`
+	// SortOptions specifies the order of the resources
+	// a build of this kustomization outputs.  The sort
+	// options of bases are ignored.
+	SortOptions *SortOptions `json:"sortOptions,omitempty" yaml:"sortOptions,omitempty"`
 
 	// Inventory appends an object that contains the record
 	// of all other objects, which can be used in apply, prune and delete
@@ -147,12 +295,16 @@ const (
 // moving content of deprecated fields to newer
 // fields.
 func (k *Kustomization) FixKustomizationPostUnmarshalling() {
-	if k.APIVersion == "" {
-		k.APIVersion = KustomizationVersion
-	}
 	if k.Kind == "" {
 		k.Kind = KustomizationKind
 	}
+	if k.APIVersion == "" {
+		if k.Kind == ComponentKind {
+			k.APIVersion = ComponentVersion
+		} else {
+			k.APIVersion = KustomizationVersion
+		}
+	}
 	// The EnvSource field is deprecated in favor of the list.
 	for i, g := range k.ConfigMapGenerator {
 		if g.EnvSource != "" {
@@ -169,18 +321,127 @@ func (k *Kustomization) FixKustomizationPostUnmarshalling() {
 		}
 	}
 	for _, b := range k.Bases {
-		k.Resources = append(k.Resources, b)
+		k.Resources = append(k.Resources, ResourceEntry{Path: b})
 	}
 	k.Bases = nil
 }
 
 func (k *Kustomization) EnforceFields() []string {
 	var errs []string
-	if k.APIVersion != "" && k.APIVersion != KustomizationVersion {
-		errs = append(errs, "apiVersion should be "+KustomizationVersion)
+	switch k.Kind {
+	case "", KustomizationKind:
+		if k.APIVersion != "" && k.APIVersion != KustomizationVersion {
+			errs = append(errs, "apiVersion should be "+KustomizationVersion)
+		}
+	case ComponentKind:
+		if k.APIVersion != "" && k.APIVersion != ComponentVersion {
+			errs = append(errs, "apiVersion for "+ComponentKind+
+				" should be "+ComponentVersion)
+		}
+	default:
+		errs = append(errs,
+			"kind should be "+KustomizationKind+" or "+ComponentKind)
+	}
+	if _, ok := k.KubeVersion.Minor(); k.KubeVersion != "" && !ok {
+		errs = append(errs, "invalid kubeVersion "+
+			string(k.KubeVersion)+", must be like 1.22")
 	}
-	if k.Kind != "" && k.Kind != KustomizationKind {
-		errs = append(errs, "kind should be "+KustomizationKind)
+	if k.CreateNamespace && k.Namespace == "" {
+		errs = append(errs, "createNamespace requires a namespace")
+	}
+	if k.BuildMetadata != nil {
+		for _, v := range k.BuildMetadata.Values() {
+			if !v.IsValid() {
+				errs = append(errs, "unknown buildMetadata value "+string(v))
+			}
+		}
+	}
+	if k.NamespaceOptions != nil && !k.NamespaceOptions.Subjects.IsValid() {
+		errs = append(errs, "unknown namespaceOptions subjects "+
+			string(k.NamespaceOptions.Subjects))
+	}
+	if !k.MergeStrategy.IsValid() {
+		errs = append(errs, "unknown mergeStrategy "+string(k.MergeStrategy))
+	}
+	if !k.AllowIdConflicts.IsValid() {
+		errs = append(errs,
+			"unknown allowIdConflicts "+string(k.AllowIdConflicts))
+	}
+	for _, r := range k.Resources {
+		if !r.MergeStrategy.IsValid() {
+			errs = append(errs, "unknown mergeStrategy "+
+				string(r.MergeStrategy)+" for resource "+r.Path)
+		}
+	}
+	if o := k.SortOptions; o != nil {
+		if !o.Order.IsValid() {
+			errs = append(errs, "unknown sortOptions order "+string(o.Order))
+		}
+		if o.Order == SortOrderFIFO && o.LegacySortOptions != nil {
+			errs = append(errs,
+				"sortOptions legacySortOptions requires the legacy order")
+		}
+	}
+	for _, x := range k.ExcludeResources {
+		hasSelector := x.Selector != (Selector{})
+		if (x.Path == "") == !hasSelector {
+			errs = append(errs,
+				"excludeResources entry must have either a path or a selector")
+		}
+	}
+	for _, v := range k.Vars {
+		if v.Literal != "" && v.Env != "" ||
+			!v.RefersToObject() && v.ObjRef.Name != "" {
+			errs = append(errs, "var "+v.Name+
+				" must have only one of objref, literal and env")
+		}
+	}
+	exported := make(map[string]bool)
+	for _, e := range k.Exports {
+		switch {
+		case e.Name == "":
+			errs = append(errs, "export must have a name")
+		case e.Select == nil:
+			errs = append(errs, "export "+e.Name+" must have select")
+		case exported[e.Name]:
+			errs = append(errs, "export "+e.Name+" is repeated")
+		}
+		exported[e.Name] = true
+	}
+	declared := make(map[string]bool)
+	for _, p := range k.Parameters {
+		switch {
+		case p.Name == "":
+			errs = append(errs, "parameter must have a name")
+		case p.Select == nil || p.FieldPath == "":
+			errs = append(errs, "parameter "+p.Name+
+				" must have select and fieldPath")
+		case declared[p.Name]:
+			errs = append(errs, "parameter "+p.Name+" is repeated")
+		}
+		declared[p.Name] = true
+	}
+	settings := make(map[string]bool)
+	for _, s := range k.Settings {
+		switch {
+		case s.Name == "":
+			errs = append(errs, "setting must have a name")
+		case strings.Contains(s.Name, "="):
+			errs = append(errs, "setting "+s.Name+" must not contain '='")
+		case len(s.Targets) == 0:
+			errs = append(errs, "setting "+s.Name+" must have targets")
+		case settings[s.Name]:
+			errs = append(errs, "setting "+s.Name+" is repeated")
+		}
+		settings[s.Name] = true
+	}
+	if k.EnvSubst != nil {
+		errs = append(errs, k.EnvSubst.EnforceFields()...)
+	}
+	for _, p := range k.Patches {
+		if !p.Stage.IsValid() {
+			errs = append(errs, "unknown stage "+string(p.Stage)+" for patch")
+		}
 	}
 	return errs
 }
@@ -195,6 +456,43 @@ type PluginConfig struct {
 
 	// Enabled is true if plugins are enabled.
 	Enabled bool
+
+	// ExternalSecretsEnabled is true if builtin generators
+	// may read secrets from external stores, e.g. Vault.
+	ExternalSecretsEnabled bool
+
+	// ContainerRuntime is the command running the
+	// containers of containerized plugins, e.g. docker.
+	ContainerRuntime string
+
+	// NetworkEnabled is true if containerized plugins
+	// asking for the network may use it.
+	NetworkEnabled bool
+
+	// Mounts are added to the containers of containerized
+	// plugins, e.g. "type=bind,src=/data,dst=/data".
+	Mounts []string
+
+	// CatalogPath is the plugin catalog, listing
+	// the plugins trusted to run.
+	CatalogPath string
+
+	// UntrustedEnabled is true if plugins may run
+	// without being listed in the plugin catalog.
+	UntrustedEnabled bool
+
+	// Sandboxed is true if exec plugins run without
+	// the environment, the network and most of the
+	// filesystem, unless the plugin catalog allows them.
+	Sandboxed bool
+
+	// SandboxEnv names environment variables
+	// sandboxed plugins keep.
+	SandboxEnv []string
+
+	// SandboxPaths are files and directories
+	// sandboxed plugins see.
+	SandboxPaths []string
 }
 
 // Pair is a key value pair.
@@ -229,8 +527,8 @@ type NameArgs struct {
 // PatchJson6902 represents a json patch for an object
 // with format documented https://tools.ietf.org/html/rfc6902.
 type PatchJson6902 struct {
-	// PatchTarget refers to a Kubernetes object that the json patch will be
-	// applied to. It must refer to a Kubernetes resource under the
+	// PatchTarget refers to the Kubernetes objects that the json patch will be
+	// applied to. It must match at least one Kubernetes resource under the
 	// purview of this kustomization. PatchTarget should use the
 	// raw name of the object (the name specified in its YAML,
 	// before addition of a namePrefix and a nameSuffix).
@@ -256,4 +554,12 @@ type Patch struct {
 
 	// Target points to the resources that the patch is applied to
 	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Exclude lists selectors of resources that the patch
+	// isn't applied to, even though Target selects them.
+	Exclude []Selector `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+
+	// Stage is when the patch is applied, relative to
+	// the generators and transformers.
+	Stage PatchStage `json:"stage,omitempty" yaml:"stage,omitempty"`
 }
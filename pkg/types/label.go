@@ -0,0 +1,21 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Label specifies labels to add to all objects and,
+// unlike CommonLabels, where else to add them.
+type Label struct {
+	// Pairs are the labels to add.
+	Pairs map[string]string `json:"pairs,omitempty" yaml:"pairs,omitempty"`
+
+	// IncludeSelectors, if true, adds the labels to
+	// selectors, and to the templates they select, as
+	// CommonLabels does.  Selectors of existing workloads
+	// are immutable, so this is false by default.
+	IncludeSelectors bool `json:"includeSelectors,omitempty" yaml:"includeSelectors,omitempty"`
+
+	// IncludeTemplates, if true, adds the labels to
+	// templates, e.g. pod templates, but not to selectors.
+	IncludeTemplates bool `json:"includeTemplates,omitempty" yaml:"includeTemplates,omitempty"`
+}
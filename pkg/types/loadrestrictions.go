@@ -0,0 +1,21 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// LoadRestrictions restrict the files a kustomization may load.
+//go:generate stringer -type=LoadRestrictions
+type LoadRestrictions int
+
+const (
+	LoadRestrictionsUnknown LoadRestrictions = iota
+
+	// Files referenced by a kustomization must be in
+	// or under its root directory, the default.
+	LoadRestrictionsRootOnly
+
+	// A kustomization may load files from anywhere,
+	// e.g. patches shared by sibling overlays, which
+	// breaks the relocatability of the kustomization.
+	LoadRestrictionsNone
+)
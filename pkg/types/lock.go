@@ -0,0 +1,25 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Lock records what the remote references of a
+// kustomization, and of the kustomizations it refers
+// to, resolved to, for later builds to verify.
+type Lock struct {
+	Remotes []LockedRemote `json:"remotes,omitempty" yaml:"remotes,omitempty"`
+}
+
+// LockedRemote is a remote reference and what it resolved to.
+type LockedRemote struct {
+	// URL is the reference, as a kustomization has it.
+	URL string `json:"url" yaml:"url"`
+
+	// Commit is the git commit the ref of a repo resolved to.
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
+
+	// Digest is the sha256 of a remote file or archive,
+	// or of the objects copied from a bucket's prefix,
+	// e.g. sha256:9f86d0...
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+}
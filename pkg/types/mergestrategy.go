@@ -0,0 +1,35 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// MergeStrategy specifies what to do when two entries in
+// a kustomization's resources list yield resources with
+// the same id.
+type MergeStrategy string
+
+const (
+	// MergeStrategyUnspecified is treated as MergeStrategyError.
+	MergeStrategyUnspecified MergeStrategy = ""
+	// MergeStrategyError fails the build on an id collision.
+	MergeStrategyError MergeStrategy = "error"
+	// MergeStrategyMerge combines colliding resources that
+	// are customized variants of a common base resource,
+	// e.g. the same base resource reached through two
+	// different overlays (a "diamond").
+	MergeStrategyMerge MergeStrategy = "merge"
+	// MergeStrategyReplace keeps the resource from the later
+	// entry, discarding the one from the earlier entry.
+	MergeStrategyReplace MergeStrategy = "replace"
+)
+
+// IsValid returns true if the strategy is a known value.
+func (s MergeStrategy) IsValid() bool {
+	switch s {
+	case MergeStrategyUnspecified, MergeStrategyError,
+		MergeStrategyMerge, MergeStrategyReplace:
+		return true
+	default:
+		return false
+	}
+}
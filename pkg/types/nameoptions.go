@@ -0,0 +1,18 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// NameOptions modify which resources NamePrefix
+// and NameSuffix apply to.
+type NameOptions struct {
+	// SkipKinds are kinds whose names are left alone,
+	// e.g. CustomResourceDefinition or the kinds of
+	// cluster-scoped singletons.
+	SkipKinds []string `json:"skipKinds,omitempty" yaml:"skipKinds,omitempty"`
+
+	// Target, if not nil, selects the resources to
+	// rename, e.g. the workloads of an app but not the
+	// shared infrastructure included from the same base.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// NamespaceOptions modify how the namespace
+// of the kustomization is set.
+type NamespaceOptions struct {
+	// UnsetOnly if true sets the namespace only on resources
+	// that don't declare one, leaving explicit namespaces,
+	// e.g. those of bases from other teams, untouched.
+	UnsetOnly bool `json:"unsetOnly,omitempty" yaml:"unsetOnly,omitempty"`
+
+	// Subjects specifies which subjects of RoleBindings
+	// and ClusterRoleBindings the namespace is set on.
+	Subjects SubjectsPolicy `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+}
+
+// SubjectsPolicy specifies which subjects of RoleBindings
+// and ClusterRoleBindings get the kustomization's namespace.
+type SubjectsPolicy string
+
+const (
+	// SubjectsUnspecified is treated as SubjectsDefaultOnly.
+	SubjectsUnspecified SubjectsPolicy = ""
+	// SubjectsDefaultOnly sets the namespace of
+	// subjects named default.
+	SubjectsDefaultOnly SubjectsPolicy = "defaultOnly"
+	// SubjectsAllServiceAccounts sets the namespace
+	// of all subjects of kind ServiceAccount.
+	SubjectsAllServiceAccounts SubjectsPolicy = "allServiceAccounts"
+	// SubjectsNone leaves subjects untouched, e.g. for
+	// bindings granting access to service accounts of
+	// other namespaces.
+	SubjectsNone SubjectsPolicy = "none"
+)
+
+// IsValid returns true if the policy is a known value.
+func (p SubjectsPolicy) IsValid() bool {
+	switch p {
+	case SubjectsUnspecified, SubjectsDefaultOnly,
+		SubjectsAllServiceAccounts, SubjectsNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// Effective returns the policy, resolving an unspecified one.
+func (p SubjectsPolicy) Effective() SubjectsPolicy {
+	if p == SubjectsUnspecified {
+		return SubjectsDefaultOnly
+	}
+	return p
+}
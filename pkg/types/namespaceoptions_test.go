@@ -0,0 +1,43 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateNamespaceWithoutNamespace(t *testing.T) {
+	k := Kustomization{CreateNamespace: true}
+	errs := k.EnforceFields()
+	expected := []string{"createNamespace requires a namespace"}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+	k.Namespace = "prod"
+	if errs := k.EnforceFields(); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
+
+func TestNamespaceOptionsUnknownSubjects(t *testing.T) {
+	k := Kustomization{
+		Namespace:        "prod",
+		NamespaceOptions: &NamespaceOptions{Subjects: "bogus"},
+	}
+	errs := k.EnforceFields()
+	expected := []string{"unknown namespaceOptions subjects bogus"}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
+
+func TestSubjectsPolicyEffective(t *testing.T) {
+	if p := SubjectsUnspecified.Effective(); p != SubjectsDefaultOnly {
+		t.Fatalf("expected %s, got %s", SubjectsDefaultOnly, p)
+	}
+	if p := SubjectsNone.Effective(); p != SubjectsNone {
+		t.Fatalf("expected %s, got %s", SubjectsNone, p)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Parameter is a field of a resource that kustomizations
+// including the one declaring it must set, e.g. with a
+// patch or a replacement, for the build to succeed.
+type Parameter struct {
+	// Name is the name of the parameter, e.g. "domain".
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Select must select exactly one resource.
+	Select *Selector `json:"select,omitempty" yaml:"select,omitempty"`
+
+	// FieldPath is a dotted path to the field, e.g.
+	// "spec.rules[0].host".
+	FieldPath string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
+
+	// Description, if given, is shown in the error
+	// of builds that don't set the parameter.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParameterFields(t *testing.T) {
+	s := &Selector{Name: "web"}
+	k := Kustomization{Parameters: []Parameter{
+		{Name: "a", Select: s, FieldPath: "spec.host"},
+		{Name: "b", Select: s},
+		{Name: "c", FieldPath: "spec.host"},
+		{Select: s, FieldPath: "spec.host"},
+		{Name: "a", Select: s, FieldPath: "spec.port"},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{
+		"parameter b must have select and fieldPath",
+		"parameter c must have select and fieldPath",
+		"parameter must have a name",
+		"parameter a is repeated",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// PatchStage specifies when, during the build of a
+// kustomization, an entry of its patches list is applied.
+type PatchStage string
+
+const (
+	// PatchStageUnspecified is treated as PatchStageBeforeTransformers.
+	PatchStageUnspecified PatchStage = ""
+	// PatchStageBeforeGenerators applies the patch to the
+	// accumulated resources before any generator runs, so it
+	// can't affect generated resources.
+	PatchStageBeforeGenerators PatchStage = "beforeGenerators"
+	// PatchStageBeforeTransformers applies the patch after the
+	// generators, but before the builtin transformers, e.g.
+	// before namePrefix and commonLabels are applied.
+	PatchStageBeforeTransformers PatchStage = "beforeTransformers"
+	// PatchStageAfterTransformers applies the patch after the
+	// builtin transformers, but before those listed in the
+	// kustomization's transformers field.
+	PatchStageAfterTransformers PatchStage = "afterTransformers"
+)
+
+// IsValid returns true if the stage is a known value.
+func (s PatchStage) IsValid() bool {
+	switch s {
+	case PatchStageUnspecified, PatchStageBeforeGenerators,
+		PatchStageBeforeTransformers, PatchStageAfterTransformers:
+		return true
+	default:
+		return false
+	}
+}
+
+// Effective returns the stage, resolving an unspecified one.
+func (s PatchStage) Effective() PatchStage {
+	if s == PatchStageUnspecified {
+		return PatchStageBeforeTransformers
+	}
+	return s
+}
@@ -0,0 +1,29 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatchStageEffective(t *testing.T) {
+	if s := PatchStageUnspecified.Effective(); s != PatchStageBeforeTransformers {
+		t.Fatalf("expected %s, got %s", PatchStageBeforeTransformers, s)
+	}
+	if s := PatchStageAfterTransformers.Effective(); s != PatchStageAfterTransformers {
+		t.Fatalf("expected %s, got %s", PatchStageAfterTransformers, s)
+	}
+}
+
+func TestPatchUnknownStage(t *testing.T) {
+	k := Kustomization{Patches: []Patch{
+		{Path: "patch.yaml", Stage: "bogus"},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{"unknown stage bogus for patch"}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
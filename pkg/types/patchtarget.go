@@ -5,9 +5,17 @@ package types
 
 import "sigs.k8s.io/kustomize/v3/pkg/gvk"
 
-// PatchTarget represents the kubernetes object that the patch is applied to
+// PatchTarget represents the kubernetes objects that the patch is applied to.
+// The Name and Namespace may be regular expressions, and the Name, Kind and
+// the rest of the Gvk may be left empty to match any value, in which case the
+// patch is applied to every object matched.
 type PatchTarget struct {
 	gvk.Gvk   `json:",inline,omitempty" yaml:",inline,omitempty"`
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
-	Name      string `json:"name" yaml:"name"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// AnnotationSelector and LabelSelector further restrict
+	// the objects matched, as in a Selector.
+	AnnotationSelector string `json:"annotationSelector,omitempty" yaml:"annotationSelector,omitempty"`
+	LabelSelector      string `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty"`
 }
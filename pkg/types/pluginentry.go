@@ -0,0 +1,59 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"encoding/json"
+)
+
+// PluginEntry is an entry in a kustomization's generators or
+// transformers list.  In a kustomization file it's either a
+// plain string holding the path of the plugin's config, e.g.
+//
+//	transformers:
+//	- prefixer.yaml
+//
+// or the config itself, inline, e.g.
+//
+//	transformers:
+//	- apiVersion: builtin
+//	  kind: PrefixSuffixTransformer
+//	  metadata:
+//	    name: prefixer
+//	  prefix: baked-
+//	  fieldSpecs:
+//	  - path: metadata/name
+type PluginEntry struct {
+	// Path is a relative path to a file or kustomization
+	// directory, or a URL of a kustomization directory,
+	// holding plugin configs.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Inline is a plugin config given in place of a path.
+	Inline map[string]interface{} `json:"inline,omitempty" yaml:"inline,omitempty"`
+}
+
+// UnmarshalJSON accepts either a string or an object.
+func (e *PluginEntry) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*e = PluginEntry{Path: path}
+		return nil
+	}
+	var inline map[string]interface{}
+	if err := json.Unmarshal(data, &inline); err != nil {
+		return err
+	}
+	*e = PluginEntry{Inline: inline}
+	return nil
+}
+
+// MarshalJSON writes the path if the entry has one,
+// and the inline config otherwise.
+func (e PluginEntry) MarshalJSON() ([]byte, error) {
+	if e.Inline == nil {
+		return json.Marshal(e.Path)
+	}
+	return json.Marshal(e.Inline)
+}
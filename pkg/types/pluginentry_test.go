@@ -0,0 +1,57 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestPluginEntryRoundTrip(t *testing.T) {
+	data := []byte(`transformers:
+- prefixer.yaml
+- apiVersion: builtin
+  kind: PrefixSuffixTransformer
+  metadata:
+    name: prefixer
+  prefix: baked-
+`)
+	var k Kustomization
+	if err := yaml.Unmarshal(data, &k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []PluginEntry{
+		{Path: "prefixer.yaml"},
+		{Inline: map[string]interface{}{
+			"apiVersion": "builtin",
+			"kind":       "PrefixSuffixTransformer",
+			"metadata": map[string]interface{}{
+				"name": "prefixer",
+			},
+			"prefix": "baked-",
+		}},
+	}
+	if !reflect.DeepEqual(k.Transformers, expected) {
+		t.Fatalf("expected %v, got %v", expected, k.Transformers)
+	}
+	out, err := yaml.Marshal(Kustomization{Transformers: k.Transformers})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != string(data) {
+		t.Fatalf("expected\n%s\ngot\n%s", data, out)
+	}
+}
+
+func TestPluginEntryNotAnObject(t *testing.T) {
+	var k Kustomization
+	err := yaml.Unmarshal([]byte(`generators:
+- [a, b]
+`), &k)
+	if err == nil {
+		t.Fatalf("expected error for a list entry")
+	}
+}
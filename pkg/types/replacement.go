@@ -0,0 +1,158 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultReplacementFieldPath is the field copied
+// from a replacement source if none is given.
+const DefaultReplacementFieldPath = "metadata.name"
+
+// Replacement copies the value of a field of one
+// source resource into fields of target resources.
+type Replacement struct {
+	// Source is the resource and field to copy from.
+	Source *ReplacementSource `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Targets are the resources and fields to copy to.
+	Targets []ReplacementTarget `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
+// ReplacementSource specifies a field of a resource,
+// either with a selector and a field path, or by the
+// name of an export.
+type ReplacementSource struct {
+	// Selector must select exactly one resource.
+	Selector `json:",inline,omitempty" yaml:",inline,omitempty"`
+
+	// Export is the name of an export of an included
+	// kustomization, in place of Selector and FieldPath.
+	Export string `json:"export,omitempty" yaml:"export,omitempty"`
+
+	// FieldPath is a dotted path to the field, e.g.
+	// "spec.template.spec.containers[0].image".
+	// Defaults to "metadata.name".
+	FieldPath string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
+
+	// Options, if given, copy only part of the field.
+	Options *ReplacementOptions `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ReplacementTarget specifies fields of resources
+// to replace with the value of the source.
+type ReplacementTarget struct {
+	// Select selects the resources to replace fields in.
+	Select *Selector `json:"select,omitempty" yaml:"select,omitempty"`
+
+	// Reject excludes resources that Select selects.
+	Reject []Selector `json:"reject,omitempty" yaml:"reject,omitempty"`
+
+	// FieldPaths are dotted paths to the fields, e.g.
+	// "spec.serviceName".  A path passing through a list
+	// addresses the field in every element of the list.
+	FieldPaths []string `json:"fieldPaths,omitempty" yaml:"fieldPaths,omitempty"`
+
+	// Create, if true, adds fields that are missing
+	// instead of leaving the resource alone.
+	Create bool `json:"create,omitempty" yaml:"create,omitempty"`
+
+	// Options, if given, replace only part of the fields.
+	Options *ReplacementOptions `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ReplacementOptions address part of a string field,
+// either the part at Index of the parts Delimiter
+// separates, or the part Regex matches.
+type ReplacementOptions struct {
+	// Delimiter separates the parts of the field, e.g. ":".
+	Delimiter string `json:"delimiter,omitempty" yaml:"delimiter,omitempty"`
+
+	// Index is the index of the part, counting from 0.
+	Index int `json:"index,omitempty" yaml:"index,omitempty"`
+
+	// Regex matches the part, or contains a group
+	// capturing it, e.g. ":([^:/]+)$" for an image tag.
+	Regex string `json:"regex,omitempty" yaml:"regex,omitempty"`
+}
+
+// Validate returns an error if the options
+// don't address a part of a field.
+func (o *ReplacementOptions) Validate() error {
+	if (o.Delimiter == "") == (o.Regex == "") {
+		return fmt.Errorf(
+			"replacement options must have one of delimiter and regex")
+	}
+	if o.Index < 0 {
+		return fmt.Errorf(
+			"replacement options index %d must not be negative", o.Index)
+	}
+	if o.Regex != "" {
+		if _, err := regexp.Compile(o.Regex); err != nil {
+			return fmt.Errorf("replacement options regex: %v", err)
+		}
+	}
+	return nil
+}
+
+// Select returns the part of s the options address.
+func (o *ReplacementOptions) Select(s string) (string, error) {
+	if o.Regex != "" {
+		start, end, err := o.match(s)
+		if err != nil {
+			return "", err
+		}
+		return s[start:end], nil
+	}
+	parts := strings.Split(s, o.Delimiter)
+	if o.Index >= len(parts) {
+		return "", o.indexError(s, len(parts))
+	}
+	return parts[o.Index], nil
+}
+
+// Replace returns s with the part the
+// options address replaced by value.
+func (o *ReplacementOptions) Replace(s, value string) (string, error) {
+	if o.Regex != "" {
+		start, end, err := o.match(s)
+		if err != nil {
+			return "", err
+		}
+		return s[:start] + value + s[end:], nil
+	}
+	parts := strings.Split(s, o.Delimiter)
+	if o.Index >= len(parts) {
+		return "", o.indexError(s, len(parts))
+	}
+	parts[o.Index] = value
+	return strings.Join(parts, o.Delimiter), nil
+}
+
+// match returns the bounds of the first group
+// of the first match of Regex in s, or of the
+// match if Regex has no groups.
+func (o *ReplacementOptions) match(s string) (int, int, error) {
+	re, err := regexp.Compile(o.Regex)
+	if err != nil {
+		return 0, 0, err
+	}
+	m := re.FindStringSubmatchIndex(s)
+	if m == nil {
+		return 0, 0, fmt.Errorf("regex %s doesn't match '%s'", o.Regex, s)
+	}
+	if len(m) > 2 && m[2] >= 0 {
+		return m[2], m[3], nil
+	}
+	return m[0], m[1], nil
+}
+
+func (o *ReplacementOptions) indexError(s string, n int) error {
+	return fmt.Errorf(
+		"index %d out of range of the %d parts of '%s' delimited by '%s'",
+		o.Index, n, s, o.Delimiter)
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "testing"
+
+func TestReplacementOptions(t *testing.T) {
+	tag := ReplacementOptions{Regex: ":([^:/]+)$"}
+	second := ReplacementOptions{Delimiter: ".", Index: 1}
+	for _, tc := range []struct {
+		o        ReplacementOptions
+		s        string
+		selected string
+		replaced string
+		err      string
+	}{
+		{tag, "nginx:1.17", "1.17", "nginx:1.19", ""},
+		{tag, "localhost:5000/app:v1", "v1", "localhost:5000/app:1.19", ""},
+		{tag, "localhost:5000/app", "", "",
+			"regex :([^:/]+)$ doesn't match 'localhost:5000/app'"},
+		{ReplacementOptions{Regex: "[0-9]+"}, "web-v2-app", "2", "web-v1.19-app", ""},
+		{second, "a.b.c", "b", "a.1.19.c", ""},
+		{second, "a", "", "",
+			"index 1 out of range of the 1 parts of 'a' delimited by '.'"},
+	} {
+		selected, err := tc.o.Select(tc.s)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("selecting from %s: expected error %s, got %v",
+					tc.s, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if selected != tc.selected {
+			t.Errorf("selecting from %s: expected %s, got %s",
+				tc.s, tc.selected, selected)
+		}
+		replaced, err := tc.o.Replace(tc.s, "1.19")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if replaced != tc.replaced {
+			t.Errorf("replacing in %s: expected %s, got %s",
+				tc.s, tc.replaced, replaced)
+		}
+	}
+}
+
+func TestReplacementOptionsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		o   ReplacementOptions
+		err string
+	}{
+		{ReplacementOptions{Delimiter: ":"}, ""},
+		{ReplacementOptions{Regex: "v(.*)"}, ""},
+		{ReplacementOptions{},
+			"replacement options must have one of delimiter and regex"},
+		{ReplacementOptions{Delimiter: ":", Regex: "v(.*)"},
+			"replacement options must have one of delimiter and regex"},
+		{ReplacementOptions{Delimiter: ":", Index: -1},
+			"replacement options index -1 must not be negative"},
+		{ReplacementOptions{Regex: "v("},
+			"replacement options regex: error parsing regexp: " +
+				"missing closing ): `v(`"},
+	} {
+		err := tc.o.Validate()
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", tc.o, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("%v: expected error %s, got %v", tc.o, tc.err, err)
+		}
+	}
+}
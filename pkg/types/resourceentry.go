@@ -0,0 +1,75 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ResourceEntry is an entry in a kustomization's resources list.
+// In a kustomization file it's either a plain string holding
+// the path, e.g.
+//
+//	resources:
+//	- ../base
+//
+// or an object holding the path and options that apply only
+// to the resources accumulated from that path, e.g.
+//
+//	resources:
+//	- path: ../base
+//	  mergeStrategy: replace
+type ResourceEntry struct {
+	// Path is a relative path to a file or kustomization
+	// directory, a glob pattern matching files, or a URL
+	// of a kustomization directory.
+	Path string `json:"path" yaml:"path"`
+
+	// MergeStrategy, if specified, overrides the kustomization's
+	// MergeStrategy for resources accumulated from this entry
+	// that collide with resources accumulated from earlier entries.
+	MergeStrategy MergeStrategy `json:"mergeStrategy,omitempty" yaml:"mergeStrategy,omitempty"`
+
+	// NamePrefix and NameSuffix, if specified, are added to the
+	// names of the resources accumulated from this entry before
+	// they join the resources accumulated from other entries.
+	// This lets a kustomization include the same base more
+	// than once, e.g. to run two copies of a StatefulSet.
+	NamePrefix string `json:"namePrefix,omitempty" yaml:"namePrefix,omitempty"`
+	NameSuffix string `json:"nameSuffix,omitempty" yaml:"nameSuffix,omitempty"`
+}
+
+// UnmarshalJSON accepts either a string or an object.
+func (e *ResourceEntry) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*e = ResourceEntry{Path: path}
+		return nil
+	}
+	// Alias the type to avoid recursing into this method.
+	type entry ResourceEntry
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*entry)(e))
+}
+
+// MarshalJSON writes a string if the entry has nothing but a path,
+// so that rewriting a kustomization file leaves plain entries plain.
+func (e ResourceEntry) MarshalJSON() ([]byte, error) {
+	if e == (ResourceEntry{Path: e.Path}) {
+		return json.Marshal(e.Path)
+	}
+	type entry ResourceEntry
+	return json.Marshal(entry(e))
+}
+
+// ResourcePaths returns the paths of the kustomization's resources.
+func (k *Kustomization) ResourcePaths() []string {
+	var result []string
+	for _, r := range k.Resources {
+		result = append(result, r.Path)
+	}
+	return result
+}
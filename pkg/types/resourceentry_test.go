@@ -0,0 +1,63 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestResourceEntryRoundTrip(t *testing.T) {
+	data := []byte(`resources:
+- ../base
+- mergeStrategy: replace
+  path: ../other
+- namePrefix: a-
+  nameSuffix: -b
+  path: ../base
+`)
+	var k Kustomization
+	if err := yaml.Unmarshal(data, &k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []ResourceEntry{
+		{Path: "../base"},
+		{Path: "../other", MergeStrategy: MergeStrategyReplace},
+		{Path: "../base", NamePrefix: "a-", NameSuffix: "-b"},
+	}
+	if !reflect.DeepEqual(k.Resources, expected) {
+		t.Fatalf("expected %v, got %v", expected, k.Resources)
+	}
+	out, err := yaml.Marshal(Kustomization{Resources: k.Resources})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != string(data) {
+		t.Fatalf("expected\n%s\ngot\n%s", data, out)
+	}
+}
+
+func TestResourceEntryUnknownField(t *testing.T) {
+	var k Kustomization
+	err := yaml.Unmarshal([]byte(`resources:
+- path: ../base
+  bogus: true
+`), &k)
+	if err == nil {
+		t.Fatalf("expected error for unknown field")
+	}
+}
+
+func TestResourceEntryUnknownMergeStrategy(t *testing.T) {
+	k := Kustomization{Resources: []ResourceEntry{
+		{Path: "../base", MergeStrategy: "bogus"},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{"unknown mergeStrategy bogus for resource ../base"}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ResourceExclusion is an entry in a kustomization's
+// excludeResources list.  In a kustomization file it's
+// either a plain string holding a path, or a pattern
+// matching paths, of the resources list, e.g.
+//
+//	excludeResources:
+//	- manifests/debug-*.yaml
+//
+// or a selector of resources, e.g.
+//
+//	excludeResources:
+//	- kind: Job
+//	  name: migrations
+type ResourceExclusion struct {
+	// Path, if specified, drops the entries of the resources
+	// list, once glob patterns are expanded, that it matches.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Selector, if Path isn't specified, drops the
+	// accumulated resources it selects.
+	Selector `json:",inline,omitempty" yaml:",inline,omitempty"`
+}
+
+// UnmarshalJSON accepts either a string or an object.
+func (x *ResourceExclusion) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*x = ResourceExclusion{Path: path}
+		return nil
+	}
+	// Alias the type to avoid recursing into this method.
+	type exclusion ResourceExclusion
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*exclusion)(x))
+}
+
+// MarshalJSON writes a string if the exclusion has
+// nothing but a path, so that rewriting a kustomization
+// file leaves plain exclusions plain.
+func (x ResourceExclusion) MarshalJSON() ([]byte, error) {
+	if x == (ResourceExclusion{Path: x.Path}) {
+		return json.Marshal(x.Path)
+	}
+	type exclusion ResourceExclusion
+	return json.Marshal(exclusion(x))
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/yaml"
+)
+
+func TestResourceExclusionRoundTrip(t *testing.T) {
+	data := []byte(`excludeResources:
+- manifests/debug-*.yaml
+- kind: Job
+  name: migrations
+`)
+	var k Kustomization
+	if err := yaml.Unmarshal(data, &k); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []ResourceExclusion{
+		{Path: "manifests/debug-*.yaml"},
+		{Selector: Selector{Gvk: gvk.Gvk{Kind: "Job"}, Name: "migrations"}},
+	}
+	if !reflect.DeepEqual(k.ExcludeResources, expected) {
+		t.Fatalf("expected %v, got %v", expected, k.ExcludeResources)
+	}
+	out, err := yaml.Marshal(Kustomization{ExcludeResources: k.ExcludeResources})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != string(data) {
+		t.Fatalf("expected\n%s\ngot\n%s", data, out)
+	}
+}
+
+func TestResourceExclusionPathOrSelector(t *testing.T) {
+	k := Kustomization{ExcludeResources: []ResourceExclusion{
+		{},
+		{Path: "a.yaml", Selector: Selector{Name: "a"}},
+		{Path: "b.yaml"},
+		{Selector: Selector{Name: "b"}},
+	}}
+	errs := k.EnforceFields()
+	msg := "excludeResources entry must have either a path or a selector"
+	expected := []string{msg, msg}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
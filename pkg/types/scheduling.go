@@ -0,0 +1,29 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Scheduling specifies scheduling constraints and the
+// priority class to add to the pod specs of selected
+// workloads.
+type Scheduling struct {
+	// Target selects the workloads to constrain.
+	// If nil, all workloads are.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// NodeSelector labels are added to the pod specs'
+	// node selectors, replacing values of the same keys.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+
+	// PriorityClassName, if not empty, replaces the
+	// priority class name of the pod specs.
+	PriorityClassName string `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+
+	// Tolerations are appended to the pod specs'
+	// tolerations, unless they're there already.
+	Tolerations []map[string]interface{} `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints are appended to the pod
+	// specs' constraints, unless they're there already.
+	TopologySpreadConstraints []map[string]interface{} `json:"topologySpreadConstraints,omitempty" yaml:"topologySpreadConstraints,omitempty"`
+}
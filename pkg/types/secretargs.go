@@ -16,4 +16,43 @@ type SecretArgs struct {
 	// If type is "kubernetes.io/tls", then "literals" or "files" must have exactly two
 	// keys: "tls.key" and "tls.crt"
 	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// TLS, if specified, adds the "tls.crt" and "tls.key" keys
+	// from a certificate and its private key, checking that they
+	// match, and defaults the type to "kubernetes.io/tls".
+	TLS *TLSArgs `json:"tls,omitempty" yaml:"tls,omitempty"`
+
+	// DockerConfig, if specified, adds the ".dockerconfigjson" key
+	// holding registry credentials, and defaults the type to
+	// "kubernetes.io/dockerconfigjson".
+	DockerConfig *DockerConfigArgs `json:"dockerConfig,omitempty" yaml:"dockerConfig,omitempty"`
+}
+
+// TLSArgs holds the paths to a PEM encoded certificate
+// (chain) and its PEM encoded private key.
+type TLSArgs struct {
+	CertFile string `json:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+}
+
+// DockerConfigArgs holds either the path to an existing docker
+// config file, or the credentials for a single registry.
+type DockerConfigArgs struct {
+	// ConfigFile is the path to a docker config file,
+	// like ~/.docker/config.json, holding "auths".
+	ConfigFile string `json:"configFile,omitempty" yaml:"configFile,omitempty"`
+
+	// Server is the registry, e.g. "https://index.docker.io/v1/".
+	Server string `json:"server,omitempty" yaml:"server,omitempty"`
+
+	// Username for the registry.
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+
+	// PasswordFile is the path to a file holding the
+	// password, so the password needn't be in the
+	// kustomization file.
+	PasswordFile string `json:"passwordFile,omitempty" yaml:"passwordFile,omitempty"`
+
+	// Email, optional.
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
 }
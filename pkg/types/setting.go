@@ -0,0 +1,17 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Setting names fields of resources that a value given
+// at build time, e.g. with `kustomize build --set
+// image-tag=v1.2.3`, replaces.  Without a value for the
+// setting, the fields are left alone.
+type Setting struct {
+	// Name is the name of the setting, e.g. "image-tag".
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Targets are the resources and fields to replace
+	// with the value, as in a Replacement.
+	Targets []ReplacementTarget `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
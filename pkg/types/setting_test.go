@@ -0,0 +1,30 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSettingFields(t *testing.T) {
+	targets := []ReplacementTarget{{FieldPaths: []string{"spec.host"}}}
+	k := Kustomization{Settings: []Setting{
+		{Name: "a", Targets: targets},
+		{Name: "b"},
+		{Targets: targets},
+		{Name: "c=d", Targets: targets},
+		{Name: "a", Targets: targets},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{
+		"setting b must have targets",
+		"setting must have a name",
+		"setting c=d must not contain '='",
+		"setting a is repeated",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// Sidecar specifies a pod spec fragment, e.g. logging
+// or mesh proxy containers with their volumes, to merge
+// into the pod specs of selected workloads.
+type Sidecar struct {
+	// Path is a relative file path to the fragment.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Spec is the content of the fragment.
+	Spec string `json:"spec,omitempty" yaml:"spec,omitempty"`
+
+	// Target selects the workloads to inject the
+	// fragment into.  If nil, all workloads are.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
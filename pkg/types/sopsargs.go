@@ -0,0 +1,14 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// SopsArgs asks a secret generator to decrypt its file and
+// env sources with sops (https://github.com/mozilla/sops)
+// before reading them.  Sops finds the decryption keys as
+// usual, e.g. from its environment variables or a key service.
+type SopsArgs struct {
+	// KeyServices are passed to sops as --keyservice
+	// flags, e.g. "tcp://localhost:5000".
+	KeyServices []string `json:"keyServices,omitempty" yaml:"keyServices,omitempty"`
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// SortOrder specifies the order of the
+// resources a build outputs.
+type SortOrder string
+
+const (
+	// SortOrderUnspecified is treated as SortOrderLegacy.
+	SortOrderUnspecified SortOrder = ""
+	// SortOrderLegacy orders the resources by kind, e.g.
+	// Namespaces first and ValidatingWebhookConfigurations
+	// last, then by id.
+	SortOrderLegacy SortOrder = "legacy"
+	// SortOrderFIFO keeps the resources in the
+	// order they were read and generated in.
+	SortOrderFIFO SortOrder = "fifo"
+)
+
+// IsValid returns true if the order is a known value.
+func (o SortOrder) IsValid() bool {
+	switch o {
+	case SortOrderUnspecified, SortOrderLegacy, SortOrderFIFO:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortOptions specifies the order of the resources
+// a build outputs, e.g.
+//
+//	sortOptions:
+//	  order: legacy
+//	  legacySortOptions:
+//	    orderFirst:
+//	    - Namespace
+//	    - CustomResourceDefinition
+//	    orderLast:
+//	    - MutatingWebhookConfiguration
+//	    - ValidatingWebhookConfiguration
+type SortOptions struct {
+	Order SortOrder `json:"order,omitempty" yaml:"order,omitempty"`
+
+	// LegacySortOptions, if specified, replaces the kinds
+	// the legacy order puts first and last.
+	LegacySortOptions *LegacySortOptions `json:"legacySortOptions,omitempty" yaml:"legacySortOptions,omitempty"`
+}
+
+// LegacySortOptions lists the kinds of resources
+// the legacy order puts first and last, in order.
+// Resources of other kinds go in between.
+type LegacySortOptions struct {
+	OrderFirst []string `json:"orderFirst,omitempty" yaml:"orderFirst,omitempty"`
+	OrderLast  []string `json:"orderLast,omitempty" yaml:"orderLast,omitempty"`
+}
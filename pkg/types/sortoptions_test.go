@@ -0,0 +1,32 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortOptionsEnforceFields(t *testing.T) {
+	for _, c := range []struct {
+		options  SortOptions
+		expected []string
+	}{
+		{SortOptions{}, nil},
+		{SortOptions{Order: SortOrderFIFO}, nil},
+		{SortOptions{LegacySortOptions: &LegacySortOptions{
+			OrderFirst: []string{"Namespace"}}}, nil},
+		{SortOptions{Order: "random"},
+			[]string{"unknown sortOptions order random"}},
+		{SortOptions{Order: SortOrderFIFO,
+			LegacySortOptions: &LegacySortOptions{}},
+			[]string{"sortOptions legacySortOptions requires the legacy order"}},
+	} {
+		k := Kustomization{SortOptions: &c.options}
+		errs := k.EnforceFields()
+		if !reflect.DeepEqual(errs, c.expected) {
+			t.Fatalf("expected %v, got %v for %v", c.expected, errs, c.options)
+		}
+	}
+}
@@ -45,6 +45,15 @@ type Var struct {
 	// replacing $(FOO).
 	// If unspecified, this defaults to fieldPath: $defaultFieldPath
 	FieldRef FieldSelector `json:"fieldref,omitempty" yaml:"fieldref,omitempty"`
+
+	// Literal, if not empty, is the value of the var,
+	// in place of a field of an object.
+	Literal string `json:"literal,omitempty" yaml:"literal,omitempty"`
+
+	// Env, if not empty, names the environment variable
+	// holding the value of the var, in place of a field of
+	// an object.  The build must allow reading it.
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
 }
 
 // Target refers to a kubernetes object by Group, Version, Kind and Name
@@ -65,8 +74,18 @@ type FieldSelector struct {
 	FieldPath string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
 }
 
+// RefersToObject returns true if the value of the var is
+// a field of an object, rather than a literal or the value
+// of an environment variable.
+func (v *Var) RefersToObject() bool {
+	return v.Literal == "" && v.Env == ""
+}
+
 // defaulting sets reference to field used by default.
 func (v *Var) Defaulting() {
+	if !v.RefersToObject() {
+		return
+	}
 	if v.FieldRef.FieldPath == "" {
 		v.FieldRef.FieldPath = defaultFieldPath
 	}
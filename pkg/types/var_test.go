@@ -182,3 +182,21 @@ func TestVarSetCopy(t *testing.T) {
 		t.Fatalf("set %v should not contain %v", set1.AsSlice(), w)
 	}
 }
+
+func TestVarSources(t *testing.T) {
+	k := Kustomization{Vars: []Var{
+		{Name: "A", Literal: "a"},
+		{Name: "B", Env: "B"},
+		{Name: "C", ObjRef: Target{Name: "c"}},
+		{Name: "D", Literal: "d", Env: "D"},
+		{Name: "E", ObjRef: Target{Name: "e"}, Env: "E"},
+	}}
+	errs := k.EnforceFields()
+	expected := []string{
+		"var D must have only one of objref, literal and env",
+		"var E must have only one of objref, literal and env",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+}
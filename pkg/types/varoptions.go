@@ -0,0 +1,13 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// VarOptions modify where Vars are expanded.
+type VarOptions struct {
+	// AllFields, if true, expands vars in all string fields
+	// of all resources, rather than only in the fields the
+	// varReference transformer configurations list.  It
+	// applies to the whole build, like configurations do.
+	AllFields bool `json:"allFields,omitempty" yaml:"allFields,omitempty"`
+}
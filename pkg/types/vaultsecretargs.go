@@ -0,0 +1,33 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// VaultSecretArgs specifies a secret whose values are read
+// from HashiCorp Vault at build time, so the secret material
+// needn't live next to the kustomization.  The address of
+// the Vault server is that of the VAULT_ADDR environment
+// variable, never the kustomization's, which mustn't get
+// the token sent along.
+type VaultSecretArgs struct {
+	// SecretArgs for the secret.  Values from literals,
+	// files, etc. are added to those read from Vault.
+	SecretArgs `json:",inline,omitempty" yaml:",inline,omitempty"`
+
+	// Refs are the Vault values to add to the secret.
+	Refs []VaultRef `json:"refs,omitempty" yaml:"refs,omitempty"`
+}
+
+// VaultRef refers to one value of a Vault secret.
+type VaultRef struct {
+	// Path of the Vault secret, e.g. "secret/data/db"
+	// for a secret in the kv version 2 engine at "secret".
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Key of the value in the Vault secret.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// SecretKey is the key of the value in the generated
+	// secret.  Defaults to Key.
+	SecretKey string `json:"secretKey,omitempty" yaml:"secretKey,omitempty"`
+}
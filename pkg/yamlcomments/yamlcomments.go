@@ -0,0 +1,268 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package yamlcomments carries the comments of YAML
+// documents over to other YAML forms of their objects,
+// e.g. to the output of a build, in which the objects
+// are customized and serialized afresh.
+package yamlcomments
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Comment holds the comments on a field or list item:
+// the lines of comments above it, and the comment
+// following it on its line.
+type Comment struct {
+	Head string
+	Line string
+}
+
+// Comments holds comments by the path of the field or
+// list item they're on.  Paths are like JSON pointers,
+// e.g. /metadata/name, except that a list item with a
+// name field is referred to by it, e.g.
+// /spec/containers/[name=app]/image, so that its comments
+// follow it when a patch reorders the list.  The comments
+// above the document have the empty path.
+type Comments map[string]Comment
+
+// Merge returns the comments of both, those of
+// other replacing those of c on the same path.
+func (c Comments) Merge(other Comments) Comments {
+	if len(other) == 0 {
+		return c
+	}
+	result := make(Comments, len(c)+len(other))
+	for p, v := range c {
+		result[p] = v
+	}
+	for p, v := range other {
+		result[p] = v
+	}
+	return result
+}
+
+// Document holds the comments of a YAML document,
+// and the identity of the object it holds.
+type Document struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	Comments   Comments
+}
+
+// Extract returns the documents of the YAML stream
+// that hold a commented object.
+func Extract(in []byte) ([]Document, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(in))
+	var result []Document
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		obj := doc.Content[0]
+		c := Comments{}
+		if doc.HeadComment != "" {
+			c[""] = Comment{Head: doc.HeadComment}
+		}
+		collect(c, "", obj)
+		if len(c) == 0 {
+			continue
+		}
+		metadata := field(obj, "metadata")
+		result = append(result, Document{
+			APIVersion: scalar(field(obj, "apiVersion")),
+			Kind:       scalar(field(obj, "kind")),
+			Namespace:  scalar(field(metadata, "namespace")),
+			Name:       scalar(field(metadata, "name")),
+			Comments:   c,
+		})
+	}
+}
+
+// collect adds the comments below the node at the path.
+func collect(c Comments, path string, n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			p := path + "/" + escape(k.Value)
+			line := k.LineComment
+			if line == "" && (v.Kind == yaml.ScalarNode || v.Style&yaml.FlowStyle != 0) {
+				line = v.LineComment
+			}
+			if k.HeadComment != "" || line != "" {
+				c[p] = Comment{Head: k.HeadComment, Line: line}
+			}
+			collect(c, p, v)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			p := path + "/" + itemKey(item, i)
+			if item.HeadComment != "" || item.LineComment != "" {
+				c[p] = Comment{Head: item.HeadComment, Line: item.LineComment}
+			}
+			collect(c, p, item)
+		}
+	}
+}
+
+// Apply returns the YAML document, which must be in block
+// style, with the comments inserted at their paths.  A line
+// comment on a value spanning lines goes above it instead.
+func Apply(doc []byte, c Comments) ([]byte, error) {
+	if len(c) == 0 {
+		return doc, nil
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) != 1 {
+		return doc, nil
+	}
+	lines := strings.Split(strings.TrimSuffix(string(doc), "\n"), "\n")
+	heads := make(map[int][]string)
+	tails := make(map[int]string)
+	if d, ok := c[""]; ok {
+		heads[0] = append(heads[0], d.Head)
+	}
+	var insert func(path string, n *yaml.Node)
+	mark := func(p string, line, column int, value *yaml.Node) {
+		v, ok := c[p]
+		if !ok {
+			return
+		}
+		i := line - 1
+		indent := column - 1
+		if indent > indentOf(lines[i]) {
+			// The first field of a list item goes
+			// on the line of the item's dash.
+			indent = indentOf(lines[i])
+		}
+		if v.Head != "" {
+			heads[i] = append(heads[i], indented(v.Head, indent))
+		}
+		if v.Line == "" {
+			return
+		}
+		if continues(value) && i+1 < len(lines) &&
+			indentOf(lines[i+1]) > column-1 {
+			// The value continues on the next line.
+			heads[i] = append(heads[i], indented(v.Line, indent))
+			return
+		}
+		tails[i] = v.Line
+	}
+	insert = func(path string, n *yaml.Node) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k := n.Content[i]
+				p := path + "/" + escape(k.Value)
+				mark(p, k.Line, k.Column, n.Content[i+1])
+				insert(p, n.Content[i+1])
+			}
+		case yaml.SequenceNode:
+			for i, item := range n.Content {
+				p := path + "/" + itemKey(item, i)
+				// Block sequence items follow a dash and a space.
+				mark(p, item.Line, item.Column-2, item)
+				insert(p, item)
+			}
+		}
+	}
+	insert("", root.Content[0])
+	var out bytes.Buffer
+	for i, l := range lines {
+		for _, h := range heads[i] {
+			out.WriteString(h)
+			out.WriteString("\n")
+		}
+		out.WriteString(l)
+		if t, ok := tails[i]; ok {
+			out.WriteString(" ")
+			out.WriteString(t)
+		}
+		out.WriteString("\n")
+	}
+	return out.Bytes(), nil
+}
+
+// continues returns whether the value may continue
+// past its first line, on more indented lines, with
+// no room for a comment at the end of the first.
+func continues(value *yaml.Node) bool {
+	return value.Kind == yaml.ScalarNode &&
+		value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0
+}
+
+// itemKey returns the path segment of the list item at
+// the index, which is its name field, if it has one.
+func itemKey(item *yaml.Node, i int) string {
+	if name := field(item, "name"); name != nil && name.Kind == yaml.ScalarNode {
+		return "[name=" + escape(name.Value) + "]"
+	}
+	return strconv.Itoa(i)
+}
+
+// field returns the value of the field
+// of the mapping node, if it has one.
+func field(n *yaml.Node, name string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == name {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalar(n *yaml.Node) string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
+
+// escape escapes a path segment as in a JSON pointer.
+func escape(s string) string {
+	return strings.Replace(strings.Replace(s, "~", "~0", -1), "/", "~1", -1)
+}
+
+// indented returns the comment lines indented by n spaces.
+func indented(comment string, n int) string {
+	if n < 0 {
+		n = 0
+	}
+	pad := strings.Repeat(" ", n)
+	ls := strings.Split(comment, "\n")
+	for i, l := range ls {
+		if l != "" {
+			ls[i] = pad + l
+		}
+	}
+	return strings.Join(ls, "\n")
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package yamlcomments
+
+import (
+	"reflect"
+	"testing"
+)
+
+const commented = `# The app.
+
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app # Referred to by the service.
+  namespace: prod
+spec:
+  # Scaled by hand.
+  replicas: 3
+  template:
+    spec:
+      containers:
+      # The main container.
+      - name: app
+        image: app:1.0 # Bumped by CI.
+        args: [serve, "8080"] # The port.
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`
+
+func TestExtract(t *testing.T) {
+	docs, err := Extract([]byte(commented))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Document{{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Namespace:  "prod",
+		Name:       "app",
+		Comments: Comments{
+			"":               {Head: "# The app."},
+			"/metadata/name": {Line: "# Referred to by the service."},
+			"/spec/replicas": {Head: "# Scaled by hand."},
+			"/spec/template/spec/containers/[name=app]": {
+				Head: "# The main container."},
+			"/spec/template/spec/containers/[name=app]/image": {
+				Line: "# Bumped by CI."},
+			"/spec/template/spec/containers/[name=app]/args": {
+				Line: "# The port."},
+		},
+	}}
+	if !reflect.DeepEqual(docs, expected) {
+		t.Fatalf("expected\n%#v\nbut got\n%#v", expected, docs)
+	}
+}
+
+func TestApply(t *testing.T) {
+	testCases := map[string]struct {
+		comments Comments
+		input    string
+		expected string
+	}{
+		"none": {
+			input:    "a: b\n",
+			expected: "a: b\n",
+		},
+		"fields": {
+			comments: Comments{
+				"":        {Head: "# Top."},
+				"/a":      {Line: "# On a."},
+				"/b":      {Head: "# Above b."},
+				"/b/c":    {Head: "# Above c.", Line: "# On c."},
+				"/absent": {Head: "# Dropped."},
+			},
+			input: `a: x
+b:
+  c: y
+`,
+			expected: `# Top.
+a: x # On a.
+# Above b.
+b:
+  # Above c.
+  c: y # On c.
+`,
+		},
+		"listItems": {
+			comments: Comments{
+				"/l/[name=one]/name":  {Head: "# First."},
+				"/l/[name=two]":       {Head: "# Second."},
+				"/l/[name=two]/image": {Line: "# Pinned."},
+				"/s/1":                {Line: "# Last."},
+			},
+			input: `l:
+- name: one
+- image: b
+  name: two
+s:
+- x
+- y
+`,
+			expected: `l:
+# First.
+- name: one
+# Second.
+- image: b # Pinned.
+  name: two
+s:
+- x
+- y # Last.
+`,
+		},
+		"multiLineValues": {
+			comments: Comments{
+				"/a": {Line: "# On a."},
+				"/b": {Line: "# On b."},
+			},
+			input: `a: |
+  one
+  two
+b: a long value that
+  spans lines
+`,
+			expected: `a: | # On a.
+  one
+  two
+# On b.
+b: a long value that
+  spans lines
+`,
+		},
+	}
+	for n, tc := range testCases {
+		actual, err := Apply([]byte(tc.input), tc.comments)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", n, err)
+		}
+		if string(actual) != tc.expected {
+			t.Fatalf("%s: expected\n%s\nbut got\n%s", n, tc.expected, actual)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	c := Comments{"/a": {Head: "# a"}, "/b": {Head: "# b"}}
+	merged := c.Merge(Comments{"/b": {Line: "# B"}, "/c": {Line: "# c"}})
+	expected := Comments{
+		"/a": {Head: "# a"}, "/b": {Line: "# B"}, "/c": {Line: "# c"}}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("expected %v but got %v", expected, merged)
+	}
+	if len(c) != 2 || c["/b"].Head != "# b" {
+		t.Fatalf("merge changed its receiver: %v", c)
+	}
+}
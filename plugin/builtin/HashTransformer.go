@@ -22,7 +22,9 @@ func (p *HashTransformerPlugin) Config(
 func (p *HashTransformerPlugin) Transform(m resmap.ResMap) error {
 	for _, res := range m.Resources() {
 		if res.NeedHashSuffix() {
-			h, err := p.hasher.Hash(res)
+			h, err := p.hasher.HashWith(
+				res, res.HashAlgorithm(), res.HashLength(),
+				res.HashNamespace())
 			if err != nil {
 				return err
 			}
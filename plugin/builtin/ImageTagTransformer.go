@@ -30,6 +30,9 @@ func (p *ImageTagTransformerPlugin) Config(
 
 func (p *ImageTagTransformerPlugin) Transform(m resmap.ResMap) error {
 	for _, r := range m.Resources() {
+		if transformers.Skips(r, "images") {
+			continue
+		}
 		for _, path := range p.FieldSpecs {
 			if !r.OrgId().IsSelected(&path.Gvk) {
 				continue
@@ -77,7 +80,7 @@ func (p *ImageTagTransformerPlugin) mutateImage(in interface{}) (interface{}, er
 // session, finds matched ones and update the
 // image name and tag name
 func (p *ImageTagTransformerPlugin) findAndReplaceImage(obj map[string]interface{}) error {
-	paths := []string{"containers", "initContainers"}
+	paths := []string{"containers", "initContainers", "ephemeralContainers"}
 	updated := false
 	for _, path := range paths {
 		containers, found := obj[path]
@@ -30,7 +30,7 @@ func (p *LabelTransformerPlugin) Transform(m resmap.ResMap) error {
 	if err != nil {
 		return err
 	}
-	return t.Transform(m)
+	return t.Transform(transformers.WithoutSkipping(m, "commonLabels"))
 }
 
 func NewLabelTransformerPlugin() resmap.TransformerPlugin {
@@ -5,9 +5,11 @@ import (
 	"sort"
 
 	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/yaml"
 )
 
 // Sort the resources using an ordering defined in the Gvk class.
@@ -15,18 +17,28 @@ import (
 // dependencies (like Namespace, StorageClass, etc.)
 // first, and resources with a high number of dependencies
 // (like ValidatingWebhookConfiguration) last.
-type LegacyOrderTransformerPlugin struct{}
+// OrderFirst and OrderLast, if either is set, replace
+// the kinds put first and last.
+type LegacyOrderTransformerPlugin struct {
+	OrderFirst []string `json:"orderFirst,omitempty" yaml:"orderFirst,omitempty"`
+	OrderLast  []string `json:"orderLast,omitempty" yaml:"orderLast,omitempty"`
+}
 
-// Nothing needed for configuration.
 func (p *LegacyOrderTransformerPlugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
-	return nil
+	p.OrderFirst = nil
+	p.OrderLast = nil
+	return yaml.Unmarshal(c, p)
 }
 
 func (p *LegacyOrderTransformerPlugin) Transform(m resmap.ResMap) (err error) {
 	resources := make([]*resource.Resource, m.Size())
 	ids := m.AllIds()
-	sort.Sort(resmap.IdSlice(ids))
+	if p.OrderFirst == nil && p.OrderLast == nil {
+		sort.Sort(resmap.IdSlice(ids))
+	} else {
+		resmap.SortIdsIn(ids, gvk.NewKindOrder(p.OrderFirst, p.OrderLast))
+	}
 	for i, id := range ids {
 		resources[i], err = m.GetByCurrentId(id)
 		if err != nil {
@@ -18,12 +18,22 @@ import (
 type NamespaceTransformerPlugin struct {
 	types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
 	FieldSpecs       []config.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+
+	// UnsetOnly if true leaves namespaces that
+	// are set already untouched.
+	UnsetOnly bool `json:"unsetOnly,omitempty" yaml:"unsetOnly,omitempty"`
+
+	// Subjects specifies which subjects of
+	// (Cluster)RoleBindings get the namespace.
+	Subjects types.SubjectsPolicy `json:"subjects,omitempty" yaml:"subjects,omitempty"`
 }
 
 func (p *NamespaceTransformerPlugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
 	p.Namespace = ""
 	p.FieldSpecs = nil
+	p.UnsetOnly = false
+	p.Subjects = types.SubjectsUnspecified
 	return yaml.Unmarshal(c, p)
 }
 
@@ -36,6 +46,9 @@ func (p *NamespaceTransformerPlugin) Transform(m resmap.ResMap) error {
 			// Don't mutate empty objects?
 			continue
 		}
+		if transformers.Skips(r, "namespace") {
+			continue
+		}
 
 		id := r.OrgId()
 		applicableFs := p.applicableFieldSpecs(id)
@@ -80,9 +93,16 @@ func (p *NamespaceTransformerPlugin) changeNamespace(
 		case string:
 			// will happen when the metadata/namespace
 			// value is replaced
+			if p.UnsetOnly && in.(string) != "" {
+				return in, nil
+			}
 			return p.Namespace, nil
 		case []interface{}:
 			l, _ := in.([]interface{})
+			policy := p.Subjects.Effective()
+			if policy == types.SubjectsNone {
+				return in, nil
+			}
 			for idx, item := range l {
 				switch item.(type) {
 				case map[string]interface{}:
@@ -96,10 +116,17 @@ func (p *NamespaceTransformerPlugin) changeNamespace(
 					if !ok {
 						continue
 					}
-					// The only case we need to force the namespace
-					// if for the "service account". "default" is
-					// kind of hardcoded here for right now.
-					if name != "default" {
+					if policy == types.SubjectsAllServiceAccounts {
+						if inMap["kind"] != "ServiceAccount" {
+							continue
+						}
+					} else if name != "default" {
+						// By default, the only case we need to
+						// force the namespace is for the "default"
+						// service account.
+						continue
+					}
+					if ns, _ := inMap["namespace"].(string); p.UnsetOnly && ns != "" {
 						continue
 					}
 					inMap["namespace"] = p.Namespace
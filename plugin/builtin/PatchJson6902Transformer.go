@@ -3,6 +3,9 @@ package builtin
 
 import (
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/pkg/errors"
@@ -10,6 +13,7 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
@@ -29,8 +33,16 @@ func (p *PatchJson6902TransformerPlugin) Config(
 	if err != nil {
 		return err
 	}
-	if p.Target.Name == "" {
-		return fmt.Errorf("must specify the target name")
+	for _, s := range []string{p.Target.Name, p.Target.Namespace} {
+		if _, err := regexp.Compile(s); err != nil {
+			return errors.Wrapf(err, "bad target %q", s)
+		}
+	}
+	if p.Target.Kind == "" && p.Target.Name == "" &&
+		p.Target.LabelSelector == "" && p.Target.AnnotationSelector == "" {
+		return fmt.Errorf(
+			"target must have a kind, a name, " +
+				"a labelSelector or an annotationSelector")
 	}
 	if p.Path == "" && p.JsonOp == "" {
 		return fmt.Errorf("empty file path and empty jsonOp")
@@ -69,29 +81,82 @@ func (p *PatchJson6902TransformerPlugin) Config(
 }
 
 func (p *PatchJson6902TransformerPlugin) Transform(m resmap.ResMap) error {
-	id := resid.NewResIdWithNamespace(
-		gvk.Gvk{
-			Group:   p.Target.Group,
-			Version: p.Target.Version,
-			Kind:    p.Target.Kind,
-		},
-		p.Target.Name,
-		p.Target.Namespace,
-	)
-	obj, err := m.GetById(id)
+	targets, err := p.targets(m)
 	if err != nil {
 		return err
 	}
-	rawObj, err := obj.MarshalJSON()
-	if err != nil {
-		return err
+	for _, obj := range targets {
+		rawObj, err := obj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		modifiedObj, err := p.decodedPatch.Apply(rawObj)
+		if err != nil {
+			return errors.Wrapf(
+				err, "failed to apply json patch '%s'", p.JsonOp)
+		}
+		if p.Path != "" {
+			obj.AppendProvenance(filepath.Join(p.ldr.Root(), p.Path))
+		}
+		err = obj.UnmarshalJSON(modifiedObj)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// targets returns the resources to patch.  A target
+// with a name, a kind and nothing else matches exactly
+// one resource.  Otherwise, i.e. if the name or namespace
+// is a regular expression (which gets anchored), the name
+// or kind is left empty, or a label or annotation selector
+// is given, the target may match many resources, but must
+// match at least one.
+func (p *PatchJson6902TransformerPlugin) targets(m resmap.ResMap) ([]*resource.Resource, error) {
+	if p.isSingleTarget() {
+		id := resid.NewResIdWithNamespace(
+			gvk.Gvk{
+				Group:   p.Target.Group,
+				Version: p.Target.Version,
+				Kind:    p.Target.Kind,
+			},
+			p.Target.Name,
+			p.Target.Namespace,
+		)
+		obj, err := m.GetById(id)
+		if err != nil {
+			return nil, err
+		}
+		return []*resource.Resource{obj}, nil
 	}
-	modifiedObj, err := p.decodedPatch.Apply(rawObj)
+	result, err := m.Select(types.Selector{
+		Gvk:                p.Target.Gvk,
+		Namespace:          p.Target.Namespace,
+		Name:               p.Target.Name,
+		AnnotationSelector: p.Target.AnnotationSelector,
+		LabelSelector:      p.Target.LabelSelector,
+	})
 	if err != nil {
-		return errors.Wrapf(
-			err, "failed to apply json patch '%s'", p.JsonOp)
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf(
+			"no matches for json patch target %v", p.Target)
 	}
-	return obj.UnmarshalJSON(modifiedObj)
+	return result, nil
+}
+
+func (p *PatchJson6902TransformerPlugin) isSingleTarget() bool {
+	return p.Target.Name != "" && p.Target.Kind != "" &&
+		!isRegex(p.Target.Name) && !isRegex(p.Target.Namespace) &&
+		p.Target.AnnotationSelector == "" && p.Target.LabelSelector == ""
+}
+
+// isRegex returns true if s holds regular expression
+// metacharacters other than the dots found in names.
+func isRegex(s string) bool {
+	return strings.Replace(regexp.QuoteMeta(s), `\.`, ".", -1) != s
 }
 
 func NewPatchJson6902TransformerPlugin() resmap.TransformerPlugin {
@@ -3,10 +3,12 @@ package builtin
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
@@ -17,6 +19,10 @@ type PatchStrategicMergeTransformerPlugin struct {
 	loadedPatches []*resource.Resource
 	Paths         []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
 	Patches       string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+
+	// PatchMergeKeys declares how to merge lists in
+	// objects of types without a built-in schema.
+	PatchMergeKeys []config.PatchMergeKey `json:"patchMergeKeys,omitempty" yaml:"patchMergeKeys,omitempty"`
 }
 
 func (p *PatchStrategicMergeTransformerPlugin) Config(
@@ -41,6 +47,9 @@ func (p *PatchStrategicMergeTransformerPlugin) Config(
 			if err != nil {
 				return err
 			}
+			for _, r := range res {
+				r.AppendProvenance(filepath.Join(ldr.Root(), string(onePath)))
+			}
 			p.loadedPatches = append(p.loadedPatches, res...)
 		}
 	}
@@ -64,12 +73,19 @@ func (p *PatchStrategicMergeTransformerPlugin) Transform(m resmap.ResMap) error
 	if err != nil {
 		return err
 	}
+	for _, patch := range p.loadedPatches {
+		if target, err := m.GetById(patch.OrgId()); err == nil {
+			target.AppendProvenance(patch.Provenance()...)
+			target.MergeComments(patch.Comments())
+		}
+	}
 	for _, patch := range patches.Resources() {
 		target, err := m.GetById(patch.OrgId())
 		if err != nil {
 			return err
 		}
-		err = target.Patch(patch.Kunstructured)
+		err = target.PatchWithMergeKeys(patch.Kunstructured,
+			config.MergeKeysFor(p.PatchMergeKeys, target.GetGvk()))
 		if err != nil {
 			return err
 		}
@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
 
@@ -19,6 +20,12 @@ type PrefixSuffixTransformerPlugin struct {
 	Prefix     string             `json:"prefix,omitempty" yaml:"prefix,omitempty"`
 	Suffix     string             `json:"suffix,omitempty" yaml:"suffix,omitempty"`
 	FieldSpecs []config.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+
+	// SkipKinds are kinds whose names are left alone.
+	SkipKinds []string `json:"skipKinds,omitempty" yaml:"skipKinds,omitempty"`
+
+	// Target, if not nil, selects the resources to rename.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
 }
 
 // Not placed in a file yet due to lack of demand.
@@ -36,6 +43,8 @@ func (p *PrefixSuffixTransformerPlugin) Config(
 	p.Prefix = ""
 	p.Suffix = ""
 	p.FieldSpecs = nil
+	p.SkipKinds = nil
+	p.Target = nil
 	err = yaml.Unmarshal(c, p)
 	if err != nil {
 		return
@@ -52,12 +61,27 @@ func (p *PrefixSuffixTransformerPlugin) Transform(m resmap.ResMap) error {
 	// to proceed with the transformation. This allows to add contextual
 	// information to the resources (AddNamePrefix and AddNameSuffix).
 
-	for _, r := range m.Resources() {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
 		if p.shouldSkip(r.OrgId()) {
 			// Don't change the actual definition
 			// of a CRD.
 			continue
 		}
+		prefix, suffix := p.Prefix, p.Suffix
+		if transformers.Skips(r, "namePrefix") {
+			prefix = ""
+		}
+		if transformers.Skips(r, "nameSuffix") {
+			suffix = ""
+		}
 		id := r.OrgId()
 		// current default configuration contains
 		// only one entry: "metadata/name" with no GVK
@@ -74,8 +98,8 @@ func (p *PrefixSuffixTransformerPlugin) Transform(m resmap.ResMap) error {
 				// this will add a prefix and a suffix
 				// to the resource even if those are
 				// empty
-				r.AddNamePrefix(p.Prefix)
-				r.AddNameSuffix(p.Suffix)
+				r.AddNamePrefix(prefix)
+				r.AddNameSuffix(suffix)
 			}
 
 			// the addPrefixSuffix method will not
@@ -85,7 +109,7 @@ func (p *PrefixSuffixTransformerPlugin) Transform(m resmap.ResMap) error {
 				r.Map(),
 				path.PathSlice(),
 				path.CreateIfNotPresent,
-				p.addPrefixSuffix)
+				addPrefixSuffix(prefix, suffix))
 			if err != nil {
 				return err
 			}
@@ -105,16 +129,23 @@ func (p *PrefixSuffixTransformerPlugin) shouldSkip(
 			return true
 		}
 	}
+	for _, kind := range p.SkipKinds {
+		if id.Kind == kind {
+			return true
+		}
+	}
 	return false
 }
 
-func (p *PrefixSuffixTransformerPlugin) addPrefixSuffix(
-	in interface{}) (interface{}, error) {
-	s, ok := in.(string)
-	if !ok {
-		return nil, fmt.Errorf("%#v is expected to be %T", in, s)
+func addPrefixSuffix(
+	prefix, suffix string) func(interface{}) (interface{}, error) {
+	return func(in interface{}) (interface{}, error) {
+		s, ok := in.(string)
+		if !ok {
+			return nil, fmt.Errorf("%#v is expected to be %T", in, s)
+		}
+		return fmt.Sprintf("%s%s%s", prefix, s, suffix), nil
 	}
-	return fmt.Sprintf("%s%s%s", p.Prefix, s, p.Suffix), nil
 }
 
 func NewPrefixSuffixTransformerPlugin() resmap.TransformerPlugin {
@@ -3,6 +3,7 @@ package builtin
 
 import (
 	"fmt"
+	"log"
 
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
@@ -38,6 +39,15 @@ func (p *ReplicaCountTransformerPlugin) Transform(m resmap.ResMap) error {
 
 		for _, res := range append(matchOriginal, matchCurrent...) {
 			found = true
+			if transformers.Skips(res, "replicas") {
+				continue
+			}
+			if hpa := transformers.Autoscaler(m, res); hpa != nil {
+				log.Printf(
+					"warning: not setting the replicas of %s, "+
+						"it's scaled by %s", res.CurId(), hpa.CurId())
+				continue
+			}
 			err := transformers.MutateField(
 				res.Map(), replicaSpec.PathSlice(),
 				replicaSpec.CreateIfNotPresent, p.addReplicas)
@@ -7,8 +7,10 @@ package main
 import (
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
 
@@ -16,6 +18,14 @@ import (
 type plugin struct {
 	Annotations map[string]string  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	FieldSpecs  []config.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+
+	// Include, if not empty, limits the annotated resources
+	// to those that any of its selectors selects.
+	Include []types.Selector `json:"include,omitempty" yaml:"include,omitempty"`
+
+	// Exclude selects resources that aren't annotated,
+	// even though Include selects them.
+	Exclude []types.Selector `json:"exclude,omitempty" yaml:"exclude,omitempty"`
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -25,6 +35,8 @@ func (p *plugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
 	p.Annotations = nil
 	p.FieldSpecs = nil
+	p.Include = nil
+	p.Exclude = nil
 	return yaml.Unmarshal(c, p)
 }
 
@@ -36,5 +48,46 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	if err != nil {
 		return err
 	}
-	return t.Transform(m)
+	selected, err := p.selected(
+		transformers.WithoutSkipping(m, "commonAnnotations"))
+	if err != nil {
+		return err
+	}
+	return t.Transform(selected)
+}
+
+// selected returns the resources of m that the include
+// selectors, if any, select and the exclude selectors don't.
+// The resources are shared with m.
+func (p *plugin) selected(m resmap.ResMap) (resmap.ResMap, error) {
+	if len(p.Include) == 0 && len(p.Exclude) == 0 {
+		return m, nil
+	}
+	included := make(map[*resource.Resource]bool)
+	for _, s := range p.Include {
+		resources, err := m.Select(s)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			included[res] = true
+		}
+	}
+	for _, s := range p.Exclude {
+		resources, err := m.Select(s)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			included[res] = false
+		}
+	}
+	result := resmap.New()
+	for _, res := range m.Resources() {
+		if keep, ok := included[res]; keep || (!ok && len(p.Include) == 0) {
+			// Can't fail, as the resources of m have distinct ids.
+			_ = result.Append(res)
+		}
+	}
+	return result, nil
 }
@@ -53,3 +53,63 @@ spec:
   - port: 7002
 `)
 }
+
+func TestAnnotationsTransformerSelected(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "AnnotationsTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: AnnotationsTransformer
+metadata:
+  name: notImportantHere
+annotations:
+  nginx.ingress.kubernetes.io/ssl-redirect: "true"
+fieldSpecs:
+- path: metadata/annotations
+  create: true
+include:
+- kind: Ingress
+exclude:
+- name: internal
+`, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: public
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: internal
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: public
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  annotations:
+    nginx.ingress.kubernetes.io/ssl-redirect: "true"
+  name: public
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: internal
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: public
+`)
+}
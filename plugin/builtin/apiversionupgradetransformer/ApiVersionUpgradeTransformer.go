@@ -0,0 +1,402 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Upgrade resources of apiVersions that the given
+// Kubernetes version no longer serves to their
+// replacements, moving fields as the new ones require.
+type plugin struct {
+	KubeVersion types.KubeVersion `json:"kubeVersion,omitempty" yaml:"kubeVersion,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// apiUpgrade replaces the group and version of the
+// given kinds, from the release that removed them on.
+type apiUpgrade struct {
+	from      gvk.Gvk
+	kinds     []string
+	to        gvk.Gvk
+	removedIn int
+	// move, if not nil, moves the fields of an
+	// object to where the new version has them.
+	move func(obj map[string]interface{}) error
+}
+
+var workloadKinds = []string{
+	"DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"}
+
+var rbacKinds = []string{
+	"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}
+
+var webhookKinds = []string{
+	"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}
+
+var apiUpgrades = []apiUpgrade{
+	{gvk.Gvk{Group: "extensions", Version: "v1beta1"}, workloadKinds,
+		gvk.Gvk{Group: "apps", Version: "v1"}, 16, moveWorkload},
+	{gvk.Gvk{Group: "apps", Version: "v1beta1"}, workloadKinds,
+		gvk.Gvk{Group: "apps", Version: "v1"}, 16, moveWorkload},
+	{gvk.Gvk{Group: "apps", Version: "v1beta2"}, workloadKinds,
+		gvk.Gvk{Group: "apps", Version: "v1"}, 16, moveWorkload},
+	{gvk.Gvk{Group: "extensions", Version: "v1beta1"}, []string{"NetworkPolicy"},
+		gvk.Gvk{Group: "networking.k8s.io", Version: "v1"}, 16, nil},
+	{gvk.Gvk{Group: "extensions", Version: "v1beta1"}, []string{"PodSecurityPolicy"},
+		gvk.Gvk{Group: "policy", Version: "v1beta1"}, 16, nil},
+	{gvk.Gvk{Group: "extensions", Version: "v1beta1"}, []string{"Ingress"},
+		gvk.Gvk{Group: "networking.k8s.io", Version: "v1"}, 22, moveIngress},
+	{gvk.Gvk{Group: "networking.k8s.io", Version: "v1beta1"}, []string{"Ingress"},
+		gvk.Gvk{Group: "networking.k8s.io", Version: "v1"}, 22, moveIngress},
+	{gvk.Gvk{Group: "networking.k8s.io", Version: "v1beta1"}, []string{"IngressClass"},
+		gvk.Gvk{Group: "networking.k8s.io", Version: "v1"}, 22, nil},
+	{gvk.Gvk{Group: "rbac.authorization.k8s.io", Version: "v1beta1"}, rbacKinds,
+		gvk.Gvk{Group: "rbac.authorization.k8s.io", Version: "v1"}, 22, nil},
+	{gvk.Gvk{Group: "scheduling.k8s.io", Version: "v1beta1"}, []string{"PriorityClass"},
+		gvk.Gvk{Group: "scheduling.k8s.io", Version: "v1"}, 22, nil},
+	{gvk.Gvk{Group: "coordination.k8s.io", Version: "v1beta1"}, []string{"Lease"},
+		gvk.Gvk{Group: "coordination.k8s.io", Version: "v1"}, 22, nil},
+	{gvk.Gvk{Group: "apiextensions.k8s.io", Version: "v1beta1"}, []string{"CustomResourceDefinition"},
+		gvk.Gvk{Group: "apiextensions.k8s.io", Version: "v1"}, 22, moveCustomResourceDefinition},
+	{gvk.Gvk{Group: "admissionregistration.k8s.io", Version: "v1beta1"}, webhookKinds,
+		gvk.Gvk{Group: "admissionregistration.k8s.io", Version: "v1"}, 22, moveWebhooks},
+	{gvk.Gvk{Group: "batch", Version: "v1beta1"}, []string{"CronJob"},
+		gvk.Gvk{Group: "batch", Version: "v1"}, 25, nil},
+	{gvk.Gvk{Group: "policy", Version: "v1beta1"}, []string{"PodDisruptionBudget"},
+		gvk.Gvk{Group: "policy", Version: "v1"}, 25, nil},
+	{gvk.Gvk{Group: "autoscaling", Version: "v2beta1"}, []string{"HorizontalPodAutoscaler"},
+		gvk.Gvk{Group: "autoscaling", Version: "v2"}, 25, moveAutoscaler},
+	{gvk.Gvk{Group: "autoscaling", Version: "v2beta2"}, []string{"HorizontalPodAutoscaler"},
+		gvk.Gvk{Group: "autoscaling", Version: "v2"}, 26, nil},
+}
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.KubeVersion = ""
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	minor, ok := p.KubeVersion.Minor()
+	if !ok {
+		return fmt.Errorf(
+			"invalid kubeVersion %s, must be like 1.22", p.KubeVersion)
+	}
+	for _, r := range m.Resources() {
+		if transformers.Skips(r, "kubeVersion") {
+			continue
+		}
+		u := findUpgrade(r.GetGvk(), minor)
+		if u == nil {
+			continue
+		}
+		if u.move != nil {
+			if err := u.move(r.Map()); err != nil {
+				return fmt.Errorf("upgrading %s: %v", r.CurId(), err)
+			}
+		}
+		r.SetGvk(gvk.Gvk{
+			Group: u.to.Group, Version: u.to.Version, Kind: r.GetKind()})
+	}
+	return nil
+}
+
+// findUpgrade returns the upgrade of the given group,
+// version and kind that the given minor version of
+// Kubernetes requires, or nil if there's none.
+func findUpgrade(x gvk.Gvk, minor int) *apiUpgrade {
+	for i, u := range apiUpgrades {
+		if u.removedIn > minor ||
+			x.Group != u.from.Group || x.Version != u.from.Version {
+			continue
+		}
+		for _, k := range u.kinds {
+			if k == x.Kind {
+				return &apiUpgrades[i]
+			}
+		}
+	}
+	return nil
+}
+
+// moveWorkload sets the selector, which apps/v1 requires,
+// to the labels of the template if it's missing, and
+// drops the fields that apps/v1 doesn't have.
+func moveWorkload(obj map[string]interface{}) error {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	delete(spec, "rollbackTo")
+	delete(spec, "templateGeneration")
+	if _, ok := spec["selector"]; ok {
+		return nil
+	}
+	template, _ := spec["template"].(map[string]interface{})
+	metadata, _ := template["metadata"].(map[string]interface{})
+	labels, ok := metadata["labels"].(map[string]interface{})
+	if !ok || len(labels) == 0 {
+		return fmt.Errorf(
+			"no selector, and no template labels to select")
+	}
+	spec["selector"] = map[string]interface{}{
+		"matchLabels": runtime.DeepCopyJSONValue(labels),
+	}
+	return nil
+}
+
+// moveIngress moves the default backend to defaultBackend,
+// converts the backends to networking.k8s.io/v1 service
+// backends, and sets the pathType, which it requires, of
+// the paths that don't set it.
+func moveIngress(obj map[string]interface{}) error {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if b, ok := spec["backend"].(map[string]interface{}); ok {
+		delete(spec, "backend")
+		spec["defaultBackend"] = b
+		if err := moveBackend(b); err != nil {
+			return err
+		}
+	}
+	rules, _ := spec["rules"].([]interface{})
+	for _, rule := range rules {
+		rule, _ := rule.(map[string]interface{})
+		http, _ := rule["http"].(map[string]interface{})
+		paths, _ := http["paths"].([]interface{})
+		for _, path := range paths {
+			path, ok := path.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := path["pathType"]; !ok {
+				path["pathType"] = "ImplementationSpecific"
+			}
+			if b, ok := path["backend"].(map[string]interface{}); ok {
+				if err := moveBackend(b); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// moveCustomResourceDefinition moves the version, and the
+// schema, subresources and printer columns of all versions,
+// to each version, gives the versions without a schema one
+// keeping unknown fields, as apiextensions.k8s.io/v1 requires
+// a schema, and moves the client config of a conversion
+// webhook under webhook.
+func moveCustomResourceDefinition(obj map[string]interface{}) error {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	versions, _ := spec["versions"].([]interface{})
+	if v, ok := spec["version"]; ok {
+		delete(spec, "version")
+		if len(versions) == 0 {
+			versions = []interface{}{map[string]interface{}{
+				"name": v, "served": true, "storage": true,
+			}}
+			spec["versions"] = versions
+		}
+	}
+	common := map[string]interface{}{}
+	for old, key := range map[string]string{
+		"validation":               "schema",
+		"subresources":             "subresources",
+		"additionalPrinterColumns": "additionalPrinterColumns",
+	} {
+		if v, ok := spec[old]; ok {
+			delete(spec, old)
+			common[key] = v
+		}
+	}
+	for _, version := range versions {
+		version, ok := version.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, v := range common {
+			if _, ok := version[key]; !ok {
+				version[key] = runtime.DeepCopyJSONValue(v)
+			}
+		}
+		if _, ok := version["schema"]; !ok {
+			version["schema"] = map[string]interface{}{
+				"openAPIV3Schema": map[string]interface{}{
+					"type":                                 "object",
+					"x-kubernetes-preserve-unknown-fields": true,
+				},
+			}
+		}
+		columns, _ := version["additionalPrinterColumns"].([]interface{})
+		for _, column := range columns {
+			column, ok := column.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if path, ok := column["JSONPath"]; ok {
+				delete(column, "JSONPath")
+				column["jsonPath"] = path
+			}
+		}
+	}
+	conversion, _ := spec["conversion"].(map[string]interface{})
+	if config, ok := conversion["webhookClientConfig"]; ok {
+		reviewVersions, ok := conversion["conversionReviewVersions"]
+		if !ok {
+			reviewVersions = []interface{}{"v1beta1"}
+		}
+		delete(conversion, "webhookClientConfig")
+		delete(conversion, "conversionReviewVersions")
+		conversion["webhook"] = map[string]interface{}{
+			"clientConfig":             config,
+			"conversionReviewVersions": reviewVersions,
+		}
+	}
+	return nil
+}
+
+// moveWebhooks sets the fields of the webhooks whose
+// default admissionregistration.k8s.io/v1 changed to
+// the v1beta1 default, and fails on the side effects
+// that v1 doesn't allow.
+func moveWebhooks(obj map[string]interface{}) error {
+	webhooks, _ := obj["webhooks"].([]interface{})
+	for _, webhook := range webhooks {
+		webhook, ok := webhook.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sideEffects, ok := webhook["sideEffects"]
+		if !ok {
+			sideEffects = "Unknown"
+		}
+		if sideEffects != "None" && sideEffects != "NoneOnDryRun" {
+			return fmt.Errorf(
+				"webhook %v has sideEffects %v, but v1 allows only "+
+					"None or NoneOnDryRun", webhook["name"], sideEffects)
+		}
+		for key, v := range map[string]interface{}{
+			"admissionReviewVersions": []interface{}{"v1beta1"},
+			"failurePolicy":           "Ignore",
+			"matchPolicy":             "Exact",
+			"timeoutSeconds":          int64(30),
+		} {
+			if _, ok := webhook[key]; !ok {
+				webhook[key] = v
+			}
+		}
+	}
+	return nil
+}
+
+// metricSources maps the types of autoscaler
+// metrics to the fields holding their source.
+var metricSources = map[string]string{
+	"Resource": "resource",
+	"Pods":     "pods",
+	"Object":   "object",
+	"External": "external",
+}
+
+// moveAutoscaler moves the target values of the metrics of
+// an autoscaler to a target, and their metric names and
+// selectors to a metric, as autoscaling/v2 has them.  The
+// target object of an Object metric becomes its
+// describedObject.
+func moveAutoscaler(obj map[string]interface{}) error {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	metrics, _ := spec["metrics"].([]interface{})
+	for _, metric := range metrics {
+		metric, ok := metric.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := metric["type"].(string)
+		source, ok := metric[metricSources[kind]].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kind == "Object" {
+			if ref, ok := source["target"]; ok {
+				delete(source, "target")
+				source["describedObject"] = ref
+			}
+		}
+		if name, ok := source["metricName"]; ok {
+			delete(source, "metricName")
+			m := map[string]interface{}{"name": name}
+			for _, key := range []string{"selector", "metricSelector"} {
+				if selector, ok := source[key]; ok {
+					delete(source, key)
+					m["selector"] = selector
+				}
+			}
+			source["metric"] = m
+		}
+		target := map[string]interface{}{}
+		// An average value, if any, sets the type.
+		for _, t := range []struct{ old, key, kind string }{
+			{"targetAverageUtilization", "averageUtilization", "Utilization"},
+			{"targetValue", "value", "Value"},
+			{"targetAverageValue", "averageValue", "AverageValue"},
+			{"averageValue", "averageValue", "AverageValue"},
+		} {
+			if v, ok := source[t.old]; ok {
+				delete(source, t.old)
+				target[t.key] = v
+				target["type"] = t.kind
+			}
+		}
+		if len(target) > 0 {
+			source["target"] = target
+		}
+	}
+	return nil
+}
+
+// moveBackend replaces the serviceName and servicePort
+// of a backend with a service holding a name and a port
+// number or name.  Resource backends are left alone.
+func moveBackend(b map[string]interface{}) error {
+	name, ok := b["serviceName"]
+	if !ok {
+		return nil
+	}
+	port := map[string]interface{}{}
+	switch v := b["servicePort"].(type) {
+	case string:
+		port["name"] = v
+	case int64, float64:
+		port["number"] = v
+	default:
+		return fmt.Errorf("unexpected servicePort %v", v)
+	}
+	delete(b, "serviceName")
+	delete(b, "servicePort")
+	b["service"] = map[string]interface{}{"name": name, "port": port}
+	return nil
+}
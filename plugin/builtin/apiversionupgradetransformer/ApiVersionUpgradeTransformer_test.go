@@ -0,0 +1,346 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+const deprecatedInput = `
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  rollbackTo:
+    revision: 1
+  template:
+    metadata:
+      labels:
+        app: web
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  backend:
+    serviceName: default
+    servicePort: 80
+  rules:
+  - http:
+      paths:
+      - backend:
+          serviceName: web
+          servicePort: http
+        path: /
+      - backend:
+          serviceName: api
+          servicePort: 8080
+        path: /api
+        pathType: Prefix
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+`
+
+func TestApiVersionUpgradeTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ApiVersionUpgradeTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ApiVersionUpgradeTransformer
+metadata:
+  name: notImportantHere
+kubeVersion: "1.22"
+`, deprecatedInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  defaultBackend:
+    service:
+      name: default
+      port:
+        number: 80
+  rules:
+  - http:
+      paths:
+      - backend:
+          service:
+            name: web
+            port:
+              name: http
+        path: /
+        pathType: ImplementationSpecific
+      - backend:
+          service:
+            name: api
+            port:
+              number: 8080
+        path: /api
+        pathType: Prefix
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+`)
+}
+
+func TestApiVersionUpgradeTransformerOlderVersion(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ApiVersionUpgradeTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ApiVersionUpgradeTransformer
+metadata:
+  name: notImportantHere
+kubeVersion: v1.15.3
+`, deprecatedInput)
+
+	th.AssertActualEqualsExpected(rm, deprecatedInput)
+}
+
+func TestApiVersionUpgradeTransformerNoSelector(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ApiVersionUpgradeTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: ApiVersionUpgradeTransformer
+metadata:
+  name: notImportantHere
+kubeVersion: "1.16"
+`, `
+apiVersion: apps/v1beta1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  serviceName: db
+`)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"no selector, and no template labels to select") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApiVersionUpgradeTransformerMovedFields(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ApiVersionUpgradeTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ApiVersionUpgradeTransformer
+metadata:
+  name: notImportantHere
+kubeVersion: "1.25"
+`, `
+apiVersion: autoscaling/v2beta1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  maxReplicas: 10
+  metrics:
+  - type: Resource
+    resource:
+      name: cpu
+      targetAverageUtilization: 80
+  - type: Pods
+    pods:
+      metricName: requests
+      targetAverageValue: 100
+  - type: Object
+    object:
+      metricName: hits
+      target:
+        apiVersion: networking.k8s.io/v1
+        kind: Ingress
+        name: web
+      targetValue: 2k
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.example.com
+spec:
+  group: example.com
+  version: v1
+  validation:
+    openAPIV3Schema:
+      type: object
+  additionalPrinterColumns:
+  - name: Schedule
+    type: string
+    JSONPath: .spec.schedule
+  conversion:
+    strategy: Webhook
+    webhookClientConfig:
+      url: https://convert.example.com
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: check
+webhooks:
+- name: check.example.com
+  sideEffects: None
+  clientConfig:
+    url: https://check.example.com
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  maxReplicas: 10
+  metrics:
+  - resource:
+      name: cpu
+      target:
+        averageUtilization: 80
+        type: Utilization
+    type: Resource
+  - pods:
+      metric:
+        name: requests
+      target:
+        averageValue: 100
+        type: AverageValue
+    type: Pods
+  - object:
+      describedObject:
+        apiVersion: networking.k8s.io/v1
+        kind: Ingress
+        name: web
+      metric:
+        name: hits
+      target:
+        type: Value
+        value: 2k
+    type: Object
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.example.com
+spec:
+  conversion:
+    strategy: Webhook
+    webhook:
+      clientConfig:
+        url: https://convert.example.com
+      conversionReviewVersions:
+      - v1beta1
+  group: example.com
+  versions:
+  - additionalPrinterColumns:
+    - jsonPath: .spec.schedule
+      name: Schedule
+      type: string
+    name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+    served: true
+    storage: true
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: check
+webhooks:
+- admissionReviewVersions:
+  - v1beta1
+  clientConfig:
+    url: https://check.example.com
+  failurePolicy: Ignore
+  matchPolicy: Exact
+  name: check.example.com
+  sideEffects: None
+  timeoutSeconds: 30
+`)
+}
+
+func TestApiVersionUpgradeTransformerWebhookSideEffects(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ApiVersionUpgradeTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: ApiVersionUpgradeTransformer
+metadata:
+  name: notImportantHere
+kubeVersion: "1.22"
+`, `
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: inject
+webhooks:
+- name: inject.example.com
+`)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "has sideEffects Unknown") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pkg/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Set resource requests and limits on the
+// containers of workloads that don't set them.
+type plugin struct {
+	Target   *types.Selector   `json:"target,omitempty" yaml:"target,omitempty"`
+	Requests map[string]string `json:"requests,omitempty" yaml:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Target = nil
+	p.Requests = nil
+	p.Limits = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		if transformers.Skips(r, "defaultResources") {
+			continue
+		}
+		for _, spec := range transformers.PodSpecs(r.Map()) {
+			for _, key := range []string{"containers", "initContainers"} {
+				if err := p.setDefaults(r, spec[key]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// setDefaults sets the defaults on the given
+// containers, if any.
+func (p *plugin) setDefaults(
+	r *resource.Resource, in interface{}) error {
+	if in == nil {
+		return nil
+	}
+	containers, ok := in.([]interface{})
+	if !ok {
+		return fmt.Errorf(
+			"containers of %s are not a list but %T", r.OrgId(), in)
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf(
+				"container of %s is not a map but %T", r.OrgId(), c)
+		}
+		if err := p.setDefaultsOf(r, container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setDefaultsOf sets each request and limit the container
+// lacks, but no request above the container's own limit of the
+// resource, nor limit below its own request, which the API
+// server would reject.
+func (p *plugin) setDefaultsOf(
+	r *resource.Resource, container map[string]interface{}) error {
+	own := map[string]map[string]interface{}{}
+	if resources, ok := container["resources"].(map[string]interface{}); ok {
+		for _, field := range []string{"requests", "limits"} {
+			if quantities, ok := resources[field].(map[string]interface{}); ok {
+				own[field] = quantities
+			}
+		}
+	}
+	for _, d := range []struct {
+		field    string
+		defaults map[string]string
+		bound    string
+		// sign of the comparison to the bound that makes
+		// the default invalid.
+		exceeding int
+	}{
+		{"requests", p.Requests, "limits", 1},
+		{"limits", p.Limits, "requests", -1},
+	} {
+		if len(d.defaults) == 0 {
+			continue
+		}
+		resources, err := childMap(r, container, "resources")
+		if err != nil {
+			return err
+		}
+		quantities, err := childMap(r, resources, d.field)
+		if err != nil {
+			return err
+		}
+		for name, quantity := range d.defaults {
+			if _, found := quantities[name]; found {
+				continue
+			}
+			if bound, found := own[d.bound][name]; found {
+				cmp, err := compareQuantities(quantity, bound)
+				if err != nil {
+					return errors.Wrapf(err, "%s of %s", name, r.OrgId())
+				}
+				if cmp == d.exceeding {
+					log.Printf(
+						"warning: not defaulting the %s %s of %s to %s, "+
+							"the container's %s is %v",
+						name, d.field, r.OrgId(), quantity, d.bound, bound)
+					continue
+				}
+			}
+			quantities[name] = quantity
+		}
+	}
+	return nil
+}
+
+// compareQuantities returns -1, 0 or 1 as the quantity
+// q is less than, equal to, or greater than the other.
+func compareQuantities(q string, other interface{}) (int, error) {
+	x, err := k8sresource.ParseQuantity(q)
+	if err != nil {
+		return 0, err
+	}
+	y, err := k8sresource.ParseQuantity(fmt.Sprint(other))
+	if err != nil {
+		return 0, err
+	}
+	return x.Cmp(y), nil
+}
+
+// childMap returns the map at the given key of the
+// parent, adding an empty one if the key is missing.
+func childMap(r *resource.Resource,
+	parent map[string]interface{}, key string) (map[string]interface{}, error) {
+	v, found := parent[key]
+	if !found || v == nil {
+		child := map[string]interface{}{}
+		parent[key] = child
+		return child, nil
+	}
+	child, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf(
+			"%s in %s is not a map but %T", key, r.OrgId(), v)
+	}
+	return child, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+func TestDefaultResourcesTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "DefaultResourcesTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: DefaultResourcesTransformer
+metadata:
+  name: notImportantHere
+target:
+  kind: Deployment
+requests:
+  cpu: 100m
+  memory: 128Mi
+limits:
+  memory: 256Mi
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate
+      containers:
+      - name: nginx
+        image: nginx
+      - name: app
+        image: app
+        resources:
+          requests:
+            cpu: "2"
+          limits:
+            cpu: "4"
+            memory: 1Gi
+      - name: worker
+        image: worker
+        resources:
+          requests:
+            memory: 512Mi
+          limits:
+            cpu: 50m
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: shell
+    image: busybox
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+        resources:
+          limits:
+            memory: 256Mi
+          requests:
+            cpu: 100m
+            memory: 128Mi
+      - image: app
+        name: app
+        resources:
+          limits:
+            cpu: "4"
+            memory: 1Gi
+          requests:
+            cpu: "2"
+            memory: 128Mi
+      - image: worker
+        name: worker
+        resources:
+          limits:
+            cpu: 50m
+          requests:
+            memory: 512Mi
+      initContainers:
+      - image: migrate
+        name: migrate
+        resources:
+          limits:
+            memory: 256Mi
+          requests:
+            cpu: 100m
+            memory: 128Mi
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - image: busybox
+    name: shell
+`)
+}
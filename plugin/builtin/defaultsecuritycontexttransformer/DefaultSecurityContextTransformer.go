@@ -0,0 +1,126 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Merge default security contexts into those
+// of the pods and containers of workloads.
+type plugin struct {
+	Target    *types.Selector        `json:"target,omitempty" yaml:"target,omitempty"`
+	Pod       map[string]interface{} `json:"pod,omitempty" yaml:"pod,omitempty"`
+	Container map[string]interface{} `json:"container,omitempty" yaml:"container,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Target = nil
+	p.Pod = nil
+	p.Container = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		if transformers.Skips(r, "defaultSecurityContext") {
+			continue
+		}
+		for _, spec := range transformers.PodSpecs(r.Map()) {
+			if err := mergeSecurityContext(r, spec, p.Pod); err != nil {
+				return err
+			}
+			for _, key := range []string{
+				"containers", "initContainers", "ephemeralContainers"} {
+				if err := p.mergeIntoContainers(r, spec[key]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *plugin) mergeIntoContainers(
+	r *resource.Resource, in interface{}) error {
+	if in == nil {
+		return nil
+	}
+	containers, ok := in.([]interface{})
+	if !ok {
+		return fmt.Errorf(
+			"containers of %s are not a list but %T", r.OrgId(), in)
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf(
+				"container of %s is not a map but %T", r.OrgId(), c)
+		}
+		if err := mergeSecurityContext(r, container, p.Container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeSecurityContext merges the defaults into the
+// securityContext of the given pod spec or container.
+func mergeSecurityContext(r *resource.Resource,
+	obj map[string]interface{}, defaults map[string]interface{}) error {
+	if len(defaults) == 0 {
+		return nil
+	}
+	v, found := obj["securityContext"]
+	if !found || v == nil {
+		v = map[string]interface{}{}
+		obj["securityContext"] = v
+	}
+	sc, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf(
+			"securityContext in %s is not a map but %T", r.OrgId(), v)
+	}
+	mergeMissing(sc, defaults)
+	return nil
+}
+
+// mergeMissing sets the fields of defaults that dst
+// lacks, recursing into the maps both have.  Lists,
+// e.g. the capabilities to drop, aren't merged.
+func mergeMissing(dst, defaults map[string]interface{}) {
+	for k, v := range defaults {
+		existing, found := dst[k]
+		if !found {
+			dst[k] = runtime.DeepCopyJSONValue(v)
+			continue
+		}
+		dstMap, ok1 := existing.(map[string]interface{})
+		defaultsMap, ok2 := v.(map[string]interface{})
+		if ok1 && ok2 {
+			mergeMissing(dstMap, defaultsMap)
+		}
+	}
+}
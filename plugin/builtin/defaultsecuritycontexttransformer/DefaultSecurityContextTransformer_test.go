@@ -0,0 +1,125 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+func TestDefaultSecurityContextTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "DefaultSecurityContextTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: DefaultSecurityContextTransformer
+metadata:
+  name: notImportantHere
+target:
+  kind: Deployment
+pod:
+  runAsNonRoot: true
+  seccompProfile:
+    type: RuntimeDefault
+container:
+  allowPrivilegeEscalation: false
+  capabilities:
+    drop:
+    - ALL
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: false
+        seccompProfile:
+          localhostProfile: web.json
+      initContainers:
+      - name: chown
+        image: busybox
+        securityContext:
+          capabilities:
+            add:
+            - CHOWN
+      containers:
+      - name: nginx
+        image: nginx
+      - name: tcpdump
+        image: tcpdump
+        securityContext:
+          capabilities:
+            drop:
+            - KILL
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: shell
+    image: busybox
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+      - image: tcpdump
+        name: tcpdump
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - KILL
+      initContainers:
+      - image: busybox
+        name: chown
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            add:
+            - CHOWN
+            drop:
+            - ALL
+      securityContext:
+        runAsNonRoot: false
+        seccompProfile:
+          localhostProfile: web.json
+          type: RuntimeDefault
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - image: busybox
+    name: shell
+`)
+}
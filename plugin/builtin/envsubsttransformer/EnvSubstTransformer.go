@@ -0,0 +1,94 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Substitute the values of allowed environment
+// variables for references like ${CLUSTER} in
+// fields of resources.
+type plugin struct {
+	types.EnvSubst `json:",inline,omitempty" yaml:",inline,omitempty"`
+
+	// replacer replaces the references by the values.
+	replacer *strings.Replacer
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.EnvSubst = types.EnvSubst{}
+	p.replacer = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if errs := p.EnforceFields(); len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	if len(p.Vars) > 0 {
+		if err = loader.EnvReadable(ldr); err != nil {
+			return errors.Wrap(err, "envSubst")
+		}
+	}
+	var pairs []string
+	for _, name := range p.Vars {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return fmt.Errorf(
+				"environment variable %s of envSubst isn't set", name)
+		}
+		pairs = append(pairs, "${"+name+"}", value)
+	}
+	p.replacer = strings.NewReplacer(pairs...)
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, t := range p.Targets {
+		resources, err := m.Select(*t.Select)
+		if err != nil {
+			return err
+		}
+		for _, res := range resources {
+			if transformers.Skips(res, "envSubst") {
+				continue
+			}
+			for _, path := range t.FieldPaths {
+				err = transformers.MutateField(
+					res.Map(), strings.Split(path, "."), false, p.substitute)
+				if err != nil {
+					return errors.Wrapf(
+						err, "substituting into %s in %s", path, res.CurId())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// substitute replaces the references to the
+// variables in the field, which must be a string.
+func (p *plugin) substitute(in interface{}) (interface{}, error) {
+	s, ok := in.(string)
+	if !ok {
+		return nil, fmt.Errorf("field is not a string but %T", in)
+	}
+	return p.replacer.Replace(s), nil
+}
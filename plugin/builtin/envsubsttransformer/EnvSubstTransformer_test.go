@@ -0,0 +1,112 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+const envSubstConfig = `
+apiVersion: builtin
+kind: EnvSubstTransformer
+metadata:
+  name: notImportantHere
+vars:
+- KUSTOMIZE_TEST_CLUSTER
+targets:
+- select:
+    kind: Deployment
+  fieldPaths:
+  - spec.template.spec.containers.env.value
+  - spec.template.spec.containers.image
+`
+
+const envSubstResources = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: registry.${KUSTOMIZE_TEST_CLUSTER}.example.com/web
+        env:
+        - name: CLUSTER
+          value: ${KUSTOMIZE_TEST_CLUSTER}
+        - name: HOME
+          value: ${HOME}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  cluster: ${KUSTOMIZE_TEST_CLUSTER}
+`
+
+func TestEnvSubstTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "EnvSubstTransformer")
+
+	os.Setenv("KUSTOMIZE_TEST_CLUSTER", "eu-1")
+	defer os.Unsetenv("KUSTOMIZE_TEST_CLUSTER")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(envSubstConfig, envSubstResources)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: CLUSTER
+          value: eu-1
+        - name: HOME
+          value: ${HOME}
+        image: registry.eu-1.example.com/web
+        name: web
+---
+apiVersion: v1
+data:
+  cluster: ${KUSTOMIZE_TEST_CLUSTER}
+kind: ConfigMap
+metadata:
+  name: config
+`)
+}
+
+func TestEnvSubstTransformerUnset(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "EnvSubstTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	err := th.ErrorFromLoadAndRunTransformer(envSubstConfig, envSubstResources)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"environment variable KUSTOMIZE_TEST_CLUSTER of envSubst isn't set") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
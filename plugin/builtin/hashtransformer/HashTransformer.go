@@ -28,7 +28,9 @@ func (p *plugin) Config(
 func (p *plugin) Transform(m resmap.ResMap) error {
 	for _, res := range m.Resources() {
 		if res.NeedHashSuffix() {
-			h, err := p.hasher.Hash(res)
+			h, err := p.hasher.HashWith(
+				res, res.HashAlgorithm(), res.HashLength(),
+				res.HashNamespace())
 			if err != nil {
 				return err
 			}
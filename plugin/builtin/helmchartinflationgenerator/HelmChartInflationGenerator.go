@@ -0,0 +1,189 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/fs"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Inflate a helm chart with "helm template".
+type plugin struct {
+	ldr         ifc.Loader
+	rf          *resmap.Factory
+	helmCommand string
+	types.HelmGlobals
+	types.HelmChart
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+const (
+	defaultChartHome   = "charts"
+	chartFile          = "Chart.yaml"
+	defaultReleaseName = "release-name"
+)
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, config []byte) (err error) {
+	p.HelmGlobals = types.HelmGlobals{}
+	p.HelmChart = types.HelmChart{}
+	err = yaml.Unmarshal(config, p)
+	if err != nil {
+		return
+	}
+	if p.Name == "" {
+		return fmt.Errorf("helm chart must specify a name")
+	}
+	if p.ChartHome == "" {
+		p.ChartHome = defaultChartHome
+	}
+	if p.ReleaseName == "" {
+		p.ReleaseName = defaultReleaseName
+	}
+	p.helmCommand, err = loader.HelmCommand(ldr)
+	if err != nil {
+		return
+	}
+	p.ldr = ldr
+	p.rf = rf
+	return
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	tmpDir, err := ioutil.TempDir("", "kustomize-helm-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating tmp dir for helm")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	chartDir, err := p.chartDir()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"template", p.ReleaseName, chartDir}
+	if p.Namespace != "" {
+		args = append(args, "--namespace", p.Namespace)
+	}
+	if p.IncludeCRDs {
+		args = append(args, "--include-crds")
+	}
+	valuesFiles, err := p.writeValues(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range valuesFiles {
+		args = append(args, "--values", f)
+	}
+	out, err := p.runHelm(args...)
+	if err != nil {
+		return nil, err
+	}
+	return p.rf.NewResMapFromBytes(out)
+}
+
+// chartDir returns the directory holding the chart,
+// fetching the chart into it if it's not there yet.
+// The chart home must be in the kustomization root,
+// and the chart's name can't lead out of it.
+func (p *plugin) chartDir() (string, error) {
+	if filepath.IsAbs(p.ChartHome) {
+		return "", fmt.Errorf(
+			"helm chart home %s must be relative to the kustomization root",
+			p.ChartHome)
+	}
+	if strings.ContainsAny(p.Name, `/\`) || strings.Contains(p.Name, "..") {
+		return "", fmt.Errorf("helm chart name %s must not be a path", p.Name)
+	}
+	root := p.ldr.Root()
+	home := filepath.Join(root, p.ChartHome)
+	if !fs.ConfirmedDir(home).HasPrefix(fs.ConfirmedDir(root)) {
+		return "", fmt.Errorf(
+			"helm chart home %s is outside the kustomization root %s",
+			p.ChartHome, root)
+	}
+	dir := filepath.Join(home, p.Name)
+	// Every chart holds a Chart.yaml; loading it, rather than
+	// looking at the disk, keeps to the loader's file system
+	// and restrictions.
+	if _, err := p.ldr.Load(filepath.Join(dir, chartFile)); err == nil {
+		return dir, nil
+	}
+	if p.Repo == "" {
+		return "", fmt.Errorf(
+			"helm chart %s not found in %s and no repo specified",
+			p.Name, home)
+	}
+	args := []string{
+		"pull", p.Name, "--repo", p.Repo,
+		"--untar", "--untardir", home}
+	if p.Version != "" {
+		args = append(args, "--version", p.Version)
+	}
+	if _, err := p.runHelm(args...); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// writeValues writes the values files, loaded through the
+// loader to honor its restrictions, and the inline values
+// to dir, returning the paths to hand to helm, in order.
+func (p *plugin) writeValues(dir string) ([]string, error) {
+	var result []string
+	write := func(content []byte) error {
+		f := filepath.Join(dir, fmt.Sprintf("values-%d.yaml", len(result)))
+		if err := ioutil.WriteFile(f, content, 0600); err != nil {
+			return errors.Wrap(err, "writing helm values")
+		}
+		result = append(result, f)
+		return nil
+	}
+	for _, path := range p.ValuesFiles {
+		content, err := p.ldr.Load(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading helm values %s", path)
+		}
+		if err = write(content); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.ValuesInline) > 0 {
+		content, err := yaml.Marshal(p.ValuesInline)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshalling inline helm values")
+		}
+		if err = write(content); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (p *plugin) runHelm(args ...string) ([]byte, error) {
+	cmd := exec.Command(p.helmCommand, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "running %s %v: %s", p.helmCommand, args, stderr.String())
+	}
+	return out, nil
+}
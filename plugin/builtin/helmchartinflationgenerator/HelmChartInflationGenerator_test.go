@@ -0,0 +1,130 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+// fakeHelm is a stand-in for the helm binary.  It doesn't
+// fetch anything, and renders a ConfigMap recording the
+// arguments and values it was given.
+const fakeHelm = `#!/bin/sh
+if [ "$1" = pull ]; then
+  exit 0
+fi
+release=$2
+chart=$3
+shift 3
+echo "---"
+echo "# Source: chart/templates/configmap.yaml"
+echo "apiVersion: v1"
+echo "kind: ConfigMap"
+echo "metadata:"
+echo "  name: $release-config"
+echo "data:"
+echo "  chart: $(basename $chart)"
+echo "  flags: \"$(echo "$@" | sed 's|/[^ ]*/||g')\""
+echo "  values: |"
+while [ $# -gt 0 ]; do
+  if [ "$1" = --values ]; then
+    sed 's/^/    /' "$2"
+    shift
+  fi
+  shift
+done
+`
+
+func writeFakeHelm(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "fake-helm-")
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	bin := filepath.Join(dir, "helm")
+	err = ioutil.WriteFile(bin, []byte(fakeHelm), 0700)
+	if err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	return bin, func() { os.RemoveAll(dir) }
+}
+
+func TestHelmChartInflationGenerator(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "HelmChartInflationGenerator")
+
+	bin, cleanup := writeFakeHelm(t)
+	defer cleanup()
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.EnableHelm(bin)
+	th.WriteF("/app/values.yaml", `replicas: 2
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: HelmChartInflationGenerator
+metadata:
+  name: notImportantHere
+name: minecraft
+repo: https://example.com/charts
+releaseName: moria
+namespace: games
+includeCRDs: true
+valuesFiles:
+- values.yaml
+valuesInline:
+  difficulty: hard
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+# Source: chart/templates/configmap.yaml
+apiVersion: v1
+data:
+  chart: minecraft
+  flags: --namespace games --include-crds --values values-0.yaml --values values-1.yaml
+  values: |
+    replicas: 2
+    difficulty: hard
+kind: ConfigMap
+metadata:
+  name: moria-config
+`)
+}
+
+func TestHelmChartInflationGeneratorChartNotFound(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "HelmChartInflationGenerator")
+
+	bin, cleanup := writeFakeHelm(t)
+	defer cleanup()
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.EnableHelm(bin)
+	err := th.ErrorFromLoadAndRunGenerator(`
+apiVersion: builtin
+kind: HelmChartInflationGenerator
+metadata:
+  name: notImportantHere
+name: minecraft
+`)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "helm chart minecraft not found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/image"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/yaml"
+)
+
+// Rewrite the registry of every container image,
+// e.g. to pull images from a mirror.
+type plugin struct {
+	Rewrites   []image.RegistryRewrite `json:"rewrites,omitempty" yaml:"rewrites,omitempty"`
+	FieldSpecs []config.FieldSpec      `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Rewrites = nil
+	p.FieldSpecs = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		if transformers.Skips(r, "imageRegistryRewrite") {
+			continue
+		}
+		for _, path := range p.FieldSpecs {
+			if !r.OrgId().IsSelected(&path.Gvk) {
+				continue
+			}
+			err := transformers.MutateField(
+				r.Map(), path.PathSlice(), false, p.mutateImage)
+			if err != nil {
+				return err
+			}
+		}
+		if err := p.findContainers(r.Map()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mutateImage applies the first rewrite whose
+// prefix matches the image.
+func (p *plugin) mutateImage(in interface{}) (interface{}, error) {
+	original, ok := in.(string)
+	if !ok {
+		return nil, fmt.Errorf("image path is not of type string but %T", in)
+	}
+	for _, rw := range p.Rewrites {
+		if rewritten, ok := rw.Rewrite(original); ok {
+			return rewritten, nil
+		}
+	}
+	return original, nil
+}
+
+// findContainers searches the object, at any depth,
+// for container lists and rewrites their images.
+func (p *plugin) findContainers(obj map[string]interface{}) error {
+	for key, v := range obj {
+		switch typedV := v.(type) {
+		case map[string]interface{}:
+			if err := p.findContainers(typedV); err != nil {
+				return err
+			}
+		case []interface{}:
+			isContainers := key == "containers" ||
+				key == "initContainers" || key == "ephemeralContainers"
+			for _, item := range typedV {
+				typedItem, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if isContainers {
+					if err := p.updateContainer(typedItem); err != nil {
+						return err
+					}
+				}
+				if err := p.findContainers(typedItem); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *plugin) updateContainer(container map[string]interface{}) error {
+	containerImage, found := container["image"]
+	if !found {
+		return nil
+	}
+	newImage, err := p.mutateImage(containerImage)
+	if err != nil {
+		return err
+	}
+	container["image"] = newImage
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+func TestImageRegistryTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ImageRegistryTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ImageRegistryTransformer
+metadata:
+  name: notImportantHere
+rewrites:
+- from: gcr.io/my-project
+  to: registry.local/gcr
+- from: docker.io
+  to: internal-mirror.example.com
+fieldSpecs:
+- path: spec/runner/image
+  kind: Job
+`, `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  runner:
+    image: gcr.io/my-project/migrate:v2
+  template:
+    spec:
+      initContainers:
+      - name: wait
+        image: busybox:1.31
+      containers:
+      - name: migrate
+        image: gcr.io/my-project/migrate@sha256:24a0c4b4
+      - name: proxy
+        image: quay.io/proxy/proxy:v1
+      ephemeralContainers:
+      - name: debug
+        image: bitnami/kubectl
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: skipped
+  annotations:
+    kustomize.config.k8s.io/skip: imageRegistryRewrite
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+spec:
+  runner:
+    image: registry.local/gcr/migrate:v2
+  template:
+    spec:
+      containers:
+      - image: registry.local/gcr/migrate@sha256:24a0c4b4
+        name: migrate
+      - image: quay.io/proxy/proxy:v1
+        name: proxy
+      ephemeralContainers:
+      - image: internal-mirror.example.com/bitnami/kubectl
+        name: debug
+      initContainers:
+      - image: internal-mirror.example.com/library/busybox:1.31
+        name: wait
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    kustomize.config.k8s.io/skip: imageRegistryRewrite
+  name: skipped
+spec:
+  containers:
+  - image: nginx
+    name: nginx
+`)
+}
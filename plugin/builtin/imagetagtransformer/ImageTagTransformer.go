@@ -36,6 +36,9 @@ func (p *plugin) Config(
 
 func (p *plugin) Transform(m resmap.ResMap) error {
 	for _, r := range m.Resources() {
+		if transformers.Skips(r, "images") {
+			continue
+		}
 		for _, path := range p.FieldSpecs {
 			if !r.OrgId().IsSelected(&path.Gvk) {
 				continue
@@ -83,7 +86,7 @@ func (p *plugin) mutateImage(in interface{}) (interface{}, error) {
 // session, finds matched ones and update the
 // image name and tag name
 func (p *plugin) findAndReplaceImage(obj map[string]interface{}) error {
-	paths := []string{"containers", "initContainers"}
+	paths := []string{"containers", "initContainers", "ephemeralContainers"}
 	updated := false
 	for _, path := range paths {
 		containers, found := obj[path]
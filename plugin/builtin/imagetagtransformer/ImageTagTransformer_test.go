@@ -370,3 +370,75 @@ spec:
         name: init-alpine
 `)
 }
+
+func TestImageTagTransformerEphemeralContainersAndFieldSpecs(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ImageTagTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ImageTagTransformer
+metadata:
+  name: notImportantHere
+imageTag:
+  name: redis
+  newName: my-registry/redis
+  newTag: "6.0"
+fieldSpecs:
+- path: spec/image
+  kind: RedisCluster
+- path: spec/sentinels/image
+  kind: RedisCluster
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - image: nginx
+    name: nginx
+  ephemeralContainers:
+  - image: redis:5
+    name: redis-cli
+---
+apiVersion: example.com/v1
+kind: RedisCluster
+metadata:
+  name: cache
+spec:
+  image: redis:5
+  sentinels:
+  - image: redis
+  - image: busybox
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - image: nginx
+    name: nginx
+  ephemeralContainers:
+  - image: my-registry/redis:6.0
+    name: redis-cli
+---
+apiVersion: example.com/v1
+kind: RedisCluster
+metadata:
+  name: cache
+spec:
+  image: my-registry/redis:6.0
+  sentinels:
+  - image: my-registry/redis:6.0
+  - image: busybox
+`)
+}
@@ -36,5 +36,5 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	if err != nil {
 		return err
 	}
-	return t.Transform(m)
+	return t.Transform(transformers.WithoutSkipping(m, "commonLabels"))
 }
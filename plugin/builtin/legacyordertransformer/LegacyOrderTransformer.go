@@ -8,9 +8,11 @@ import (
 	"sort"
 
 	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/gvk"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/yaml"
 )
 
 // Sort the resources using an ordering defined in the Gvk class.
@@ -18,21 +20,31 @@ import (
 // dependencies (like Namespace, StorageClass, etc.)
 // first, and resources with a high number of dependencies
 // (like ValidatingWebhookConfiguration) last.
-type plugin struct{}
+// OrderFirst and OrderLast, if either is set, replace
+// the kinds put first and last.
+type plugin struct {
+	OrderFirst []string `json:"orderFirst,omitempty" yaml:"orderFirst,omitempty"`
+	OrderLast  []string `json:"orderLast,omitempty" yaml:"orderLast,omitempty"`
+}
 
 //noinspection GoUnusedGlobalVariable
 var KustomizePlugin plugin
 
-// Nothing needed for configuration.
 func (p *plugin) Config(
 	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
-	return nil
+	p.OrderFirst = nil
+	p.OrderLast = nil
+	return yaml.Unmarshal(c, p)
 }
 
 func (p *plugin) Transform(m resmap.ResMap) (err error) {
 	resources := make([]*resource.Resource, m.Size())
 	ids := m.AllIds()
-	sort.Sort(resmap.IdSlice(ids))
+	if p.OrderFirst == nil && p.OrderLast == nil {
+		sort.Sort(resmap.IdSlice(ids))
+	} else {
+		resmap.SortIdsIn(ids, gvk.NewKindOrder(p.OrderFirst, p.OrderLast))
+	}
 	for i, id := range ids {
 		resources[i], err = m.GetByCurrentId(id)
 		if err != nil {
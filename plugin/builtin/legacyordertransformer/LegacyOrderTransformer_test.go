@@ -117,3 +117,66 @@ metadata:
   name: pomegranate
 `)
 }
+
+func TestLegacyOrderTransformerWithKindOrder(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "LegacyOrderTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: LegacyOrderTransformer
+metadata:
+  name: notImportantHere
+orderFirst:
+- CustomResourceDefinition
+- Namespace
+orderLast:
+- MutatingWebhookConfiguration
+`, `
+apiVersion: v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: papaya
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apple
+---
+apiVersion: v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: pomegranate
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: banana
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: banana
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apple
+---
+apiVersion: v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: pomegranate
+---
+apiVersion: v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: papaya
+`)
+}
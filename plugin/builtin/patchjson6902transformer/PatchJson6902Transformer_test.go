@@ -114,6 +114,32 @@ target:
 	}
 }
 
+func TestEmptyTargetJson6902Transformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PatchJson6902Transformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: PatchJson6902Transformer
+metadata:
+  name: notImportantHere
+target:
+  namespace: default
+jsonOp: '[{"op": "add", "path": "/spec/replicas", "value": "3"}]'
+`, target)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "target must have a kind, a name") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
 func TestBothSpecifiedJson6902Transformer(t *testing.T) {
 	tc := testenv.NewEnvForTest(t).Set()
 	defer tc.Reset()
@@ -334,3 +360,194 @@ spec:
       dnsPolicy: ClusterFirst
 `)
 }
+
+func TestPatchJson6902TransformerRegexTarget(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PatchJson6902Transformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: PatchJson6902Transformer
+metadata:
+  name: notImportantHere
+target:
+  group: apps
+  version: v1
+  kind: StatefulSet
+  name: my-sts-.*
+jsonOp: '[{"op": "replace", "path": "/spec/serviceName", "value": "new-svc"}]'
+`, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts-a
+spec:
+  serviceName: my-svc
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts-b
+spec:
+  serviceName: my-svc
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: other-sts
+spec:
+  serviceName: my-svc
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts-a
+spec:
+  serviceName: new-svc
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-sts-b
+spec:
+  serviceName: new-svc
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: other-sts
+spec:
+  serviceName: my-svc
+`)
+}
+
+func TestPatchJson6902TransformerRegexTargetNoMatch(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PatchJson6902Transformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: PatchJson6902Transformer
+metadata:
+  name: notImportantHere
+target:
+  group: apps
+  version: v1
+  kind: Deployment
+  name: other-.*
+jsonOp: '[{"op": "add", "path": "/spec/template/spec/dnsPolicy", "value": "ClusterFirst"}]'
+`, target)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "no matches for json patch target") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestPatchJson6902TransformerMultipleTargets(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PatchJson6902Transformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: PatchJson6902Transformer
+metadata:
+  name: notImportantHere
+target:
+  labelSelector: tier=web
+jsonOp: '[{"op": "add", "path": "/metadata/annotations", "value": {"patched": "true"}}]'
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    tier: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: db
+  labels:
+    tier: db
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    patched: "true"
+  labels:
+    tier: web
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    patched: "true"
+  labels:
+    tier: web
+  name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: db
+  name: db
+`)
+}
+
+func TestPatchJson6902TransformerMultipleTargetsNoMatch(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PatchJson6902Transformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: PatchJson6902Transformer
+metadata:
+  name: notImportantHere
+target:
+  kind: Deployment
+  labelSelector: tier=web
+jsonOp: '[{"op": "add", "path": "/spec/template/spec/dnsPolicy", "value": "ClusterFirst"}]'
+`, target)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "no matches for json patch target") {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
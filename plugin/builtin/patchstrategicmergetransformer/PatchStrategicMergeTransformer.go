@@ -6,10 +6,12 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
@@ -20,6 +22,10 @@ type plugin struct {
 	loadedPatches []*resource.Resource
 	Paths         []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
 	Patches       string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+
+	// PatchMergeKeys declares how to merge lists in
+	// objects of types without a built-in schema.
+	PatchMergeKeys []config.PatchMergeKey `json:"patchMergeKeys,omitempty" yaml:"patchMergeKeys,omitempty"`
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -47,6 +53,9 @@ func (p *plugin) Config(
 			if err != nil {
 				return err
 			}
+			for _, r := range res {
+				r.AppendProvenance(filepath.Join(ldr.Root(), string(onePath)))
+			}
 			p.loadedPatches = append(p.loadedPatches, res...)
 		}
 	}
@@ -70,12 +79,19 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	if err != nil {
 		return err
 	}
+	for _, patch := range p.loadedPatches {
+		if target, err := m.GetById(patch.OrgId()); err == nil {
+			target.AppendProvenance(patch.Provenance()...)
+			target.MergeComments(patch.Comments())
+		}
+	}
 	for _, patch := range patches.Resources() {
 		target, err := m.GetById(patch.OrgId())
 		if err != nil {
 			return err
 		}
-		err = target.Patch(patch.Kunstructured)
+		err = target.PatchWithMergeKeys(patch.Kunstructured,
+			config.MergeKeysFor(p.PatchMergeKeys, target.GetGvk()))
 		if err != nil {
 			return err
 		}
@@ -6,12 +6,14 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/evanphx/json-patch"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
 	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
@@ -24,6 +26,14 @@ type plugin struct {
 	Path         string          `json:"path,omitempty" yaml:"path,omitempty"`
 	Patch        string          `json:"patch,omitempty" yaml:"patch,omitempty"`
 	Target       *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Exclude selects resources the patch isn't applied
+	// to, even though Target selects them.
+	Exclude []types.Selector `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+
+	// PatchMergeKeys declares how strategic merge patches
+	// merge lists in objects of types without a built-in schema.
+	PatchMergeKeys []config.PatchMergeKey `json:"patchMergeKeys,omitempty" yaml:"patchMergeKeys,omitempty"`
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -85,11 +95,14 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 		if err != nil {
 			return err
 		}
-		err = target.Patch(p.loadedPatch.Kunstructured)
+		err = target.PatchWithMergeKeys(p.loadedPatch.Kunstructured,
+			config.MergeKeysFor(p.PatchMergeKeys, target.GetGvk()))
 		if err != nil {
 			return err
 		}
-		return nil
+		target.MergeComments(p.loadedPatch.Comments())
+		p.recordProvenance(target)
+		return removeIfDeleted(m, target)
 	}
 
 	if p.Target == nil {
@@ -100,7 +113,14 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	if err != nil {
 		return err
 	}
+	excluded, err := p.excluded(m)
+	if err != nil {
+		return err
+	}
 	for _, res := range resources {
+		if excluded[res] {
+			continue
+		}
 		if p.decodedPatch != nil {
 			rawObj, err := res.MarshalJSON()
 			if err != nil {
@@ -121,15 +141,55 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 			patchCopy.SetName(res.GetName())
 			patchCopy.SetNamespace(res.GetNamespace())
 			patchCopy.SetGvk(res.GetGvk())
-			err = res.Patch(patchCopy.Kunstructured)
+			err = res.PatchWithMergeKeys(patchCopy.Kunstructured,
+				config.MergeKeysFor(p.PatchMergeKeys, res.GetGvk()))
 			if err != nil {
 				return err
 			}
+			res.MergeComments(p.loadedPatch.Comments())
 		}
+		p.recordProvenance(res)
+		err = removeIfDeleted(m, res)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeIfDeleted removes the resource from the resmap
+// if a patch holding $patch: delete emptied it.
+func removeIfDeleted(m resmap.ResMap, res *resource.Resource) error {
+	if len(res.Map()) == 0 {
+		return m.Remove(res.CurId())
 	}
 	return nil
 }
 
+// excluded returns the resources selected by any
+// of the exclude selectors.
+func (p *plugin) excluded(m resmap.ResMap) (map[*resource.Resource]bool, error) {
+	result := make(map[*resource.Resource]bool)
+	for _, s := range p.Exclude {
+		resources, err := m.Select(s)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			result[res] = true
+		}
+	}
+	return result, nil
+}
+
+// recordProvenance records the patch file, if any,
+// as a contributor to the given resource.
+func (p *plugin) recordProvenance(res *resource.Resource) {
+	if p.Path != "" {
+		res.AppendProvenance(filepath.Join(p.ldr.Root(), p.Path))
+	}
+}
+
 // jsonPatchFromBytes loads a Json 6902 patch from
 // a bytes input
 func jsonPatchFromBytes(
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/kustomize/v3/pkg/resmap"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers"
 	"sigs.k8s.io/kustomize/v3/pkg/transformers/config"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
 	"sigs.k8s.io/yaml"
 )
 
@@ -22,6 +23,12 @@ type plugin struct {
 	Prefix     string             `json:"prefix,omitempty" yaml:"prefix,omitempty"`
 	Suffix     string             `json:"suffix,omitempty" yaml:"suffix,omitempty"`
 	FieldSpecs []config.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+
+	// SkipKinds are kinds whose names are left alone.
+	SkipKinds []string `json:"skipKinds,omitempty" yaml:"skipKinds,omitempty"`
+
+	// Target, if not nil, selects the resources to rename.
+	Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -42,6 +49,8 @@ func (p *plugin) Config(
 	p.Prefix = ""
 	p.Suffix = ""
 	p.FieldSpecs = nil
+	p.SkipKinds = nil
+	p.Target = nil
 	err = yaml.Unmarshal(c, p)
 	if err != nil {
 		return
@@ -58,12 +67,27 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	// to proceed with the transformation. This allows to add contextual
 	// information to the resources (AddNamePrefix and AddNameSuffix).
 
-	for _, r := range m.Resources() {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
 		if p.shouldSkip(r.OrgId()) {
 			// Don't change the actual definition
 			// of a CRD.
 			continue
 		}
+		prefix, suffix := p.Prefix, p.Suffix
+		if transformers.Skips(r, "namePrefix") {
+			prefix = ""
+		}
+		if transformers.Skips(r, "nameSuffix") {
+			suffix = ""
+		}
 		id := r.OrgId()
 		// current default configuration contains
 		// only one entry: "metadata/name" with no GVK
@@ -80,8 +104,8 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 				// this will add a prefix and a suffix
 				// to the resource even if those are
 				// empty
-				r.AddNamePrefix(p.Prefix)
-				r.AddNameSuffix(p.Suffix)
+				r.AddNamePrefix(prefix)
+				r.AddNameSuffix(suffix)
 			}
 
 			// the addPrefixSuffix method will not
@@ -91,7 +115,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 				r.Map(),
 				path.PathSlice(),
 				path.CreateIfNotPresent,
-				p.addPrefixSuffix)
+				addPrefixSuffix(prefix, suffix))
 			if err != nil {
 				return err
 			}
@@ -111,14 +135,21 @@ func (p *plugin) shouldSkip(
 			return true
 		}
 	}
+	for _, kind := range p.SkipKinds {
+		if id.Kind == kind {
+			return true
+		}
+	}
 	return false
 }
 
-func (p *plugin) addPrefixSuffix(
-	in interface{}) (interface{}, error) {
-	s, ok := in.(string)
-	if !ok {
-		return nil, fmt.Errorf("%#v is expected to be %T", in, s)
+func addPrefixSuffix(
+	prefix, suffix string) func(interface{}) (interface{}, error) {
+	return func(in interface{}) (interface{}, error) {
+		s, ok := in.(string)
+		if !ok {
+			return nil, fmt.Errorf("%#v is expected to be %T", in, s)
+		}
+		return fmt.Sprintf("%s%s%s", prefix, s, suffix), nil
 	}
-	return fmt.Sprintf("%s%s%s", p.Prefix, s, p.Suffix), nil
 }
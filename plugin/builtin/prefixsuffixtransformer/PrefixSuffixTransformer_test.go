@@ -144,3 +144,111 @@ metadata:
   name: cm
 `)
 }
+
+func TestPrefixSuffixTransformerSkipKinds(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PrefixSuffixTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: PrefixSuffixTransformer
+metadata:
+  name: notImportantHere
+prefix: baked-
+suffix: -pie
+skipKinds:
+- Namespace
+- ClusterRole
+fieldSpecs:
+  - path: metadata/name
+`, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shared
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shared
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: baked-cm-pie
+`)
+}
+
+func TestPrefixSuffixTransformerTarget(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "PrefixSuffixTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: PrefixSuffixTransformer
+metadata:
+  name: notImportantHere
+prefix: baked-
+target:
+  labelSelector: tier=app
+fieldSpecs:
+  - path: metadata/name
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    tier: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared-config
+  labels:
+    tier: infra
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: app
+  name: baked-web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    tier: infra
+  name: shared-config
+`)
+}
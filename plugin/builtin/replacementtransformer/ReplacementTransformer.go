@@ -0,0 +1,255 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Copy a field of a source resource, or a value,
+// into fields of targets.
+type plugin struct {
+	types.Replacement `json:",inline,omitempty" yaml:",inline,omitempty"`
+
+	// Value, if given, is copied in place of a source field.
+	Value *string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Replacement = types.Replacement{}
+	p.Value = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.Value != nil {
+		if p.Source != nil {
+			return fmt.Errorf(
+				"replacement must not specify both a source and a value")
+		}
+		return p.checkTargets()
+	}
+	if p.Source == nil {
+		return fmt.Errorf("replacement must specify a source")
+	}
+	if p.Source.Export != "" {
+		if p.Source.Selector != (types.Selector{}) || p.Source.FieldPath != "" {
+			return fmt.Errorf(
+				"replacement source with an export must not " +
+					"specify a selector or fieldPath")
+		}
+	} else if p.Source.FieldPath == "" {
+		p.Source.FieldPath = types.DefaultReplacementFieldPath
+	}
+	if p.Source.Options != nil {
+		if err = p.Source.Options.Validate(); err != nil {
+			return err
+		}
+	}
+	return p.checkTargets()
+}
+
+// checkTargets returns an error if a target doesn't
+// specify what to replace, or has invalid options.
+func (p *plugin) checkTargets() error {
+	for _, t := range p.Targets {
+		if t.Select == nil {
+			return fmt.Errorf("replacement target must specify select")
+		}
+		if len(t.FieldPaths) == 0 {
+			return fmt.Errorf("replacement target must specify fieldPaths")
+		}
+		if t.Options != nil {
+			if err := t.Options.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	value, err := p.sourceValue(m)
+	if err != nil {
+		return err
+	}
+	for _, t := range p.Targets {
+		resources, err := m.Select(*t.Select)
+		if err != nil {
+			return err
+		}
+		rejected, err := rejectedBy(m, t.Reject)
+		if err != nil {
+			return err
+		}
+		replace := func(interface{}) (interface{}, error) {
+			return deepCopy(value), nil
+		}
+		if t.Options != nil {
+			replace = replacePart(t.Options, value)
+		}
+		for _, res := range resources {
+			if rejected[res] || transformers.Skips(res, "replacements") {
+				continue
+			}
+			for _, path := range t.FieldPaths {
+				err = transformers.MutateField(
+					res.Map(), strings.Split(path, "."), t.Create, replace)
+				if err != nil {
+					return errors.Wrapf(
+						err, "replacing %s in %s", path, res.CurId())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sourceValue returns the value, if given, or else
+// the value of the source field of the one resource
+// the source selects.
+func (p *plugin) sourceValue(m resmap.ResMap) (interface{}, error) {
+	if p.Value != nil {
+		return *p.Value, nil
+	}
+	res, path, err := p.sourceField(m)
+	if err != nil {
+		return nil, err
+	}
+	value, err := res.GetFieldValue(path)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "replacement source %s", res.CurId())
+	}
+	if p.Source.Options == nil {
+		return value, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf(
+			"replacement source %s field %s with options must be a string",
+			res.CurId(), path)
+	}
+	value, err = p.Source.Options.Select(s)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err, "replacement source %s", res.CurId())
+	}
+	return value, nil
+}
+
+// sourceField returns the one resource the source
+// selects, or that exports the source's export, and
+// the path to the source field.
+func (p *plugin) sourceField(
+	m resmap.ResMap) (*resource.Resource, string, error) {
+	if p.Source.Export != "" {
+		var exporters []*resource.Resource
+		var path string
+		for _, res := range m.Resources() {
+			if f, ok := res.GetExports()[p.Source.Export]; ok {
+				exporters = append(exporters, res)
+				path = f
+			}
+		}
+		if len(exporters) != 1 {
+			return nil, "", fmt.Errorf(
+				"replacement source export %s must be exported "+
+					"by one resource, exported by %d",
+				p.Source.Export, len(exporters))
+		}
+		return exporters[0], path, nil
+	}
+	resources, err := m.Select(p.Source.Selector)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(resources) != 1 {
+		return nil, "", fmt.Errorf(
+			"replacement source must select one resource, selected %d",
+			len(resources))
+	}
+	return resources[0], p.Source.FieldPath, nil
+}
+
+// replacePart returns a function replacing the part of
+// a string field that the options address by the value.
+// A field just created to replace is an empty map, and
+// counts as an empty string.
+func replacePart(
+	o *types.ReplacementOptions,
+	value interface{}) func(interface{}) (interface{}, error) {
+	return func(in interface{}) (interface{}, error) {
+		v, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf(
+				"replacement value %v of a target with options must be a string",
+				value)
+		}
+		var s string
+		switch f := in.(type) {
+		case string:
+			s = f
+		case map[string]interface{}:
+			if len(f) != 0 {
+				return nil, fmt.Errorf("field with options must be a string")
+			}
+		default:
+			return nil, fmt.Errorf("field with options must be a string")
+		}
+		return o.Replace(s, v)
+	}
+}
+
+// rejectedBy returns the resources selected by
+// any of the selectors.
+func rejectedBy(
+	m resmap.ResMap, selectors []types.Selector) (map[*resource.Resource]bool, error) {
+	result := make(map[*resource.Resource]bool)
+	for _, s := range selectors {
+		resources, err := m.Select(s)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			result[res] = true
+		}
+	}
+	return result, nil
+}
+
+// deepCopy copies the maps and lists of the value,
+// so targets don't share them with the source.
+func deepCopy(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = deepCopy(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = deepCopy(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,392 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+const replacementInput = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: dev-nginx
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+      - name: sidecar
+        image: busybox
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  serviceName: db
+`
+
+func TestReplacementTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ReplacementTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+source:
+  kind: Service
+  name: dev-nginx
+targets:
+- select:
+    kind: StatefulSet
+  fieldPaths:
+  - spec.serviceName
+- select:
+    kind: StatefulSet
+  reject:
+  - name: db
+  fieldPaths:
+  - spec.template.spec.containers.env.SERVICE
+  create: true
+`, replacementInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: dev-nginx
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+          SERVICE: dev-nginx
+        image: nginx
+        name: nginx
+      - env:
+          SERVICE: dev-nginx
+        image: busybox
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  serviceName: dev-nginx
+`)
+}
+
+func TestReplacementTransformerFieldPath(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ReplacementTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+source:
+  kind: StatefulSet
+  name: web
+  fieldPath: spec.template.spec.containers[1].image
+targets:
+- select:
+    name: db
+  fieldPaths:
+  - spec.serviceName
+  - spec.image
+`, replacementInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: dev-nginx
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+      - image: busybox
+        name: sidecar
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  serviceName: busybox
+`)
+}
+
+func TestReplacementTransformerAmbiguousSource(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ReplacementTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+source:
+  kind: StatefulSet
+targets:
+- select:
+    kind: Service
+  fieldPaths:
+  - spec.clusterIP
+`, replacementInput)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"replacement source must select one resource, selected 2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReplacementTransformerOptions(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ReplacementTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+source:
+  kind: Deployment
+  name: web
+  fieldPath: spec.template.spec.containers[0].image
+  options:
+    regex: ":([^:/]+)$"
+targets:
+- select:
+    kind: Deployment
+  reject:
+  - name: web
+  fieldPaths:
+  - spec.template.spec.containers.image
+  options:
+    delimiter: ":"
+    index: 1
+- select:
+    kind: Deployment
+  fieldPaths:
+  - metadata.labels.version
+  options:
+    regex: "^v?(.*)$"
+  create: true
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: registry.local:5000/web:1.17
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+  labels:
+    version: v1.16
+spec:
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: worker:1.16
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    version: "1.17"
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: registry.local:5000/web:1.17
+        name: web
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    version: v1.17
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+      - image: worker:1.17
+        name: worker
+`)
+}
+
+func TestReplacementTransformerBadOptions(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ReplacementTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+source:
+  kind: Service
+  options:
+    delimiter: "-"
+    index: 3
+targets:
+- select:
+    kind: StatefulSet
+  fieldPaths:
+  - spec.serviceName
+`, replacementInput)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"index 3 out of range of the 2 parts of 'dev-nginx' delimited by '-'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReplacementTransformerExportAndSelector(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ReplacementTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+source:
+  export: https-service
+  kind: Service
+targets:
+- select:
+    kind: StatefulSet
+  fieldPaths:
+  - spec.serviceName
+`, replacementInput)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"replacement source with an export must not specify a selector or fieldPath") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReplacementTransformerValue(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "ReplacementTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplacementTransformer
+metadata:
+  name: notImportantHere
+value: "8080"
+targets:
+- select:
+    kind: Service
+  fieldPaths:
+  - metadata.annotations.port
+  create: true
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: dev-nginx
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    port: "8080"
+  name: dev-nginx
+`)
+}
@@ -6,6 +6,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 
 	"sigs.k8s.io/kustomize/v3/pkg/ifc"
 	"sigs.k8s.io/kustomize/v3/pkg/resid"
@@ -44,6 +45,15 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 
 		for _, res := range append(matchOriginal, matchCurrent...) {
 			found = true
+			if transformers.Skips(res, "replicas") {
+				continue
+			}
+			if hpa := transformers.Autoscaler(m, res); hpa != nil {
+				log.Printf(
+					"warning: not setting the replicas of %s, "+
+						"it's scaled by %s", res.CurId(), hpa.CurId())
+				continue
+			}
 			err := transformers.MutateField(
 				res.Map(), replicaSpec.PathSlice(),
 				replicaSpec.CreateIfNotPresent, p.addReplicas)
@@ -0,0 +1,135 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Add node selectors, tolerations, topology spread
+// constraints and the priority class name to the
+// pod specs of workloads.
+type plugin struct {
+	Target                    *types.Selector          `json:"target,omitempty" yaml:"target,omitempty"`
+	NodeSelector              map[string]string        `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+	PriorityClassName         string                   `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+	Tolerations               []map[string]interface{} `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	TopologySpreadConstraints []map[string]interface{} `json:"topologySpreadConstraints,omitempty" yaml:"topologySpreadConstraints,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Target = nil
+	p.NodeSelector = nil
+	p.PriorityClassName = ""
+	p.Tolerations = nil
+	p.TopologySpreadConstraints = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		if transformers.Skips(r, "scheduling") {
+			continue
+		}
+		for _, spec := range transformers.PodSpecs(r.Map()) {
+			if err := p.constrain(r, spec); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *plugin) constrain(
+	r *resource.Resource, spec map[string]interface{}) error {
+	if len(p.NodeSelector) > 0 {
+		v, found := spec["nodeSelector"]
+		if !found || v == nil {
+			v = map[string]interface{}{}
+			spec["nodeSelector"] = v
+		}
+		nodeSelector, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf(
+				"nodeSelector in %s is not a map but %T", r.OrgId(), v)
+		}
+		for k, label := range p.NodeSelector {
+			nodeSelector[k] = label
+		}
+	}
+	if p.PriorityClassName != "" {
+		spec["priorityClassName"] = p.PriorityClassName
+	}
+	if err := appendMissing(
+		r, spec, "tolerations", p.Tolerations); err != nil {
+		return err
+	}
+	return appendMissing(
+		r, spec, "topologySpreadConstraints", p.TopologySpreadConstraints)
+}
+
+// appendMissing appends the entries to the list at the
+// given key of the pod spec, unless they're in it already.
+func appendMissing(r *resource.Resource, spec map[string]interface{},
+	key string, entries []map[string]interface{}) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var list []interface{}
+	if v, found := spec[key]; found && v != nil {
+		var ok bool
+		list, ok = v.([]interface{})
+		if !ok {
+			return fmt.Errorf(
+				"%s in %s is not a list but %T", key, r.OrgId(), v)
+		}
+	}
+	for _, entry := range entries {
+		entryCopy := runtime.DeepCopyJSON(entry)
+		if !containsEntry(list, entryCopy) {
+			list = append(list, entryCopy)
+		}
+	}
+	spec[key] = list
+	return nil
+}
+
+// containsEntry compares entries as JSON, as numbers
+// may be int64 in resources but float64 in entries.
+func containsEntry(list []interface{}, entry map[string]interface{}) bool {
+	want, err := json.Marshal(entry)
+	if err != nil {
+		return false
+	}
+	for _, e := range list {
+		got, err := json.Marshal(e)
+		if err == nil && bytes.Equal(got, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Merge a pod spec fragment, e.g. sidecar containers
+// and their volumes, into the pod specs of workloads.
+type plugin struct {
+	fragment map[string]interface{}
+	Path     string          `json:"path,omitempty" yaml:"path,omitempty"`
+	Spec     string          `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Target   *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Path = ""
+	p.Spec = ""
+	p.Target = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.Spec == "" && p.Path == "" {
+		return fmt.Errorf(
+			"must specify one of spec and path in\n%s", string(c))
+	}
+	if p.Spec != "" && p.Path != "" {
+		return fmt.Errorf(
+			"spec and path can't be set at the same time\n%s", string(c))
+	}
+	in := []byte(p.Spec)
+	if p.Path != "" {
+		in, err = ldr.Load(p.Path)
+		if err != nil {
+			return err
+		}
+	}
+	p.fragment = nil
+	return yaml.Unmarshal(in, &p.fragment)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	if p.Target != nil {
+		var err error
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		if transformers.Skips(r, "sidecars") {
+			continue
+		}
+		for _, spec := range transformers.PodSpecs(r.Map()) {
+			if err := p.merge(r, spec); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// merge appends the entries of the fragment's lists,
+// e.g. containers and volumes, to the pod spec's lists,
+// unless an entry of the same name is there already, and
+// sets the fragment's other fields the pod spec lacks.
+func (p *plugin) merge(
+	r *resource.Resource, spec map[string]interface{}) error {
+	for k, v := range p.fragment {
+		entries, ok := v.([]interface{})
+		if !ok {
+			if _, found := spec[k]; !found {
+				spec[k] = runtime.DeepCopyJSONValue(v)
+			}
+			continue
+		}
+		var existing []interface{}
+		if found, ok := spec[k]; ok {
+			existing, ok = found.([]interface{})
+			if !ok {
+				return fmt.Errorf(
+					"%s of %s is not a list but %T", k, r.OrgId(), found)
+			}
+		}
+		for _, entry := range entries {
+			if !hasNamed(existing, nameOf(entry)) {
+				existing = append(existing, runtime.DeepCopyJSONValue(entry))
+			}
+		}
+		spec[k] = existing
+	}
+	return nil
+}
+
+func nameOf(entry interface{}) interface{} {
+	if m, ok := entry.(map[string]interface{}); ok {
+		return m["name"]
+	}
+	return nil
+}
+
+// hasNamed returns true if one of the entries has the
+// given, non nil, name.
+func hasNamed(entries []interface{}, name interface{}) bool {
+	if name == nil {
+		return false
+	}
+	for _, entry := range entries {
+		if nameOf(entry) == name {
+			return true
+		}
+	}
+	return false
+}
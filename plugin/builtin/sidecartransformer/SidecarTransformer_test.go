@@ -0,0 +1,238 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+const sidecarInput = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+      - name: log-shipper
+        image: custom-shipper
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+  labels:
+    app: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            image: backup
+          volumes:
+          - name: data
+            emptyDir: {}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  ports:
+  - port: 80
+`
+
+func TestSidecarTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "SidecarTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: SidecarTransformer
+metadata:
+  name: notImportantHere
+spec: |
+  shareProcessNamespace: true
+  containers:
+  - name: log-shipper
+    image: fluent/fluent-bit
+    volumeMounts:
+    - name: logs
+      mountPath: /var/log/app
+  volumes:
+  - name: logs
+    emptyDir: {}
+`, sidecarInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+      - image: custom-shipper
+        name: log-shipper
+      shareProcessNamespace: true
+      volumes:
+      - emptyDir: {}
+        name: logs
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  labels:
+    app: backup
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - image: backup
+            name: backup
+          - image: fluent/fluent-bit
+            name: log-shipper
+            volumeMounts:
+            - mountPath: /var/log/app
+              name: logs
+          shareProcessNamespace: true
+          volumes:
+          - emptyDir: {}
+            name: data
+          - emptyDir: {}
+            name: logs
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: web
+  name: web
+spec:
+  ports:
+  - port: 80
+`)
+}
+
+func TestSidecarTransformerTargetFromFile(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "SidecarTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.WriteF("/app/proxy.yaml", `
+initContainers:
+- name: proxy-init
+  image: mesh/init
+`)
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: SidecarTransformer
+metadata:
+  name: notImportantHere
+path: proxy.yaml
+target:
+  labelSelector: app=web
+`, sidecarInput)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+      - image: custom-shipper
+        name: log-shipper
+      initContainers:
+      - image: mesh/init
+        name: proxy-init
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  labels:
+    app: backup
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - image: backup
+            name: backup
+          volumes:
+          - emptyDir: {}
+            name: data
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: web
+  name: web
+spec:
+  ports:
+  - port: 80
+`)
+}
+
+func TestSidecarTransformerMissingSpec(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "SidecarTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	_, err := th.RunTransformer(`
+apiVersion: builtin
+kind: SidecarTransformer
+metadata:
+  name: notImportantHere
+`, sidecarInput)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "must specify one of spec and path") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
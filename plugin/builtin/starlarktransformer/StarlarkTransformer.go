@@ -0,0 +1,125 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/resource"
+	"sigs.k8s.io/kustomize/v3/pkg/transformers"
+	"sigs.k8s.io/yaml"
+)
+
+// starlarkIndexAnnotation holds the index of a resource
+// while a script runs, to match the resources the script
+// leaves to those it was given.
+const starlarkIndexAnnotation = "config.kubernetes.io/index"
+
+// Run a Starlark script mutating the resources in
+// ctx.resource_list["items"], as in a KRM function.
+// The plugin config is the ctx.resource_list["functionConfig"].
+type plugin struct {
+	// Source is the script.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Path is the file holding the script,
+	// in place of the source.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	name           string
+	functionConfig map[string]interface{}
+	rf             *resmap.Factory
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, c []byte) (err error) {
+	p.Source = ""
+	p.Path = ""
+	p.functionConfig = nil
+	p.rf = rf
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	err = yaml.Unmarshal(c, &p.functionConfig)
+	if err != nil {
+		return err
+	}
+	if (p.Source == "") == (p.Path == "") {
+		return fmt.Errorf(
+			"starlark transformer must have one of source and path")
+	}
+	p.name = "inline script"
+	if p.Path != "" {
+		content, err := ldr.Load(p.Path)
+		if err != nil {
+			return err
+		}
+		p.Source = string(content)
+		p.name = p.Path
+	}
+	return nil
+}
+
+// Transform replaces the resources by those the script
+// leaves.  Resources it keeps the index annotation of
+// update the resources they came from, others are
+// added, and resources it drops are removed.
+func (p *plugin) Transform(m resmap.ResMap) error {
+	resources := m.Resources()
+	items := make([]map[string]interface{}, len(resources))
+	for i, r := range resources {
+		c := r.DeepCopy()
+		annotations := c.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[starlarkIndexAnnotation] = strconv.Itoa(i)
+		c.SetAnnotations(annotations)
+		items[i] = c.Map()
+	}
+	out, err := transformers.RunStarlark(
+		p.name, p.Source, items, p.functionConfig)
+	if err != nil {
+		return errors.Wrap(err, "running starlark")
+	}
+	var result []*resource.Resource
+	for _, item := range out {
+		r := p.rf.RF().FromMap(item)
+		annotations := r.GetAnnotations()
+		index, ok := annotations[starlarkIndexAnnotation]
+		if !ok {
+			result = append(result, r)
+			continue
+		}
+		delete(annotations, starlarkIndexAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		r.SetAnnotations(annotations)
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 || i >= len(resources) {
+			return fmt.Errorf(
+				"starlark %s changed annotation %s to %s",
+				p.name, starlarkIndexAnnotation, index)
+		}
+		resources[i].Kunstructured = r.Kunstructured
+		result = append(result, resources[i])
+	}
+	m.Clear()
+	for _, r := range result {
+		if err := m.Append(r); err != nil {
+			return errors.Wrapf(err, "starlark %s", p.name)
+		}
+	}
+	return nil
+}
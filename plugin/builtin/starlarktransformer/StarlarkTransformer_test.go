@@ -0,0 +1,166 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+const starlarkResources = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scratch
+`
+
+func TestStarlarkTransformer(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "StarlarkTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+replicas: 3
+source: |
+  config = ctx.resource_list["functionConfig"]
+  items = []
+  for r in ctx.resource_list["items"]:
+    if r["kind"] == "ConfigMap":
+      continue
+    if r["kind"] == "Deployment":
+      r["spec"]["replicas"] = config["replicas"]
+      r["metadata"]["labels"] = {"scaled": "true"}
+    items.append(r)
+  items.append({
+    "apiVersion": "v1",
+    "kind": "Namespace",
+    "metadata": {"name": "web"},
+  })
+  ctx.resource_list["items"] = items
+`, starlarkResources)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    scaled: "true"
+  name: web
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: web
+`)
+}
+
+func TestStarlarkTransformerPath(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "StarlarkTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	th.WriteF("/app/annotate.star", `
+def annotate(r):
+  r["metadata"].setdefault("annotations", {})["owner"] = "web-team"
+
+for r in ctx.resource_list["items"]:
+  annotate(r)
+`)
+
+	rm := th.LoadAndRunTransformer(`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+path: annotate.star
+`, starlarkResources)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    owner: web-team
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    owner: web-team
+  name: scratch
+`)
+}
+
+func TestStarlarkTransformerErrors(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "StarlarkTransformer")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+
+	for _, c := range []struct {
+		config   string
+		expected string
+	}{
+		{`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+`, "starlark transformer must have one of source and path"},
+		{`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+source: |
+  ctx.resource_list["items"][0]["spec"]["replicas"] = 1 // 0
+`, "division by zero"},
+		{`
+apiVersion: builtin
+kind: StarlarkTransformer
+metadata:
+  name: notImportantHere
+source: |
+  ctx.resource_list["items"] = [len]
+`, "inline script left items that hold a builtin_function_or_method"},
+	} {
+		err := th.ErrorFromLoadAndRunTransformer(c.config, starlarkResources)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if !strings.Contains(err.Error(), c.expected) {
+			t.Fatalf("expected error containing '%s', got '%v'",
+				c.expected, err)
+		}
+	}
+}
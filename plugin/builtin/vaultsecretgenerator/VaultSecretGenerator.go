@@ -0,0 +1,185 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/v3/pkg/ifc"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/resmap"
+	"sigs.k8s.io/kustomize/v3/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate a secret from values read from HashiCorp Vault.
+type plugin struct {
+	ldr              ifc.Loader
+	rf               *resmap.Factory
+	types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+	types.GeneratorOptions
+	types.VaultSecretArgs
+
+	// address is that of the Vault server.
+	address string
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	ldr ifc.Loader, rf *resmap.Factory, config []byte) (err error) {
+	p.GeneratorOptions = types.GeneratorOptions{}
+	p.VaultSecretArgs = types.VaultSecretArgs{}
+	err = yaml.Unmarshal(config, p)
+	if err != nil {
+		return
+	}
+	if p.SecretArgs.Name == "" {
+		p.SecretArgs.Name = p.Name
+	}
+	if p.SecretArgs.Namespace == "" {
+		p.SecretArgs.Namespace = p.Namespace
+	}
+	// The token is sent to the address, so neither
+	// may come from a remote kustomization.
+	if err = loader.EnvReadable(ldr); err != nil {
+		return errors.Wrapf(err, "vault secret %s", p.SecretArgs.Name)
+	}
+	p.address = os.Getenv("VAULT_ADDR")
+	if p.address == "" {
+		return fmt.Errorf(
+			"vault secret %s needs VAULT_ADDR", p.SecretArgs.Name)
+	}
+	for _, ref := range p.Refs {
+		if ref.Path == "" || ref.Key == "" {
+			return fmt.Errorf(
+				"vault secret %s has a ref without path or key",
+				p.SecretArgs.Name)
+		}
+	}
+	p.ldr = ldr
+	p.rf = rf
+	return
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	token, err := vaultToken()
+	if err != nil {
+		return nil, err
+	}
+	secrets := map[string]map[string]interface{}{}
+	var pairs []types.Pair
+	for _, ref := range p.Refs {
+		data, ok := secrets[ref.Path]
+		if !ok {
+			data, err = p.readVaultSecret(token, ref.Path)
+			if err != nil {
+				return nil, err
+			}
+			secrets[ref.Path] = data
+		}
+		v, ok := data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf(
+				"vault secret %s has no key %s", ref.Path, ref.Key)
+		}
+		s, ok := v.(string)
+		if !ok {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			s = string(b)
+		}
+		k := ref.SecretKey
+		if k == "" {
+			k = ref.Key
+		}
+		pairs = append(pairs, types.Pair{Key: k, Value: s})
+	}
+	return p.rf.FromSecretArgs(
+		kvLoader{Loader: p.ldr, pairs: pairs},
+		&p.GeneratorOptions, p.SecretArgs)
+}
+
+// readVaultSecret returns the data of the Vault secret at
+// path.  The data of a kv version 2 secret is unwrapped.
+func (p *plugin) readVaultSecret(
+	token, path string) (map[string]interface{}, error) {
+	url := strings.TrimSuffix(p.address, "/") +
+		"/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading vault secret %s", path)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading vault secret %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"reading vault secret %s: %s", path, resp.Status)
+	}
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err = json.Unmarshal(body, &secret); err != nil {
+		return nil, errors.Wrapf(err, "decoding vault secret %s", path)
+	}
+	inner, isKv2 := secret.Data["data"].(map[string]interface{})
+	if _, ok := secret.Data["metadata"].(map[string]interface{}); ok && isKv2 {
+		return inner, nil
+	}
+	return secret.Data, nil
+}
+
+// vaultToken returns the VAULT_TOKEN environment variable,
+// or else the token the vault CLI saved at login.
+func vaultToken() (string, error) {
+	if t := os.Getenv("VAULT_TOKEN"); t != "" {
+		return t, nil
+	}
+	home, err := os.UserHomeDir()
+	if err == nil {
+		b, err := ioutil.ReadFile(filepath.Join(home, ".vault-token"))
+		if err == nil {
+			return strings.TrimSpace(string(b)), nil
+		}
+	}
+	return "", fmt.Errorf("no vault token; set VAULT_TOKEN or log in")
+}
+
+// kvLoader adds pairs to those the loader reads
+// for generator args.
+type kvLoader struct {
+	ifc.Loader
+	pairs []types.Pair
+}
+
+func (l kvLoader) LoadKvPairs(
+	args types.GeneratorArgs) ([]types.Pair, error) {
+	all, err := l.Loader.LoadKvPairs(args)
+	if err != nil {
+		return nil, err
+	}
+	return append(all, l.pairs...), nil
+}
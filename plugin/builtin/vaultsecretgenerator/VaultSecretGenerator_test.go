@@ -0,0 +1,151 @@
+// Copyright 2019 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/v3/pkg/kusttest"
+	"sigs.k8s.io/kustomize/v3/pkg/loader"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins"
+	"sigs.k8s.io/kustomize/v3/pkg/plugins/testenv"
+)
+
+// fakeVault serves a kv version 2 secret at secret/data/db,
+// and a kv version 1 secret at kv/api, to the token "root".
+func fakeVault() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Vault-Token") != "root" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			switch r.URL.Path {
+			case "/v1/secret/data/db":
+				w.Write([]byte(`{"data": {
+  "data": {"username": "admin", "password": "'hunter2'"},
+  "metadata": {"version": 3}}}`))
+			case "/v1/kv/api":
+				w.Write([]byte(`{"data": {"token": "abc=="}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+}
+
+func makeHarness(t *testing.T) *kusttest_test.KustTestHarness {
+	pc := plugins.ActivePluginConfig()
+	pc.ExternalSecretsEnabled = true
+	return kusttest_test.NewKustTestHarnessFull(
+		t, "/app", loader.RestrictionRootOnly, pc)
+}
+
+func TestVaultSecretGenerator(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "VaultSecretGenerator")
+
+	vault := fakeVault()
+	defer vault.Close()
+	os.Setenv("VAULT_TOKEN", "root")
+	defer os.Unsetenv("VAULT_TOKEN")
+	os.Setenv("VAULT_ADDR", vault.URL)
+	defer os.Unsetenv("VAULT_ADDR")
+
+	th := makeHarness(t)
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: VaultSecretGenerator
+metadata:
+  name: db
+  namespace: prod
+literals:
+- host=db.example.com
+refs:
+- path: secret/data/db
+  key: username
+- path: secret/data/db
+  key: password
+- path: kv/api
+  key: token
+  secretKey: api-token
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: v1
+data:
+  api-token: YWJjPT0=
+  host: ZGIuZXhhbXBsZS5jb20=
+  password: J2h1bnRlcjIn
+  username: YWRtaW4=
+kind: Secret
+metadata:
+  name: db
+  namespace: prod
+type: Opaque
+`)
+}
+
+func TestVaultSecretGeneratorMissingKey(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "VaultSecretGenerator")
+
+	vault := fakeVault()
+	defer vault.Close()
+	os.Setenv("VAULT_TOKEN", "root")
+	defer os.Unsetenv("VAULT_TOKEN")
+	os.Setenv("VAULT_ADDR", vault.URL)
+	defer os.Unsetenv("VAULT_ADDR")
+
+	th := makeHarness(t)
+	err := th.ErrorFromLoadAndRunGenerator(`
+apiVersion: builtin
+kind: VaultSecretGenerator
+metadata:
+  name: db
+refs:
+- path: secret/data/db
+  key: port
+`)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "vault secret secret/data/db has no key port") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestVaultSecretGeneratorNotEnabled(t *testing.T) {
+	tc := testenv.NewEnvForTest(t).Set()
+	defer tc.Reset()
+
+	tc.BuildGoPlugin(
+		"builtin", "", "VaultSecretGenerator")
+
+	th := kusttest_test.NewKustTestPluginHarness(t, "/app")
+	err := th.ErrorFromLoadAndRunGenerator(`
+apiVersion: builtin
+kind: VaultSecretGenerator
+metadata:
+  name: db
+refs:
+- path: secret/data/db
+  key: password
+`)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "requires --enable-external-secrets") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}